@@ -1,16 +1,23 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/parser"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/schema"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/gateway"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/helm"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/ingress"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/istio"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/k8s"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/kustomize"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/netpol"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/traffic"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +27,13 @@ var (
 	validateOnly   bool
 	image          string
 	applyManifests bool
+	outputFormat   string
+	tlsSeed        string
+
+	// kubectl options for apply and delete
+	kubeContext string
+	kubeconfig  string
+	dryRun      string
 )
 
 func main() {
@@ -38,6 +52,8 @@ func main() {
 	generateCmd.Flags().StringVarP(&outputDir, "output-dir", "o", "./output", "Output directory for manifests")
 	generateCmd.Flags().BoolVar(&validateOnly, "validate-only", false, "Only validate, don't generate")
 	generateCmd.Flags().StringVarP(&image, "image", "i", "testservice:latest", "TestService container image")
+	generateCmd.Flags().StringVar(&outputFormat, "format", "yaml", "Output format: yaml (raw manifests), helm (chart) or kustomize (base)")
+	addTLSSeedFlag(generateCmd)
 
 	validateCmd := &cobra.Command{
 		Use:   "validate <dsl-file>",
@@ -53,6 +69,19 @@ func main() {
 		RunE:  runApply,
 	}
 	applyCmd.Flags().StringVarP(&image, "image", "i", "testservice:latest", "TestService container image")
+	addTLSSeedFlag(applyCmd)
+	addKubectlFlags(applyCmd)
+	addDryRunFlag(applyCmd)
+
+	diffCmd := &cobra.Command{
+		Use:   "diff <dsl-file>",
+		Short: "Generate manifests and diff them against the cluster",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDiff,
+	}
+	diffCmd.Flags().StringVarP(&image, "image", "i", "testservice:latest", "TestService container image")
+	addTLSSeedFlag(diffCmd)
+	addKubectlFlags(diffCmd)
 
 	deleteCmd := &cobra.Command{
 		Use:   "delete <dsl-file>",
@@ -60,6 +89,8 @@ func main() {
 		Args:  cobra.ExactArgs(1),
 		RunE:  runDelete,
 	}
+	addKubectlFlags(deleteCmd)
+	addDryRunFlag(deleteCmd)
 
 	examplesCmd := &cobra.Command{
 		Use:   "examples",
@@ -74,9 +105,22 @@ func main() {
 		RunE:  runInit,
 	}
 
-	rootCmd.AddCommand(generateCmd, validateCmd, applyCmd, deleteCmd, examplesCmd, initCmd)
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for the DSL",
+		Long:  "Print a JSON Schema describing the DSL, for editor autocompletion and validation of DSL files",
+		Args:  cobra.NoArgs,
+		RunE:  runSchema,
+	}
+
+	rootCmd.AddCommand(generateCmd, validateCmd, applyCmd, diffCmd, deleteCmd, examplesCmd, initCmd, schemaCmd)
 
 	if err := rootCmd.Execute(); err != nil {
+		// Mirror kubectl's exit code (e.g. kubectl diff exits 1 when there are differences)
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
 		os.Exit(1)
 	}
 }
@@ -103,9 +147,11 @@ func getGenerators(spec *types.AppSpec) []Generator {
 
 		switch ingressProvider {
 		case "gateway-api":
-			generators = append(generators, &gatewayGeneratorAdapter{gen: gateway.NewGenerator(spec)})
+			generators = append(generators, &gatewayGeneratorAdapter{gen: gateway.NewGenerator(spec).WithTLSSeed(tlsSeed)})
 		case "istio-gateway":
 			generators = append(generators, istio.NewGatewayGenerator(spec))
+		case "nginx", "k8s-ingress":
+			generators = append(generators, &ingressGeneratorAdapter{gen: ingress.NewGenerator(spec).WithTLSSeed(tlsSeed)})
 		case "none":
 			// skip
 		}
@@ -121,6 +167,11 @@ func getGenerators(spec *types.AppSpec) []Generator {
 		}
 	}
 
+	// NetworkPolicies derived from the upstream graph (opt-in)
+	if spec.App.NetworkPolicies {
+		generators = append(generators, &netpolGeneratorAdapter{gen: netpol.NewGenerator(spec)})
+	}
+
 	// Traffic generator (if any traffic configs exist)
 	if len(spec.Traffic) > 0 {
 		generators = append(generators, &trafficGeneratorAdapter{gen: traffic.NewGenerator(spec)})
@@ -164,6 +215,30 @@ func (a *gatewayGeneratorAdapter) Generate() (map[string]string, error) {
 	return a.gen.GenerateAll()
 }
 
+type ingressGeneratorAdapter struct {
+	gen *ingress.Generator
+}
+
+func (a *ingressGeneratorAdapter) Name() string {
+	return "nginx-ingress"
+}
+
+func (a *ingressGeneratorAdapter) Generate() (map[string]string, error) {
+	return a.gen.GenerateAll()
+}
+
+type netpolGeneratorAdapter struct {
+	gen *netpol.Generator
+}
+
+func (a *netpolGeneratorAdapter) Name() string {
+	return "networkpolicy"
+}
+
+func (a *netpolGeneratorAdapter) Generate() (map[string]string, error) {
+	return a.gen.GenerateAll()
+}
+
 type trafficGeneratorAdapter struct {
 	gen *traffic.Generator
 }
@@ -183,6 +258,14 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Parsing DSL file: %s\n", dslFile)
 	spec, err := parser.Parse(dslFile)
 	if err != nil {
+		var verrs parser.ValidationErrors
+		if errors.As(err, &verrs) {
+			fmt.Fprintf(os.Stderr, "✗ DSL validation failed:\n")
+			for _, e := range verrs {
+				fmt.Fprintf(os.Stderr, "  - %v\n", e)
+			}
+			return fmt.Errorf("%s has %d validation error(s)", dslFile, len(verrs))
+		}
 		return fmt.Errorf("failed to parse DSL: %w", err)
 	}
 
@@ -196,6 +279,12 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	switch outputFormat {
+	case "", "yaml", "helm", "kustomize":
+	default:
+		return fmt.Errorf("unknown format %q (expected yaml, helm or kustomize)", outputFormat)
+	}
+
 	// Generate manifests
 	fmt.Println("\nGenerating manifests...")
 
@@ -220,6 +309,25 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	switch outputFormat {
+	case "helm":
+		// Wrap manifests in a Helm chart, templating image, replicas and namespaces
+		chart, err := helm.NewGenerator(spec, image).GenerateChart(allManifests)
+		if err != nil {
+			return fmt.Errorf("failed to generate helm chart: %w", err)
+		}
+		allManifests = chart
+		fmt.Printf("  ✓ helm: chart with %d templates\n", len(chart)-2)
+	case "kustomize":
+		// Lay manifests out as a kustomize base for environment overlays
+		base, err := kustomize.NewGenerator(spec).GenerateBase(allManifests)
+		if err != nil {
+			return fmt.Errorf("failed to generate kustomize base: %w", err)
+		}
+		allManifests = base
+		fmt.Printf("  ✓ kustomize: base with %d resources\n", len(base)-1)
+	}
+
 	// Write manifests to disk
 	fmt.Println("\nWriting manifests...")
 	appOutputDir := filepath.Join(outputDir, spec.App.Name)
@@ -250,8 +358,17 @@ func runGenerate(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  ✓ README.md\n")
 
 	fmt.Printf("\n✓ Generated %d manifests in %s\n", len(allManifests)+1, appOutputDir)
-	fmt.Printf("\nTo apply:\n")
-	fmt.Printf("  kubectl apply -f %s/\n", appOutputDir)
+	switch outputFormat {
+	case "helm":
+		fmt.Printf("\nTo install:\n")
+		fmt.Printf("  helm install %s %s/\n", spec.App.Name, appOutputDir)
+	case "kustomize":
+		fmt.Printf("\nTo apply:\n")
+		fmt.Printf("  kubectl apply -k %s/base/\n", appOutputDir)
+	default:
+		fmt.Printf("\nTo apply:\n")
+		fmt.Printf("  kubectl apply -f %s/\n", appOutputDir)
+	}
 
 	return nil
 }
@@ -261,9 +378,68 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	return runGenerate(cmd, args)
 }
 
+// addKubectlFlags registers the cluster connection flags passed through to kubectl
+func addKubectlFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&kubeContext, "context", "", "kubeconfig context to use")
+	cmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file")
+}
+
+// addTLSSeedFlag registers --tls-seed for commands that generate the self-signed ingress certificate
+func addTLSSeedFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&tlsSeed, "tls-seed", "", "Derive the self-signed TLS certificate from this seed, so regenerating doesn't change it")
+}
+
+// addDryRunFlag registers --dry-run for kubectl verbs that support it
+func addDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "kubectl dry-run strategy: none, server or client")
+}
+
 func runApply(cmd *cobra.Command, args []string) error {
-	// First generate
-	outputDir = "/tmp/testgen-" + filepath.Base(args[0])
+	return generateAndRunKubectl(cmd, args, "apply")
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	err := generateAndRunKubectl(cmd, args, "diff")
+
+	// kubectl diff exits 1 when the cluster differs: report it and keep the exit code,
+	// without cobra treating it as a usage error
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		fmt.Println("\n✗ Cluster differs from the generated manifests")
+	}
+	return err
+}
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	return generateAndRunKubectl(cmd, args, "delete", "--ignore-not-found")
+}
+
+// generateAndRunKubectl generates manifests into a temporary directory and runs
+// kubectl <verb> on them, streaming its output
+func generateAndRunKubectl(cmd *cobra.Command, args []string, verb string, extraArgs ...string) error {
+	switch dryRun {
+	case "", "none", "server", "client":
+	default:
+		return fmt.Errorf("invalid --dry-run %q (expected none, server or client)", dryRun)
+	}
+
+	// Fail before generating anything if kubectl can't be run
+	kubectl, err := exec.LookPath("kubectl")
+	if err != nil {
+		return fmt.Errorf("kubectl not found on PATH: install kubectl to use testgen %s", verb)
+	}
+
+	// A fresh directory per run, so manifests left over from earlier runs (e.g. of a
+	// service since removed from the DSL) aren't applied, diffed or deleted with -R
+	tmpDir, err := os.MkdirTemp("", "testgen-"+filepath.Base(args[0])+"-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	outputDir = tmpDir
 	if err := runGenerate(cmd, args); err != nil {
 		return err
 	}
@@ -273,35 +449,46 @@ func runApply(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-
 	appOutputDir := filepath.Join(outputDir, spec.App.Name)
 
-	// Apply with kubectl
-	fmt.Println("\nApplying manifests...")
-	// Note: In a real implementation, use os/exec to run kubectl
-	fmt.Printf("  kubectl apply -f %s/\n", appOutputDir)
-	fmt.Println("\n✓ To actually apply, run: kubectl apply -f " + appOutputDir + "/")
+	kubectlArgs := kubectlGlobalArgs()
+	kubectlArgs = append(kubectlArgs, verb, "-R", "-f", appOutputDir)
+	kubectlArgs = append(kubectlArgs, extraArgs...)
+	if dryRun != "" && dryRun != "none" {
+		kubectlArgs = append(kubectlArgs, "--dry-run="+dryRun)
+	}
 
+	fmt.Printf("\nRunning: kubectl %s\n\n", strings.Join(kubectlArgs, " "))
+	kc := exec.CommandContext(cmd.Context(), kubectl, kubectlArgs...)
+	kc.Stdout = os.Stdout
+	kc.Stderr = os.Stderr
+	if err := kc.Run(); err != nil {
+		return fmt.Errorf("kubectl %s failed: %w", verb, err)
+	}
+
+	fmt.Printf("\n✓ kubectl %s succeeded\n", verb)
 	return nil
 }
 
-func runDelete(cmd *cobra.Command, args []string) error {
-	spec, err := parser.Parse(args[0])
-	if err != nil {
-		return err
+// kubectlGlobalArgs returns the connection flags that precede the kubectl verb
+func kubectlGlobalArgs() []string {
+	var args []string
+	if kubeconfig != "" {
+		args = append(args, "--kubeconfig", kubeconfig)
 	}
-
-	fmt.Printf("To delete the application, run:\n")
-	fmt.Printf("  kubectl delete namespace ")
-	for i, ns := range spec.App.Namespaces {
-		if i > 0 {
-			fmt.Printf(" ")
-		}
-		fmt.Printf("%s", ns)
+	if kubeContext != "" {
+		args = append(args, "--context", kubeContext)
 	}
-	fmt.Println()
+	return args
+}
 
-	return nil
+func runSchema(cmd *cobra.Command, args []string) error {
+	data, err := schema.Generate()
+	if err != nil {
+		return fmt.Errorf("failed to generate schema: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
 }
 
 func runExamples(cmd *cobra.Command, args []string) error {
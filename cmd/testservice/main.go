@@ -18,10 +18,12 @@ import (
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
 	pb "github.com/aslakknutsen/kkbase/testapp/proto/testservice"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
@@ -50,6 +52,22 @@ func main() {
 		zap.Int("upstreams", len(cfg.Upstreams)),
 	)
 
+	// Optional server TLS from TLS_CERT_FILE / TLS_KEY_FILE (plaintext when unset)
+	tlsConfig, err := cfg.ServerTLSConfig()
+	if err != nil {
+		tel.Logger.Fatal("Invalid TLS configuration", zap.Error(err))
+	}
+	unified := cfg.HTTPPort == cfg.GRPCPort
+
+	// Export the resources held by resource-exhaustion behaviors as gauges
+	behavior.SetResourceRecorder(tel)
+
+	// Reproducible chaos runs: seed the random source of injected faults
+	if cfg.RandSeed != 0 {
+		service.Random.Seed(cfg.RandSeed)
+		tel.Logger.Info("Seeded random source", zap.Int64("seed", cfg.RandSeed))
+	}
+
 	// Check for CRASH_ON_FILE_CONTENT configuration
 	if crashOnFileContent := os.Getenv("CRASH_ON_FILE_CONTENT"); crashOnFileContent != "" {
 		tel.Logger.Info("Checking for invalid config file content", zap.String("config", crashOnFileContent))
@@ -62,101 +80,125 @@ func main() {
 		checkErrorOnFileContent(errorOnFileContent, tel, cfg)
 	}
 
+	// Dependency-aware readiness checks the configured upstreams. Enable it at startup
+	// when the default behavior asks for it, so /ready reflects upstreams before any traffic.
+	service.UpstreamReadiness.SetUpstreams(cfg.Upstreams)
+	if cfg.DefaultBehavior != "" {
+		if chain, err := behavior.ParseChain(cfg.DefaultBehavior); err == nil {
+			if beh := chain.ForService(cfg.Name); beh != nil {
+				beh.ApplyReadyFromUpstreams()
+			}
+		}
+	}
+
+	// Schedule time-based scenarios from SCENARIOS / SCENARIOS_FILE
+	scenarios, err := service.LoadScenarios()
+	if err != nil {
+		tel.Logger.Error("Failed to load scenarios - continuing without them", zap.Error(err))
+	} else if len(scenarios) > 0 {
+		if err := validateScenarioBehaviors(scenarios); err != nil {
+			tel.Logger.Error("Invalid scenario behavior - continuing without scenarios", zap.Error(err))
+		} else {
+			tel.Logger.Info("Starting scenarios", zap.Int("count", len(scenarios)))
+			service.Scenarios.Start(scenarios, tel.Logger)
+			defer service.Scenarios.Stop()
+		}
+	}
+
 	// Create servers
 	httpSrv := httpserver.NewServer(cfg, tel)
 	grpcSrv := grpcserver.NewServer(cfg, tel)
 
-	// Setup graceful shutdown
+	// Setup graceful shutdown, draining for SHUTDOWN_DELAY (or a shutdown= behavior) first
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	service.Drain.SetDelay(cfg.ShutdownDelay)
 
 	// Setup HTTP handler
 	httpMux := http.NewServeMux()
 	httpMux.Handle("/", httpSrv)
-	httpMux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
-	httpMux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	httpMux.HandleFunc("/ws", httpSrv.ServeWebSocket)
+	httpMux.HandleFunc("/health", service.HealthHandler)
+	httpMux.HandleFunc("/ready", service.ReadyHandler)
+	httpMux.HandleFunc("/admin/reload", service.ReloadHandler)
 
 	httpServer := &http.Server{
-		Handler: httpMux,
+		Handler:   httpMux,
+		TLSConfig: tlsConfig,
 	}
 
 	// Setup gRPC server with Prometheus interceptors
-	grpcServer := grpc.NewServer(
+	grpcOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_prometheus.UnaryServerInterceptor),
 		grpc.StreamInterceptor(grpc_prometheus.StreamServerInterceptor),
-	)
+	}
+	// On the unified port the HTTP server terminates TLS; on its own port gRPC terminates it
+	if tlsConfig != nil && !unified {
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
 	pb.RegisterTestServiceServer(grpcServer, grpcSrv)
+	// Reflection lets generic clients (e.g. the ghz traffic generator) call TestService without the proto
+	reflection.Register(grpcServer)
 
 	// Initialize gRPC metrics
 	grpc_prometheus.Register(grpcServer)
 
 	// Determine which port configuration to use
-	// If HTTP and gRPC ports are the same, use cmux for multiplexing
+	// If HTTP and gRPC ports are the same, serve gRPC through the HTTP server
 	// Otherwise, start them on separate ports (backward compatibility)
-	if cfg.HTTPPort == cfg.GRPCPort {
-		// Unified port mode: use cmux to multiplex HTTP and gRPC on same port
-		tel.Logger.Info("Starting unified HTTP/gRPC server with cmux",
-			zap.Int("port", cfg.HTTPPort))
+	if unified {
+		// Unified port mode: one HTTP server hands gRPC requests to the gRPC server
+		tel.Logger.Info("Starting unified HTTP/gRPC server",
+			zap.Int("port", cfg.HTTPPort),
+			zap.Bool("tls", tlsConfig != nil))
 
-		listener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.HTTPPort))
+		listener, err := service.Listen(cfg.HTTPPort, cfg.MaxConnections)
 		if err != nil {
 			tel.Logger.Fatal("Failed to create listener", zap.Error(err))
 		}
 
-		// Create cmux multiplexer
-		mux := cmux.New(listener)
-
-		// Match HTTP/1.x requests
-		httpListener := mux.Match(cmux.HTTP1Fast())
-
-		// Match HTTP/2 (gRPC) requests
-		grpcListener := mux.Match(cmux.HTTP2())
-
-		// Start HTTP server
-		go func() {
-			tel.Logger.Info("HTTP server starting on unified port", zap.Int("port", cfg.HTTPPort))
-			if err := httpServer.Serve(httpListener); err != nil && err != http.ErrServerClosed {
-				tel.Logger.Fatal("HTTP server failed", zap.Error(err))
-			}
-		}()
+		httpServer.Handler = service.UnifiedHandler(grpcServer, httpMux)
+		httpServer.Protocols = service.UnifiedProtocols()
 
-		// Start gRPC server
 		go func() {
-			tel.Logger.Info("gRPC server starting on unified port", zap.Int("port", cfg.HTTPPort))
-			if err := grpcServer.Serve(grpcListener); err != nil {
-				tel.Logger.Fatal("gRPC server failed", zap.Error(err))
+			tel.Logger.Info("HTTP/gRPC server starting on unified port", zap.Int("port", cfg.HTTPPort))
+			serve := httpServer.Serve
+			if tlsConfig != nil {
+				// The certificate is already loaded into TLSConfig
+				serve = func(l net.Listener) error { return httpServer.ServeTLS(l, "", "") }
 			}
-		}()
-
-		// Start the multiplexer
-		go func() {
-			if err := mux.Serve(); err != nil {
-				tel.Logger.Fatal("cmux server failed", zap.Error(err))
+			if err := serve(listener); err != nil && err != http.ErrServerClosed {
+				tel.Logger.Fatal("HTTP/gRPC server failed", zap.Error(err))
 			}
 		}()
 	} else {
 		// Separate port mode: traditional setup
 		tel.Logger.Info("Starting HTTP and gRPC servers on separate ports",
 			zap.Int("http_port", cfg.HTTPPort),
-			zap.Int("grpc_port", cfg.GRPCPort))
+			zap.Int("grpc_port", cfg.GRPCPort),
+			zap.Bool("tls", tlsConfig != nil))
 
 		// Start HTTP server
-		httpServer.Addr = fmt.Sprintf(":%d", cfg.HTTPPort)
+		httpListener, err := service.Listen(cfg.HTTPPort, cfg.MaxConnections)
+		if err != nil {
+			tel.Logger.Fatal("Failed to listen for HTTP", zap.Error(err))
+		}
+
 		go func() {
 			tel.Logger.Info("HTTP server starting", zap.Int("port", cfg.HTTPPort))
-			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serve := httpServer.Serve
+			if tlsConfig != nil {
+				// The certificate is already loaded into TLSConfig
+				serve = func(l net.Listener) error { return httpServer.ServeTLS(l, "", "") }
+			}
+			if err := serve(httpListener); err != nil && err != http.ErrServerClosed {
 				tel.Logger.Fatal("HTTP server failed", zap.Error(err))
 			}
 		}()
 
 		// Start gRPC server
-		grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+		grpcListener, err := service.Listen(cfg.GRPCPort, cfg.MaxConnections)
 		if err != nil {
 			tel.Logger.Fatal("Failed to listen for gRPC", zap.Error(err))
 		}
@@ -171,7 +213,11 @@ func main() {
 
 	// Start metrics server
 	metricsMux := http.NewServeMux()
-	metricsMux.Handle("/metrics", promhttp.Handler())
+	// OpenMetrics exposition carries the trace ID exemplars on the duration histograms
+	metricsMux.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	))
 
 	metricsServer := &http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.MetricsPort),
@@ -191,6 +237,18 @@ func main() {
 	<-sigChan
 	tel.Logger.Info("Shutdown signal received, gracefully shutting down...")
 
+	// Drain: fail readiness so the pod leaves its endpoints, while still serving new and
+	// in-flight requests until the delay passes. A second signal skips the rest of it.
+	if delay := service.Drain.Delay(); delay > 0 {
+		tel.Logger.Info("Draining before shutdown", zap.Duration("delay", delay))
+		service.Drain.Begin()
+		select {
+		case <-time.After(delay):
+		case <-sigChan:
+			tel.Logger.Info("Second shutdown signal received, skipping the rest of the drain")
+		}
+	}
+
 	// Graceful shutdown with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -201,6 +259,14 @@ func main() {
 
 	grpcServer.GracefulStop()
 
+	// Release pooled upstream connections once no more requests are in flight
+	if err := httpSrv.Close(); err != nil {
+		tel.Logger.Error("HTTP upstream connections close error", zap.Error(err))
+	}
+	if err := grpcSrv.Close(); err != nil {
+		tel.Logger.Error("gRPC upstream connections close error", zap.Error(err))
+	}
+
 	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
 		tel.Logger.Error("Metrics server shutdown error", zap.Error(err))
 	}
@@ -208,6 +274,16 @@ func main() {
 	tel.Logger.Info("Shutdown complete")
 }
 
+// validateScenarioBehaviors checks that every scenario's behavior chain parses
+func validateScenarioBehaviors(scenarios []service.Scenario) error {
+	for _, s := range scenarios {
+		if _, err := behavior.ParseChain(s.Behavior); err != nil {
+			return fmt.Errorf("scenario %s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
 // checkCrashOnFileContent checks for invalid content in config files and crashes if found
 // Format: /path/to/file:invalid1,invalid2|/other/file:bad
 func checkCrashOnFileContent(config string, tel *telemetry.Telemetry) {
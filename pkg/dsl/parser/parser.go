@@ -3,26 +3,39 @@ package parser
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
-	"gopkg.in/yaml.v3"
 )
 
-// Parse parses a DSL file and returns an AppSpec
+// Parse parses a DSL file and returns an AppSpec.
+// Includes are resolved relative to the file's directory.
 func Parse(filename string) (*types.AppSpec, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	return ParseBytes(data)
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	return parse(data, filepath.Dir(abs), map[string]bool{abs: true})
 }
 
-// ParseBytes parses DSL from bytes
+// ParseBytes parses DSL from bytes.
+// Includes are resolved relative to the working directory.
 func ParseBytes(data []byte) (*types.AppSpec, error) {
-	var spec types.AppSpec
-	if err := yaml.Unmarshal(data, &spec); err != nil {
-		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	return parse(data, ".", map[string]bool{})
+}
+
+// parse substitutes variables, merges includes, applies defaults and validates
+func parse(data []byte, dir string, seen map[string]bool) (*types.AppSpec, error) {
+	spec, err := load(data, dir, nil, seen)
+	if err != nil {
+		return nil, err
 	}
 
 	// Apply defaults
@@ -31,34 +44,76 @@ func ParseBytes(data []byte) (*types.AppSpec, error) {
 	}
 
 	// Validate
-	if err := Validate(&spec); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+	if errs := Validate(spec); len(errs) > 0 {
+		return nil, ValidationErrors(errs)
 	}
 
-	return &spec, nil
+	return spec, nil
+}
+
+// ValidationErrors collects every problem found in a spec so they can be fixed in one pass
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "validation failed with %d error(s):", len(e))
+	for _, err := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
 }
 
-// Validate validates the AppSpec
-func Validate(spec *types.AppSpec) error {
+// Unwrap exposes the individual errors to errors.Is and errors.As
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// knownProtocols are the protocols a service may declare
+var knownProtocols = map[string]bool{"http": true, "grpc": true, "tcp": true}
+
+// knownMethods are the HTTP methods a traffic generator may send
+var knownMethods = map[string]bool{"GET": true, "HEAD": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// k6Patterns are the traffic patterns the k6 load tool implements
+var k6Patterns = map[string]bool{"": true, "steady": true, "spiky": true, "ramp": true}
+
+// fieldError prefixes a validation message with the path of the offending field
+func fieldError(path, format string, args ...interface{}) error {
+	return fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...))
+}
+
+// Validate checks the AppSpec and returns every problem found, each prefixed with its field path
+func Validate(spec *types.AppSpec) []error {
+	var errs []error
+
 	if spec.App.Name == "" {
-		return fmt.Errorf("app.name is required")
+		errs = append(errs, fieldError("app.name", "is required"))
 	}
 
 	if len(spec.Services) == 0 {
-		return fmt.Errorf("at least one service is required")
+		errs = append(errs, fieldError("services", "at least one service is required"))
 	}
 
-	// Build service name map for validation
+	if spec.App.Providers.CertManager && spec.App.Providers.ClusterIssuer == "" {
+		errs = append(errs, fieldError("app.providers.clusterIssuer", "is required when certManager is enabled"))
+	}
+
+	// Build service name maps for validation
 	serviceNames := make(map[string]bool)
-	for _, svc := range spec.Services {
+	declared := make(map[string]bool)
+	for i, svc := range spec.Services {
+		path := fmt.Sprintf("services[%d]", i)
+
 		if svc.Name == "" {
-			return fmt.Errorf("service name is required")
+			errs = append(errs, fieldError(path+".name", "is required"))
 		}
+		declared[svc.Name] = true
 
 		// Check for duplicate names
 		key := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
-		if serviceNames[key] {
-			return fmt.Errorf("duplicate service name: %s in namespace %s", svc.Name, svc.Namespace)
+		if svc.Name != "" && serviceNames[key] {
+			errs = append(errs, fieldError(path+".name", "duplicate service name %s in namespace %s", svc.Name, svc.Namespace))
 		}
 		serviceNames[key] = true
 
@@ -67,66 +122,187 @@ func Validate(spec *types.AppSpec) error {
 		case "Deployment", "StatefulSet", "DaemonSet":
 			// Valid
 		default:
-			return fmt.Errorf("invalid service type: %s (must be Deployment, StatefulSet, or DaemonSet)", svc.Type)
+			errs = append(errs, fieldError(path+".type", "invalid service type %q (must be Deployment, StatefulSet, or DaemonSet)", svc.Type))
 		}
 
 		// Validate protocols
-		for _, proto := range svc.Protocols {
-			if proto != "http" && proto != "grpc" {
-				return fmt.Errorf("invalid protocol %s for service %s (must be http or grpc)", proto, svc.Name)
+		for j, proto := range svc.Protocols {
+			if !knownProtocols[proto] {
+				errs = append(errs, fieldError(fmt.Sprintf("%s.protocols[%d]", path, j), "invalid protocol %q (must be http, grpc or tcp)", proto))
 			}
 		}
 
+		if svc.Replicas < 0 {
+			errs = append(errs, fieldError(path+".replicas", "cannot be negative, got %d", svc.Replicas))
+		}
+
 		// Validate StatefulSet requirements
 		if svc.Type == "StatefulSet" && svc.Storage.Size == "" {
-			return fmt.Errorf("StatefulSet %s requires storage.size", svc.Name)
+			errs = append(errs, fieldError(path+".storage.size", "is required for StatefulSet %s", svc.Name))
 		}
 
 		// Validate replicas for DaemonSet
 		if svc.Type == "DaemonSet" && svc.Replicas > 1 {
-			return fmt.Errorf("DaemonSet %s cannot specify replicas (managed by DaemonSet controller)", svc.Name)
+			errs = append(errs, fieldError(path+".replicas", "DaemonSet %s cannot specify replicas (managed by DaemonSet controller)", svc.Name))
 		}
+
+		// Validate autoscaling bounds
+		if as := svc.Autoscaling; as != nil {
+			if as.MaxReplicas < 1 {
+				errs = append(errs, fieldError(path+".autoscaling.maxReplicas", "is required"))
+			}
+			if as.MinReplicas < 1 {
+				errs = append(errs, fieldError(path+".autoscaling.minReplicas", "must be at least 1"))
+			}
+			if as.MaxReplicas < as.MinReplicas {
+				errs = append(errs, fieldError(path+".autoscaling.maxReplicas", "(%d) must be >= minReplicas (%d)", as.MaxReplicas, as.MinReplicas))
+			}
+			if as.TargetCPUUtilization < 1 || as.TargetCPUUtilization > 100 {
+				errs = append(errs, fieldError(path+".autoscaling.targetCPUUtilization", "must be between 1 and 100"))
+			}
+		}
+	}
+
+	// Ingress hosts route to exactly one service
+	hosts := make(map[string]string)
+	for i, svc := range spec.Services {
+		if !svc.Ingress.Enabled || svc.Ingress.Host == "" {
+			continue
+		}
+		if other, ok := hosts[svc.Ingress.Host]; ok {
+			errs = append(errs, fieldError(fmt.Sprintf("services[%d].ingress.host", i), "host %s is already used by service %s", svc.Ingress.Host, other))
+			continue
+		}
+		hosts[svc.Ingress.Host] = svc.Name
+	}
+
+	// Each exposed TCP service gets its own Gateway listener, so ports can't be shared
+	tcpPorts := make(map[int]string)
+	for i, svc := range spec.Services {
+		if !svc.HasTCP() || !svc.NeedsIngress() {
+			continue
+		}
+		if other, ok := tcpPorts[svc.Ports.TCP]; ok {
+			errs = append(errs, fieldError(fmt.Sprintf("services[%d].ports.tcp", i), "port %d is already exposed through ingress by service %s", svc.Ports.TCP, other))
+			continue
+		}
+		tcpPorts[svc.Ports.TCP] = svc.Name
 	}
 
 	// Validate upstream references
-	for _, svc := range spec.Services {
-		for _, upstream := range svc.Upstreams {
+	for i, svc := range spec.Services {
+		for j, upstream := range svc.Upstreams {
+			path := fmt.Sprintf("services[%d].upstreams[%d]", i, j)
+
 			// Use EffectiveService() to get the target service name
 			// (Service field if set, otherwise Name)
-			targetService := upstream.EffectiveService()
-			found := false
-			for _, target := range spec.Services {
-				if target.Name == targetService {
-					found = true
-					break
+			if targetService := upstream.EffectiveService(); !declared[targetService] {
+				field := ".name"
+				if upstream.Service != "" {
+					field = ".service"
 				}
+				errs = append(errs, fieldError(path+field, "service %s references unknown upstream %s", svc.Name, targetService))
+			}
+			if upstream.Probability < 0 || upstream.Probability > 1 {
+				errs = append(errs, fieldError(path+".probability", "must be between 0 and 1, got %v", upstream.Probability))
 			}
-			if !found {
-				return fmt.Errorf("service %s references unknown upstream: %s", svc.Name, targetService)
+			if upstream.Probability > 0 && upstream.Group != "" {
+				errs = append(errs, fieldError(path+".probability", "cannot be combined with group %s (grouped upstreams are selected by weight)", upstream.Group))
+			}
+			if upstream.Timeout != "" {
+				if d, err := time.ParseDuration(upstream.Timeout); err != nil || d <= 0 {
+					errs = append(errs, fieldError(path+".timeout", "invalid timeout %q", upstream.Timeout))
+				}
 			}
 		}
 	}
 
-	// Validate traffic targets
-	for _, traffic := range spec.Traffic {
-		found := false
-		for _, svc := range spec.Services {
-			if svc.Name == traffic.Target {
-				found = true
-				break
+	// Validate traffic targets and requests
+	for i, traffic := range spec.Traffic {
+		path := fmt.Sprintf("traffic[%d]", i)
+		if !declared[traffic.Target] {
+			errs = append(errs, fieldError(path+".target", "traffic %s targets unknown service %s", traffic.Name, traffic.Target))
+		}
+		if traffic.Method != "" && !knownMethods[traffic.Method] {
+			errs = append(errs, fieldError(path+".method", "traffic %s has unsupported method %q", traffic.Name, traffic.Method))
+		}
+		if traffic.Body != "" && (traffic.EffectiveMethod() == "GET" || traffic.EffectiveMethod() == "HEAD") {
+			errs = append(errs, fieldError(path+".body", "traffic %s cannot send a body with %s", traffic.Name, traffic.EffectiveMethod()))
+		}
+		switch traffic.Tool {
+		case "", "fortio":
+		case "k6":
+			if !k6Patterns[traffic.Pattern] {
+				errs = append(errs, fieldError(path+".pattern", "traffic %s uses k6, which supports steady, spiky and ramp patterns, not %s", traffic.Name, traffic.Pattern))
+			}
+			for _, svc := range spec.Services {
+				if svc.Name == traffic.Target && !svc.HasHTTP() {
+					errs = append(errs, fieldError(path+".tool", "traffic %s uses k6 but target %s has no HTTP port", traffic.Name, traffic.Target))
+				}
 			}
+		default:
+			errs = append(errs, fieldError(path+".tool", "traffic %s has unknown tool %q (expected fortio or k6)", traffic.Name, traffic.Tool))
 		}
-		if !found {
-			return fmt.Errorf("traffic %s targets unknown service: %s", traffic.Name, traffic.Target)
+		if len(traffic.PathWeights) > 0 {
+			errs = append(errs, validatePathWeights(path, traffic)...)
+		}
+		for name := range traffic.Headers {
+			if name == "" || strings.ContainsAny(name, ": \t\r\n") {
+				errs = append(errs, fieldError(path+".headers", "traffic %s has invalid header name %q", traffic.Name, name))
+			}
+		}
+	}
+
+	// Validate scenario timing
+	for i, sc := range spec.Scenarios {
+		path := fmt.Sprintf("scenarios[%d]", i)
+		if _, err := time.ParseDuration(sc.At); err != nil {
+			errs = append(errs, fieldError(path+".at", "scenario %s has invalid at %q", sc.Name, sc.At))
+		}
+		if sc.Duration != "" {
+			if _, err := time.ParseDuration(sc.Duration); err != nil {
+				errs = append(errs, fieldError(path+".duration", "scenario %s has invalid duration %q", sc.Name, sc.Duration))
+			}
 		}
 	}
 
 	// Check for circular dependencies
 	if err := checkCircularDeps(spec); err != nil {
-		return err
+		errs = append(errs, fieldError("services", "%v", err))
 	}
 
-	return nil
+	return errs
+}
+
+// validatePathWeights checks that traffic path weights name listed paths, are
+// non-negative and leave at least one path with traffic
+func validatePathWeights(path string, traffic types.TrafficConfig) []error {
+	var errs []error
+	if len(traffic.Paths) == 0 {
+		return []error{fieldError(path+".pathWeights", "traffic %s sets pathWeights without paths", traffic.Name)}
+	}
+	if traffic.PathPattern != "random" {
+		errs = append(errs, fieldError(path+".pathWeights", "traffic %s sets pathWeights, which require pathPattern random", traffic.Name))
+	}
+
+	listed := make(map[string]bool, len(traffic.Paths))
+	for _, p := range traffic.Paths {
+		listed[p] = true
+	}
+	total := 0
+	for p, w := range traffic.PathWeights {
+		if !listed[p] {
+			errs = append(errs, fieldError(path+".pathWeights", "traffic %s weights unknown path %s", traffic.Name, p))
+		}
+		if w < 0 {
+			errs = append(errs, fieldError(path+".pathWeights", "traffic %s has negative weight %d for path %s", traffic.Name, w, p))
+		}
+		total += w
+	}
+	if total <= 0 {
+		errs = append(errs, fieldError(path+".pathWeights", "traffic %s path weights must sum to more than 0", traffic.Name))
+	}
+	return errs
 }
 
 // checkCircularDeps checks for circular dependencies in upstream calls
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidateUpstreamProbability(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		wantErr  string
+	}{
+		{name: "probability", upstream: "{name: orders, probability: 0.5}"},
+		{name: "whole-number probability", upstream: "{name: orders, probability: 1}"},
+		{name: "group", upstream: "{name: orders, group: checkout}"},
+		{
+			name:     "group and probability",
+			upstream: "{name: orders, group: checkout, probability: 0.5}",
+			wantErr:  "services[0].upstreams[0].probability: cannot be combined with group checkout",
+		},
+		{
+			name:     "probability above 1",
+			upstream: "{name: orders, probability: 2}",
+			wantErr:  "services[0].upstreams[0].probability: must be between 0 and 1, got 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBytes([]byte(fmt.Sprintf(`
+app:
+  name: shop
+  namespaces: [shop]
+services:
+  - name: frontend
+    namespace: shop
+    protocols: [http]
+    upstreams:
+      - %s
+  - name: orders
+    namespace: shop
+    protocols: [http]
+`, tt.upstream)))
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+	"gopkg.in/yaml.v3"
+)
+
+// varPattern matches ${name} and ${name:-default} references, and the $${ escape
+var varPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_.-]*)(:-([^}]*))?\}`)
+
+// header holds the directives read before a document is substituted and decoded
+type header struct {
+	Vars    map[string]string `yaml:"vars"`
+	Include []string          `yaml:"include"`
+}
+
+// load reads a DSL document, substitutes variables and merges its includes.
+// vars from the including document take precedence over the document's own.
+// seen holds the absolute paths of the documents being loaded, to reject include cycles.
+func load(data []byte, dir string, parentVars map[string]string, seen map[string]bool) (*types.AppSpec, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var hdr header
+	if err := doc.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("failed to parse vars/include: %w", err)
+	}
+	vars := make(map[string]string, len(hdr.Vars)+len(parentVars))
+	for k, v := range hdr.Vars {
+		// Var values may themselves fall back to the environment, e.g. ${IMAGE:-testservice:latest}
+		value, err := substitute(v, nil)
+		if err != nil {
+			return nil, fmt.Errorf("vars.%s: %w", k, err)
+		}
+		vars[k] = value
+	}
+	for k, v := range parentVars {
+		vars[k] = v
+	}
+
+	if err := substituteDocument(&doc, vars); err != nil {
+		return nil, err
+	}
+
+	var spec types.AppSpec
+	if err := doc.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	for _, inc := range spec.Include {
+		path := inc
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc, err)
+		}
+		if seen[abs] {
+			return nil, fmt.Errorf("include cycle: %s is already being loaded", inc)
+		}
+
+		incData, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: failed to read file: %w", inc, err)
+		}
+		seen[abs] = true
+		included, err := load(incData, filepath.Dir(path), vars, seen)
+		delete(seen, abs)
+		if err != nil {
+			return nil, fmt.Errorf("include %s: %w", inc, err)
+		}
+		merge(&spec, included)
+	}
+
+	return &spec, nil
+}
+
+// merge folds an included document into spec: services, traffic and scenarios are
+// appended, and the included app section is used only if spec has none
+func merge(spec, included *types.AppSpec) {
+	if spec.App.Name == "" {
+		spec.App = included.App
+	}
+	spec.Services = append(spec.Services, included.Services...)
+	spec.Traffic = append(spec.Traffic, included.Traffic...)
+	spec.Scenarios = append(spec.Scenarios, included.Scenarios...)
+}
+
+// substituteDocument expands variable references in every scalar value of the document,
+// leaving the top-level vars block as written
+func substituteDocument(doc *yaml.Node, vars map[string]string) error {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return substituteNode(root, vars)
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "vars" {
+			continue
+		}
+		if err := substituteNode(root.Content[i+1], vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// substituteNode expands variable references in every scalar value below n
+func substituteNode(n *yaml.Node, vars map[string]string) error {
+	switch n.Kind {
+	case yaml.SequenceNode, yaml.MappingNode:
+		for _, c := range n.Content {
+			if err := substituteNode(c, vars); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		value, err := substitute(n.Value, vars)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		if value != n.Value {
+			n.Value = value
+			// Re-resolve plain scalars so "replicas: ${REPLICAS}" decodes as an int
+			if n.Style == 0 {
+				n.Tag = ""
+			}
+		}
+	}
+	return nil
+}
+
+// substitute expands ${name} from vars, then the environment, then the :-default.
+// $${ yields a literal ${. Undefined variables without a default are an error.
+func substitute(s string, vars map[string]string) (string, error) {
+	var missing []string
+	out := varPattern.ReplaceAllStringFunc(s, func(m string) string {
+		if m == "$${" {
+			return "${"
+		}
+		sub := varPattern.FindStringSubmatch(m)
+		name, hasDefault, def := sub[1], sub[2] != "", sub[3]
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+		if hasDefault {
+			return def
+		}
+		missing = append(missing, name)
+		return m
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined variable(s): %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+)
+
+// draft is the JSON Schema dialect emitted; draft-07 is what editor YAML tooling supports best
+const draft = "http://json-schema.org/draft-07/schema#"
+
+// enums lists the allowed values of fields with a fixed value set, keyed by "Type.yamlField".
+// For list fields the enum applies to the items.
+var enums = map[string][]string{
+	"ProviderConfig.ingress":    {"gateway-api", "istio-gateway", "nginx", "k8s-ingress", "openshift-routes", "none"},
+	"ProviderConfig.mesh":       {"istio", "linkerd", "gateway-api-mesh", "none"},
+	"ServiceConfig.type":        {"Deployment", "StatefulSet", "DaemonSet"},
+	"ServiceConfig.protocols":   {"http", "grpc", "tcp"},
+	"MeshConfig.loadBalancing":  {"ROUND_ROBIN", "LEAST_REQUEST", "RANDOM", "PASSTHROUGH"},
+	"MeshConfig.mtls":           {"STRICT", "PERMISSIVE", "DISABLE"},
+	"TrafficConfig.type":        {"load-generator"},
+	"TrafficConfig.pattern":     {"steady", "spiky", "diurnal", "ramp", "poisson"},
+	"TrafficConfig.pathPattern": {"round-robin", "random", "sequential"},
+	"TrafficConfig.method":      {"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+	"TrafficConfig.tool":        {"fortio", "k6"},
+	"ScenarioConfig.action":     {"inject"},
+}
+
+// Schema is a JSON Schema node. Only the keywords the DSL needs are modelled.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+// Generate returns the JSON Schema for the DSL as indented JSON.
+// Output is stable: properties and definitions are emitted in sorted order.
+func Generate() ([]byte, error) {
+	data, err := json.MarshalIndent(Build(), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// Build reflects over types.AppSpec and returns its schema, with every nested
+// struct type emitted once under definitions
+func Build() *Schema {
+	b := &builder{defs: make(map[string]*Schema)}
+	root := b.structSchema(reflect.TypeOf(types.AppSpec{}))
+	root.Schema = draft
+	root.Title = "TestApp DSL"
+	root.Definitions = b.defs
+	return root
+}
+
+type builder struct {
+	defs map[string]*Schema
+}
+
+// ref returns a $ref to the definition of struct type t, building it on first use
+func (b *builder) ref(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, ok := b.defs[name]; !ok {
+		b.defs[name] = nil // Reserve the name so recursive types terminate
+		b.defs[name] = b.structSchema(t)
+	}
+	return &Schema{Ref: "#/definitions/" + name}
+}
+
+// structSchema describes a struct's yaml fields. Fields without omitempty are required,
+// except booleans, whose zero value is a meaningful setting.
+func (b *builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		prop := b.fieldSchema(t.Name(), name, f.Type)
+		s.Properties[name] = prop
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Bool {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
+
+// fieldSchema describes a single field, applying enums and the DSL's special cases
+func (b *builder) fieldSchema(typeName, field string, t reflect.Type) *Schema {
+	// Upstreams accept plain service names as well as full route objects
+	if typeName == "ServiceConfig" && field == "upstreams" {
+		return &Schema{
+			Type: "array",
+			Items: &Schema{OneOf: []*Schema{
+				{Type: "string"},
+				b.ref(reflect.TypeOf(types.UpstreamRoute{})),
+			}},
+		}
+	}
+
+	s := b.typeSchema(t)
+	if values, ok := enums[typeName+"."+field]; ok {
+		if s.Items != nil {
+			s.Items.Enum = values
+		} else {
+			s.Enum = values
+		}
+	}
+	return s
+}
+
+// typeSchema maps a Go type to its JSON Schema
+func (b *builder) typeSchema(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return b.typeSchema(t.Elem())
+	case reflect.Struct:
+		return b.ref(t)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: b.typeSchema(t.Elem())}
+	case reflect.Map:
+		s := &Schema{Type: "object"}
+		if t.Elem().Kind() != reflect.Interface {
+			s.AdditionalProperties = b.typeSchema(t.Elem())
+		}
+		return s
+	default:
+		// interface{} and anything else: any value
+		return &Schema{}
+	}
+}
@@ -2,10 +2,12 @@ package types
 
 // AppSpec defines the complete application specification
 type AppSpec struct {
-	App       AppConfig        `yaml:"app"`
-	Services  []ServiceConfig  `yaml:"services"`
-	Traffic   []TrafficConfig  `yaml:"traffic,omitempty"`
-	Scenarios []ScenarioConfig `yaml:"scenarios,omitempty"`
+	Vars      map[string]string `yaml:"vars,omitempty"`    // Values for ${name} references elsewhere in the document
+	Include   []string          `yaml:"include,omitempty"` // Further DSL files whose services, traffic and scenarios are merged in
+	App       AppConfig         `yaml:"app"`
+	Services  []ServiceConfig   `yaml:"services"`
+	Traffic   []TrafficConfig   `yaml:"traffic,omitempty"`
+	Scenarios []ScenarioConfig  `yaml:"scenarios,omitempty"`
 }
 
 // AppConfig defines application-level configuration
@@ -14,22 +16,30 @@ type AppConfig struct {
 	Namespaces   []string       `yaml:"namespaces,omitempty"`
 	Providers    ProviderConfig `yaml:"providers,omitempty"`
 	MeshDefaults MeshConfig     `yaml:"meshDefaults,omitempty"`
+
+	NetworkPolicies bool `yaml:"networkPolicies,omitempty"` // Generate default-deny NetworkPolicies from the upstream graph
 }
 
 // ProviderConfig defines which providers to use for ingress and mesh
 type ProviderConfig struct {
-	Ingress string `yaml:"ingress,omitempty"` // gateway-api, istio-gateway, k8s-ingress, openshift-routes, none
+	Ingress string `yaml:"ingress,omitempty"` // gateway-api, istio-gateway, nginx (k8s-ingress), openshift-routes, none
 	Mesh    string `yaml:"mesh,omitempty"`    // istio, linkerd, gateway-api-mesh, none
+
+	CertManager   bool   `yaml:"certManager,omitempty"`   // Issue ingress TLS certs via cert-manager instead of baking self-signed secrets
+	ClusterIssuer string `yaml:"clusterIssuer,omitempty"` // cert-manager ClusterIssuer used when CertManager is set
 }
 
 // UpstreamRoute defines an upstream service with optional path-based routing
 type UpstreamRoute struct {
-	Name        string   `yaml:"name"`              // Unique ID for this upstream entry (used for behavior targeting)
-	Service     string   `yaml:"service,omitempty"` // Target service name (defaults to Name if not specified)
-	Match       []string `yaml:"match,omitempty"`   // Incoming paths that trigger routing to this upstream (HTTP callers only)
-	Path        string   `yaml:"path,omitempty"`    // Explicit forward path to call on upstream (HTTP upstreams only), defaults to "/"
-	Group       string   `yaml:"group,omitempty"`   // Weighted selection group - upstreams in same group are mutually exclusive
+	Name        string   `yaml:"name"`                  // Unique ID for this upstream entry (used for behavior targeting)
+	Service     string   `yaml:"service,omitempty"`     // Target service name (defaults to Name if not specified)
+	Match       []string `yaml:"match,omitempty"`       // Incoming paths that trigger routing to this upstream (HTTP callers only)
+	Path        string   `yaml:"path,omitempty"`        // Explicit forward path to call on upstream (HTTP upstreams only), defaults to "/"
+	Group       string   `yaml:"group,omitempty"`       // Weighted selection group - upstreams in same group are mutually exclusive
 	Probability float64  `yaml:"probability,omitempty"` // Independent call probability (0.0-1.0), only for ungrouped upstreams
+	Timeout     string   `yaml:"timeout,omitempty"`     // Per-call timeout (e.g. "2s"), defaults to the client timeout
+	Priority    int      `yaml:"priority,omitempty"`    // Breaks ties between equally specific matches, higher wins
+	Mirror      bool     `yaml:"mirror,omitempty"`      // Shadow traffic: called asynchronously, never affects the response
 }
 
 // EffectiveService returns the target service name (Service if set, otherwise Name)
@@ -42,26 +52,28 @@ func (u *UpstreamRoute) EffectiveService() string {
 
 // ServiceConfig defines a service
 type ServiceConfig struct {
-	Name        string            `yaml:"name"`
-	Namespace   string            `yaml:"namespace,omitempty"`
-	Replicas    int               `yaml:"replicas,omitempty"`
-	Type        string            `yaml:"type,omitempty"` // Deployment, StatefulSet, DaemonSet
-	Protocols   []string          `yaml:"protocols,omitempty"`
-	Ports       PortsConfig       `yaml:"ports,omitempty"`
-	Upstreams   []UpstreamRoute   `yaml:"upstreams,omitempty"`
-	Behavior    BehaviorConfig    `yaml:"behavior,omitempty"`
-	Storage     StorageConfig     `yaml:"storage,omitempty"`
-	Ingress     IngressConfig     `yaml:"ingress,omitempty"`
-	Mesh        MeshConfig        `yaml:"mesh,omitempty"`
-	Resources   ResourceConfig    `yaml:"resources,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty"`
+	Name        string             `yaml:"name"`
+	Namespace   string             `yaml:"namespace,omitempty"`
+	Replicas    int                `yaml:"replicas,omitempty"`
+	Type        string             `yaml:"type,omitempty"` // Deployment, StatefulSet, DaemonSet
+	Protocols   []string           `yaml:"protocols,omitempty"`
+	Ports       PortsConfig        `yaml:"ports,omitempty"`
+	Upstreams   []UpstreamRoute    `yaml:"upstreams,omitempty"`
+	Behavior    BehaviorConfig     `yaml:"behavior,omitempty"`
+	Storage     StorageConfig      `yaml:"storage,omitempty"`
+	Ingress     IngressConfig      `yaml:"ingress,omitempty"`
+	Mesh        MeshConfig         `yaml:"mesh,omitempty"`
+	Resources   ResourceConfig     `yaml:"resources,omitempty"`
+	Autoscaling *AutoscalingConfig `yaml:"autoscaling,omitempty"`
+	Labels      map[string]string  `yaml:"labels,omitempty"`
+	Annotations map[string]string  `yaml:"annotations,omitempty"`
 }
 
 // PortsConfig defines service ports
 type PortsConfig struct {
 	HTTP    int `yaml:"http,omitempty"`
 	GRPC    int `yaml:"grpc,omitempty"`
+	TCP     int `yaml:"tcp,omitempty"` // Plain TCP port, exposed through a Gateway TCP listener
 	Metrics int `yaml:"metrics,omitempty"`
 }
 
@@ -121,6 +133,14 @@ type TrafficSplitConfig struct {
 	Subset  string `yaml:"subset,omitempty"`
 }
 
+// AutoscalingConfig defines a HorizontalPodAutoscaler for the service
+type AutoscalingConfig struct {
+	MinReplicas          int  `yaml:"minReplicas,omitempty"`          // Defaults to replicas
+	MaxReplicas          int  `yaml:"maxReplicas"`                    // Required
+	TargetCPUUtilization int  `yaml:"targetCPUUtilization,omitempty"` // Percent of CPU requests, defaults to 80
+	AllowStateful        bool `yaml:"allowStateful,omitempty"`        // Generate the HPA for a StatefulSet too
+}
+
 // ResourceConfig defines resource requests and limits
 type ResourceConfig struct {
 	Requests ResourceValues `yaml:"requests,omitempty"`
@@ -135,15 +155,32 @@ type ResourceValues struct {
 
 // TrafficConfig defines traffic generation
 type TrafficConfig struct {
-	Name        string   `yaml:"name"`
-	Type        string   `yaml:"type,omitempty"` // load-generator
-	Target      string   `yaml:"target"`
-	Rate        string   `yaml:"rate,omitempty"`
-	Pattern     string   `yaml:"pattern,omitempty"` // steady, spiky, diurnal
-	Duration    string   `yaml:"duration,omitempty"`
-	Paths       []string `yaml:"paths,omitempty"`       // List of paths to call
-	PathPattern string   `yaml:"pathPattern,omitempty"` // round-robin, random, sequential
-	Behavior    string   `yaml:"behavior,omitempty"`    // Behavior query param to inject
+	Name        string            `yaml:"name"`
+	Type        string            `yaml:"type,omitempty"` // load-generator
+	Target      string            `yaml:"target"`
+	Rate        string            `yaml:"rate,omitempty"`
+	Pattern     string            `yaml:"pattern,omitempty"` // steady, spiky, diurnal, ramp, poisson
+	Duration    string            `yaml:"duration,omitempty"`
+	Paths       []string          `yaml:"paths,omitempty"`       // List of paths to call
+	PathPattern string            `yaml:"pathPattern,omitempty"` // round-robin, random, sequential
+	PathWeights map[string]int    `yaml:"pathWeights,omitempty"` // Relative weight per path for random selection
+	Behavior    string            `yaml:"behavior,omitempty"`    // Behavior query param to inject
+	Method      string            `yaml:"method,omitempty"`      // HTTP method (default GET, or POST with a body)
+	Body        string            `yaml:"body,omitempty"`        // Request body sent with each request
+	Headers     map[string]string `yaml:"headers,omitempty"`     // Request headers (e.g. Authorization, X-Behavior)
+	Tool        string            `yaml:"tool,omitempty"`        // Load tool for HTTP targets: fortio (default) or k6
+}
+
+// EffectiveMethod returns the HTTP method used by the traffic generator:
+// the configured method, POST when only a body is set, or GET
+func (t *TrafficConfig) EffectiveMethod() string {
+	if t.Method != "" {
+		return t.Method
+	}
+	if t.Body != "" {
+		return "POST"
+	}
+	return "GET"
 }
 
 // ScenarioConfig defines time-based scenarios
@@ -173,6 +210,9 @@ func (s *ServiceConfig) Defaults() {
 	if s.Ports.GRPC == 0 && contains(s.Protocols, "grpc") {
 		s.Ports.GRPC = 8080
 	}
+	if s.Ports.TCP == 0 && contains(s.Protocols, "tcp") {
+		s.Ports.TCP = 9000
+	}
 	if s.Ports.Metrics == 0 {
 		s.Ports.Metrics = 9091
 	}
@@ -185,6 +225,14 @@ func (s *ServiceConfig) Defaults() {
 	if s.Annotations == nil {
 		s.Annotations = make(map[string]string)
 	}
+	if s.Autoscaling != nil {
+		if s.Autoscaling.MinReplicas == 0 {
+			s.Autoscaling.MinReplicas = s.Replicas
+		}
+		if s.Autoscaling.TargetCPUUtilization == 0 {
+			s.Autoscaling.TargetCPUUtilization = 80
+		}
+	}
 }
 
 // HasHTTP returns true if the service supports HTTP
@@ -197,11 +245,32 @@ func (s *ServiceConfig) HasGRPC() bool {
 	return contains(s.Protocols, "grpc")
 }
 
+// HasTCP returns true if the service exposes a plain TCP port
+func (s *ServiceConfig) HasTCP() bool {
+	return contains(s.Protocols, "tcp")
+}
+
 // NeedsIngress returns true if the service needs ingress
 func (s *ServiceConfig) NeedsIngress() bool {
 	return s.Ingress.Enabled
 }
 
+// NeedsHPA returns true if a HorizontalPodAutoscaler should be generated.
+// StatefulSets are only autoscaled when explicitly allowed; DaemonSets never are.
+func (s *ServiceConfig) NeedsHPA() bool {
+	if s.Autoscaling == nil {
+		return false
+	}
+	switch s.Type {
+	case "Deployment":
+		return true
+	case "StatefulSet":
+		return s.Autoscaling.AllowStateful
+	default:
+		return false
+	}
+}
+
 // IsStateful returns true if this is a StatefulSet
 func (s *ServiceConfig) IsStateful() bool {
 	return s.Type == "StatefulSet"
@@ -270,20 +339,21 @@ func contains(slice []string, item string) bool {
 func (s *ServiceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	// Define an aux struct with all fields explicit
 	aux := &struct {
-		Name        string            `yaml:"name"`
-		Namespace   string            `yaml:"namespace,omitempty"`
-		Replicas    int               `yaml:"replicas,omitempty"`
-		Type        string            `yaml:"type,omitempty"`
-		Protocols   []string          `yaml:"protocols,omitempty"`
-		Ports       PortsConfig       `yaml:"ports,omitempty"`
-		Upstreams   interface{}       `yaml:"upstreams,omitempty"`
-		Behavior    BehaviorConfig    `yaml:"behavior,omitempty"`
-		Storage     StorageConfig     `yaml:"storage,omitempty"`
-		Ingress     IngressConfig     `yaml:"ingress,omitempty"`
-		Mesh        MeshConfig        `yaml:"mesh,omitempty"`
-		Resources   ResourceConfig    `yaml:"resources,omitempty"`
-		Labels      map[string]string `yaml:"labels,omitempty"`
-		Annotations map[string]string `yaml:"annotations,omitempty"`
+		Name        string             `yaml:"name"`
+		Namespace   string             `yaml:"namespace,omitempty"`
+		Replicas    int                `yaml:"replicas,omitempty"`
+		Type        string             `yaml:"type,omitempty"`
+		Protocols   []string           `yaml:"protocols,omitempty"`
+		Ports       PortsConfig        `yaml:"ports,omitempty"`
+		Upstreams   interface{}        `yaml:"upstreams,omitempty"`
+		Behavior    BehaviorConfig     `yaml:"behavior,omitempty"`
+		Storage     StorageConfig      `yaml:"storage,omitempty"`
+		Ingress     IngressConfig      `yaml:"ingress,omitempty"`
+		Mesh        MeshConfig         `yaml:"mesh,omitempty"`
+		Resources   ResourceConfig     `yaml:"resources,omitempty"`
+		Autoscaling *AutoscalingConfig `yaml:"autoscaling,omitempty"`
+		Labels      map[string]string  `yaml:"labels,omitempty"`
+		Annotations map[string]string  `yaml:"annotations,omitempty"`
 	}{}
 
 	if err := unmarshal(aux); err != nil {
@@ -302,6 +372,7 @@ func (s *ServiceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	s.Ingress = aux.Ingress
 	s.Mesh = aux.Mesh
 	s.Resources = aux.Resources
+	s.Autoscaling = aux.Autoscaling
 	s.Labels = aux.Labels
 	s.Annotations = aux.Annotations
 
@@ -350,8 +421,21 @@ func (s *ServiceConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
 							if group, ok := m["group"].(string); ok {
 								route.Group = group
 							}
-							if prob, ok := m["probability"].(float64); ok {
+							// YAML decodes whole numbers such as "probability: 1" as int
+							switch prob := m["probability"].(type) {
+							case float64:
 								route.Probability = prob
+							case int:
+								route.Probability = float64(prob)
+							}
+							if timeout, ok := m["timeout"].(string); ok {
+								route.Timeout = timeout
+							}
+							if priority, ok := m["priority"].(int); ok {
+								route.Priority = priority
+							}
+							if mirror, ok := m["mirror"].(bool); ok {
+								route.Mirror = mirror
 							}
 							s.Upstreams = append(s.Upstreams, route)
 						}
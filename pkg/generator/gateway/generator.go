@@ -2,19 +2,14 @@ package gateway
 
 import (
 	"bytes"
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
-	"crypto/x509/pkix"
 	"embed"
 	"encoding/base64"
-	"encoding/pem"
 	"fmt"
-	"math/big"
+	"sort"
 	"text/template"
-	"time"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/tlscert"
 )
 
 //go:embed templates/*.tmpl
@@ -24,13 +19,20 @@ var templatesFS embed.FS
 type Generator struct {
 	spec      *types.AppSpec
 	templates *template.Template
+	tlsSeed   string // Derives the self-signed certificate from a seed (empty = random)
 }
 
 // Template data structures
 type gatewayData struct {
-	Name       string
-	NeedsHTTP  bool
-	NeedsHTTPS bool
+	Name         string
+	NeedsHTTP    bool
+	NeedsHTTPS   bool
+	TCPListeners []tcpListener
+}
+
+type tcpListener struct {
+	Name string
+	Port int
 }
 
 type httpRouteData struct {
@@ -57,11 +59,26 @@ type grpcRouteData struct {
 	BackendPort int
 }
 
+type tcpRouteData struct {
+	Name         string
+	Namespace    string
+	GatewayName  string
+	ListenerName string
+	BackendName  string
+	BackendPort  int
+}
+
 type tlsSecretData struct {
 	CertBase64 string
 	KeyBase64  string
 }
 
+type certificateData struct {
+	Issuer     string
+	CommonName string
+	DNSNames   []string
+}
+
 type referenceGrantsData struct {
 	Grants []referenceGrant
 }
@@ -82,6 +99,13 @@ func NewGenerator(spec *types.AppSpec) *Generator {
 	}
 }
 
+// WithTLSSeed makes the self-signed TLS certificate derive from seed, so repeated
+// generation produces the same Secret
+func (g *Generator) WithTLSSeed(seed string) *Generator {
+	g.tlsSeed = seed
+	return g
+}
+
 // GenerateAll generates all Gateway API manifests
 func (g *Generator) GenerateAll() (map[string]string, error) {
 	manifests := make(map[string]string)
@@ -112,15 +136,28 @@ func (g *Generator) GenerateAll() (map[string]string, error) {
 	}
 
 	if needsTLS {
-		certs, err := g.GenerateTLSSecrets(ingressServices)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate TLS secrets: %w", err)
+		// cert-manager issues and rotates the secret the Gateway listener references;
+		// otherwise bake a self-signed one for clusters without cert-manager
+		if g.spec.App.Providers.CertManager {
+			manifests["20-gateway/certificates.yaml"] = g.GenerateCertificate(ingressServices)
+		} else {
+			certs, err := g.GenerateTLSSecrets(ingressServices)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate TLS secrets: %w", err)
+			}
+			manifests["20-gateway/certificates.yaml"] = certs
 		}
-		manifests["20-gateway/certificates.yaml"] = certs
 	}
 
-	// Generate HTTPRoute or GRPCRoute for each service
+	// Generate HTTPRoute, GRPCRoute and/or TCPRoute for each service
 	for _, svc := range ingressServices {
+		if svc.HasTCP() {
+			route := g.GenerateTCPRoute(&svc)
+			manifests[fmt.Sprintf("20-gateway/%s-tcproute.yaml", svc.Name)] = route
+			if !svc.HasHTTP() && !svc.HasGRPC() {
+				continue
+			}
+		}
 		if svc.HasGRPC() && !svc.HasHTTP() {
 			route := g.GenerateGRPCRoute(&svc)
 			manifests[fmt.Sprintf("20-gateway/%s-grpcroute.yaml", svc.Name)] = route
@@ -144,20 +181,33 @@ func (g *Generator) GenerateGateway() string {
 	// Determine if we need HTTP and/or HTTPS listeners
 	needsHTTP := false
 	needsHTTPS := false
+	var tcpListeners []tcpListener
 
 	for _, svc := range g.spec.Services {
-		if svc.NeedsIngress() {
-			needsHTTP = true
-			if svc.Ingress.TLS {
-				needsHTTPS = true
+		if !svc.NeedsIngress() {
+			continue
+		}
+		// TCP services get a dedicated listener on their own port
+		if svc.HasTCP() {
+			tcpListeners = append(tcpListeners, tcpListener{
+				Name: tcpListenerName(&svc),
+				Port: svc.Ports.TCP,
+			})
+			if !svc.HasHTTP() && !svc.HasGRPC() {
+				continue
 			}
 		}
+		needsHTTP = true
+		if svc.Ingress.TLS {
+			needsHTTPS = true
+		}
 	}
 
 	data := gatewayData{
-		Name:       g.spec.App.Name,
-		NeedsHTTP:  needsHTTP,
-		NeedsHTTPS: needsHTTPS,
+		Name:         g.spec.App.Name,
+		NeedsHTTP:    needsHTTP,
+		NeedsHTTPS:   needsHTTPS,
+		TCPListeners: tcpListeners,
 	}
 
 	var buf bytes.Buffer
@@ -217,9 +267,31 @@ func (g *Generator) GenerateGRPCRoute(svc *types.ServiceConfig) string {
 	return buf.String()
 }
 
-// GenerateTLSSecrets generates self-signed TLS certificates
-func (g *Generator) GenerateTLSSecrets(services []types.ServiceConfig) (string, error) {
-	// Collect all unique hosts
+// GenerateTCPRoute generates a TCPRoute manifest bound to the service's Gateway TCP listener
+func (g *Generator) GenerateTCPRoute(svc *types.ServiceConfig) string {
+	data := tcpRouteData{
+		Name:         svc.Name,
+		Namespace:    svc.Namespace,
+		GatewayName:  g.spec.App.Name,
+		ListenerName: tcpListenerName(svc),
+		BackendName:  svc.Name,
+		BackendPort:  svc.Ports.TCP,
+	}
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "tcproute.yaml.tmpl", data); err != nil {
+		panic(fmt.Sprintf("failed to execute tcproute template: %v", err))
+	}
+	return buf.String()
+}
+
+// tcpListenerName returns the Gateway listener name for a TCP service
+func tcpListenerName(svc *types.ServiceConfig) string {
+	return fmt.Sprintf("tcp-%s", svc.Name)
+}
+
+// GenerateCertificate generates a cert-manager Certificate for the Gateway's TLS secret
+func (g *Generator) GenerateCertificate(services []types.ServiceConfig) string {
 	hosts := make(map[string]bool)
 	for _, svc := range services {
 		if svc.Ingress.TLS && svc.Ingress.Host != "" {
@@ -231,46 +303,50 @@ func (g *Generator) GenerateTLSSecrets(services []types.ServiceConfig) (string,
 		hosts["*.local"] = true
 	}
 
-	// Generate self-signed certificate
-	priv, err := rsa.GenerateKey(rand.Reader, 2048)
-	if err != nil {
-		return "", err
+	// Sort hosts for deterministic output
+	var dnsNames []string
+	for host := range hosts {
+		dnsNames = append(dnsNames, host)
 	}
+	sort.Strings(dnsNames)
 
-	notBefore := time.Now()
-	notAfter := notBefore.Add(365 * 24 * time.Hour)
+	data := certificateData{
+		Issuer:     g.spec.App.Providers.ClusterIssuer,
+		CommonName: dnsNames[0],
+		DNSNames:   dnsNames,
+	}
 
-	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
-	if err != nil {
-		return "", err
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "certificate.yaml.tmpl", data); err != nil {
+		panic(fmt.Sprintf("failed to execute certificate template: %v", err))
+	}
+	return buf.String()
+}
+
+// GenerateTLSSecrets generates self-signed TLS certificates
+func (g *Generator) GenerateTLSSecrets(services []types.ServiceConfig) (string, error) {
+	// Collect all unique hosts
+	hosts := make(map[string]bool)
+	for _, svc := range services {
+		if svc.Ingress.TLS && svc.Ingress.Host != "" {
+			hosts[svc.Ingress.Host] = true
+		}
 	}
 
-	template := x509.Certificate{
-		SerialNumber: serialNumber,
-		Subject: pkix.Name{
-			Organization: []string{"TestApp"},
-			CommonName:   g.spec.App.Name,
-		},
-		NotBefore:             notBefore,
-		NotAfter:              notAfter,
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-		BasicConstraintsValid: true,
+	if len(hosts) == 0 {
+		hosts["*.local"] = true
 	}
 
+	// Generate self-signed certificate
+	var dnsNames []string
 	for host := range hosts {
-		template.DNSNames = append(template.DNSNames, host)
+		dnsNames = append(dnsNames, host)
 	}
-
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	certPEM, keyPEM, err := tlscert.SelfSigned(g.spec.App.Name, dnsNames, g.tlsSeed)
 	if err != nil {
 		return "", err
 	}
 
-	// Encode to PEM
-	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
-	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
-
 	// Base64 encode for Secret
 	certBase64 := base64.StdEncoding.EncodeToString(certPEM)
 	keyBase64 := base64.StdEncoding.EncodeToString(keyPEM)
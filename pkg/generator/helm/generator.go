@@ -0,0 +1,200 @@
+package helm
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Generator packages generated manifests as a Helm chart
+type Generator struct {
+	spec      *types.AppSpec
+	image     string
+	templates *template.Template
+}
+
+// Template data structures
+type chartData struct {
+	Name       string
+	AppVersion string
+}
+
+type valuesData struct {
+	Image      string
+	Namespaces []string
+	Services   []serviceValues
+}
+
+type serviceValues struct {
+	Name     string
+	Replicas int
+}
+
+var (
+	replicasLine  = regexp.MustCompile(`(?m)^  replicas: \d+$`)
+	workloadKind  = regexp.MustCompile(`(?m)^kind: (Deployment|StatefulSet)$`)
+	metadataName  = regexp.MustCompile(`(?m)^  name: (\S+)$`)
+	imageLine     = regexp.MustCompile(`(?m)^(\s+image: )(\S+)$`)
+	namespaceLine = regexp.MustCompile(`(?m)^(\s*(?:- )?(?:namespace|kubernetes\.io/metadata\.name): )(\S+)$`)
+	namespaceName = regexp.MustCompile(`(?m)^(kind: Namespace\nmetadata:\n  name: )(\S+)$`)
+	clusterDNS    = regexp.MustCompile(`\.([a-z0-9-]+)\.svc\b`)
+)
+
+// NewGenerator creates a new Helm chart generator
+func NewGenerator(spec *types.AppSpec, image string) *Generator {
+	tmpl := template.Must(template.New("helm").ParseFS(templatesFS, "templates/*.tmpl"))
+
+	return &Generator{
+		spec:      spec,
+		image:     image,
+		templates: tmpl,
+	}
+}
+
+// GenerateChart wraps raw manifests (as produced by the other generators) in a Helm chart.
+// The image, per-service replicas and namespaces become values; everything else is kept as is.
+func (g *Generator) GenerateChart(manifests map[string]string) (map[string]string, error) {
+	chart := make(map[string]string)
+
+	chartYAML, err := g.render("chart.yaml.tmpl", chartData{
+		Name:       g.spec.App.Name,
+		AppVersion: imageTag(g.image),
+	})
+	if err != nil {
+		return nil, err
+	}
+	chart["Chart.yaml"] = chartYAML
+
+	namespaces := g.namespaces()
+	values, err := g.render("values.yaml.tmpl", valuesData{
+		Image:      g.image,
+		Namespaces: namespaces,
+		Services:   g.serviceValues(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	chart["values.yaml"] = values
+
+	known := make(map[string]bool)
+	for _, ns := range namespaces {
+		known[ns] = true
+	}
+	for filename, content := range manifests {
+		chart["templates/"+filename] = templatize(content, g.image, known)
+	}
+
+	return chart, nil
+}
+
+// templatize turns a raw manifest into a Helm template referencing values
+func templatize(content, image string, namespaces map[string]bool) string {
+	// Anything that already looks like a template action (e.g. in a behavior string) is literal
+	content = strings.ReplaceAll(content, "{{", `{{ "{{" }}`)
+
+	docs := strings.Split(content, "\n---\n")
+	for i, doc := range docs {
+		if workloadKind.MatchString(doc) {
+			if m := metadataName.FindStringSubmatch(doc); m != nil {
+				doc = replicasLine.ReplaceAllString(doc,
+					fmt.Sprintf(`  replicas: {{ (index .Values.services %q).replicas }}`, m[1]))
+			}
+		}
+		docs[i] = doc
+	}
+	content = strings.Join(docs, "\n---\n")
+
+	// Only the TestService image is a value; other images (e.g. load generators) are kept
+	content = imageLine.ReplaceAllStringFunc(content, func(line string) string {
+		m := imageLine.FindStringSubmatch(line)
+		if m[2] != image {
+			return line
+		}
+		return m[1] + "{{ .Values.image }}"
+	})
+
+	nsValue := func(ns string) string {
+		return fmt.Sprintf(`{{ index .Values.namespaces %q }}`, ns)
+	}
+	rewriteNamespace := func(re *regexp.Regexp) {
+		content = re.ReplaceAllStringFunc(content, func(line string) string {
+			m := re.FindStringSubmatch(line)
+			if !namespaces[m[2]] {
+				return line
+			}
+			return m[1] + nsValue(m[2])
+		})
+	}
+	rewriteNamespace(namespaceLine)
+	rewriteNamespace(namespaceName)
+
+	// Cluster DNS names in upstream URLs must follow the namespace they point into
+	content = clusterDNS.ReplaceAllStringFunc(content, func(match string) string {
+		ns := clusterDNS.FindStringSubmatch(match)[1]
+		if !namespaces[ns] {
+			return match
+		}
+		return "." + nsValue(ns) + ".svc"
+	})
+
+	return content
+}
+
+// namespaces returns every namespace used by the app, sorted for deterministic values
+func (g *Generator) namespaces() []string {
+	set := make(map[string]bool)
+	for _, ns := range g.spec.App.Namespaces {
+		set[ns] = true
+	}
+	for _, svc := range g.spec.Services {
+		set[svc.Namespace] = true
+	}
+
+	var namespaces []string
+	for ns := range set {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// serviceValues returns the per-service values in DSL order
+func (g *Generator) serviceValues() []serviceValues {
+	var services []serviceValues
+	for _, svc := range g.spec.Services {
+		// The HPA owns the replica count of autoscaled workloads
+		if svc.NeedsHPA() {
+			continue
+		}
+		services = append(services, serviceValues{
+			Name:     svc.Name,
+			Replicas: svc.Replicas,
+		})
+	}
+	return services
+}
+
+func (g *Generator) render(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// imageTag returns the tag of an image reference, or "latest" if it has none
+func imageTag(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
@@ -0,0 +1,193 @@
+package ingress
+
+import (
+	"bytes"
+	"embed"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/tlscert"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+const (
+	// ingressClassName is the IngressClass served by ingress-nginx
+	ingressClassName = "nginx"
+
+	// tlsSecretName is the self-signed certificate Secret created in each namespace with TLS ingress
+	tlsSecretName = "ingress-tls-cert"
+)
+
+// Generator generates Kubernetes Ingress manifests for the nginx ingress controller
+type Generator struct {
+	spec      *types.AppSpec
+	templates *template.Template
+	tlsSeed   string // Derives the self-signed certificate from a seed (empty = random)
+}
+
+// Template data structures
+type ingressData struct {
+	Name        string
+	Namespace   string
+	ClassName   string
+	Host        string
+	TLSSecret   string
+	GRPC        bool
+	Paths       []string
+	BackendName string
+	BackendPort int
+}
+
+type tlsSecretData struct {
+	Name       string
+	Namespaces []string
+	CertBase64 string
+	KeyBase64  string
+}
+
+// NewGenerator creates a new Ingress manifest generator
+func NewGenerator(spec *types.AppSpec) *Generator {
+	tmpl := template.Must(template.New("ingress").ParseFS(templatesFS, "templates/*.tmpl"))
+
+	return &Generator{
+		spec:      spec,
+		templates: tmpl,
+	}
+}
+
+// WithTLSSeed makes the self-signed TLS certificate derive from seed, so repeated
+// generation produces the same Secret
+func (g *Generator) WithTLSSeed(seed string) *Generator {
+	g.tlsSeed = seed
+	return g
+}
+
+// GenerateAll generates all Ingress manifests
+func (g *Generator) GenerateAll() (map[string]string, error) {
+	manifests := make(map[string]string)
+
+	// Find services that need ingress
+	ingressServices := []types.ServiceConfig{}
+	for _, svc := range g.spec.Services {
+		if svc.NeedsIngress() {
+			ingressServices = append(ingressServices, svc)
+		}
+	}
+
+	if len(ingressServices) == 0 {
+		return manifests, nil
+	}
+
+	// Generate TLS certificates if needed
+	needsTLS := false
+	for _, svc := range ingressServices {
+		if svc.Ingress.TLS {
+			needsTLS = true
+			break
+		}
+	}
+
+	if needsTLS {
+		certs, err := g.GenerateTLSSecrets(ingressServices)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate TLS secrets: %w", err)
+		}
+		manifests["20-ingress/certificates.yaml"] = certs
+	}
+
+	// Generate an Ingress for each service
+	for _, svc := range ingressServices {
+		ing, err := g.GenerateIngress(&svc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate Ingress for %s: %w", svc.Name, err)
+		}
+		manifests[fmt.Sprintf("20-ingress/%s-ingress.yaml", svc.Name)] = ing
+	}
+
+	return manifests, nil
+}
+
+// GenerateIngress generates an Ingress manifest
+// gRPC-only services are routed to the gRPC port with the GRPC backend protocol
+func (g *Generator) GenerateIngress(svc *types.ServiceConfig) (string, error) {
+	paths := svc.Ingress.Paths
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	data := ingressData{
+		Name:        svc.Name,
+		Namespace:   svc.Namespace,
+		ClassName:   ingressClassName,
+		Host:        svc.Ingress.Host,
+		Paths:       paths,
+		BackendName: svc.Name,
+		BackendPort: svc.Ports.HTTP,
+	}
+	if svc.HasGRPC() && !svc.HasHTTP() {
+		data.GRPC = true
+		data.BackendPort = svc.Ports.GRPC
+	}
+	if svc.Ingress.TLS {
+		data.TLSSecret = tlsSecretName
+	}
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "ingress.yaml.tmpl", data); err != nil {
+		return "", fmt.Errorf("failed to execute ingress template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// GenerateTLSSecrets generates a self-signed TLS certificate covering all TLS hosts.
+// Ingress can only reference Secrets in its own namespace, so a copy is emitted
+// for every namespace containing a TLS-enabled service.
+func (g *Generator) GenerateTLSSecrets(services []types.ServiceConfig) (string, error) {
+	// Collect all unique hosts and namespaces
+	hosts := make(map[string]bool)
+	namespaces := make(map[string]bool)
+	for _, svc := range services {
+		if !svc.Ingress.TLS {
+			continue
+		}
+		namespaces[svc.Namespace] = true
+		if svc.Ingress.Host != "" {
+			hosts[svc.Ingress.Host] = true
+		}
+	}
+
+	if len(hosts) == 0 {
+		hosts["*.local"] = true
+	}
+
+	// Generate self-signed certificate
+	var dnsNames []string
+	for host := range hosts {
+		dnsNames = append(dnsNames, host)
+	}
+	certPEM, keyPEM, err := tlscert.SelfSigned(g.spec.App.Name, dnsNames, g.tlsSeed)
+	if err != nil {
+		return "", err
+	}
+
+	data := tlsSecretData{
+		Name:       tlsSecretName,
+		CertBase64: base64.StdEncoding.EncodeToString(certPEM),
+		KeyBase64:  base64.StdEncoding.EncodeToString(keyPEM),
+	}
+	for ns := range namespaces {
+		data.Namespaces = append(data.Namespaces, ns)
+	}
+	sort.Strings(data.Namespaces)
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "secret-tls.yaml.tmpl", data); err != nil {
+		return "", fmt.Errorf("failed to execute tls secret template: %w", err)
+	}
+	return buf.String(), nil
+}
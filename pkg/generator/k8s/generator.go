@@ -5,10 +5,19 @@ import (
 	"embed"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
 	"text/template"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenarios are mounted from a per-service ConfigMap and read via SCENARIOS_FILE
+const (
+	scenariosMountPath = "/etc/testservice/scenarios"
+	scenariosFileName  = "scenarios.yaml"
 )
 
 //go:embed templates/*.tmpl
@@ -29,16 +38,20 @@ type namespaceData struct {
 }
 
 type workloadData struct {
-	Name      string
-	Namespace string
-	Labels    map[string]string
-	Replicas  int
-	Image     string
-	Ports     []portData
-	EnvVars   []envVarData
-	Resources resourcesData
-	Probes    *probesData
-	Storage   *storageData
+	Name       string
+	Namespace  string
+	Labels     map[string]string
+	Replicas   int
+	Autoscaled bool // spec.replicas is left to the HPA, so re-applying doesn't reset it
+	Image      string
+	Ports      []portData
+	EnvVars    []envVarData
+	Resources  resourcesData
+	Probes     *probesData
+	Storage    *storageData
+
+	ScenariosConfigMap string // ConfigMap holding scenarios (empty = none mounted)
+	ScenariosMountPath string
 }
 
 type portData struct {
@@ -99,6 +112,24 @@ type serviceMonitorData struct {
 	Labels    map[string]string
 }
 
+type hpaData struct {
+	Name                 string
+	Namespace            string
+	Labels               map[string]string
+	Kind                 string
+	MinReplicas          int
+	MaxReplicas          int
+	TargetCPUUtilization int
+}
+
+type scenarioConfigMapData struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+	FileName  string
+	Content   string
+}
+
 // NewGenerator creates a new Kubernetes manifest generator
 func NewGenerator(spec *types.AppSpec, image string) *Generator {
 	if image == "" {
@@ -151,6 +182,21 @@ func (g *Generator) GenerateAll() (map[string]string, error) {
 		// ServiceMonitor
 		monitor := g.GenerateServiceMonitor(&svc)
 		manifests[fmt.Sprintf("%s-servicemonitor.yaml", prefix)] = monitor
+
+		// HorizontalPodAutoscaler (only when autoscaling is configured)
+		if svc.NeedsHPA() {
+			hpa := g.GenerateHPA(&svc)
+			manifests[fmt.Sprintf("%s-hpa.yaml", prefix)] = hpa
+		}
+
+		// Scenarios ConfigMap (mounted by the workload)
+		if len(g.spec.Scenarios) > 0 {
+			scenarios, err := g.GenerateScenarioConfigMap(&svc)
+			if err != nil {
+				return nil, err
+			}
+			manifests[fmt.Sprintf("%s-scenarios.yaml", prefix)] = scenarios
+		}
 	}
 
 	return manifests, nil
@@ -250,20 +296,73 @@ func (g *Generator) GenerateServiceMonitor(svc *types.ServiceConfig) string {
 	return buf.String()
 }
 
-// Helper methods
+// GenerateHPA generates an autoscaling/v2 HorizontalPodAutoscaler scaling the workload on CPU
+func (g *Generator) GenerateHPA(svc *types.ServiceConfig) string {
+	data := hpaData{
+		Name:                 svc.Name,
+		Namespace:            svc.Namespace,
+		Labels:               g.getLabels(svc),
+		Kind:                 svc.Type,
+		MinReplicas:          svc.Autoscaling.MinReplicas,
+		MaxReplicas:          svc.Autoscaling.MaxReplicas,
+		TargetCPUUtilization: svc.Autoscaling.TargetCPUUtilization,
+	}
 
-func (g *Generator) buildWorkloadData(svc *types.ServiceConfig) workloadData {
-	return workloadData{
-		Name:      svc.Name,
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "hpa.yaml.tmpl", data); err != nil {
+		panic(fmt.Sprintf("failed to execute hpa template: %v", err))
+	}
+	return buf.String()
+}
+
+// GenerateScenarioConfigMap generates the ConfigMap holding the app's scenarios for a service.
+// Every service gets all scenarios; service-prefixed behaviors only apply to their target.
+func (g *Generator) GenerateScenarioConfigMap(svc *types.ServiceConfig) (string, error) {
+	content, err := yaml.Marshal(g.spec.Scenarios)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal scenarios for %s: %w", svc.Name, err)
+	}
+
+	data := scenarioConfigMapData{
+		Name:      scenariosConfigMapName(svc),
 		Namespace: svc.Namespace,
 		Labels:    g.getLabels(svc),
-		Replicas:  svc.Replicas,
-		Image:     g.image,
-		Ports:     g.getPorts(svc),
-		EnvVars:   g.getEnvVars(svc),
-		Resources: g.getResources(svc),
-		Probes:    g.getProbes(svc),
+		FileName:  scenariosFileName,
+		Content:   strings.TrimRight(string(content), "\n"),
 	}
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "scenarios-configmap.yaml.tmpl", data); err != nil {
+		panic(fmt.Sprintf("failed to execute scenarios configmap template: %v", err))
+	}
+	return buf.String(), nil
+}
+
+// scenariosConfigMapName is the ConfigMap name shared by the ConfigMap and the workload volume
+func scenariosConfigMapName(svc *types.ServiceConfig) string {
+	return svc.Name + "-scenarios"
+}
+
+// Helper methods
+
+func (g *Generator) buildWorkloadData(svc *types.ServiceConfig) workloadData {
+	data := workloadData{
+		Name:       svc.Name,
+		Namespace:  svc.Namespace,
+		Labels:     g.getLabels(svc),
+		Replicas:   svc.Replicas,
+		Autoscaled: svc.NeedsHPA(),
+		Image:      g.image,
+		Ports:      g.getPorts(svc),
+		EnvVars:    g.getEnvVars(svc),
+		Resources:  g.getResources(svc),
+		Probes:     g.getProbes(svc),
+	}
+	if len(g.spec.Scenarios) > 0 {
+		data.ScenariosConfigMap = scenariosConfigMapName(svc)
+		data.ScenariosMountPath = scenariosMountPath
+	}
+	return data
 }
 
 func (g *Generator) getLabels(svc *types.ServiceConfig) map[string]string {
@@ -330,10 +429,16 @@ func (g *Generator) getEnvVars(svc *types.ServiceConfig) []envVarData {
 		env["GRPC_PORT"] = fmt.Sprintf("%d", svc.Ports.GRPC)
 	}
 
-	for k, v := range env {
+	// Sorted so regenerating the manifests doesn't reorder them
+	names := make([]string, 0, len(env))
+	for k := range env {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
 		envVars = append(envVars, envVarData{
 			Name:  k,
-			Value: v,
+			Value: env[k],
 		})
 	}
 
@@ -346,6 +451,14 @@ func (g *Generator) getEnvVars(svc *types.ServiceConfig) []envVarData {
 		})
 	}
 
+	// Point the service at its mounted scenarios
+	if len(g.spec.Scenarios) > 0 {
+		envVars = append(envVars, envVarData{
+			Name:  "SCENARIOS_FILE",
+			Value: scenariosMountPath + "/" + scenariosFileName,
+		})
+	}
+
 	// Add behavior
 	if svc.Behavior.Latency != "" || svc.Behavior.ErrorRate > 0 || len(svc.Behavior.UpstreamWeights) > 0 {
 		behavior := g.buildBehaviorString(svc)
@@ -403,7 +516,7 @@ func (g *Generator) buildUpstreamsEnv(svc *types.ServiceConfig) string {
 				url := fmt.Sprintf("%s://%s.%s.svc.cluster.local:%d",
 					protocol, target.Name, target.Namespace, port)
 
-				// Build upstream string: id=url[:match=/a,/b][:path=/forward][:group=name][:prob=0.5]
+				// Build upstream string: id=url[:match=/a,/b][:path=/forward][:group=name][:prob=0.5][:timeout=2s][:priority=10][:mirror=true]
 				// The id is the unique upstream.Name, used for behavior targeting
 				upstreamStr := fmt.Sprintf("%s=%s", upstream.Name, url)
 				if len(upstream.Match) > 0 {
@@ -416,7 +529,16 @@ func (g *Generator) buildUpstreamsEnv(svc *types.ServiceConfig) string {
 					upstreamStr += ":group=" + upstream.Group
 				}
 				if upstream.Probability > 0 {
-					upstreamStr += fmt.Sprintf(":prob=%.2f", upstream.Probability)
+					upstreamStr += ":prob=" + strconv.FormatFloat(upstream.Probability, 'f', -1, 64)
+				}
+				if upstream.Timeout != "" {
+					upstreamStr += ":timeout=" + upstream.Timeout
+				}
+				if upstream.Priority != 0 {
+					upstreamStr += ":priority=" + strconv.Itoa(upstream.Priority)
+				}
+				if upstream.Mirror {
+					upstreamStr += ":mirror=true"
 				}
 
 				parts = append(parts, upstreamStr)
@@ -444,6 +566,7 @@ func (g *Generator) buildBehaviorString(svc *types.ServiceConfig) string {
 		for id, weight := range svc.Behavior.UpstreamWeights {
 			weightParts = append(weightParts, fmt.Sprintf("%s:%d", id, weight))
 		}
+		sort.Strings(weightParts)
 		parts = append(parts, fmt.Sprintf("upstreamWeights=%s", strings.Join(weightParts, ";")))
 	}
 	return strings.Join(parts, ",")
@@ -519,6 +642,17 @@ func (g *Generator) getServicePorts(svc *types.ServiceConfig) []servicePortData
 		}
 	}
 
+	// testservice has no raw TCP listener: the tcp port forwards to its HTTP listener,
+	// which a TCPRoute carries as opaque bytes
+	if svc.HasTCP() {
+		ports = append(ports, servicePortData{
+			Name:       "tcp",
+			Port:       svc.Ports.TCP,
+			TargetPort: fmt.Sprintf("%d", svc.Ports.HTTP),
+			Protocol:   "TCP",
+		})
+	}
+
 	ports = append(ports, servicePortData{
 		Name:       "metrics",
 		Port:       svc.Ports.Metrics,
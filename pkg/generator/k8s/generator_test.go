@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/parser"
+)
+
+func TestBuildUpstreamsEnv(t *testing.T) {
+	const url = "http://orders.shop.svc.cluster.local:8080"
+
+	tests := []struct {
+		name     string
+		upstream string
+		want     string
+	}{
+		{
+			name:     "group only",
+			upstream: "{name: orders, group: checkout}",
+			want:     "orders=" + url + ":group=checkout",
+		},
+		{
+			name:     "probability only",
+			upstream: "{name: orders, probability: 0.25}",
+			want:     "orders=" + url + ":prob=0.25",
+		},
+		{
+			name:     "whole-number probability",
+			upstream: "{name: orders, probability: 1}",
+			want:     "orders=" + url + ":prob=1",
+		},
+		{
+			name:     "group and timeout",
+			upstream: "{name: orders, group: checkout, timeout: 2s}",
+			want:     "orders=" + url + ":group=checkout:timeout=2s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parser.ParseBytes([]byte(fmt.Sprintf(`
+app:
+  name: shop
+  namespaces: [shop]
+services:
+  - name: frontend
+    namespace: shop
+    protocols: [http]
+    upstreams:
+      - %s
+  - name: orders
+    namespace: shop
+    protocols: [http]
+`, tt.upstream)))
+			if err != nil {
+				t.Fatalf("ParseBytes() failed: %v", err)
+			}
+
+			g := NewGenerator(spec, "")
+			if got := g.buildUpstreamsEnv(&spec.Services[0]); got != tt.want {
+				t.Errorf("buildUpstreamsEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateWorkload_Replicas(t *testing.T) {
+	tests := []struct {
+		name         string
+		service      string
+		wantReplicas bool
+	}{
+		{
+			name:         "deployment",
+			service:      "{name: api, namespace: shop, replicas: 2}",
+			wantReplicas: true,
+		},
+		{
+			name:         "autoscaled deployment",
+			service:      "{name: api, namespace: shop, replicas: 2, autoscaling: {maxReplicas: 6}}",
+			wantReplicas: false,
+		},
+		{
+			name:         "statefulset without allowStateful",
+			service:      "{name: api, namespace: shop, type: StatefulSet, storage: {size: 1Gi}, replicas: 2, autoscaling: {maxReplicas: 6}}",
+			wantReplicas: true,
+		},
+		{
+			name:         "autoscaled statefulset",
+			service:      "{name: api, namespace: shop, type: StatefulSet, storage: {size: 1Gi}, replicas: 2, autoscaling: {maxReplicas: 6, allowStateful: true}}",
+			wantReplicas: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := parser.ParseBytes([]byte(fmt.Sprintf(`
+app:
+  name: shop
+  namespaces: [shop]
+services:
+  - %s
+`, tt.service)))
+			if err != nil {
+				t.Fatalf("ParseBytes() failed: %v", err)
+			}
+
+			workload := NewGenerator(spec, "").GenerateWorkload(&spec.Services[0])
+			if got := strings.Contains(workload, "\n  replicas: 2\n"); got != tt.wantReplicas {
+				t.Errorf("Expected replicas in the manifest: %v, got:\n%s", tt.wantReplicas, workload)
+			}
+		})
+	}
+}
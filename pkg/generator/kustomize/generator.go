@@ -0,0 +1,67 @@
+package kustomize
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// baseDir is where the generated manifests live; overlays reference it as ../../base
+const baseDir = "base"
+
+// Generator lays out generated manifests as a kustomize base
+type Generator struct {
+	spec      *types.AppSpec
+	templates *template.Template
+}
+
+// Template data structures
+type kustomizationData struct {
+	AppName   string
+	Resources []string
+}
+
+// NewGenerator creates a new kustomize layout generator
+func NewGenerator(spec *types.AppSpec) *Generator {
+	tmpl := template.Must(template.New("kustomize").ParseFS(templatesFS, "templates/*.tmpl"))
+
+	return &Generator{
+		spec:      spec,
+		templates: tmpl,
+	}
+}
+
+// GenerateBase moves raw manifests (as produced by the other generators) under base/
+// and adds a kustomization.yaml listing them. Manifest paths are kept as is, so
+// overlays can patch them by the same names raw output uses.
+func (g *Generator) GenerateBase(manifests map[string]string) (map[string]string, error) {
+	layout := make(map[string]string, len(manifests)+1)
+
+	// Sorted so the numbered prefixes keep apply order (namespaces first)
+	resources := make([]string, 0, len(manifests))
+	for filename, content := range manifests {
+		resources = append(resources, filename)
+		layout[baseDir+"/"+filename] = content
+	}
+	sort.Strings(resources)
+
+	data := kustomizationData{
+		AppName:   g.spec.App.Name,
+		Resources: resources,
+	}
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "kustomization.yaml.tmpl", data); err != nil {
+		return nil, fmt.Errorf("failed to execute kustomization template: %w", err)
+	}
+	layout[baseDir+"/kustomization.yaml"] = buf.String()
+
+	return layout, nil
+}
@@ -0,0 +1,191 @@
+package netpol
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+const (
+	// monitoringNamespace is where Prometheus scrapes metrics from
+	monitoringNamespace = "monitoring"
+
+	// Trace export target, matching OTEL_EXPORTER_OTLP_ENDPOINT set by the k8s generator
+	tracingNamespace = "observability"
+	tracingPort      = 4317
+)
+
+// Generator generates NetworkPolicies from the service dependency graph
+type Generator struct {
+	spec      *types.AppSpec
+	templates *template.Template
+}
+
+// Template data structures
+type policyData struct {
+	Name                string
+	Namespace           string
+	AppName             string
+	Ports               []int // Serving ports opened to callers
+	MetricsPort         int
+	Public              bool // Ingress-exposed, reachable from any namespace
+	Mesh                bool // Sidecar needs the Istio control plane
+	Callers             []peer
+	TrafficSources      []string // Traffic generator names targeting this service
+	Upstreams           []peer
+	MonitoringNamespace string
+	TracingNamespace    string
+	TracingPort         int
+}
+
+type peer struct {
+	Name           string
+	Namespace      string
+	CrossNamespace bool  // Peer lives in another namespace and needs a namespaceSelector
+	Ports          []int // Upstream serving ports (egress only)
+}
+
+// NewGenerator creates a new NetworkPolicy generator
+func NewGenerator(spec *types.AppSpec) *Generator {
+	tmpl := template.Must(template.New("netpol").ParseFS(templatesFS, "templates/*.tmpl"))
+
+	return &Generator{
+		spec:      spec,
+		templates: tmpl,
+	}
+}
+
+// GenerateAll generates a default-deny NetworkPolicy per service that only allows
+// the traffic implied by the upstream graph
+func (g *Generator) GenerateAll() (map[string]string, error) {
+	manifests := make(map[string]string)
+
+	for _, svc := range g.spec.Services {
+		policy, err := g.GeneratePolicy(&svc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate NetworkPolicy for %s: %w", svc.Name, err)
+		}
+		manifests[fmt.Sprintf("15-netpol/%s-networkpolicy.yaml", svc.Name)] = policy
+	}
+
+	return manifests, nil
+}
+
+// GeneratePolicy generates the NetworkPolicy for a service: ingress only from services
+// that list it as an upstream (plus traffic generators, ingress and metrics scraping),
+// egress only to its own upstreams (plus DNS, tracing and the mesh control plane)
+func (g *Generator) GeneratePolicy(svc *types.ServiceConfig) (string, error) {
+	data := policyData{
+		Name:                svc.Name,
+		Namespace:           svc.Namespace,
+		AppName:             g.spec.App.Name,
+		Ports:               servingPorts(svc),
+		MetricsPort:         svc.Ports.Metrics,
+		Public:              svc.NeedsIngress(),
+		Mesh:                svc.MeshEnabled(g.spec.App.Providers.Mesh),
+		Callers:             g.callers(svc),
+		Upstreams:           g.upstreams(svc),
+		MonitoringNamespace: monitoringNamespace,
+		TracingNamespace:    tracingNamespace,
+		TracingPort:         tracingPort,
+	}
+
+	for _, traffic := range g.spec.Traffic {
+		if traffic.Target == svc.Name {
+			data.TrafficSources = append(data.TrafficSources, traffic.Name)
+		}
+	}
+	sort.Strings(data.TrafficSources)
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "networkpolicy.yaml.tmpl", data); err != nil {
+		return "", fmt.Errorf("failed to execute networkpolicy template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// callers returns the services that list svc as an upstream, deduplicated and sorted
+func (g *Generator) callers(svc *types.ServiceConfig) []peer {
+	seen := make(map[string]bool)
+	var callers []peer
+	for _, caller := range g.spec.Services {
+		for _, upstream := range caller.Upstreams {
+			if upstream.EffectiveService() != svc.Name {
+				continue
+			}
+			key := caller.Namespace + "/" + caller.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			callers = append(callers, peer{
+				Name:           caller.Name,
+				Namespace:      caller.Namespace,
+				CrossNamespace: caller.Namespace != svc.Namespace,
+			})
+		}
+	}
+	sortPeers(callers)
+	return callers
+}
+
+// upstreams returns the services svc calls with their serving ports, deduplicated and sorted.
+// Several upstream entries (e.g. path routes) may target the same service.
+func (g *Generator) upstreams(svc *types.ServiceConfig) []peer {
+	seen := make(map[string]bool)
+	var upstreams []peer
+	for _, upstream := range svc.Upstreams {
+		target := g.findService(upstream.EffectiveService())
+		if target == nil {
+			continue
+		}
+		key := target.Namespace + "/" + target.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		upstreams = append(upstreams, peer{
+			Name:           target.Name,
+			Namespace:      target.Namespace,
+			CrossNamespace: target.Namespace != svc.Namespace,
+			Ports:          servingPorts(target),
+		})
+	}
+	sortPeers(upstreams)
+	return upstreams
+}
+
+// findService finds a service by name
+func (g *Generator) findService(name string) *types.ServiceConfig {
+	for i := range g.spec.Services {
+		if g.spec.Services[i].Name == name {
+			return &g.spec.Services[i]
+		}
+	}
+	return nil
+}
+
+// servingPorts returns the HTTP port plus the gRPC port when served separately
+func servingPorts(svc *types.ServiceConfig) []int {
+	ports := []int{svc.Ports.HTTP}
+	if svc.HasGRPC() && svc.Ports.GRPC != svc.Ports.HTTP {
+		ports = append(ports, svc.Ports.GRPC)
+	}
+	return ports
+}
+
+func sortPeers(peers []peer) {
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Namespace != peers[j].Namespace {
+			return peers[i].Namespace < peers[j].Namespace
+		}
+		return peers[i].Name < peers[j].Name
+	})
+}
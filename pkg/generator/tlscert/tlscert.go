@@ -0,0 +1,162 @@
+package tlscert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+)
+
+const keyBits = 2048
+
+// seededNotBefore starts the validity window of seeded certificates, pinned so that
+// repeated generation yields identical certificates
+var seededNotBefore = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// SelfSigned returns a PEM-encoded self-signed certificate for dnsNames and its RSA key.
+// Without a seed the key and serial number are random and the certificate is valid for
+// a year from now. With a seed they derive from the seed alone and the certificate is
+// valid from 2025 for 20 years, so repeated generation produces the same Secret.
+func SelfSigned(commonName string, dnsNames []string, seed string) (certPEM, keyPEM []byte, err error) {
+	var (
+		r         io.Reader = rand.Reader
+		priv      *rsa.PrivateKey
+		notBefore = time.Now()
+		notAfter  = notBefore.Add(365 * 24 * time.Hour)
+	)
+	if seed != "" {
+		r = newSeededReader(seed)
+		priv, err = seededKey(r, keyBits)
+		notBefore = seededNotBefore
+		notAfter = seededNotBefore.AddDate(20, 0, 0)
+	} else {
+		priv, err = rsa.GenerateKey(r, keyBits)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serialNumber, err := rand.Int(r, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := append([]string(nil), dnsNames...)
+	sort.Strings(names)
+
+	template := x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject: pkix.Name{
+			Organization: []string{"TestApp"},
+			CommonName:   commonName,
+		},
+		DNSNames:              names,
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	// PKCS #1 v1.5 signatures are deterministic, so a seeded key signs identically
+	derBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, &priv.PublicKey, priv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: derBytes})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+	return certPEM, keyPEM, nil
+}
+
+// seededReader is an endless stream of SHA-256(seed || counter) blocks
+type seededReader struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+func newSeededReader(seed string) *seededReader {
+	return &seededReader{seed: []byte(seed)}
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(r.buf) == 0 {
+			block := make([]byte, 0, len(r.seed)+8)
+			block = append(block, r.seed...)
+			block = binary.BigEndian.AppendUint64(block, r.counter)
+			r.counter++
+			sum := sha256.Sum256(block)
+			r.buf = sum[:]
+		}
+		copied := copy(p[n:], r.buf)
+		r.buf = r.buf[copied:]
+		n += copied
+	}
+	return n, nil
+}
+
+// seededKey derives an RSA key from r. rsa.GenerateKey deliberately doesn't produce the
+// same key for the same random stream, so the primes are drawn here instead.
+func seededKey(r io.Reader, bits int) (*rsa.PrivateKey, error) {
+	e := big.NewInt(65537)
+	one := big.NewInt(1)
+	for {
+		p, err := seededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := seededPrime(r, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		phi := new(big.Int).Mul(new(big.Int).Sub(p, one), new(big.Int).Sub(q, one))
+		d := new(big.Int).ModInverse(e, phi)
+		if d == nil {
+			continue
+		}
+
+		priv := &rsa.PrivateKey{
+			PublicKey: rsa.PublicKey{N: new(big.Int).Mul(p, q), E: int(e.Int64())},
+			D:         d,
+			Primes:    []*big.Int{p, q},
+		}
+		if err := priv.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid seeded key: %w", err)
+		}
+		priv.Precompute()
+		return priv, nil
+	}
+}
+
+// seededPrime draws odd candidates of exactly bits bits (a multiple of 8) from r until
+// one is prime. The top two bits are set so the product of two has 2*bits bits.
+func seededPrime(r io.Reader, bits int) (*big.Int, error) {
+	b := make([]byte, bits/8)
+	for {
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		b[0] |= 0xC0
+		b[len(b)-1] |= 1
+
+		p := new(big.Int).SetBytes(b)
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
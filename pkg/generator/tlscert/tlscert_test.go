@@ -0,0 +1,103 @@
+package tlscert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+)
+
+// parse decodes a certificate and key returned by SelfSigned, failing the test if either is invalid
+func parse(t *testing.T, certPEM, keyPEM []byte) *x509.Certificate {
+	t.Helper()
+
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil || certBlock.Type != "CERTIFICATE" {
+		t.Fatalf("Expected a CERTIFICATE PEM block, got %q", certPEM)
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse certificate: %v", err)
+	}
+
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil || keyBlock.Type != "RSA PRIVATE KEY" {
+		t.Fatalf("Expected an RSA PRIVATE KEY PEM block, got %q", keyPEM)
+	}
+	key, err := x509.ParsePKCS1PrivateKey(keyBlock.Bytes)
+	if err != nil {
+		t.Fatalf("Failed to parse key: %v", err)
+	}
+	if err := key.Validate(); err != nil {
+		t.Fatalf("Invalid key: %v", err)
+	}
+	if key.N.BitLen() != keyBits {
+		t.Errorf("Expected a %d-bit key, got %d bits", keyBits, key.N.BitLen())
+	}
+	if !key.PublicKey.Equal(cert.PublicKey) {
+		t.Errorf("Key doesn't match the certificate")
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		t.Errorf("Certificate isn't signed by its own key: %v", err)
+	}
+	return cert
+}
+
+func TestSelfSigned_Seeded(t *testing.T) {
+	cert1, key1, err := SelfSigned("shop", []string{"shop.local", "api.shop.local"}, "gitops")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+	cert2, key2, err := SelfSigned("shop", []string{"api.shop.local", "shop.local"}, "gitops")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+
+	if !bytes.Equal(cert1, cert2) || !bytes.Equal(key1, key2) {
+		t.Errorf("Expected the same seed to produce the same certificate and key, regardless of name order")
+	}
+
+	cert := parse(t, cert1, key1)
+	wantNotBefore := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if !cert.NotBefore.Equal(wantNotBefore) || !cert.NotAfter.Equal(wantNotBefore.AddDate(20, 0, 0)) {
+		t.Errorf("Expected validity 2025-01-01 to 2045-01-01, got %s to %s", cert.NotBefore, cert.NotAfter)
+	}
+	if len(cert.DNSNames) != 2 || cert.DNSNames[0] != "api.shop.local" || cert.DNSNames[1] != "shop.local" {
+		t.Errorf("Expected sorted DNS names, got %v", cert.DNSNames)
+	}
+	if cert.Subject.CommonName != "shop" {
+		t.Errorf("Expected common name shop, got %s", cert.Subject.CommonName)
+	}
+
+	_, other, err := SelfSigned("shop", []string{"shop.local", "api.shop.local"}, "other")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+	if bytes.Equal(key1, other) {
+		t.Errorf("Expected a different seed to produce a different key")
+	}
+}
+
+func TestSelfSigned_Unseeded(t *testing.T) {
+	cert1, key1, err := SelfSigned("shop", []string{"shop.local"}, "")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+	_, key2, err := SelfSigned("shop", []string{"shop.local"}, "")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+
+	if bytes.Equal(key1, key2) {
+		t.Errorf("Expected unseeded keys to differ between calls")
+	}
+
+	cert := parse(t, cert1, key1)
+	if time.Since(cert.NotBefore) > time.Minute {
+		t.Errorf("Expected the certificate to be valid from now, got %s", cert.NotBefore)
+	}
+	if validity := cert.NotAfter.Sub(cert.NotBefore); validity != 365*24*time.Hour {
+		t.Errorf("Expected one year of validity, got %s", validity)
+	}
+}
@@ -3,8 +3,10 @@ package traffic
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -27,6 +29,7 @@ type Generator struct {
 type trafficJobData struct {
 	Name            string
 	Namespace       string
+	Protocol        string // http or grpc
 	TargetURL       string
 	Pattern         string
 	Rate            string
@@ -37,6 +40,11 @@ type trafficJobData struct {
 	Paths           []string
 	PathPattern     string
 	Behavior        string
+	Method          string            // HTTP method sent by the load tool (empty for gRPC)
+	Body            string            // Request body, mounted into the Job as /scripts/payload
+	Headers         map[string]string // Request headers sent by the load tool
+	Tool            string            // Load tool: fortio, k6 or ghz
+	K6Script        string            // k6 JS script, mounted into the Job as /scripts/script.js
 }
 
 // NewGenerator creates a new traffic generator
@@ -105,9 +113,12 @@ func (g *Generator) generateTrafficJob(traffic *types.TrafficConfig) (string, er
 		}
 	}
 
-	// Construct target URL
-	targetURL := fmt.Sprintf("%s://%s.%s.svc.cluster.local:%d",
-		protocol, targetService.Name, targetService.Namespace, port)
+	// Construct target URL; gRPC load is driven against host:port
+	targetURL := fmt.Sprintf("%s.%s.svc.cluster.local:%d",
+		targetService.Name, targetService.Namespace, port)
+	if protocol == "http" {
+		targetURL = "http://" + targetURL
+	}
 
 	// Parse rate (e.g., "100/s" -> 100)
 	rateNumeric := parseRate(traffic.Rate)
@@ -128,11 +139,29 @@ func (g *Generator) generateTrafficJob(traffic *types.TrafficConfig) (string, er
 	g.currentTraffic = traffic
 
 	// Generate wrapper script based on pattern
-	wrapperScript := g.generateWrapperScript(traffic, rateNumeric, durationSeconds, targetURL)
+	var wrapperScript, k6Script, method string
+	tool := "fortio"
+	switch {
+	case protocol == "grpc":
+		tool = "ghz"
+		wrapperScript = g.generateGRPCScript(traffic, rateNumeric, durationSeconds, targetURL)
+	case traffic.Tool == "k6":
+		tool = "k6"
+		var err error
+		wrapperScript, k6Script, err = g.generateK6(traffic, rateNumeric, durationSeconds, targetURL)
+		if err != nil {
+			return "", err
+		}
+		method = traffic.EffectiveMethod()
+	default:
+		wrapperScript = g.generateWrapperScript(traffic, rateNumeric, durationSeconds, targetURL)
+		method = traffic.EffectiveMethod()
+	}
 
 	data := trafficJobData{
 		Name:            traffic.Name,
 		Namespace:       namespace,
+		Protocol:        protocol,
 		TargetURL:       targetURL,
 		Pattern:         traffic.Pattern,
 		Rate:            traffic.Rate,
@@ -143,6 +172,11 @@ func (g *Generator) generateTrafficJob(traffic *types.TrafficConfig) (string, er
 		Paths:           traffic.Paths,
 		PathPattern:     pathPattern,
 		Behavior:        traffic.Behavior,
+		Method:          method,
+		Body:            traffic.Body,
+		Headers:         traffic.Headers,
+		Tool:            tool,
+		K6Script:        k6Script,
 	}
 
 	var buf bytes.Buffer
@@ -166,16 +200,63 @@ func (g *Generator) generateWrapperScript(traffic *types.TrafficConfig, rate, du
 		url = fmt.Sprintf("%s?behavior=%s", targetURL, traffic.Behavior)
 	}
 
+	var script string
 	switch pattern {
 	case "steady":
-		return g.generateSteadyScript(rate, duration, url)
+		script = g.generateSteadyScript(rate, duration, url)
 	case "spiky":
-		return g.generateSpikyScript(rate, duration, url)
+		script = g.generateSpikyScript(rate, duration, url)
 	case "diurnal":
-		return g.generateDiurnalScript(rate, duration, url)
+		script = g.generateDiurnalScript(rate, duration, url)
+	case "ramp":
+		script = g.generateRampScript(rate, duration, url)
+	case "poisson":
+		script = g.generatePoissonScript(rate, duration, url)
 	default:
-		return g.generateSteadyScript(rate, duration, url)
+		script = g.generateSteadyScript(rate, duration, url)
+	}
+
+	// Every fortio invocation sends the configured method and body
+	if flags := requestFlags(traffic); flags != "" {
+		script = strings.ReplaceAll(script, "fortio load ", "fortio load "+flags+" ")
+	}
+	return script
+}
+
+// payloadPath is where the traffic Job mounts the request body
+const payloadPath = "/scripts/payload"
+
+// requestFlags returns the fortio flags for a non-GET method, headers and request body
+func requestFlags(traffic *types.TrafficConfig) string {
+	var flags []string
+	if method := traffic.EffectiveMethod(); method != "GET" {
+		flags = append(flags, "-X", method)
+	}
+	for _, name := range sortedKeys(traffic.Headers) {
+		flags = append(flags, "-H", shellQuote(name+": "+traffic.Headers[name]))
+	}
+	if traffic.Body != "" {
+		flags = append(flags, "-payload-file", payloadPath)
+		if json.Valid([]byte(traffic.Body)) {
+			flags = append(flags, "-content-type", "application/json")
+		}
 	}
+	return strings.Join(flags, " ")
+}
+
+// sortedKeys returns the keys of m in order, for stable scripts
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// shellQuote quotes s for use as a single-quoted shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 // generateSteadyScript generates a steady traffic pattern
@@ -315,6 +396,222 @@ echo "$(date): Diurnal traffic complete"
 `, targetURL, rate, duration, rate, duration, sampleInterval, targetURL)
 }
 
+// poissonWindow is the length in seconds of each Poisson arrival window
+const poissonWindow = 5
+
+// poissonCountFunc is a shell function sampling the number of arrivals in one
+// window of a Poisson process by summing exponentially distributed gaps
+const poissonCountFunc = `# poisson_count MEAN: arrivals in one window of a Poisson process with MEAN arrivals per window
+poisson_count() {
+    awk -v mean="$1" -v seed="$(od -An -N2 -tu2 /dev/urandom)" 'BEGIN {
+        srand(seed); n = 0
+        if (mean > 0) {
+            t = -log(1 - rand()) / mean
+            while (t < 1) { n++; t += -log(1 - rand()) / mean }
+        }
+        print n
+    }'
+}
+`
+
+// generatePoissonScript generates a Poisson arrival pattern: each window fires a
+// Poisson-distributed number of requests with jittered, desynchronized spacing,
+// so the average QPS matches rate while gaps between requests vary
+func (g *Generator) generatePoissonScript(rate, duration int, targetURL string) string {
+	if g.currentTraffic != nil && len(g.currentTraffic.Paths) > 0 {
+		return g.generateMultiPathScript(rate, duration, targetURL, g.currentTraffic.Paths, g.currentTraffic.PathPattern, "poisson")
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+
+echo "Starting poisson traffic generation"
+echo "Target: %s"
+echo "Mean rate: %d qps"
+echo "Duration: %ds (0 = continuous)"
+
+%s
+WINDOW=%d
+MEAN=$((%d * WINDOW))
+DURATION=%d
+END_TIME=$(($(date +%%s) + DURATION))
+
+while [ $DURATION -eq 0 ] || [ $(date +%%s) -lt $END_TIME ]; do
+    COUNT=$(poisson_count $MEAN)
+    if [ $COUNT -eq 0 ]; then
+        sleep $WINDOW
+        continue
+    fi
+    QPS=$(awk -v c=$COUNT -v w=$WINDOW 'BEGIN { printf "%%.2f", c / w }')
+    echo "$(date): $COUNT requests over ${WINDOW}s (${QPS} qps)"
+    fortio load -qps $QPS -n $COUNT -c 8 -uniform -jitter %s || true
+done
+
+echo "$(date): Poisson traffic complete"
+`, targetURL, rate, duration, poissonCountFunc, poissonWindow, rate, duration, targetURL)
+}
+
+// rampSteps is the number of equal QPS increments a ramp climbs through
+const rampSteps = 10
+
+// rampContinuousStep is the step length of a ramp without a duration, which
+// then holds the target rate indefinitely
+const rampContinuousStep = 60
+
+// rampRates returns the QPS of each ramp step, climbing linearly to rate
+func rampRates(rate int) []int {
+	rates := make([]int, rampSteps)
+	for i := range rates {
+		rates[i] = rate * (i + 1) / rampSteps
+		if rates[i] < 1 {
+			rates[i] = 1
+		}
+	}
+	return rates
+}
+
+// generateRampScript generates a ramp pattern that climbs linearly from a low
+// QPS to the target rate over the duration, to find a service's breaking point
+func (g *Generator) generateRampScript(rate, duration int, targetURL string) string {
+	interval := duration / rampSteps
+	if duration == 0 {
+		interval = rampContinuousStep
+	}
+	if interval < 1 {
+		interval = 1
+	}
+
+	// Split off the behavior query param so it can follow each path
+	baseURL, behaviorParam := targetURL, ""
+	if i := strings.Index(targetURL, "?"); i >= 0 {
+		baseURL, behaviorParam = targetURL[:i], targetURL[i:]
+	}
+
+	var paths []string
+	var weights map[string]int
+	pathPattern := ""
+	if g.currentTraffic != nil {
+		paths = g.currentTraffic.Paths
+		pathPattern = g.currentTraffic.PathPattern
+		weights = g.currentTraffic.PathWeights
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `#!/bin/sh
+set -e
+
+echo "Starting ramp traffic generation"
+echo "Target: %s"
+echo "Ramp: up to %d qps in %d steps of %ds"
+`, targetURL, rate, rampSteps, interval)
+
+	if len(paths) > 0 {
+		fmt.Fprintf(&sb, "echo \"Paths: %d (%s)\"\n", len(paths), pathPatternOrDefault(pathPattern))
+		if pathPattern == "random" {
+			sb.WriteString(`
+PATHS="` + strings.Join(paths, " ") + `"
+PATH_COUNT=$(echo $PATHS | wc -w)
+`)
+		}
+	}
+
+	for i, qps := range rampRates(rate) {
+		fmt.Fprintf(&sb, "\necho \"$(date): Step %d/%d - %d qps for %ds\"\n", i+1, rampSteps, qps, interval)
+		sb.WriteString(rampStepCommand(qps, fmt.Sprintf("%ds", interval), i, baseURL, behaviorParam, paths, pathPattern, weights))
+	}
+
+	if duration == 0 {
+		fmt.Fprintf(&sb, "\necho \"$(date): Ramp complete, holding at %d qps\"\n", rate)
+		sb.WriteString(rampStepCommand(rate, "0", rampSteps, baseURL, behaviorParam, paths, pathPattern, weights))
+	}
+
+	sb.WriteString("\necho \"$(date): Ramp traffic complete\"\n")
+	return sb.String()
+}
+
+// rampStepCommand returns the fortio invocation(s) for one ramp step, distributing
+// the step's QPS across paths according to the path pattern
+func rampStepCommand(qps int, length string, step int, baseURL, behaviorParam string, paths []string, pathPattern string, weights map[string]int) string {
+	if len(paths) == 0 {
+		return fmt.Sprintf("fortio load -qps %d -t %s -c 8 %s%s || true\n", qps, length, baseURL, behaviorParam)
+	}
+
+	switch pathPattern {
+	case "random":
+		selection := `SELECTED_PATH=$(echo $PATHS | cut -d' ' -f$(($(od -An -N2 -i /dev/urandom) % PATH_COUNT + 1)))`
+		if len(weights) > 0 {
+			table, total := cumulativeWeights(paths, weights)
+			selection = fmt.Sprintf(`SELECTED_PATH=$(printf '%%s\n' %s | awk -v r="$(($(od -An -N2 -tu2 /dev/urandom) %% %d))" 'r < $2 {print $1; exit}')`, table, total)
+		}
+		return fmt.Sprintf(`%s
+echo "  Calling $SELECTED_PATH"
+fortio load -qps %d -t %s -c 4 "%s${SELECTED_PATH}%s" || true
+`, selection, qps, length, baseURL, behaviorParam)
+	case "sequential":
+		path := paths[step%len(paths)]
+		return fmt.Sprintf(`echo "  Calling %s"
+fortio load -qps %d -t %s -c 4 "%s%s%s" || true
+`, path, qps, length, baseURL, path, behaviorParam)
+	default: // round-robin
+		perPath := qps / len(paths)
+		if perPath < 1 {
+			perPath = 1
+		}
+		var sb strings.Builder
+		for _, path := range paths {
+			fmt.Fprintf(&sb, "fortio load -qps %d -t %s -c 2 \"%s%s%s\" &\n", perPath, length, baseURL, path, behaviorParam)
+		}
+		sb.WriteString("wait\n")
+		return sb.String()
+	}
+}
+
+// randomPathSelection returns the loop body that sets SELECTED_PATH for the random
+// path pattern. With pathWeights, a roll against the cumulative weights picks the
+// path; otherwise every path in PATHS is equally likely.
+func (g *Generator) randomPathSelection(paths []string) string {
+	var weights map[string]int
+	if g.currentTraffic != nil {
+		weights = g.currentTraffic.PathWeights
+	}
+	if len(weights) == 0 {
+		return `    # Pick random path
+    PATH_COUNT=$(echo "$PATHS" | wc -l)
+    RANDOM_INDEX=$(($(od -An -N2 -i /dev/urandom) % PATH_COUNT + 1))
+    SELECTED_PATH=$(echo "$PATHS" | sed -n "${RANDOM_INDEX}p" | tr -d ' "')
+`
+	}
+
+	table, total := cumulativeWeights(paths, weights)
+	return fmt.Sprintf(`    # Pick path by weight: the first path whose cumulative weight exceeds the roll
+    ROLL=$(($(od -An -N2 -tu2 /dev/urandom) %% %d))
+    SELECTED_PATH=$(printf '%%s\n' %s | awk -v r="$ROLL" 'r < $2 {print $1; exit}')
+`, total, table)
+}
+
+// cumulativeWeights returns shell-quoted "path cumulative-weight" words for the
+// weighted paths and the total weight. Paths without a weight get no traffic.
+func cumulativeWeights(paths []string, weights map[string]int) (string, int) {
+	var entries []string
+	total := 0
+	for _, path := range paths {
+		if weights[path] <= 0 {
+			continue
+		}
+		total += weights[path]
+		entries = append(entries, shellQuote(fmt.Sprintf("%s %d", path, total)))
+	}
+	return strings.Join(entries, " "), total
+}
+
+// pathPatternOrDefault returns the path pattern, defaulting to round-robin
+func pathPatternOrDefault(pathPattern string) string {
+	if pathPattern == "" {
+		return "round-robin"
+	}
+	return pathPattern
+}
+
 // generateMultiPathScript generates a script that distributes traffic across multiple paths
 func (g *Generator) generateMultiPathScript(rate, duration int, baseURL string, paths []string, pathPattern, trafficPattern string) string {
 	// Extract behavior query param if present
@@ -353,11 +650,7 @@ PATHS="
 END_TIME=$(($(date +%%s) + %d))
 
 while [ $(date +%%s) -lt $END_TIME ]; do
-    # Pick random path
-    PATH_COUNT=$(echo "$PATHS" | wc -l)
-    RANDOM_INDEX=$(($(od -An -N2 -i /dev/urandom) %% PATH_COUNT + 1))
-    SELECTED_PATH=$(echo "$PATHS" | sed -n "${RANDOM_INDEX}p" | tr -d ' "')
-    
+%s    
     FULL_URL="%s${SELECTED_PATH}%s"
     
     REMAINING=$((END_TIME - $(date +%%s)))
@@ -375,7 +668,7 @@ while [ $(date +%%s) -lt $END_TIME ]; do
 done
 
 echo "$(date): Multi-path traffic complete"
-`, trafficPattern, baseURL, len(paths), rate, durationStr, pathsList, duration, baseURL, behaviorParam, rate, rate)
+`, trafficPattern, baseURL, len(paths), rate, durationStr, pathsList, duration, g.randomPathSelection(paths), baseURL, behaviorParam, rate, rate)
 
 	case "sequential":
 		return fmt.Sprintf(`#!/bin/sh
@@ -448,21 +741,134 @@ fi
 
 echo "Rate per path: ${RATE_PER_PATH} qps"
 
-# Build fortio command with all paths
-FORTIO_CMD="fortio load -qps $RATE_PER_PATH -t %s -c 2"
+echo "Starting parallel load generation..."
 for path in $PATH_ARRAY; do
     FULL_URL="%s${path}%s"
     echo "  Adding path: $path"
-    FORTIO_CMD="$FORTIO_CMD $FULL_URL &"
+    fortio load -qps $RATE_PER_PATH -t %s -c 2 "$FULL_URL" &
 done
-
-echo "Starting parallel load generation..."
-eval $FORTIO_CMD
 wait
 
 echo "$(date): Multi-path traffic complete"
-`, trafficPattern, baseURL, len(paths), rate, durationStr, pathsList, rate, durationStr, baseURL, behaviorParam)
+`, trafficPattern, baseURL, len(paths), rate, durationStr, pathsList, rate, baseURL, behaviorParam, durationStr)
+	}
+}
+
+// generateGRPCScript generates a ghz-based script for gRPC-only targets.
+// ghz calls TestService/Call via server reflection with the behavior as the request's
+// behavior field; paths have no gRPC equivalent and are ignored.
+func (g *Generator) generateGRPCScript(traffic *types.TrafficConfig, rate, duration int, target string) string {
+	pattern := traffic.Pattern
+	if pattern == "" {
+		pattern = "steady"
+	}
+
+	// CallRequest fields: the behavior chain and the request body
+	payload, _ := json.Marshal(struct {
+		Behavior string `json:"behavior"`
+		Body     string `json:"body,omitempty"`
+	}{traffic.Behavior, traffic.Body})
+	data := shellQuote(string(payload))
+
+	// Headers travel as gRPC metadata, whose keys are lowercase
+	metadataFlag := ""
+	if len(traffic.Headers) > 0 {
+		metadata := make(map[string]string, len(traffic.Headers))
+		for name, value := range traffic.Headers {
+			metadata[strings.ToLower(name)] = value
+		}
+		encoded, _ := json.Marshal(metadata)
+		metadataFlag = " -m " + shellQuote(string(encoded))
 	}
+	data += metadataFlag
+
+	highRate := int(float64(rate) * 3.0) // 3x spike, as for HTTP
+	lowRate := int(float64(rate) * 0.2)  // 20% baseline
+	if lowRate < 1 {
+		lowRate = 1
+	}
+
+	var loop, helpers string
+	switch pattern {
+	case "spiky":
+		loop = fmt.Sprintf(`    run_load %d 8 5
+    run_load %d 2 25`, highRate, lowRate)
+	case "diurnal":
+		loop = fmt.Sprintf(`    CURRENT_HOUR=$(date +%%H)
+    if [ $CURRENT_HOUR -ge 9 ] && [ $CURRENT_HOUR -le 17 ]; then
+        MULTIPLIER=160
+    elif [ $CURRENT_HOUR -ge 6 ] && [ $CURRENT_HOUR -le 20 ]; then
+        MULTIPLIER=120
+    else
+        MULTIPLIER=50
+    fi
+    run_load $((%d * MULTIPLIER / 100)) 8 300`, rate)
+	case "ramp":
+		interval := duration / rampSteps
+		if duration == 0 {
+			interval = rampContinuousStep
+		}
+		if interval < 1 {
+			interval = 1
+		}
+		loop = fmt.Sprintf(`    STEP=$((${STEP:-0} + 1))
+    if [ $STEP -gt %d ]; then
+        STEP=%d
+    fi
+    QPS=$((%d * STEP / %d))
+    if [ $QPS -lt 1 ]; then
+        QPS=1
+    fi
+    run_load $QPS 8 %d`, rampSteps, rampSteps, rate, rampSteps, interval)
+	case "poisson":
+		helpers = poissonCountFunc
+		loop = fmt.Sprintf(`    COUNT=$(poisson_count %d)
+    if [ $COUNT -eq 0 ]; then
+        sleep %d
+        continue
+    fi
+    echo "$(date): $COUNT requests over %ds"
+    ghz --insecure --call testservice.TestService/Call \
+        -d %s --rps $(((COUNT + %d) / %d)) -c 8 -n $COUNT %s || true`,
+			rate*poissonWindow, poissonWindow, poissonWindow, data, poissonWindow-1, poissonWindow, target)
+	default: // steady
+		loop = fmt.Sprintf(`    run_load %d 8 300`, rate)
+	}
+
+	return fmt.Sprintf(`#!/bin/sh
+set -e
+
+echo "Starting %s gRPC traffic generation"
+echo "Target: %s"
+echo "Rate: %d qps"
+echo "Duration: %ds (0 = continuous)"
+
+DURATION=%d
+END_TIME=$(($(date +%%s) + DURATION))
+
+# run_load QPS CONCURRENCY SECONDS, capped at the remaining duration
+run_load() {
+    SECS=$3
+    if [ $DURATION -gt 0 ]; then
+        REMAINING=$((END_TIME - $(date +%%s)))
+        if [ $REMAINING -le 0 ]; then
+            return
+        fi
+        if [ $REMAINING -lt $SECS ]; then
+            SECS=$REMAINING
+        fi
+    fi
+    echo "$(date): $1 qps for ${SECS}s"
+    ghz --insecure --call testservice.TestService/Call \
+        -d %s --rps $1 -c $2 -z ${SECS}s %s || true
+}
+
+%swhile [ $DURATION -eq 0 ] || [ $(date +%%s) -lt $END_TIME ]; do
+%s
+done
+
+echo "$(date): gRPC traffic complete"
+`, pattern, target, rate, duration, duration, data, target, helpers, loop)
 }
 
 // findService finds a service by name in the spec
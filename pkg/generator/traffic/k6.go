@@ -0,0 +1,230 @@
+package traffic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+)
+
+// k6ChunkSeconds is how long each k6 run lasts for continuous traffic; the
+// wrapper script reruns k6 indefinitely
+const k6ChunkSeconds = 300
+
+// k6Scenario is a k6 arrival-rate scenario, encoded into the script's options
+type k6Scenario struct {
+	Executor        string    `json:"executor"`
+	Rate            int       `json:"rate,omitempty"`
+	StartRate       int       `json:"startRate,omitempty"`
+	TimeUnit        string    `json:"timeUnit"`
+	Duration        string    `json:"duration,omitempty"`
+	Stages          []k6Stage `json:"stages,omitempty"`
+	PreAllocatedVUs int       `json:"preAllocatedVUs"`
+	MaxVUs          int       `json:"maxVUs"`
+}
+
+// k6Stage moves the arrival rate linearly to Target over Duration ("0s" jumps)
+type k6Stage struct {
+	Duration string `json:"duration"`
+	Target   int    `json:"target"`
+}
+
+// k6ScriptData holds the JSON-encoded constants of the k6 script template
+type k6ScriptData struct {
+	Name              string
+	BaseURL           string
+	Query             string
+	Paths             string
+	PathPattern       string
+	CumulativeWeights string
+	Method            string
+	Headers           string
+	HasBody           bool
+	Scenario          string
+	HoldScenario      string // Scenario for reruns after a continuous ramp, empty otherwise
+}
+
+// generateK6 returns the wrapper script and the k6 JS script for an HTTP target.
+// Patterns map onto arrival-rate scenarios; continuous traffic reruns a fixed-length
+// chunk, and a continuous ramp holds the target rate after its first run.
+func (g *Generator) generateK6(traffic *types.TrafficConfig, rate, duration int, targetURL string) (string, string, error) {
+	pattern := traffic.Pattern
+	if pattern == "" {
+		pattern = "steady"
+	}
+
+	length := duration
+	if duration == 0 {
+		length = k6ChunkSeconds
+	}
+
+	var scenario, hold k6Scenario
+	switch pattern {
+	case "spiky":
+		scenario = k6SpikyScenario(rate, length)
+	case "ramp":
+		scenario = k6RampScenario(rate, duration)
+		if duration == 0 {
+			hold = k6ConstantScenario(rate, k6ChunkSeconds)
+		}
+	default: // steady
+		scenario = k6ConstantScenario(rate, length)
+	}
+
+	// Paths and their cumulative weights; without paths every request goes to the target URL
+	paths := traffic.Paths
+	if len(paths) == 0 {
+		paths = []string{""}
+	}
+	var cumulative []int
+	total := 0
+	for _, path := range paths {
+		weight := 1
+		if len(traffic.PathWeights) > 0 {
+			weight = traffic.PathWeights[path]
+		}
+		total += weight
+		cumulative = append(cumulative, total)
+	}
+
+	query := ""
+	if traffic.Behavior != "" {
+		query = "?behavior=" + traffic.Behavior
+	}
+
+	// JSON bodies get a JSON content type, as with fortio
+	headers := map[string]string{}
+	for name, value := range traffic.Headers {
+		headers[name] = value
+	}
+	if traffic.Body != "" && json.Valid([]byte(traffic.Body)) {
+		hasContentType := false
+		for name := range headers {
+			if strings.EqualFold(name, "Content-Type") {
+				hasContentType = true
+			}
+		}
+		if !hasContentType {
+			headers["Content-Type"] = "application/json"
+		}
+	}
+
+	data := k6ScriptData{
+		Name:              traffic.Name,
+		BaseURL:           jsonString(targetURL),
+		Query:             jsonString(query),
+		Paths:             jsonString(paths),
+		PathPattern:       jsonString(pathPatternOrDefault(traffic.PathPattern)),
+		CumulativeWeights: jsonString(cumulative),
+		Method:            jsonString(traffic.EffectiveMethod()),
+		Headers:           jsonString(headers),
+		HasBody:           traffic.Body != "",
+		Scenario:          jsonString(scenario),
+	}
+	if hold.Executor != "" {
+		data.HoldScenario = jsonString(hold)
+	}
+
+	var buf bytes.Buffer
+	if err := g.templates.ExecuteTemplate(&buf, "k6-script.js.tmpl", data); err != nil {
+		return "", "", fmt.Errorf("failed to execute k6 template: %w", err)
+	}
+
+	run := "k6 run /scripts/script.js || true"
+	if duration == 0 {
+		run = `while true; do
+    k6 run $HOLD /scripts/script.js || true
+    HOLD="-e HOLD=1"
+done`
+	}
+
+	script := fmt.Sprintf(`#!/bin/sh
+set -e
+
+echo "Starting %s traffic generation with k6"
+echo "Target: %s"
+echo "Rate: %d qps"
+echo "Duration: %ds (0 = continuous)"
+
+%s
+
+echo "$(date): k6 traffic complete"
+`, pattern, targetURL, rate, duration, run)
+
+	return script, buf.String(), nil
+}
+
+// k6ConstantScenario holds rate for the given number of seconds
+func k6ConstantScenario(rate, seconds int) k6Scenario {
+	return k6Scenario{
+		Executor:        "constant-arrival-rate",
+		Rate:            rate,
+		TimeUnit:        "1s",
+		Duration:        fmt.Sprintf("%ds", seconds),
+		PreAllocatedVUs: 8,
+		MaxVUs:          k6MaxVUs(rate),
+	}
+}
+
+// k6SpikyScenario alternates 5s bursts at 3x the rate with 25s at 20% of it,
+// as the fortio spiky script does, for the given number of seconds
+func k6SpikyScenario(rate, seconds int) k6Scenario {
+	highRate := int(float64(rate) * 3.0)
+	lowRate := int(float64(rate) * 0.2)
+
+	var stages []k6Stage
+	for elapsed := 0; elapsed < seconds; elapsed += 30 {
+		burst := min(5, seconds-elapsed)
+		stages = append(stages, k6Stage{"0s", highRate}, k6Stage{fmt.Sprintf("%ds", burst), highRate})
+		if pause := min(25, seconds-elapsed-burst); pause > 0 {
+			stages = append(stages, k6Stage{"0s", lowRate}, k6Stage{fmt.Sprintf("%ds", pause), lowRate})
+		}
+	}
+
+	return k6Scenario{
+		Executor:        "ramping-arrival-rate",
+		StartRate:       highRate,
+		TimeUnit:        "1s",
+		Stages:          stages,
+		PreAllocatedVUs: 8,
+		MaxVUs:          k6MaxVUs(highRate),
+	}
+}
+
+// k6RampScenario climbs smoothly through the ramp steps to rate
+func k6RampScenario(rate, duration int) k6Scenario {
+	interval := duration / rampSteps
+	if duration == 0 {
+		interval = rampContinuousStep
+	}
+	if interval < 1 {
+		interval = 1
+	}
+
+	var stages []k6Stage
+	for _, qps := range rampRates(rate) {
+		stages = append(stages, k6Stage{fmt.Sprintf("%ds", interval), qps})
+	}
+
+	return k6Scenario{
+		Executor:        "ramping-arrival-rate",
+		TimeUnit:        "1s",
+		Stages:          stages,
+		PreAllocatedVUs: 8,
+		MaxVUs:          k6MaxVUs(rate),
+	}
+}
+
+// k6MaxVUs allows up to one VU per request per second, so slow responses
+// (e.g. injected latency) don't drop iterations at the configured rate
+func k6MaxVUs(rate int) int {
+	return max(rate, 8)
+}
+
+// jsonString encodes v as JSON, which is also a valid JavaScript literal
+func jsonString(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
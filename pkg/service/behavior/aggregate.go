@@ -0,0 +1,58 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AggregateBehavior calls all upstreams in parallel and succeeds if enough of them
+// do, modelling a read-quorum aggregator
+type AggregateBehavior struct {
+	MinSuccess int // Successful upstream calls needed for the request to succeed
+}
+
+// String returns the string representation of aggregate behavior
+func (ab *AggregateBehavior) String() string {
+	return fmt.Sprintf("aggregate=min-success:%d", ab.MinSuccess)
+}
+
+// parseAggregate parses aggregate specifications
+// Format: min-success:N
+// Example: "min-success:3"
+func parseAggregate(value string) (*AggregateBehavior, error) {
+	count, ok := strings.CutPrefix(value, "min-success:")
+	if !ok {
+		return nil, fmt.Errorf("invalid format: %s (expected min-success:N)", value)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil {
+		return nil, fmt.Errorf("invalid min-success: %w", err)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("min-success must be at least 1, got %d", n)
+	}
+
+	return &AggregateBehavior{MinSuccess: n}, nil
+}
+
+// AggregateMinSuccess returns the successful upstream calls the request needs,
+// or 0 if every upstream must succeed
+func (b *Behavior) AggregateMinSuccess() int {
+	if b == nil || b.Aggregate == nil {
+		return 0
+	}
+	return b.Aggregate.MinSuccess
+}
+
+func init() {
+	registerParser("aggregate", func(b *Behavior, value string) error {
+		ab, err := parseAggregate(value)
+		if err != nil {
+			return fmt.Errorf("invalid aggregate: %w", err)
+		}
+		b.Aggregate = ab
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package behavior
+
+import "testing"
+
+func TestParseAggregate(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		wantError      bool
+		wantMinSuccess int
+	}{
+		{name: "valid", input: "aggregate=min-success:3", wantMinSuccess: 3},
+		{name: "one", input: "aggregate=min-success:1", wantMinSuccess: 1},
+		{name: "zero", input: "aggregate=min-success:0", wantError: true},
+		{name: "not a number", input: "aggregate=min-success:most", wantError: true},
+		{name: "missing keyword", input: "aggregate=3", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if got := b.AggregateMinSuccess(); got != tt.wantMinSuccess {
+				t.Errorf("AggregateMinSuccess() = %d, want %d", got, tt.wantMinSuccess)
+			}
+			if !b.ParallelFanout() {
+				t.Error("expected aggregate to call upstreams in parallel")
+			}
+		})
+	}
+}
+
+func TestAggregateString(t *testing.T) {
+	input := "aggregate=min-success:3"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestAggregateMinSuccessNilBehavior(t *testing.T) {
+	var b *Behavior
+	if b.AggregateMinSuccess() != 0 {
+		t.Error("expected nil behavior to require every upstream")
+	}
+}
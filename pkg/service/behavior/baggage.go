@@ -0,0 +1,85 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// BaggageBehavior adds OpenTelemetry baggage members to the service's upstream calls,
+// so they propagate down the call chain alongside the trace context
+type BaggageBehavior struct {
+	Members []BaggageMember
+}
+
+// BaggageMember is a single baggage key/value pair
+type BaggageMember struct {
+	Key   string
+	Value string
+}
+
+// String returns the string representation of baggage behavior, one directive per member
+func (bb *BaggageBehavior) String() string {
+	var parts []string
+	for _, m := range bb.Members {
+		parts = append(parts, fmt.Sprintf("baggage=%s=%s", m.Key, m.Value))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseBaggage parses a baggage member
+// Format: key=value
+// Example: "tenant=acme"
+func parseBaggage(value string) (BaggageMember, error) {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return BaggageMember{}, fmt.Errorf("invalid format: %s (expected key=value)", value)
+	}
+	if _, err := baggage.NewMemberRaw(key, val); err != nil {
+		return BaggageMember{}, err
+	}
+	return BaggageMember{Key: key, Value: val}, nil
+}
+
+// WithBaggage returns ctx with the behavior's baggage members added to any baggage
+// already received, overriding received members with the same key
+func (b *Behavior) WithBaggage(ctx context.Context) context.Context {
+	if b == nil || b.Baggage == nil {
+		return ctx
+	}
+
+	bag := baggage.FromContext(ctx)
+	for _, m := range b.Baggage.Members {
+		member, err := baggage.NewMemberRaw(m.Key, m.Value)
+		if err != nil {
+			continue
+		}
+		if next, err := bag.SetMember(member); err == nil {
+			bag = next
+		}
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+func init() {
+	registerParser("baggage", func(b *Behavior, value string) error {
+		m, err := parseBaggage(value)
+		if err != nil {
+			return fmt.Errorf("invalid baggage: %w", err)
+		}
+		// Repeated baggage= directives accumulate, a repeated key keeps the last value
+		if b.Baggage == nil {
+			b.Baggage = &BaggageBehavior{}
+		}
+		for i, existing := range b.Baggage.Members {
+			if existing.Key == m.Key {
+				b.Baggage.Members[i] = m
+				return nil
+			}
+		}
+		b.Baggage.Members = append(b.Baggage.Members, m)
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestParseBaggage(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantError   bool
+		wantMembers []BaggageMember
+	}{
+		{name: "single member", input: "baggage=tenant=acme", wantMembers: []BaggageMember{{"tenant", "acme"}}},
+		{name: "repeated members accumulate", input: "baggage=tenant=acme,baggage=user=42", wantMembers: []BaggageMember{{"tenant", "acme"}, {"user", "42"}}},
+		{name: "repeated key keeps last value", input: "baggage=tenant=acme,baggage=tenant=globex", wantMembers: []BaggageMember{{"tenant", "globex"}}},
+		{name: "empty value", input: "baggage=tenant=", wantMembers: []BaggageMember{{"tenant", ""}}},
+		{name: "missing value", input: "baggage=tenant", wantError: true},
+		{name: "missing key", input: "baggage==acme", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if len(b.Baggage.Members) != len(tt.wantMembers) {
+				t.Fatalf("got members %v, want %v", b.Baggage.Members, tt.wantMembers)
+			}
+			for i, m := range tt.wantMembers {
+				if b.Baggage.Members[i] != m {
+					t.Errorf("member %d = %v, want %v", i, b.Baggage.Members[i], m)
+				}
+			}
+		})
+	}
+}
+
+func TestBaggageString(t *testing.T) {
+	input := "baggage=tenant=acme,baggage=user=42"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestWithBaggage(t *testing.T) {
+	received, err := baggage.Parse("tenant=initech,region=eu")
+	if err != nil {
+		t.Fatalf("baggage.Parse() failed: %v", err)
+	}
+	ctx := baggage.ContextWithBaggage(context.Background(), received)
+
+	b, err := Parse("baggage=tenant=acme,baggage=user=42")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	bag := baggage.FromContext(b.WithBaggage(ctx))
+	want := map[string]string{"tenant": "acme", "user": "42", "region": "eu"}
+	if bag.Len() != len(want) {
+		t.Errorf("expected %d members, got %s", len(want), bag)
+	}
+	for key, value := range want {
+		if got := bag.Member(key).Value(); got != value {
+			t.Errorf("member %s = %q, want %q", key, got, value)
+		}
+	}
+
+	// Without the behavior the context is unchanged
+	var none *Behavior
+	if got := none.WithBaggage(ctx); got != ctx {
+		t.Error("expected nil behavior to leave the context unchanged")
+	}
+}
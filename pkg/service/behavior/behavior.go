@@ -8,15 +8,67 @@ import (
 
 // Behavior represents parsed behavior directives
 type Behavior struct {
-	Latency         *LatencyBehavior
-	Error           *ErrorBehavior
-	CPU             *CPUBehavior
-	Memory          *MemoryBehavior
-	Panic           *PanicBehavior
-	CrashIfFile     *CrashIfFileBehavior
-	ErrorIfFile     *ErrorIfFileBehavior
-	Disk            *DiskBehavior
-	UpstreamWeights *UpstreamWeightsBehavior // Weights for grouped upstreams (ID -> weight)
+	Latency            *LatencyBehavior
+	Error              *ErrorBehavior
+	CPU                *CPUBehavior
+	Memory             *MemoryBehavior
+	Panic              *PanicBehavior
+	PanicAfter         *PanicAfterBehavior
+	CrashIfFile        *CrashIfFileBehavior
+	Poison             *PoisonBehavior
+	ErrorIfFile        *ErrorIfFileBehavior
+	ErrorOnPod         *ErrorOnPodBehavior
+	ErrorWindow        *ErrorWindowBehavior
+	ErrorAfter         *ErrorAfterBehavior
+	Disk               *DiskBehavior
+	FDLeak             *FDLeakBehavior
+	GoroutineLeak      *GoroutineLeakBehavior
+	ShedWhenLoaded     *ShedWhenLoadedBehavior
+	Priority           *PriorityBehavior
+	Lock               *LockBehavior
+	SidecarOverhead    *SidecarOverheadBehavior
+	ConfigReload       *ConfigReloadBehavior
+	VersionMix         *VersionMixBehavior
+	KPI                *KPIBehavior
+	Quorum             *QuorumBehavior
+	ReplicaLag         *ReplicaLagBehavior
+	SNIMismatch        *SNIMismatchBehavior
+	ProtocolDivergence *ProtocolDivergenceBehavior
+	RollingRestart     *RollingRestartBehavior
+	ConnectionReset    *ConnectionResetBehavior
+	Redirect           *RedirectBehavior
+	LogLevel           *LogLevelBehavior
+	Expect100          *Expect100Behavior
+	SlowConsume        *SlowConsumeBehavior
+	Trailers           *TrailersBehavior
+	Readiness          *ReadinessBehavior
+	ReadyFromUpstreams *ReadyFromUpstreamsBehavior
+	Liveness           *LivenessBehavior
+	Stampede           *StampedeBehavior
+	SingleFlight       *SingleFlightBehavior
+	CacheWarmup        *CacheWarmupBehavior
+	Cache              *CacheBehavior
+	Fanout             *FanoutBehavior
+	Aggregate          *AggregateBehavior
+	DuplicateInbound   *DuplicateInboundBehavior
+	CanaryShift        *CanaryShiftBehavior
+	UpstreamWeights    *UpstreamWeightsBehavior  // Weights for grouped upstreams (ID -> weight)
+	When               *WhenBehavior             // Request conditions gating all other behaviors
+	UpstreamDegrade    *UpstreamDegradeBehavior  // Increasing latency added to calls to specific upstreams
+	UpstreamCertFail   *UpstreamCertFailBehavior // Fraction of TLS handshakes to specific upstreams failing cert verification
+	UpstreamGrow       *UpstreamGrowBehavior     // Increasing response sizes requested from specific upstreams
+	BodySize           *BodySizeBehavior         // Response body padded up to a size
+	NDJSON             *NDJSONBehavior           // Response streamed as newline-delimited JSON
+	RequireJSONField   *RequireJSONFieldBehavior // Requests without a JSON body field rejected
+	Baggage            *BaggageBehavior          // OpenTelemetry baggage added to upstream calls
+	WSCloseAfter       *WSCloseAfterBehavior     // WebSocket connections closed after a number of messages
+	Echo               *EchoBehavior             // Request body digest reported in the response
+	Route              *RouteBehavior            // Pins weighted group selection to named upstreams
+	Truncate           *TruncateBehavior         // Response body cut short by closing the connection
+	Shutdown           *ShutdownBehavior         // Drain delay between SIGTERM and closing the servers
+	Concurrency        *ConcurrencyBehavior      // Rejects requests over an in-flight limit
+	Queue              *QueueBehavior            // Bounded admission with a wait for a free slot
+	Seed               *SeedBehavior             // Seeded source for the behavior's random decisions
 }
 
 // ServiceBehavior represents a behavior targeted at a specific service
@@ -100,14 +152,33 @@ func (b *Behavior) String() string {
 		parts = append(parts, b.Panic.String())
 	}
 
+	if b.PanicAfter != nil {
+		parts = append(parts, b.PanicAfter.String())
+	}
+
 	if b.CrashIfFile != nil {
 		parts = append(parts, b.CrashIfFile.String())
 	}
 
+	if b.Poison != nil {
+		parts = append(parts, b.Poison.String())
+	}
+
 	if b.ErrorIfFile != nil {
 		parts = append(parts, b.ErrorIfFile.String())
 	}
 
+	if b.ErrorOnPod != nil {
+		parts = append(parts, b.ErrorOnPod.String())
+	}
+	if b.ErrorWindow != nil {
+		parts = append(parts, b.ErrorWindow.String())
+	}
+
+	if b.ErrorAfter != nil {
+		parts = append(parts, b.ErrorAfter.String())
+	}
+
 	if b.CPU != nil {
 		parts = append(parts, b.CPU.String())
 	}
@@ -120,25 +191,247 @@ func (b *Behavior) String() string {
 		parts = append(parts, b.Disk.String())
 	}
 
+	if b.FDLeak != nil {
+		parts = append(parts, b.FDLeak.String())
+	}
+
+	if b.GoroutineLeak != nil {
+		parts = append(parts, b.GoroutineLeak.String())
+	}
+
+	if b.ShedWhenLoaded != nil {
+		parts = append(parts, b.ShedWhenLoaded.String())
+	}
+	if b.Priority != nil {
+		parts = append(parts, b.Priority.String())
+	}
+	if b.Lock != nil {
+		parts = append(parts, b.Lock.String())
+	}
+	if b.SidecarOverhead != nil {
+		parts = append(parts, b.SidecarOverhead.String())
+	}
+
+	if b.ConfigReload != nil {
+		parts = append(parts, b.ConfigReload.String())
+	}
+
+	if b.VersionMix != nil {
+		parts = append(parts, b.VersionMix.String())
+	}
+
+	if b.KPI != nil {
+		parts = append(parts, b.KPI.String())
+	}
+
+	if b.Quorum != nil {
+		parts = append(parts, b.Quorum.String())
+	}
+
+	if b.ReplicaLag != nil {
+		parts = append(parts, b.ReplicaLag.String())
+	}
+
+	if b.SNIMismatch != nil {
+		parts = append(parts, b.SNIMismatch.String())
+	}
+	if b.ProtocolDivergence != nil {
+		parts = append(parts, b.ProtocolDivergence.String())
+	}
+	if b.RollingRestart != nil {
+		parts = append(parts, b.RollingRestart.String())
+	}
+
+	if b.ConnectionReset != nil {
+		parts = append(parts, b.ConnectionReset.String())
+	}
+
+	if b.Redirect != nil {
+		parts = append(parts, b.Redirect.String())
+	}
+
+	if b.LogLevel != nil {
+		parts = append(parts, b.LogLevel.String())
+	}
+
+	if b.Expect100 != nil {
+		parts = append(parts, b.Expect100.String())
+	}
+
+	if b.SlowConsume != nil {
+		parts = append(parts, b.SlowConsume.String())
+	}
+
+	if b.Trailers != nil {
+		parts = append(parts, b.Trailers.String())
+	}
+
+	if b.Readiness != nil {
+		parts = append(parts, b.Readiness.String())
+	}
+
+	if b.ReadyFromUpstreams != nil {
+		parts = append(parts, b.ReadyFromUpstreams.String())
+	}
+
+	if b.Liveness != nil {
+		parts = append(parts, b.Liveness.String())
+	}
+
+	if b.Stampede != nil {
+		parts = append(parts, b.Stampede.String())
+	}
+
+	if b.SingleFlight != nil {
+		parts = append(parts, b.SingleFlight.String())
+	}
+
+	if b.CacheWarmup != nil {
+		parts = append(parts, b.CacheWarmup.String())
+	}
+
+	if b.Cache != nil {
+		parts = append(parts, b.Cache.String())
+	}
+
+	if b.Fanout != nil {
+		parts = append(parts, b.Fanout.String())
+	}
+
+	if b.Aggregate != nil {
+		parts = append(parts, b.Aggregate.String())
+	}
+
+	if b.DuplicateInbound != nil {
+		parts = append(parts, b.DuplicateInbound.String())
+	}
+
+	if b.CanaryShift != nil {
+		parts = append(parts, b.CanaryShift.String())
+	}
+
 	if b.UpstreamWeights != nil {
 		parts = append(parts, b.UpstreamWeights.String())
 	}
 
+	if b.UpstreamDegrade != nil {
+		parts = append(parts, b.UpstreamDegrade.String())
+	}
+
+	if b.UpstreamCertFail != nil {
+		parts = append(parts, b.UpstreamCertFail.String())
+	}
+	if b.UpstreamGrow != nil {
+		parts = append(parts, b.UpstreamGrow.String())
+	}
+	if b.BodySize != nil {
+		parts = append(parts, b.BodySize.String())
+	}
+	if b.NDJSON != nil {
+		parts = append(parts, b.NDJSON.String())
+	}
+	if b.RequireJSONField != nil {
+		parts = append(parts, b.RequireJSONField.String())
+	}
+	if b.Baggage != nil {
+		parts = append(parts, b.Baggage.String())
+	}
+	if b.WSCloseAfter != nil {
+		parts = append(parts, b.WSCloseAfter.String())
+	}
+	if b.Echo != nil {
+		parts = append(parts, b.Echo.String())
+	}
+	if b.Route != nil {
+		parts = append(parts, b.Route.String())
+	}
+	if b.Truncate != nil {
+		parts = append(parts, b.Truncate.String())
+	}
+	if b.Shutdown != nil {
+		parts = append(parts, b.Shutdown.String())
+	}
+	if b.Concurrency != nil {
+		parts = append(parts, b.Concurrency.String())
+	}
+	if b.Queue != nil {
+		parts = append(parts, b.Queue.String())
+	}
+	if b.Seed != nil {
+		parts = append(parts, b.Seed.String())
+	}
+
+	if b.When != nil {
+		parts = append(parts, b.When.String())
+	}
+
 	return strings.Join(parts, ",")
 }
 
 // mergeBehaviors combines two behaviors (b2 takes precedence over b1)
 func mergeBehaviors(b1, b2 *Behavior) *Behavior {
 	return &Behavior{
-		Latency:         mergeField(b1.Latency, b2.Latency),
-		Error:           mergeField(b1.Error, b2.Error),
-		CPU:             mergeField(b1.CPU, b2.CPU),
-		Memory:          mergeField(b1.Memory, b2.Memory),
-		Panic:           mergeField(b1.Panic, b2.Panic),
-		CrashIfFile:     mergeField(b1.CrashIfFile, b2.CrashIfFile),
-		ErrorIfFile:     mergeField(b1.ErrorIfFile, b2.ErrorIfFile),
-		Disk:            mergeField(b1.Disk, b2.Disk),
-		UpstreamWeights: mergeField(b1.UpstreamWeights, b2.UpstreamWeights),
+		Latency:            mergeField(b1.Latency, b2.Latency),
+		Error:              mergeField(b1.Error, b2.Error),
+		CPU:                mergeField(b1.CPU, b2.CPU),
+		Memory:             mergeField(b1.Memory, b2.Memory),
+		Panic:              mergeField(b1.Panic, b2.Panic),
+		PanicAfter:         mergeField(b1.PanicAfter, b2.PanicAfter),
+		CrashIfFile:        mergeField(b1.CrashIfFile, b2.CrashIfFile),
+		Poison:             mergeField(b1.Poison, b2.Poison),
+		ErrorIfFile:        mergeField(b1.ErrorIfFile, b2.ErrorIfFile),
+		ErrorOnPod:         mergeField(b1.ErrorOnPod, b2.ErrorOnPod),
+		ErrorWindow:        mergeField(b1.ErrorWindow, b2.ErrorWindow),
+		ErrorAfter:         mergeField(b1.ErrorAfter, b2.ErrorAfter),
+		Disk:               mergeField(b1.Disk, b2.Disk),
+		FDLeak:             mergeField(b1.FDLeak, b2.FDLeak),
+		GoroutineLeak:      mergeField(b1.GoroutineLeak, b2.GoroutineLeak),
+		ShedWhenLoaded:     mergeField(b1.ShedWhenLoaded, b2.ShedWhenLoaded),
+		Priority:           mergeField(b1.Priority, b2.Priority),
+		Lock:               mergeField(b1.Lock, b2.Lock),
+		SidecarOverhead:    mergeField(b1.SidecarOverhead, b2.SidecarOverhead),
+		ConfigReload:       mergeField(b1.ConfigReload, b2.ConfigReload),
+		VersionMix:         mergeField(b1.VersionMix, b2.VersionMix),
+		KPI:                mergeField(b1.KPI, b2.KPI),
+		Quorum:             mergeField(b1.Quorum, b2.Quorum),
+		ReplicaLag:         mergeField(b1.ReplicaLag, b2.ReplicaLag),
+		SNIMismatch:        mergeField(b1.SNIMismatch, b2.SNIMismatch),
+		ProtocolDivergence: mergeField(b1.ProtocolDivergence, b2.ProtocolDivergence),
+		RollingRestart:     mergeField(b1.RollingRestart, b2.RollingRestart),
+		ConnectionReset:    mergeField(b1.ConnectionReset, b2.ConnectionReset),
+		Redirect:           mergeField(b1.Redirect, b2.Redirect),
+		LogLevel:           mergeField(b1.LogLevel, b2.LogLevel),
+		Expect100:          mergeField(b1.Expect100, b2.Expect100),
+		SlowConsume:        mergeField(b1.SlowConsume, b2.SlowConsume),
+		Trailers:           mergeField(b1.Trailers, b2.Trailers),
+		Readiness:          mergeField(b1.Readiness, b2.Readiness),
+		ReadyFromUpstreams: mergeField(b1.ReadyFromUpstreams, b2.ReadyFromUpstreams),
+		Liveness:           mergeField(b1.Liveness, b2.Liveness),
+		Stampede:           mergeField(b1.Stampede, b2.Stampede),
+		SingleFlight:       mergeField(b1.SingleFlight, b2.SingleFlight),
+		CacheWarmup:        mergeField(b1.CacheWarmup, b2.CacheWarmup),
+		Cache:              mergeField(b1.Cache, b2.Cache),
+		Fanout:             mergeField(b1.Fanout, b2.Fanout),
+		Aggregate:          mergeField(b1.Aggregate, b2.Aggregate),
+		DuplicateInbound:   mergeField(b1.DuplicateInbound, b2.DuplicateInbound),
+		CanaryShift:        mergeField(b1.CanaryShift, b2.CanaryShift),
+		UpstreamWeights:    mergeField(b1.UpstreamWeights, b2.UpstreamWeights),
+		When:               mergeField(b1.When, b2.When),
+		UpstreamDegrade:    mergeField(b1.UpstreamDegrade, b2.UpstreamDegrade),
+		UpstreamCertFail:   mergeField(b1.UpstreamCertFail, b2.UpstreamCertFail),
+		UpstreamGrow:       mergeField(b1.UpstreamGrow, b2.UpstreamGrow),
+		BodySize:           mergeField(b1.BodySize, b2.BodySize),
+		NDJSON:             mergeField(b1.NDJSON, b2.NDJSON),
+		RequireJSONField:   mergeField(b1.RequireJSONField, b2.RequireJSONField),
+		Baggage:            mergeField(b1.Baggage, b2.Baggage),
+		WSCloseAfter:       mergeField(b1.WSCloseAfter, b2.WSCloseAfter),
+		Echo:               mergeField(b1.Echo, b2.Echo),
+		Route:              mergeField(b1.Route, b2.Route),
+		Truncate:           mergeField(b1.Truncate, b2.Truncate),
+		Shutdown:           mergeField(b1.Shutdown, b2.Shutdown),
+		Concurrency:        mergeField(b1.Concurrency, b2.Concurrency),
+		Queue:              mergeField(b1.Queue, b2.Queue),
+		Seed:               mergeField(b1.Seed, b2.Seed),
 	}
 }
 
@@ -268,5 +561,21 @@ func (b *Behavior) Apply(ctx context.Context) error {
 		b.applyMemory(ctx)
 	}
 
+	if b.GoroutineLeak != nil {
+		b.applyGoroutineLeak(ctx)
+	}
+
+	if b.Readiness != nil {
+		b.applyReadiness()
+	}
+
+	if b.ReadyFromUpstreams != nil {
+		b.ApplyReadyFromUpstreams()
+	}
+
+	if b.Shutdown != nil {
+		b.applyShutdown()
+	}
+
 	return nil
 }
@@ -0,0 +1,56 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxBodySize caps padded responses. The body is held in memory several times over
+// while it is built and serialized, so larger sizes risk OOM-killing the pod itself.
+const maxBodySize = 100 << 20
+
+// BodySizeBehavior pads the response body up to a given size
+type BodySizeBehavior struct {
+	Size int64 // Target body size in bytes
+}
+
+// String returns the string representation of body-size behavior
+func (bs *BodySizeBehavior) String() string {
+	return fmt.Sprintf("body-size=%s", formatBytes(bs.Size))
+}
+
+// parseBodySize parses body-size specifications
+// Examples: "1024", "64Ki", "5Mi"
+func parseBodySize(value string) (*BodySizeBehavior, error) {
+	size, err := parseBytes(value)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("size must be positive, got %d", size)
+	}
+	if size > maxBodySize {
+		return nil, fmt.Errorf("size must be at most %s, got %s", formatBytes(maxBodySize), formatBytes(size))
+	}
+	return &BodySizeBehavior{Size: size}, nil
+}
+
+// PadBody returns body padded with filler up to the body-size target.
+// Bodies already at or above the target, or without the behavior, are returned unchanged.
+func (b *Behavior) PadBody(body string) string {
+	if b == nil || b.BodySize == nil || int64(len(body)) >= b.BodySize.Size {
+		return body
+	}
+	return body + strings.Repeat(".", int(b.BodySize.Size)-len(body))
+}
+
+func init() {
+	registerParser("body-size", func(b *Behavior, value string) error {
+		bodySize, err := parseBodySize(value)
+		if err != nil {
+			return fmt.Errorf("invalid body-size: %w", err)
+		}
+		b.BodySize = bodySize
+		return nil
+	})
+}
@@ -0,0 +1,68 @@
+package behavior
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBodySize(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantSize  int64
+	}{
+		{name: "raw bytes", input: "body-size=1000", wantSize: 1000},
+		{name: "kibibytes", input: "body-size=64Ki", wantSize: 64 * 1024},
+		{name: "mebibytes", input: "body-size=5Mi", wantSize: 5 << 20},
+		{name: "at the maximum", input: "body-size=100Mi", wantSize: 100 << 20},
+		{name: "above the maximum", input: "body-size=101Mi", wantError: true},
+		{name: "gibibytes", input: "body-size=1Gi", wantError: true},
+		{name: "zero", input: "body-size=0", wantError: true},
+		{name: "invalid", input: "body-size=large", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && b.BodySize.Size != tt.wantSize {
+				t.Errorf("Size = %d, want %d", b.BodySize.Size, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestBodySizeString(t *testing.T) {
+	for _, input := range []string{"body-size=1000", "body-size=64Ki"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestPadBody(t *testing.T) {
+	b := &Behavior{BodySize: &BodySizeBehavior{Size: 100}}
+
+	padded := b.PadBody("All ok")
+	if len(padded) != 100 || !strings.HasPrefix(padded, "All ok") {
+		t.Errorf("expected 100-byte body starting with the original, got %d bytes", len(padded))
+	}
+
+	long := strings.Repeat("x", 200)
+	if got := b.PadBody(long); got != long {
+		t.Error("expected body above the target to be unchanged")
+	}
+
+	var none *Behavior
+	if got := none.PadBody("All ok"); got != "All ok" {
+		t.Errorf("expected nil behavior to leave body unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,143 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheMaxEntries is the cache size above which expired entries are swept on fill
+const cacheMaxEntries = 10000
+
+// CacheBehavior simulates a response cache in front of the service: a repeated key
+// is served a cached response for TTL, skipping latency, errors and upstream calls
+type CacheBehavior struct {
+	TTL    time.Duration // How long a filled entry is served
+	Scope  string        // What keys the cache: "path" (default), "global" or "header"
+	Header string        // Header whose value keys the cache, for the header scope
+}
+
+// String returns the string representation of cache behavior
+func (cb *CacheBehavior) String() string {
+	switch cb.Scope {
+	case "global":
+		return fmt.Sprintf("cache=%s:scope:global", cb.TTL)
+	case "header":
+		return fmt.Sprintf("cache=%s:scope:header=%s", cb.TTL, cb.Header)
+	default:
+		return fmt.Sprintf("cache=%s", cb.TTL)
+	}
+}
+
+// responseCache maps cache keys to the time their entry expires
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// parseCache parses cache specifications
+// Format: ttl[:scope:path|global|header=<name>]
+// Examples: "30s", "1m:scope:global", "30s:scope:header=X-User"
+func parseCache(value string) (*CacheBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 1 && (len(parts) != 3 || parts[1] != "scope") {
+		return nil, fmt.Errorf("invalid format: %s (expected ttl[:scope:path|global|header=<name>])", value)
+	}
+
+	ttl, err := time.ParseDuration(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ttl: %w", err)
+	}
+	if ttl <= 0 {
+		return nil, fmt.Errorf("ttl must be positive")
+	}
+
+	cb := &CacheBehavior{TTL: ttl, Scope: "path"}
+	if len(parts) == 3 {
+		scope := parts[2]
+		switch {
+		case scope == "path" || scope == "global":
+			cb.Scope = scope
+		case strings.HasPrefix(scope, "header="):
+			cb.Scope = "header"
+			cb.Header = strings.TrimPrefix(scope, "header=")
+			if cb.Header == "" {
+				return nil, fmt.Errorf("header scope requires a header name")
+			}
+		default:
+			return nil, fmt.Errorf("unknown scope %q (expected path, global or header=<name>)", scope)
+		}
+	}
+
+	return cb, nil
+}
+
+// CacheKey returns the key the request is cached under, given its path and headers
+func (b *Behavior) CacheKey(path string, headers http.Header) string {
+	if b.Cache == nil {
+		return ""
+	}
+	switch b.Cache.Scope {
+	case "global":
+		return "*"
+	case "header":
+		return b.Cache.Header + "=" + headers.Get(b.Cache.Header)
+	default:
+		return path
+	}
+}
+
+// responseCacheFor returns the service's cache entries
+func responseCacheFor(serviceName string) *responseCache {
+	return loadState(serviceName+"/cache", func() *responseCache {
+		return &responseCache{entries: make(map[string]time.Time)}
+	})
+}
+
+// CacheHit reports whether key has a fresh cache entry at time now. A miss should be followed by CacheFill once the response is recomputed.
+func (b *Behavior) CacheHit(serviceName, key string, now time.Time) bool {
+	if b.Cache == nil {
+		return false
+	}
+
+	c := responseCacheFor(serviceName)
+	c.mu.Lock()
+	expiresAt, ok := c.entries[key]
+	c.mu.Unlock()
+
+	return ok && now.Before(expiresAt)
+}
+
+// CacheFill stores a fresh entry for key, served until the TTL passes
+func (b *Behavior) CacheFill(serviceName, key string, now time.Time) {
+	if b.Cache == nil {
+		return
+	}
+
+	c := responseCacheFor(serviceName)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Bound memory for high-cardinality keys by dropping expired entries
+	if len(c.entries) >= cacheMaxEntries {
+		for k, expiresAt := range c.entries {
+			if !now.Before(expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	c.entries[key] = now.Add(b.Cache.TTL)
+}
+
+func init() {
+	registerParser("cache", func(b *Behavior, value string) error {
+		cb, err := parseCache(value)
+		if err != nil {
+			return fmt.Errorf("invalid cache: %w", err)
+		}
+		b.Cache = cb
+		return nil
+	})
+}
@@ -0,0 +1,129 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantError  bool
+		wantTTL    time.Duration
+		wantScope  string
+		wantHeader string
+	}{
+		{name: "ttl only", input: "cache=30s", wantTTL: 30 * time.Second, wantScope: "path"},
+		{name: "path scope", input: "cache=30s:scope:path", wantTTL: 30 * time.Second, wantScope: "path"},
+		{name: "global scope", input: "cache=1m:scope:global", wantTTL: time.Minute, wantScope: "global"},
+		{name: "header scope", input: "cache=30s:scope:header=X-User", wantTTL: 30 * time.Second, wantScope: "header", wantHeader: "X-User"},
+		{name: "empty header", input: "cache=30s:scope:header=", wantError: true},
+		{name: "unknown scope", input: "cache=30s:scope:query", wantError: true},
+		{name: "zero ttl", input: "cache=0s", wantError: true},
+		{name: "invalid ttl", input: "cache=forever", wantError: true},
+		{name: "missing scope value", input: "cache=30s:scope", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			c := b.Cache
+			if c.TTL != tt.wantTTL || c.Scope != tt.wantScope || c.Header != tt.wantHeader {
+				t.Errorf("got %s/%s/%q, want %s/%s/%q", c.TTL, c.Scope, c.Header, tt.wantTTL, tt.wantScope, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestCacheString(t *testing.T) {
+	for _, input := range []string{"cache=30s", "cache=1m0s:scope:global", "cache=30s:scope:header=X-User"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", input, err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestCacheKey_Scopes(t *testing.T) {
+	headers := http.Header{"X-User": []string{"alice"}}
+
+	tests := []struct {
+		behavior string
+		want     string
+	}{
+		{"cache=30s", "/products"},
+		{"cache=30s:scope:global", "*"},
+		{"cache=30s:scope:header=X-User", "X-User=alice"},
+	}
+	for _, tt := range tests {
+		b, err := Parse(tt.behavior)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.behavior, err)
+		}
+		if got := b.CacheKey("/products", headers); got != tt.want {
+			t.Errorf("%s: CacheKey() = %q, want %q", tt.behavior, got, tt.want)
+		}
+	}
+}
+
+func TestCache_HitUntilExpiry(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("cache=10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	if b.CacheHit("api", "/products", start) {
+		t.Fatal("expected a miss on the cold cache")
+	}
+	b.CacheFill("api", "/products", start)
+
+	if !b.CacheHit("api", "/products", start.Add(5*time.Second)) {
+		t.Error("expected a hit within the TTL")
+	}
+	if b.CacheHit("api", "/cart", start.Add(5*time.Second)) {
+		t.Error("expected a miss for another key")
+	}
+	if b.CacheHit("orders", "/products", start.Add(5*time.Second)) {
+		t.Error("expected a miss for another service")
+	}
+	if b.CacheHit("api", "/products", start.Add(10*time.Second)) {
+		t.Error("expected a miss once the TTL passed")
+	}
+}
+
+func TestCache_FillSweepsExpiredEntries(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("cache=1s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	for i := 0; i < cacheMaxEntries; i++ {
+		b.CacheFill("api", fmt.Sprintf("/items/%d", i), start)
+	}
+	b.CacheFill("api", "/fresh", start.Add(time.Minute))
+
+	if n := len(responseCacheFor("api").entries); n != 1 {
+		t.Errorf("expected expired entries swept, %d entries left", n)
+	}
+}
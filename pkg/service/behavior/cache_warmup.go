@@ -0,0 +1,107 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// CacheWarmupBehavior simulates a cold cache after restart: every request misses at
+// first and the hit rate climbs linearly as the first WarmAfter requests prime it
+type CacheWarmupBehavior struct {
+	HitLatency  time.Duration // Latency of a cache hit
+	MissLatency time.Duration // Latency of a cache miss
+	WarmAfter   int64         // Requests served before the cache is fully warm
+}
+
+// String returns the string representation of cache-warmup behavior
+func (cw *CacheWarmupBehavior) String() string {
+	return fmt.Sprintf("cache-warmup=hit-latency:%s:miss-latency:%s:warm-after:%d", cw.HitLatency, cw.MissLatency, cw.WarmAfter)
+}
+
+// parseCacheWarmup parses cache-warmup specifications
+// Format: hit-latency:duration:miss-latency:duration:warm-after:N
+// Example: "hit-latency:2ms:miss-latency:200ms:warm-after:1000"
+func parseCacheWarmup(value string) (*CacheWarmupBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("invalid format: %s (expected hit-latency:duration:miss-latency:duration:warm-after:N)", value)
+	}
+
+	cw := &CacheWarmupBehavior{}
+	seen := make(map[string]bool)
+	for i := 0; i < len(parts); i += 2 {
+		name, raw := parts[i], parts[i+1]
+		if seen[name] {
+			return nil, fmt.Errorf("option %s given more than once", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "hit-latency", "miss-latency":
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			if d < 0 {
+				return nil, fmt.Errorf("%s must not be negative", name)
+			}
+			if name == "hit-latency" {
+				cw.HitLatency = d
+			} else {
+				cw.MissLatency = d
+			}
+		case "warm-after":
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid warm-after: %w", err)
+			}
+			if n < 1 {
+				return nil, fmt.Errorf("warm-after must be at least 1, got %d", n)
+			}
+			cw.WarmAfter = n
+		default:
+			return nil, fmt.Errorf("unknown option: %s", name)
+		}
+	}
+
+	return cw, nil
+}
+
+// HitProbability returns the chance a request hits the cache once served requests
+// have primed it, rising linearly from 0 to 1 at WarmAfter
+func (cw *CacheWarmupBehavior) HitProbability(served int64) float64 {
+	if served >= cw.WarmAfter {
+		return 1
+	}
+	if served < 0 {
+		return 0
+	}
+	return float64(served) / float64(cw.WarmAfter)
+}
+
+// cacheWarmupDelay counts this request as served and returns its hit or miss latency
+func (b *Behavior) cacheWarmupDelay(serviceName string) time.Duration {
+	if b.CacheWarmup == nil {
+		return 0
+	}
+
+	served := loadState(serviceName+"/"+b.CacheWarmup.String(), func() *atomic.Int64 { return &atomic.Int64{} })
+	if b.Random().Float64() < b.CacheWarmup.HitProbability(served.Add(1)-1) {
+		return b.CacheWarmup.HitLatency
+	}
+	return b.CacheWarmup.MissLatency
+}
+
+func init() {
+	registerParser("cache-warmup", func(b *Behavior, value string) error {
+		cw, err := parseCacheWarmup(value)
+		if err != nil {
+			return fmt.Errorf("invalid cache-warmup: %w", err)
+		}
+		b.CacheWarmup = cw
+		return nil
+	})
+}
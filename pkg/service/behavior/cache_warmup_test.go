@@ -0,0 +1,142 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseCacheWarmup(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantHit   time.Duration
+		wantMiss  time.Duration
+		wantWarm  int64
+	}{
+		{name: "valid", input: "cache-warmup=hit-latency:2ms:miss-latency:200ms:warm-after:1000", wantHit: 2 * time.Millisecond, wantMiss: 200 * time.Millisecond, wantWarm: 1000},
+		{name: "any option order", input: "cache-warmup=warm-after:10:miss-latency:1s:hit-latency:0s", wantMiss: time.Second, wantWarm: 10},
+		{name: "zero warm-after", input: "cache-warmup=hit-latency:2ms:miss-latency:200ms:warm-after:0", wantError: true},
+		{name: "invalid warm-after", input: "cache-warmup=hit-latency:2ms:miss-latency:200ms:warm-after:soon", wantError: true},
+		{name: "invalid latency", input: "cache-warmup=hit-latency:fast:miss-latency:200ms:warm-after:1000", wantError: true},
+		{name: "negative latency", input: "cache-warmup=hit-latency:-2ms:miss-latency:200ms:warm-after:1000", wantError: true},
+		{name: "missing option", input: "cache-warmup=hit-latency:2ms:miss-latency:200ms", wantError: true},
+		{name: "repeated option", input: "cache-warmup=hit-latency:2ms:hit-latency:200ms:warm-after:1000", wantError: true},
+		{name: "unknown option", input: "cache-warmup=hit-latency:2ms:ttl:200ms:warm-after:1000", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			cw := b.CacheWarmup
+			if cw.HitLatency != tt.wantHit || cw.MissLatency != tt.wantMiss || cw.WarmAfter != tt.wantWarm {
+				t.Errorf("got %s/%s/%d, want %s/%s/%d", cw.HitLatency, cw.MissLatency, cw.WarmAfter, tt.wantHit, tt.wantMiss, tt.wantWarm)
+			}
+		})
+	}
+}
+
+func TestCacheWarmupString(t *testing.T) {
+	input := "cache-warmup=hit-latency:2ms:miss-latency:200ms:warm-after:1000"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestCacheWarmup_HitProbability(t *testing.T) {
+	cw := &CacheWarmupBehavior{WarmAfter: 1000}
+
+	tests := []struct {
+		served int64
+		want   float64
+	}{
+		{0, 0},
+		{250, 0.25},
+		{500, 0.5},
+		{1000, 1},
+		{5000, 1},
+	}
+	for _, tt := range tests {
+		if got := cw.HitProbability(tt.served); got != tt.want {
+			t.Errorf("HitProbability(%d) = %v, want %v", tt.served, got, tt.want)
+		}
+	}
+}
+
+func TestCacheWarmup_LatencyDecays(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("cache-warmup=hit-latency:2ms:miss-latency:200ms:warm-after:1000")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	misses := func(n int) int {
+		count := 0
+		for i := 0; i < n; i++ {
+			if b.cacheWarmupDelay("api") == 200*time.Millisecond {
+				count++
+			}
+		}
+		return count
+	}
+
+	// First 100 requests: hit probability below 10%, so mostly misses
+	if early := misses(100); early < 80 {
+		t.Errorf("expected early requests to mostly miss, got %d/100 misses", early)
+	}
+
+	// Requests 900-1000: hit probability above 90%, so mostly hits
+	misses(800)
+	if late := misses(100); late > 20 {
+		t.Errorf("expected late requests to mostly hit, got %d/100 misses", late)
+	}
+
+	// Warm: every request hits
+	if warm := misses(100); warm != 0 {
+		t.Errorf("expected no misses once warm, got %d/100", warm)
+	}
+
+	// Another service starts cold
+	if b.cacheWarmupDelay("orders") != 200*time.Millisecond {
+		t.Error("expected the first request to another service to miss")
+	}
+}
+
+func TestExecutor_CacheWarmup(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("cache-warmup=hit-latency:0s:miss-latency:50ms:warm-after:1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	run := func() time.Duration {
+		start := time.Now()
+		if _, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return time.Since(start)
+	}
+
+	if elapsed := run(); elapsed < 50*time.Millisecond {
+		t.Errorf("expected the cold request to pay miss latency, took %v", elapsed)
+	}
+	if elapsed := run(); elapsed >= 25*time.Millisecond {
+		t.Errorf("expected the warm request to hit, took %v", elapsed)
+	}
+}
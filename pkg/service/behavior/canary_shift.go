@@ -0,0 +1,93 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CanaryShiftBehavior progressively shifts upstream weight from one upstream to another
+type CanaryShiftBehavior struct {
+	From     string        // Upstream receiving 100% at the start
+	To       string        // Upstream receiving 100% at the end
+	Duration time.Duration // Time over which weight shifts, measured from process start
+}
+
+// String returns the string representation of canary-shift behavior
+func (cs *CanaryShiftBehavior) String() string {
+	return fmt.Sprintf("canary-shift=%s->%s:%s", cs.From, cs.To, cs.Duration)
+}
+
+// parseCanaryShift parses canary-shift specifications
+// Example: "v1->v2:10m"
+func parseCanaryShift(value string) (*CanaryShiftBehavior, error) {
+	idx := strings.LastIndex(value, ":")
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid format: %s (expected from->to:duration)", value)
+	}
+
+	upstreams := strings.Split(value[:idx], "->")
+	if len(upstreams) != 2 || upstreams[0] == "" || upstreams[1] == "" {
+		return nil, fmt.Errorf("invalid upstreams: %s (expected from->to)", value[:idx])
+	}
+
+	d, err := time.ParseDuration(value[idx+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+
+	return &CanaryShiftBehavior{
+		From:     strings.TrimSpace(upstreams[0]),
+		To:       strings.TrimSpace(upstreams[1]),
+		Duration: d,
+	}, nil
+}
+
+// Weights returns the current from/to weights (summing to 100) by elapsed process time
+func (cs *CanaryShiftBehavior) Weights() map[string]int {
+	elapsed := time.Since(processStart)
+	toWeight := 100
+	if elapsed < cs.Duration {
+		toWeight = int(100 * float64(elapsed) / float64(cs.Duration))
+	}
+
+	return map[string]int{
+		cs.From: 100 - toWeight,
+		cs.To:   toWeight,
+	}
+}
+
+// UpstreamWeightMap returns the weights to use for grouped upstream selection,
+// combining upstreamWeights with the current canary-shift weights (canary wins)
+func (b *Behavior) UpstreamWeightMap() map[string]int {
+	if b == nil || (b.UpstreamWeights == nil && b.CanaryShift == nil) {
+		return nil
+	}
+
+	weights := make(map[string]int)
+	if b.UpstreamWeights != nil {
+		for id, w := range b.UpstreamWeights.Weights {
+			weights[id] = w
+		}
+	}
+	if b.CanaryShift != nil {
+		for id, w := range b.CanaryShift.Weights() {
+			weights[id] = w
+		}
+	}
+	return weights
+}
+
+func init() {
+	registerParser("canary-shift", func(b *Behavior, value string) error {
+		canaryShift, err := parseCanaryShift(value)
+		if err != nil {
+			return fmt.Errorf("invalid canary-shift: %w", err)
+		}
+		b.CanaryShift = canaryShift
+		return nil
+	})
+}
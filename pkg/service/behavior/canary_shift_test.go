@@ -0,0 +1,102 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCanaryShift(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "shift over 10 minutes",
+			input:     "canary-shift=v1->v2:10m",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.CanaryShift == nil {
+					t.Fatal("expected canary-shift behavior")
+				}
+				if b.CanaryShift.From != "v1" || b.CanaryShift.To != "v2" {
+					t.Errorf("expected v1->v2, got %s->%s", b.CanaryShift.From, b.CanaryShift.To)
+				}
+				if b.CanaryShift.Duration != 10*time.Minute {
+					t.Errorf("expected duration 10m, got %s", b.CanaryShift.Duration)
+				}
+			},
+		},
+		{
+			name:      "missing duration",
+			input:     "canary-shift=v1->v2",
+			wantError: true,
+		},
+		{
+			name:      "missing target",
+			input:     "canary-shift=v1:10m",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestCanaryShiftString(t *testing.T) {
+	input := "canary-shift=v1->v2:10m0s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestCanaryShiftWeightsOverWindow(t *testing.T) {
+	orig := processStart
+	defer func() { processStart = orig }()
+
+	b, err := Parse("canary-shift=v1->v2:10m,upstreamWeights=other:50")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Early in the window v1 dominates
+	processStart = time.Now().Add(-1 * time.Minute)
+	early := b.UpstreamWeightMap()
+	if early["v1"] <= early["v2"] || early["v1"]+early["v2"] != 100 {
+		t.Errorf("expected v1 to dominate early, got %v", early)
+	}
+
+	// Late in the window v2 dominates
+	processStart = time.Now().Add(-9 * time.Minute)
+	late := b.UpstreamWeightMap()
+	if late["v2"] <= late["v1"] || late["v1"]+late["v2"] != 100 {
+		t.Errorf("expected v2 to dominate late, got %v", late)
+	}
+
+	// After the window all traffic goes to v2
+	processStart = time.Now().Add(-20 * time.Minute)
+	done := b.UpstreamWeightMap()
+	if done["v1"] != 0 || done["v2"] != 100 {
+		t.Errorf("expected 0/100 after window, got %v", done)
+	}
+
+	// Other upstream weights are preserved
+	if done["other"] != 50 {
+		t.Errorf("expected upstreamWeights to be merged, got %v", done)
+	}
+}
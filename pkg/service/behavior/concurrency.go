@@ -0,0 +1,75 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ConcurrencyBehavior rejects requests while more than Max are in flight on this
+// process, modeling a service with a bounded worker pool
+type ConcurrencyBehavior struct {
+	Max  int64 // Requests served concurrently, including the one being checked
+	Code int   // HTTP status code returned over the limit
+}
+
+// String returns the string representation of concurrency behavior
+func (cb *ConcurrencyBehavior) String() string {
+	return fmt.Sprintf("concurrency=%d:%d", cb.Max, cb.Code)
+}
+
+// parseConcurrency parses concurrency specifications
+// Format: max[:code]
+// Examples: "10", "10:503"
+func parseConcurrency(value string) (*ConcurrencyBehavior, error) {
+	maxStr, codeStr, hasCode := strings.Cut(value, ":")
+
+	max, err := strconv.ParseInt(maxStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max: %w", err)
+	}
+	if max < 1 {
+		return nil, fmt.Errorf("max must be at least 1, got %d", max)
+	}
+
+	cb := &ConcurrencyBehavior{Max: max, Code: 429}
+	if hasCode {
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %w", err)
+		}
+		if code < 100 || code > 599 {
+			return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+		}
+		cb.Code = code
+	}
+	return cb, nil
+}
+
+// ShouldLimitConcurrency reports whether the request is over the concurrency limit,
+// the code to reject it with, and the number of requests in flight on this process.
+// The servers count a request as in flight from start to completion, so the count
+// includes the request being checked.
+func (b *Behavior) ShouldLimitConcurrency() (bool, int, int64) {
+	if b == nil || b.Concurrency == nil {
+		return false, 0, 0
+	}
+	inFlight := service.InFlight.Count()
+	if inFlight > b.Concurrency.Max {
+		return true, b.Concurrency.Code, inFlight
+	}
+	return false, 0, inFlight
+}
+
+func init() {
+	registerParser("concurrency", func(b *Behavior, value string) error {
+		cb, err := parseConcurrency(value)
+		if err != nil {
+			return fmt.Errorf("invalid concurrency: %w", err)
+		}
+		b.Concurrency = cb
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package behavior
+
+import (
+	"testing"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseConcurrency(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantMax   int64
+		wantCode  int
+	}{
+		{name: "default code", input: "concurrency=10", wantMax: 10, wantCode: 429},
+		{name: "custom code", input: "concurrency=5:503", wantMax: 5, wantCode: 503},
+		{name: "zero max", input: "concurrency=0", wantError: true},
+		{name: "not a number", input: "concurrency=many", wantError: true},
+		{name: "invalid code", input: "concurrency=10:abc", wantError: true},
+		{name: "code out of range", input: "concurrency=10:700", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Concurrency.Max != tt.wantMax {
+				t.Errorf("Max = %d, want %d", b.Concurrency.Max, tt.wantMax)
+			}
+			if b.Concurrency.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", b.Concurrency.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestConcurrencyString(t *testing.T) {
+	b, err := Parse("concurrency=10")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "concurrency=10:429" {
+		t.Errorf("String() = %s, want concurrency=10:429", result)
+	}
+}
+
+func TestShouldLimitConcurrency(t *testing.T) {
+	var nilBehavior *Behavior
+	if over, _, _ := nilBehavior.ShouldLimitConcurrency(); over {
+		t.Error("expected nil behavior not to limit")
+	}
+
+	b, err := Parse("concurrency=2:503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// The checked request is itself in flight
+	done1 := service.InFlight.Begin()
+	done2 := service.InFlight.Begin()
+	if over, _, inFlight := b.ShouldLimitConcurrency(); over || inFlight != 2 {
+		t.Errorf("expected 2 in flight to be within the limit, got over=%v in-flight=%d", over, inFlight)
+	}
+
+	done3 := service.InFlight.Begin()
+	over, code, inFlight := b.ShouldLimitConcurrency()
+	if !over || code != 503 || inFlight != 3 {
+		t.Errorf("expected 503 with 3 in flight, got over=%v code=%d in-flight=%d", over, code, inFlight)
+	}
+
+	// Completed requests free up the pool
+	done3()
+	done2()
+	if over, _, _ := b.ShouldLimitConcurrency(); over {
+		t.Error("expected requests to be accepted after others completed")
+	}
+	done1()
+}
@@ -0,0 +1,72 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ConfigReloadBehavior rejects requests for a window after a reload is triggered
+// via the admin endpoint, modelling a blocking config reload
+type ConfigReloadBehavior struct {
+	Duration time.Duration // How long the reload blocks requests
+	Code     int           // HTTP status code returned while reloading
+}
+
+// String returns the string representation of config reload behavior
+func (cr *ConfigReloadBehavior) String() string {
+	return fmt.Sprintf("config-reload=%s:%d", cr.Duration, cr.Code)
+}
+
+// parseConfigReload parses config reload specifications
+// Examples: "5s", "5s:503"
+func parseConfigReload(value string) (*ConfigReloadBehavior, error) {
+	cr := &ConfigReloadBehavior{Code: http.StatusServiceUnavailable}
+
+	durationStr, codeStr, hasCode := strings.Cut(value, ":")
+	d, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration: %w", err)
+	}
+	if d <= 0 {
+		return nil, fmt.Errorf("duration must be positive")
+	}
+	cr.Duration = d
+
+	if hasCode {
+		code, err := strconv.Atoi(codeStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %w", err)
+		}
+		if code < 100 || code > 599 {
+			return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+		}
+		cr.Code = code
+	}
+
+	return cr, nil
+}
+
+// ShouldRejectReloading determines if the request should be rejected because a
+// triggered config reload is still in progress
+func (b *Behavior) ShouldRejectReloading() (bool, int) {
+	if b.ConfigReload == nil || !service.ConfigReload.Reloading(b.ConfigReload.Duration) {
+		return false, 0
+	}
+	return true, b.ConfigReload.Code
+}
+
+func init() {
+	registerParser("config-reload", func(b *Behavior, value string) error {
+		reload, err := parseConfigReload(value)
+		if err != nil {
+			return fmt.Errorf("invalid config-reload: %w", err)
+		}
+		b.ConfigReload = reload
+		return nil
+	})
+}
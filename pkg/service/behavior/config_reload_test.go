@@ -0,0 +1,75 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseConfigReload(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantError    bool
+		wantDuration time.Duration
+		wantCode     int
+	}{
+		{name: "duration and code", input: "config-reload=5s:503", wantDuration: 5 * time.Second, wantCode: 503},
+		{name: "default code", input: "config-reload=2s", wantDuration: 2 * time.Second, wantCode: 503},
+		{name: "custom code", input: "config-reload=500ms:502", wantDuration: 500 * time.Millisecond, wantCode: 502},
+		{name: "invalid duration", input: "config-reload=soon:503", wantError: true},
+		{name: "zero duration", input: "config-reload=0s", wantError: true},
+		{name: "invalid code", input: "config-reload=5s:abc", wantError: true},
+		{name: "code out of range", input: "config-reload=5s:700", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.ConfigReload.Duration != tt.wantDuration || b.ConfigReload.Code != tt.wantCode {
+				t.Errorf("got %s:%d, want %s:%d", b.ConfigReload.Duration, b.ConfigReload.Code, tt.wantDuration, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestConfigReloadString(t *testing.T) {
+	b, err := Parse("config-reload=5s:503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "config-reload=5s:503" {
+		t.Errorf("String() = %s, want config-reload=5s:503", result)
+	}
+}
+
+func TestShouldRejectReloading(t *testing.T) {
+	defer service.ConfigReload.Reset()
+
+	b, err := Parse("config-reload=50ms:503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if reject, _ := b.ShouldRejectReloading(); reject {
+		t.Error("expected no rejection before a reload is triggered")
+	}
+
+	service.ConfigReload.Trigger()
+	if reject, code := b.ShouldRejectReloading(); !reject || code != 503 {
+		t.Errorf("expected 503 while reloading, got reject=%v code=%d", reject, code)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if reject, _ := b.ShouldRejectReloading(); reject {
+		t.Error("expected requests to resume after the reload window")
+	}
+}
@@ -0,0 +1,67 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConnectionResetBehavior aborts the connection instead of responding, so the
+// client sees a transport error rather than an HTTP status
+type ConnectionResetBehavior struct {
+	Probability float64 // Fraction of requests whose connection is closed (0.0-1.0)
+	Mode        string  // "rst" (default): abort with a TCP RST, "fin": close cleanly without a response
+}
+
+// String returns the string representation of connection-reset behavior
+func (cr *ConnectionResetBehavior) String() string {
+	if cr.Mode == "fin" {
+		return fmt.Sprintf("connection-reset=%v:fin", cr.Probability)
+	}
+	return fmt.Sprintf("connection-reset=%v", cr.Probability)
+}
+
+// parseConnectionReset parses connection-reset specifications
+// Format: probability[:rst|fin]
+// Examples: "0.1", "1", "0.5:fin"
+func parseConnectionReset(value string) (*ConnectionResetBehavior, error) {
+	prob, mode, _ := strings.Cut(value, ":")
+
+	p, err := strconv.ParseFloat(prob, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid probability: %w", err)
+	}
+	if p < 0 || p > 1 {
+		return nil, fmt.Errorf("probability must be between 0 and 1, got %v", p)
+	}
+
+	switch mode {
+	case "":
+		mode = "rst"
+	case "rst", "fin":
+	default:
+		return nil, fmt.Errorf("unknown mode %q (expected rst or fin)", mode)
+	}
+
+	return &ConnectionResetBehavior{Probability: p, Mode: mode}, nil
+}
+
+// ShouldResetConnection determines if the request's connection should be closed
+// instead of answered. Returns true and whether to close cleanly (FIN) rather than reset.
+func (b *Behavior) ShouldResetConnection() (bool, bool) {
+	if b.ConnectionReset == nil || b.Random().Float64() >= b.ConnectionReset.Probability {
+		return false, false
+	}
+	return true, b.ConnectionReset.Mode == "fin"
+}
+
+func init() {
+	registerParser("connection-reset", func(b *Behavior, value string) error {
+		cr, err := parseConnectionReset(value)
+		if err != nil {
+			return fmt.Errorf("invalid connection-reset: %w", err)
+		}
+		b.ConnectionReset = cr
+		return nil
+	})
+}
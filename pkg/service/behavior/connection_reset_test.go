@@ -0,0 +1,107 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseConnectionReset(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantProb  float64
+		wantMode  string
+	}{
+		{name: "probability", input: "connection-reset=0.1", wantProb: 0.1, wantMode: "rst"},
+		{name: "always", input: "connection-reset=1", wantProb: 1, wantMode: "rst"},
+		{name: "explicit rst", input: "connection-reset=0.5:rst", wantProb: 0.5, wantMode: "rst"},
+		{name: "fin", input: "connection-reset=0.5:fin", wantProb: 0.5, wantMode: "fin"},
+		{name: "unknown mode", input: "connection-reset=0.5:drop", wantError: true},
+		{name: "probability above 1", input: "connection-reset=1.5", wantError: true},
+		{name: "negative probability", input: "connection-reset=-0.1", wantError: true},
+		{name: "invalid probability", input: "connection-reset=often", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.ConnectionReset.Probability != tt.wantProb || b.ConnectionReset.Mode != tt.wantMode {
+				t.Errorf("got %v:%s, want %v:%s", b.ConnectionReset.Probability, b.ConnectionReset.Mode, tt.wantProb, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestConnectionResetString(t *testing.T) {
+	for _, input := range []string{"connection-reset=0.1", "connection-reset=1:fin"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestShouldResetConnection_Probability(t *testing.T) {
+	b, err := Parse("connection-reset=0.3")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	const requests = 5000
+	resets := 0
+	for i := 0; i < requests; i++ {
+		if reset, _ := b.ShouldResetConnection(); reset {
+			resets++
+		}
+	}
+	if fraction := float64(resets) / requests; fraction < 0.25 || fraction > 0.35 {
+		t.Errorf("expected ~30%% of connections reset, got %.1f%%", fraction*100)
+	}
+
+	never, _ := Parse("connection-reset=0")
+	if reset, _ := never.ShouldResetConnection(); reset {
+		t.Error("expected no resets with probability 0")
+	}
+}
+
+func TestExecutor_ConnectionReset(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantGraceful bool
+	}{
+		{"connection-reset=1", false},
+		{"connection-reset=1:fin", true},
+	}
+
+	for _, tt := range tests {
+		b, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+
+		result, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background())
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result == nil || !result.ShouldReturn || !result.ResetConnection {
+			t.Fatalf("%s: expected connection reset, got %+v", tt.input, result)
+		}
+		if result.CloseGracefully != tt.wantGraceful {
+			t.Errorf("%s: CloseGracefully = %v, want %v", tt.input, result.CloseGracefully, tt.wantGraceful)
+		}
+		if result.StatusCode != 503 || result.BehaviorType != "connection-reset" {
+			t.Errorf("%s: expected 503 connection-reset fallback, got %d %s", tt.input, result.StatusCode, result.BehaviorType)
+		}
+	}
+}
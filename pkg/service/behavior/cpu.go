@@ -5,31 +5,97 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 // CPUBehavior controls CPU usage patterns
 type CPUBehavior struct {
-	Pattern   string // "spike", "steady", "ramp"
+	Pattern   string // "spike", "steady", "ramp", "burst"
 	Duration  time.Duration
 	Intensity int // Percentage 0-100
+	Cores     int // Cores to load: 0 = one core, allCores = the container's CPU quota
+
+	// Burst pattern only: alternate On busy and Off idle, repeating until the process exits
+	On  time.Duration
+	Off time.Duration
 }
 
+// allCores spreads the load over every core, with intensity relative to the CPU quota
+const allCores = -1
+
+// bursts tracks running burst cycles by spec, so repeated requests don't stack them
+var bursts sync.Map
+
 // String returns the string representation of CPU behavior
 func (cb *CPUBehavior) String() string {
-	cpuStr := fmt.Sprintf("cpu=%s", cb.Pattern)
-	if cb.Duration > 0 {
-		cpuStr += fmt.Sprintf(":%s:%d", cb.Duration, cb.Intensity)
+	var cpuStr string
+	if cb.Pattern == "burst" {
+		cpuStr = fmt.Sprintf("cpu=burst:%d:%s:on:%s:off", cb.Intensity, cb.On, cb.Off)
+	} else {
+		cpuStr = fmt.Sprintf("cpu=%s", cb.Pattern)
+		if cb.Duration > 0 {
+			cpuStr += fmt.Sprintf(":%s:%d", cb.Duration, cb.Intensity)
+		}
+	}
+
+	switch {
+	case cb.Cores == allCores:
+		cpuStr += ":cores=all"
+	case cb.Cores > 0:
+		cpuStr += fmt.Sprintf(":cores=%d", cb.Cores)
 	}
 	return cpuStr
 }
 
-// parseCPU parses CPU behavior specifications
-// Examples: "spike", "spike:5s", "steady:10s:50"
+// parseCPU parses CPU behavior specifications, optionally ending in cores=<n|all>
+// Examples: "spike", "spike:5s", "steady:10s:50", "spike:5s:80:cores=all",
+// "burst:80:2s:on:10s:off"
 func parseCPU(value string) (*CPUBehavior, error) {
 	parts := strings.Split(value, ":")
+
+	cores := 0
+	if last := parts[len(parts)-1]; strings.HasPrefix(last, "cores=") {
+		n, err := parseCPUCores(strings.TrimPrefix(last, "cores="))
+		if err != nil {
+			return nil, err
+		}
+		cores = n
+		parts = parts[:len(parts)-1]
+	}
+
+	cb, err := parseCPUPattern(parts)
+	if err != nil {
+		return nil, err
+	}
+	cb.Cores = cores
+	return cb, nil
+}
+
+// parseCPUCores parses the core count of cores=<n|all>
+func parseCPUCores(value string) (int, error) {
+	if value == "all" {
+		return allCores, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cores: %s (expected a number or all)", value)
+	}
+	if n < 1 {
+		return 0, fmt.Errorf("cores must be at least 1, got %d", n)
+	}
+	return n, nil
+}
+
+// parseCPUPattern parses the pattern, duration and intensity
+func parseCPUPattern(parts []string) (*CPUBehavior, error) {
+	if parts[0] == "burst" {
+		return parseCPUBurst(parts)
+	}
+
 	cb := &CPUBehavior{
 		Pattern:   parts[0],
 		Duration:  5 * time.Second,
@@ -55,35 +121,145 @@ func parseCPU(value string) (*CPUBehavior, error) {
 	return cb, nil
 }
 
+// parseCPUBurst parses the burst pattern: burst:intensity:on:on:off:off
+func parseCPUBurst(parts []string) (*CPUBehavior, error) {
+	if len(parts) != 6 || parts[3] != "on" || parts[5] != "off" {
+		return nil, fmt.Errorf("invalid burst format: %s (expected burst:intensity:<dur>:on:<dur>:off)", strings.Join(parts, ":"))
+	}
+
+	intensity, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid burst intensity: %w", err)
+	}
+	if intensity < 1 || intensity > 100 {
+		return nil, fmt.Errorf("burst intensity must be between 1 and 100, got %d", intensity)
+	}
+
+	on, err := time.ParseDuration(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid burst on duration: %w", err)
+	}
+	off, err := time.ParseDuration(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid burst off duration: %w", err)
+	}
+	if on <= 0 || off <= 0 {
+		return nil, fmt.Errorf("burst on and off durations must be positive")
+	}
+
+	return &CPUBehavior{Pattern: "burst", Intensity: intensity, On: on, Off: off}, nil
+}
+
 // applyCPU applies CPU load
 func (b *Behavior) applyCPU(ctx context.Context) {
+	// A periodic noisy neighbor outlives the request that started it
+	if b.CPU.Pattern == "burst" {
+		if _, running := bursts.LoadOrStore(b.CPU.String(), true); !running {
+			go b.CPU.runBursts(context.Background(), nil)
+		}
+		return
+	}
+
+	endLoad := beginLoad()
 	go func() {
-		deadline := time.Now().Add(b.CPU.Duration)
-
-		// Calculate work duration based on intensity
-		// intensity = 80 means 80% busy, 20% idle
-		workDuration := time.Duration(float64(b.CPU.Intensity) / 100.0 * float64(10*time.Millisecond))
-		idleDuration := 10*time.Millisecond - workDuration
-
-		for time.Now().Before(deadline) {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				// Do CPU-intensive work
-				start := time.Now()
-				for time.Since(start) < workDuration {
-					// Busy loop - consume CPU
-					_ = math.Sqrt(rand.Float64())
-				}
-
-				// Idle period
-				if idleDuration > 0 {
-					time.Sleep(idleDuration)
-				}
+		defer endLoad()
+		b.CPU.burn(ctx, b.CPU.Duration)
+	}()
+}
+
+// burn loads the configured cores at the configured intensity for duration
+func (cb *CPUBehavior) burn(ctx context.Context, duration time.Duration) {
+	if cb.Cores == 0 {
+		burnCPU(ctx, cb.Intensity, duration)
+		return
+	}
+
+	workers, intensity := cb.Cores, cb.Intensity
+	if cb.Cores == allCores {
+		quota, err := getContainerCPUQuota()
+		if err != nil {
+			quota = 0 // No limit, load every core
+		}
+		workers, intensity = cpuWorkers(cb.Intensity, runtime.GOMAXPROCS(0), quota)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			burnCPU(ctx, intensity, duration)
+		}()
+	}
+	wg.Wait()
+}
+
+// cpuWorkers spreads intensity percent of the available cores over one worker per
+// proc, returning the worker count and each worker's intensity. The available cores
+// are the CPU quota when it is below procs, so e.g. 80% of a 2 core quota on 8 procs
+// is 8 workers at 20% rather than 8 workers at 80% being throttled.
+func cpuWorkers(intensity, procs int, quota float64) (int, int) {
+	available := float64(procs)
+	if quota > 0 && quota < available {
+		available = quota
+	}
+
+	perWorker := int(math.Round(float64(intensity) * available / float64(procs)))
+	if perWorker < 1 {
+		perWorker = 1
+	}
+	return procs, perWorker
+}
+
+// runBursts alternates On windows of busy work with Off idle windows until ctx is done.
+// phase, if set, is called at the start of each window (true = on).
+func (cb *CPUBehavior) runBursts(ctx context.Context, phase func(on bool)) {
+	for {
+		if phase != nil {
+			phase(true)
+		}
+		endLoad := beginLoad()
+		cb.burn(ctx, cb.On)
+		endLoad()
+
+		if phase != nil {
+			phase(false)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cb.Off):
+		}
+	}
+}
+
+// burnCPU keeps one core busy at the given intensity for duration, or until ctx is done
+func burnCPU(ctx context.Context, intensity int, duration time.Duration) {
+	deadline := time.Now().Add(duration)
+
+	// Calculate work duration based on intensity
+	// intensity = 80 means 80% busy, 20% idle
+	workDuration := time.Duration(float64(intensity) / 100.0 * float64(10*time.Millisecond))
+	idleDuration := 10*time.Millisecond - workDuration
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			// Do CPU-intensive work
+			start := time.Now()
+			for time.Since(start) < workDuration {
+				// Busy loop - consume CPU
+				_ = math.Sqrt(rand.Float64())
+			}
+
+			// Idle period
+			if idleDuration > 0 {
+				time.Sleep(idleDuration)
 			}
 		}
-	}()
+	}
 }
 
 func init() {
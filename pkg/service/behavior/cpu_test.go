@@ -1,6 +1,9 @@
 package behavior
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -44,6 +47,76 @@ func TestParseCPU(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "cpu burst",
+			input:     "cpu=burst:80:2s:on:10s:off",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.CPU == nil {
+					t.Fatal("expected cpu behavior")
+				}
+				if b.CPU.Pattern != "burst" {
+					t.Errorf("expected burst pattern, got %s", b.CPU.Pattern)
+				}
+				if b.CPU.Intensity != 80 {
+					t.Errorf("expected intensity 80, got %d", b.CPU.Intensity)
+				}
+				if b.CPU.On != 2*time.Second || b.CPU.Off != 10*time.Second {
+					t.Errorf("expected 2s on / 10s off, got %v on / %v off", b.CPU.On, b.CPU.Off)
+				}
+			},
+		},
+		{
+			name:      "cpu burst missing off window",
+			input:     "cpu=burst:80:2s:on",
+			wantError: true,
+		},
+		{
+			name:      "cpu burst swapped markers",
+			input:     "cpu=burst:80:2s:off:10s:on",
+			wantError: true,
+		},
+		{
+			name:      "cpu burst intensity out of range",
+			input:     "cpu=burst:150:2s:on:10s:off",
+			wantError: true,
+		},
+		{
+			name:      "cpu burst zero off window",
+			input:     "cpu=burst:80:2s:on:0s:off",
+			wantError: true,
+		},
+		{
+			name:  "cpu spike on all cores",
+			input: "cpu=spike:5s:80:cores=all",
+			validate: func(t *testing.T, b *Behavior) {
+				if b.CPU.Pattern != "spike" || b.CPU.Duration != 5*time.Second || b.CPU.Intensity != 80 {
+					t.Errorf("expected spike:5s:80, got %+v", b.CPU)
+				}
+				if b.CPU.Cores != allCores {
+					t.Errorf("expected all cores, got %d", b.CPU.Cores)
+				}
+			},
+		},
+		{
+			name:  "cpu burst on fixed cores",
+			input: "cpu=burst:80:2s:on:10s:off:cores=4",
+			validate: func(t *testing.T, b *Behavior) {
+				if b.CPU.Pattern != "burst" || b.CPU.Cores != 4 {
+					t.Errorf("expected burst on 4 cores, got %+v", b.CPU)
+				}
+			},
+		},
+		{
+			name:      "cpu zero cores",
+			input:     "cpu=spike:5s:80:cores=0",
+			wantError: true,
+		},
+		{
+			name:      "cpu invalid cores",
+			input:     "cpu=spike:5s:80:cores=many",
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,3 +145,151 @@ func TestCPUString(t *testing.T) {
 	}
 }
 
+func TestCPUBurstString(t *testing.T) {
+	b, err := Parse("cpu=burst:80:2s:on:10s:off")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "cpu=burst:80:2s:on:10s:off" {
+		t.Errorf("String() = %s, want cpu=burst:80:2s:on:10s:off", result)
+	}
+}
+
+func TestCPUCoresString(t *testing.T) {
+	for _, input := range []string{"cpu=spike:5s:80:cores=all", "cpu=steady:10s:50:cores=2", "cpu=burst:80:2s:on:10s:off:cores=all"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", input, err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestCPUWorkers(t *testing.T) {
+	tests := []struct {
+		name          string
+		intensity     int
+		procs         int
+		quota         float64
+		wantWorkers   int
+		wantIntensity int
+	}{
+		{name: "no quota", intensity: 80, procs: 4, quota: 0, wantWorkers: 4, wantIntensity: 80},
+		{name: "quota above procs", intensity: 80, procs: 4, quota: 8, wantWorkers: 4, wantIntensity: 80},
+		{name: "quota below procs", intensity: 80, procs: 8, quota: 2, wantWorkers: 8, wantIntensity: 20},
+		{name: "fractional quota", intensity: 100, procs: 4, quota: 1.5, wantWorkers: 4, wantIntensity: 38},
+		{name: "tiny quota", intensity: 10, procs: 16, quota: 0.1, wantWorkers: 16, wantIntensity: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workers, intensity := cpuWorkers(tt.intensity, tt.procs, tt.quota)
+			if workers != tt.wantWorkers || intensity != tt.wantIntensity {
+				t.Errorf("cpuWorkers() = %d workers at %d%%, want %d at %d%%", workers, intensity, tt.wantWorkers, tt.wantIntensity)
+			}
+		})
+	}
+}
+
+func TestReadCPUQuota(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string // path relative to the cgroup root -> contents
+		wantCores float64
+		wantError bool
+	}{
+		{name: "cgroup v2 limit", files: map[string]string{"cpu.max": "200000 100000\n"}, wantCores: 2},
+		{name: "cgroup v2 fractional limit", files: map[string]string{"cpu.max": "50000 100000\n"}, wantCores: 0.5},
+		{name: "cgroup v2 unlimited", files: map[string]string{"cpu.max": "max 100000\n"}, wantError: true},
+		{name: "cgroup v1 limit", files: map[string]string{"cpu/cpu.cfs_quota_us": "150000\n", "cpu/cpu.cfs_period_us": "100000\n"}, wantCores: 1.5},
+		{name: "cgroup v1 unlimited", files: map[string]string{"cpu/cpu.cfs_quota_us": "-1\n", "cpu/cpu.cfs_period_us": "100000\n"}, wantError: true},
+		{name: "cgroup v1 missing period", files: map[string]string{"cpu/cpu.cfs_quota_us": "150000\n"}, wantError: true},
+		{name: "no cgroup files", files: map[string]string{}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, contents := range tt.files {
+				full := filepath.Join(root, path)
+				if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(full, []byte(contents), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cores, err := readCPUQuota(root)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("readCPUQuota() error = %v, wantError %v", err, tt.wantError)
+			}
+			if !tt.wantError && cores != tt.wantCores {
+				t.Errorf("readCPUQuota() = %v, want %v", cores, tt.wantCores)
+			}
+		})
+	}
+}
+
+func TestCPUBurn_FixedCores(t *testing.T) {
+	cb := &CPUBehavior{Pattern: "spike", Intensity: 50, Cores: 2}
+
+	start := time.Now()
+	cb.burn(context.Background(), 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("expected burn to last ~50ms across workers, took %v", elapsed)
+	}
+}
+
+func TestCPUBurstCycles(t *testing.T) {
+	b, err := Parse("cpu=burst:50:60ms:on:40ms:off")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	type transition struct {
+		on bool
+		at time.Time
+	}
+	transitions := make(chan transition, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		b.CPU.runBursts(ctx, func(on bool) {
+			transitions <- transition{on: on, at: time.Now()}
+		})
+	}()
+
+	// Two full on/off cycles
+	var seen []transition
+	for len(seen) < 5 {
+		select {
+		case tr := <-transitions:
+			seen = append(seen, tr)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for burst cycles, saw %d transitions", len(seen))
+		}
+	}
+	cancel()
+	<-done
+
+	for i, tr := range seen {
+		if want := i%2 == 0; tr.on != want {
+			t.Fatalf("transition %d: on = %v, want %v", i, tr.on, want)
+		}
+		if i == 0 {
+			continue
+		}
+		window := tr.at.Sub(seen[i-1].at)
+		expected := 60 * time.Millisecond
+		if !seen[i-1].on {
+			expected = 40 * time.Millisecond
+		}
+		if window < expected || window > expected+50*time.Millisecond {
+			t.Errorf("window %d lasted %v, want ~%v", i, window, expected)
+		}
+	}
+}
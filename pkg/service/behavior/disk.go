@@ -3,11 +3,12 @@ package behavior
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
 )
 
 // DiskBehavior controls disk space allocation
@@ -75,7 +76,7 @@ func (b *Behavior) ApplyDisk(ctx context.Context, traceID string) error {
 	}
 
 	// Generate unique filename with trace ID
-	filename := generateDiskFillFilename(b.Disk.Path, traceID)
+	filename := generateDiskFillFilename(b.Disk.Path, traceID, b.Random())
 
 	// Create and fill file synchronously to detect errors before returning
 	if err := createDiskFillFile(filename, b.Disk.Size); err != nil {
@@ -83,7 +84,10 @@ func (b *Behavior) ApplyDisk(ctx context.Context, traceID string) error {
 	}
 
 	// File created successfully, now hold it in background
+	recordResource(ResourceDisk, b.Disk.Size)
 	go func() {
+		defer recordResource(ResourceDisk, -b.Disk.Size)
+
 		// Hold for duration
 		select {
 		case <-ctx.Done():
@@ -101,9 +105,9 @@ func (b *Behavior) ApplyDisk(ctx context.Context, traceID string) error {
 
 // generateDiskFillFilename creates a unique filename for disk fill
 // Format: .testservice-fill-<traceID>-<random>.dat
-func generateDiskFillFilename(path, traceID string) string {
+func generateDiskFillFilename(path, traceID string, rnd *service.Rand) string {
 	// Generate random suffix (8 hex chars)
-	randSuffix := fmt.Sprintf("%08x", rand.Uint32())
+	randSuffix := fmt.Sprintf("%08x", rnd.Uint32())
 
 	// Truncate trace ID if needed (use last 16 chars for readability)
 	shortTraceID := traceID
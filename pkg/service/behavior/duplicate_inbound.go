@@ -0,0 +1,50 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DuplicateInboundBehavior processes a fraction of requests twice, simulating a
+// client retry the server failed to deduplicate
+type DuplicateInboundBehavior struct {
+	Prob float64 // Probability (0.0-1.0) a request is processed twice
+}
+
+// String returns the string representation of duplicate inbound behavior
+func (db *DuplicateInboundBehavior) String() string {
+	return fmt.Sprintf("duplicate-inbound=%v", db.Prob)
+}
+
+// parseDuplicateInbound parses duplicate inbound specifications
+// Examples: "0.2", "1"
+func parseDuplicateInbound(value string) (*DuplicateInboundBehavior, error) {
+	prob, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	if prob < 0 || prob > 1 {
+		return nil, fmt.Errorf("probability must be between 0 and 1")
+	}
+	return &DuplicateInboundBehavior{Prob: prob}, nil
+}
+
+// ShouldDuplicateInbound determines if the request's upstream fan-out should run twice
+func (b *Behavior) ShouldDuplicateInbound() bool {
+	if b == nil || b.DuplicateInbound == nil {
+		return false
+	}
+
+	return b.Random().Float64() < b.DuplicateInbound.Prob
+}
+
+func init() {
+	registerParser("duplicate-inbound", func(b *Behavior, value string) error {
+		duplicate, err := parseDuplicateInbound(value)
+		if err != nil {
+			return fmt.Errorf("invalid duplicate-inbound: %w", err)
+		}
+		b.DuplicateInbound = duplicate
+		return nil
+	})
+}
@@ -0,0 +1,60 @@
+package behavior
+
+import (
+	"testing"
+)
+
+func TestParseDuplicateInbound(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantProb  float64
+	}{
+		{name: "fraction", input: "duplicate-inbound=0.2", wantProb: 0.2},
+		{name: "always", input: "duplicate-inbound=1", wantProb: 1},
+		{name: "out of range", input: "duplicate-inbound=2", wantError: true},
+		{name: "not a number", input: "duplicate-inbound=sometimes", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && b.DuplicateInbound.Prob != tt.wantProb {
+				t.Errorf("Prob = %v, want %v", b.DuplicateInbound.Prob, tt.wantProb)
+			}
+		})
+	}
+}
+
+func TestDuplicateInboundString(t *testing.T) {
+	b, err := Parse("duplicate-inbound=0.2")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "duplicate-inbound=0.2" {
+		t.Errorf("String() = %s, want duplicate-inbound=0.2", result)
+	}
+}
+
+func TestShouldDuplicateInbound(t *testing.T) {
+	always, _ := Parse("duplicate-inbound=1")
+	never, _ := Parse("duplicate-inbound=0")
+	for i := 0; i < 100; i++ {
+		if !always.ShouldDuplicateInbound() {
+			t.Fatal("expected duplicate-inbound=1 to always duplicate")
+		}
+		if never.ShouldDuplicateInbound() {
+			t.Fatal("expected duplicate-inbound=0 to never duplicate")
+		}
+	}
+
+	var b *Behavior
+	if b.ShouldDuplicateInbound() {
+		t.Error("expected nil behavior not to duplicate")
+	}
+}
@@ -0,0 +1,41 @@
+package behavior
+
+import (
+	"fmt"
+)
+
+// EchoBehavior reports a digest of the request body in the response, so payload
+// integrity can be checked through gateways and proxies that transform bodies
+type EchoBehavior struct {
+	Digest string // Digest algorithm, "sha256"
+}
+
+// String returns the string representation of echo behavior
+func (eb *EchoBehavior) String() string {
+	return fmt.Sprintf("echo=%s", eb.Digest)
+}
+
+// parseEcho parses echo specifications
+// Example: "sha256"
+func parseEcho(value string) (*EchoBehavior, error) {
+	if value != "sha256" {
+		return nil, fmt.Errorf("unknown digest %q (expected sha256)", value)
+	}
+	return &EchoBehavior{Digest: value}, nil
+}
+
+// EchoesDigest reports whether the response should include the request body's SHA-256
+func (b *Behavior) EchoesDigest() bool {
+	return b != nil && b.Echo != nil
+}
+
+func init() {
+	registerParser("echo", func(b *Behavior, value string) error {
+		eb, err := parseEcho(value)
+		if err != nil {
+			return fmt.Errorf("invalid echo: %w", err)
+		}
+		b.Echo = eb
+		return nil
+	})
+}
@@ -0,0 +1,49 @@
+package behavior
+
+import "testing"
+
+func TestParseEcho(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{name: "sha256", input: "echo=sha256"},
+		{name: "unknown digest", input: "echo=md5", wantError: true},
+		{name: "empty", input: "echo=", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && !b.EchoesDigest() {
+				t.Error("expected EchoesDigest() to be true")
+			}
+		})
+	}
+}
+
+func TestEchoString(t *testing.T) {
+	input := "echo=sha256"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestEchoesDigest_NoBehavior(t *testing.T) {
+	var b *Behavior
+	if b.EchoesDigest() {
+		t.Error("expected nil behavior not to echo a digest")
+	}
+	if (&Behavior{}).EchoesDigest() {
+		t.Error("expected empty behavior not to echo a digest")
+	}
+}
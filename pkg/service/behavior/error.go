@@ -1,20 +1,33 @@
 package behavior
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"fmt"
-	"math/rand"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // ErrorBehavior controls error injection
 type ErrorBehavior struct {
-	Rate int     // HTTP status code to return
-	Prob float64 // Probability (0.0-1.0)
+	Rate       int     // HTTP status code to return
+	Prob       float64 // Probability (0.0-1.0)
+	Correlated bool    // Decide by trace ID hash so the same request fails at every service
+
+	Every int64         // Every Nth request opens an error window (0 = probabilistic errors)
+	For   time.Duration // How long each window fails all requests
 }
 
 // String returns the string representation of error behavior
 func (eb *ErrorBehavior) String() string {
+	if eb.Every > 0 {
+		return fmt.Sprintf("error=%d:every:%d:for:%s", eb.Rate, eb.Every, eb.For)
+	}
+	if eb.Correlated {
+		return fmt.Sprintf("error=%d:%v:correlated", eb.Rate, eb.Prob)
+	}
 	// Always include rate when prob < 1.0, omit when prob is 1.0 and rate is 500
 	if eb.Prob < 1.0 || eb.Rate != 500 {
 		return fmt.Sprintf("error=%d:%v", eb.Rate, eb.Prob)
@@ -23,24 +36,32 @@ func (eb *ErrorBehavior) String() string {
 }
 
 // parseError parses error injection specifications
-// Examples: "503", "0.1", "503:0.1"
+// Examples: "503", "0.1", "503:0.1", "503:0.3:correlated", "503:every:100:for:10s"
 func parseError(value string) (*ErrorBehavior, error) {
 	eb := &ErrorBehavior{
 		Rate: 500, // Default error code
 		Prob: 0.0,
 	}
 
+	if parts := strings.Split(value, ":"); len(parts) > 1 && parts[1] == "every" {
+		return parseErrorBurst(parts)
+	}
+
 	if strings.Contains(value, ":") {
-		// Code and probability: "503:0.1"
+		// Code and probability: "503:0.1", optionally "503:0.1:correlated"
 		parts := strings.Split(value, ":")
+		if len(parts) == 3 && parts[2] == "correlated" {
+			eb.Correlated = true
+			parts = parts[:2]
+		}
 		if len(parts) != 2 {
 			return nil, fmt.Errorf("invalid error format")
 		}
-		code, err := strconv.Atoi(parts[0])
+		code, err := parseErrorCode(parts[0])
 		if err != nil {
 			return nil, err
 		}
-		prob, err := strconv.ParseFloat(parts[1], 64)
+		prob, err := parseErrorProb(parts[1])
 		if err != nil {
 			return nil, err
 		}
@@ -50,14 +71,14 @@ func parseError(value string) (*ErrorBehavior, error) {
 		// Just probability or just code
 		if strings.Contains(value, ".") {
 			// Probability
-			prob, err := strconv.ParseFloat(value, 64)
+			prob, err := parseErrorProb(value)
 			if err != nil {
 				return nil, err
 			}
 			eb.Prob = prob
 		} else {
 			// HTTP code with 100% probability
-			code, err := strconv.Atoi(value)
+			code, err := parseErrorCode(value)
 			if err != nil {
 				return nil, err
 			}
@@ -69,19 +90,130 @@ func parseError(value string) (*ErrorBehavior, error) {
 	return eb, nil
 }
 
+// parseErrorCode parses the status code of an error specification, which is written
+// as the HTTP response status and so must be a valid one
+func parseErrorCode(value string) (int, error) {
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid status code: %w", err)
+	}
+	return code, checkErrorCode(code)
+}
+
+// checkErrorCode checks that code is a valid HTTP response status
+func checkErrorCode(code int) error {
+	if code < 100 || code > 599 {
+		return fmt.Errorf("status code must be between 100 and 599, got %d", code)
+	}
+	return nil
+}
+
+// parseErrorProb parses the probability of an error specification
+func parseErrorProb(value string) (float64, error) {
+	prob, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid probability: %w", err)
+	}
+	return prob, checkErrorProb(prob)
+}
+
+// checkErrorProb checks that prob is a probability
+func checkErrorProb(prob float64) error {
+	if prob < 0 || prob > 1 {
+		return fmt.Errorf("probability must be between 0 and 1, got %v", prob)
+	}
+	return nil
+}
+
+// parseErrorBurst parses the count-triggered error window form
+// Format: code:every:N:for:duration
+func parseErrorBurst(parts []string) (*ErrorBehavior, error) {
+	if len(parts) != 5 || parts[3] != "for" {
+		return nil, fmt.Errorf("invalid error format (expected code:every:N:for:duration)")
+	}
+	code, err := parseErrorCode(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	every, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid every: %w", err)
+	}
+	if every < 1 {
+		return nil, fmt.Errorf("every must be at least 1, got %d", every)
+	}
+	window, err := time.ParseDuration(parts[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid for: %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("for must be positive")
+	}
+	return &ErrorBehavior{Rate: code, Prob: 1.0, Every: every, For: window}, nil
+}
+
+// errorBurst tracks the request count and open error window of an every:N:for error
+type errorBurst struct {
+	mu    sync.Mutex
+	count int64
+	until time.Time
+}
+
+// ShouldErrorBurst counts this request against the service's every:N:for error and reports
+// whether it fails. Requests inside an open window fail without being counted; the Nth
+// request counted outside a window opens a new one.
+func (b *Behavior) ShouldErrorBurst(serviceName string, now time.Time) (bool, int) {
+	if b.Error == nil || b.Error.Every == 0 {
+		return false, 0
+	}
+
+	burst := loadState(serviceName+"/"+b.Error.String(), func() *errorBurst { return &errorBurst{} })
+	burst.mu.Lock()
+	defer burst.mu.Unlock()
+
+	if now.Before(burst.until) {
+		return true, b.Error.Rate
+	}
+	burst.count++
+	if burst.count%b.Error.Every != 0 {
+		return false, 0
+	}
+	burst.until = now.Add(b.Error.For)
+	return true, b.Error.Rate
+}
+
 // ShouldError determines if an error should be injected
 func (b *Behavior) ShouldError() (bool, int) {
-	if b.Error == nil {
+	return b.ShouldErrorForTrace("")
+}
+
+// ShouldErrorForTrace determines if an error should be injected for the given trace.
+// Correlated errors derive the decision from the trace ID so it is reproducible and
+// consistent across every service in the call tree; otherwise the roll is random.
+// every:N:for errors are decided by ShouldErrorBurst instead.
+func (b *Behavior) ShouldErrorForTrace(traceID string) (bool, int) {
+	if b.Error == nil || b.Error.Every > 0 {
 		return false, 0
 	}
 
-	if rand.Float64() < b.Error.Prob {
+	roll := b.Random().Float64()
+	if b.Error.Correlated && traceID != "" {
+		roll = traceRoll(traceID)
+	}
+
+	if roll < b.Error.Prob {
 		return true, b.Error.Rate
 	}
 
 	return false, 0
 }
 
+// traceRoll maps a trace ID to a deterministic value in [0.0, 1.0)
+func traceRoll(traceID string) float64 {
+	sum := sha256.Sum256([]byte(traceID))
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
+
 func init() {
 	registerParser("error", func(b *Behavior, value string) error {
 		errorBehavior, err := parseError(value)
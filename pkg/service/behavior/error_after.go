@@ -0,0 +1,74 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrorAfterBehavior serves the first N requests successfully and fails every
+// request after that, modelling a resource that degrades until exhausted
+type ErrorAfterBehavior struct {
+	Count int64 // Number of requests served before erroring
+	Code  int   // HTTP status code to return once exhausted
+}
+
+// String returns the string representation of error-after behavior
+func (ea *ErrorAfterBehavior) String() string {
+	return fmt.Sprintf("error-after=%d:%d", ea.Count, ea.Code)
+}
+
+// parseErrorAfter parses error-after specifications
+// Format: count:code
+// Examples: "100:503", "1000:500"
+func parseErrorAfter(value string) (*ErrorAfterBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid format: %s (expected count:code)", value)
+	}
+
+	count, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid count: %w", err)
+	}
+	if count < 0 {
+		return nil, fmt.Errorf("count cannot be negative, got %d", count)
+	}
+
+	code, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+	if code < 400 || code > 599 {
+		return nil, fmt.Errorf("status code must be between 400 and 599, got %d", code)
+	}
+
+	return &ErrorAfterBehavior{Count: count, Code: code}, nil
+}
+
+// ShouldErrorAfter counts this request against the service's counter for the
+// error code and reports whether the first N requests have already been served
+func (b *Behavior) ShouldErrorAfter(serviceName string) (bool, int) {
+	if b.ErrorAfter == nil {
+		return false, 0
+	}
+
+	key := fmt.Sprintf("%s/error-after/%d", serviceName, b.ErrorAfter.Code)
+	counter := loadState(key, func() *atomic.Int64 { return &atomic.Int64{} })
+	if counter.Add(1) <= b.ErrorAfter.Count {
+		return false, 0
+	}
+	return true, b.ErrorAfter.Code
+}
+
+func init() {
+	registerParser("error-after", func(b *Behavior, value string) error {
+		ea, err := parseErrorAfter(value)
+		if err != nil {
+			return fmt.Errorf("invalid error-after: %w", err)
+		}
+		b.ErrorAfter = ea
+		return nil
+	})
+}
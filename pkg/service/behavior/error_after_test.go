@@ -0,0 +1,100 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseErrorAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		want      ErrorAfterBehavior
+	}{
+		{name: "valid", input: "error-after=100:503", want: ErrorAfterBehavior{Count: 100, Code: 503}},
+		{name: "fail immediately", input: "error-after=0:500", want: ErrorAfterBehavior{Count: 0, Code: 500}},
+		{name: "missing code", input: "error-after=100", wantError: true},
+		{name: "negative count", input: "error-after=-1:503", wantError: true},
+		{name: "invalid count", input: "error-after=many:503", wantError: true},
+		{name: "invalid code", input: "error-after=100:abc", wantError: true},
+		{name: "success code", input: "error-after=100:200", wantError: true},
+		{name: "too many fields", input: "error-after=100:503:0.5", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && *b.ErrorAfter != tt.want {
+				t.Errorf("ErrorAfter = %+v, want %+v", *b.ErrorAfter, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorAfterString(t *testing.T) {
+	input := "error-after=100:503"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestShouldErrorAfter(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("error-after=3:503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// The first 3 requests succeed
+	for i := 1; i <= 3; i++ {
+		if shouldErr, _ := b.ShouldErrorAfter("api"); shouldErr {
+			t.Fatalf("expected request %d to succeed", i)
+		}
+	}
+
+	// The 4th and every later request fail
+	for i := 4; i <= 6; i++ {
+		if shouldErr, code := b.ShouldErrorAfter("api"); !shouldErr || code != 503 {
+			t.Fatalf("expected request %d to fail with 503, got %v %d", i, shouldErr, code)
+		}
+	}
+
+	// Other services count separately
+	if shouldErr, _ := b.ShouldErrorAfter("orders"); shouldErr {
+		t.Error("expected the first request to another service to succeed")
+	}
+}
+
+func TestExecutor_ErrorAfter(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("error-after=1:500")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	executor := NewExecutor(b, "trace123", "api", &mockTelemetry{})
+	if result, err := executor.Execute(context.Background()); err != nil || (result != nil && result.ShouldReturn) {
+		t.Fatalf("expected the first request to proceed, got %+v, %v", result, err)
+	}
+
+	result, err := executor.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result == nil || !result.ShouldReturn || result.StatusCode != 500 || result.BehaviorType != "error-after" {
+		t.Errorf("expected 500 error-after, got %+v", result)
+	}
+}
@@ -0,0 +1,89 @@
+package behavior
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ErrorOnPodBehavior injects errors only on the replica with a given StatefulSet ordinal
+type ErrorOnPodBehavior struct {
+	Ordinal int     // Pod ordinal that fails (parsed from the POD_NAME suffix)
+	Code    int     // HTTP status code to return
+	Prob    float64 // Probability (0.0-1.0) on the matching pod
+}
+
+// String returns the string representation of error-on-pod behavior
+func (ep *ErrorOnPodBehavior) String() string {
+	return fmt.Sprintf("error-on-pod=%d:%d:%v", ep.Ordinal, ep.Code, ep.Prob)
+}
+
+// parseErrorOnPod parses error-on-pod specifications
+// Format: ordinal:code[:prob]
+// Examples: "2:503", "2:503:1.0", "0:500:0.5"
+func parseErrorOnPod(value string) (*ErrorOnPodBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid format: %s (expected ordinal:code[:prob])", value)
+	}
+
+	ordinal, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid ordinal: %w", err)
+	}
+	if ordinal < 0 {
+		return nil, fmt.Errorf("ordinal cannot be negative, got %d", ordinal)
+	}
+
+	code, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+	if code < 100 || code > 599 {
+		return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+	}
+
+	ep := &ErrorOnPodBehavior{Ordinal: ordinal, Code: code, Prob: 1.0}
+	if len(parts) == 3 {
+		prob, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probability: %w", err)
+		}
+		if prob < 0 || prob > 1 {
+			return nil, fmt.Errorf("probability must be between 0 and 1, got %v", prob)
+		}
+		ep.Prob = prob
+	}
+
+	return ep, nil
+}
+
+// errorOnPod determines if the pod with the given name should inject the error, rolling with rnd
+func (ep *ErrorOnPodBehavior) errorOnPod(podName string, rnd *service.Rand) bool {
+	if podOrdinal(podName) != ep.Ordinal {
+		return false
+	}
+	return rnd.Float64() < ep.Prob
+}
+
+// ShouldErrorOnPod determines if this pod (identified by POD_NAME) should inject an error
+func (b *Behavior) ShouldErrorOnPod() (bool, int) {
+	if b.ErrorOnPod == nil || !b.ErrorOnPod.errorOnPod(os.Getenv("POD_NAME"), b.Random()) {
+		return false, 0
+	}
+	return true, b.ErrorOnPod.Code
+}
+
+func init() {
+	registerParser("error-on-pod", func(b *Behavior, value string) error {
+		errorOnPod, err := parseErrorOnPod(value)
+		if err != nil {
+			return fmt.Errorf("invalid error-on-pod: %w", err)
+		}
+		b.ErrorOnPod = errorOnPod
+		return nil
+	})
+}
@@ -0,0 +1,93 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseErrorOnPod(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		want      ErrorOnPodBehavior
+	}{
+		{name: "full", input: "error-on-pod=2:503:1.0", want: ErrorOnPodBehavior{Ordinal: 2, Code: 503, Prob: 1.0}},
+		{name: "default probability", input: "error-on-pod=0:500", want: ErrorOnPodBehavior{Ordinal: 0, Code: 500, Prob: 1.0}},
+		{name: "partial probability", input: "error-on-pod=1:503:0.5", want: ErrorOnPodBehavior{Ordinal: 1, Code: 503, Prob: 0.5}},
+		{name: "missing code", input: "error-on-pod=2", wantError: true},
+		{name: "negative ordinal", input: "error-on-pod=-1:503", wantError: true},
+		{name: "invalid code", input: "error-on-pod=2:abc", wantError: true},
+		{name: "code out of range", input: "error-on-pod=2:42", wantError: true},
+		{name: "probability out of range", input: "error-on-pod=2:503:1.5", wantError: true},
+		{name: "too many fields", input: "error-on-pod=2:503:1.0:x", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && *b.ErrorOnPod != tt.want {
+				t.Errorf("ErrorOnPod = %+v, want %+v", *b.ErrorOnPod, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorOnPodString(t *testing.T) {
+	b, err := Parse("error-on-pod=2:503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "error-on-pod=2:503:1" {
+		t.Errorf("String() = %s, want error-on-pod=2:503:1", result)
+	}
+}
+
+func TestExecutor_ErrorOnPod(t *testing.T) {
+	tests := []struct {
+		name     string
+		podName  string
+		wantCode int // 0 = request proceeds
+	}{
+		{name: "matching ordinal", podName: "db-2", wantCode: 503},
+		{name: "other ordinal", podName: "db-1", wantCode: 0},
+		{name: "ordinal with same suffix digit", podName: "db-12", wantCode: 0},
+		{name: "deployment pod name", podName: "api-7d9f8b6c4-x2k9q", wantCode: 0},
+		{name: "no pod name", podName: "", wantCode: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAME", tt.podName)
+			b, err := Parse("error-on-pod=2:503:1.0")
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			result, err := NewExecutor(b, "trace123", "db", &mockTelemetry{}).Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if tt.wantCode == 0 {
+				if result != nil {
+					t.Errorf("expected request to proceed, got %+v", result)
+				}
+				return
+			}
+			if result == nil || !result.ShouldReturn {
+				t.Fatalf("expected early return, got %+v", result)
+			}
+			if result.StatusCode != tt.wantCode {
+				t.Errorf("StatusCode = %d, want %d", result.StatusCode, tt.wantCode)
+			}
+			if result.BehaviorType != "error-on-pod" {
+				t.Errorf("BehaviorType = %q, want error-on-pod", result.BehaviorType)
+			}
+		})
+	}
+}
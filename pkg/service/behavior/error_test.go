@@ -1,7 +1,9 @@
 package behavior
 
 import (
+	"fmt"
 	"testing"
+	"time"
 )
 
 func TestParseError(t *testing.T) {
@@ -59,6 +61,109 @@ func TestParseError(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "correlated error",
+			input:     "error=503:0.3:correlated",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if !b.Error.Correlated {
+					t.Error("expected correlated error")
+				}
+				if b.Error.Rate != 503 || b.Error.Prob != 0.3 {
+					t.Errorf("expected 503:0.3, got %d:%v", b.Error.Rate, b.Error.Prob)
+				}
+			},
+		},
+		{
+			name:      "unknown error modifier",
+			input:     "error=503:0.3:sometimes",
+			wantError: true,
+		},
+		{
+			name:      "error window every N requests",
+			input:     "error=503:every:100:for:10s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Error.Rate != 503 || b.Error.Every != 100 || b.Error.For != 10*time.Second {
+					t.Errorf("expected 503 every 100 for 10s, got %+v", b.Error)
+				}
+			},
+		},
+		{
+			name:      "error window missing duration",
+			input:     "error=503:every:100",
+			wantError: true,
+		},
+		{
+			name:      "error window zero count",
+			input:     "error=503:every:0:for:10s",
+			wantError: true,
+		},
+		{
+			name:      "error window invalid duration",
+			input:     "error=503:every:100:for:soon",
+			wantError: true,
+		},
+		{
+			name:      "code below range",
+			input:     "error=99",
+			wantError: true,
+		},
+		{
+			name:      "code above range",
+			input:     "error=700",
+			wantError: true,
+		},
+		{
+			name:      "code out of range with probability",
+			input:     "error=700:0.5",
+			wantError: true,
+		},
+		{
+			name:      "error window code out of range",
+			input:     "error=42:every:100:for:10s",
+			wantError: true,
+		},
+		{
+			name:      "probability above one",
+			input:     "error=1.5",
+			wantError: true,
+		},
+		{
+			name:      "negative probability",
+			input:     "error=-0.1",
+			wantError: true,
+		},
+		{
+			name:      "probability out of range with code",
+			input:     "error=503:2",
+			wantError: true,
+		},
+		{
+			name:      "code not a number",
+			input:     "error=abc",
+			wantError: true,
+		},
+		{
+			name:      "lowest valid code",
+			input:     "error=100",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Error.Rate != 100 || b.Error.Prob != 1.0 {
+					t.Errorf("expected code 100 with prob 1.0, got %d with %v", b.Error.Rate, b.Error.Prob)
+				}
+			},
+		},
+		{
+			name:      "probability bounds",
+			input:     "error=599:0",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Error.Rate != 599 || b.Error.Prob != 0 {
+					t.Errorf("expected code 599 with prob 0, got %d with %v", b.Error.Rate, b.Error.Prob)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -91,6 +196,16 @@ func TestErrorString(t *testing.T) {
 			input:    "error=503:0.5",
 			expected: "error=503:0.5",
 		},
+		{
+			name:     "correlated error",
+			input:    "error=503:0.3:correlated",
+			expected: "error=503:0.3:correlated",
+		},
+		{
+			name:     "error window every N requests",
+			input:    "error=503:every:100:for:10s",
+			expected: "error=503:every:100:for:10s",
+		},
 	}
 
 	for _, tt := range tests {
@@ -165,3 +280,88 @@ func TestShouldError(t *testing.T) {
 	}
 }
 
+
+func TestShouldErrorCorrelated(t *testing.T) {
+	b, err := Parse("error=503:0.3:correlated")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	errorCount := 0
+	for i := 0; i < 1000; i++ {
+		traceID := fmt.Sprintf("%032x", i)
+		first, code := b.ShouldErrorForTrace(traceID)
+
+		// Same trace ID always yields the same decision
+		for j := 0; j < 5; j++ {
+			if again, _ := b.ShouldErrorForTrace(traceID); again != first {
+				t.Fatalf("trace %s: decision changed between evaluations", traceID)
+			}
+		}
+
+		if first {
+			errorCount++
+			if code != 503 {
+				t.Errorf("expected code 503, got %d", code)
+			}
+		}
+	}
+
+	// Decisions across traces still follow the configured probability
+	rate := float64(errorCount) / 1000
+	if rate < 0.2 || rate > 0.4 {
+		t.Errorf("correlated error rate = %v, want ~0.3", rate)
+	}
+}
+
+func TestShouldErrorBurst(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("error=503:every:3:for:10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	now := time.Now()
+	for i := 1; i <= 2; i++ {
+		if shouldErr, _ := b.ShouldErrorBurst("api", now); shouldErr {
+			t.Fatalf("did not expect an error on request %d", i)
+		}
+	}
+
+	// The 3rd request crosses the threshold and opens the window
+	if shouldErr, code := b.ShouldErrorBurst("api", now); !shouldErr || code != 503 {
+		t.Fatalf("expected 3rd request to fail with 503, got %v/%d", shouldErr, code)
+	}
+
+	// Every request fails while the window is open, however many arrive
+	for _, offset := range []time.Duration{time.Second, 5 * time.Second, 9 * time.Second} {
+		for i := 0; i < 5; i++ {
+			if shouldErr, _ := b.ShouldErrorBurst("api", now.Add(offset)); !shouldErr {
+				t.Fatalf("expected request %s into the window to fail", offset)
+			}
+		}
+	}
+
+	// Once the window has passed, the service recovers until the next 3rd request
+	after := now.Add(11 * time.Second)
+	for i := 1; i <= 2; i++ {
+		if shouldErr, _ := b.ShouldErrorBurst("api", after); shouldErr {
+			t.Fatalf("did not expect an error on request %d after recovery", i)
+		}
+	}
+	if shouldErr, _ := b.ShouldErrorBurst("api", after); !shouldErr {
+		t.Error("expected the next 3rd request to open a new window")
+	}
+
+	// Other services count separately
+	if shouldErr, _ := b.ShouldErrorBurst("db", now); shouldErr {
+		t.Error("did not expect an error on another service's first request")
+	}
+
+	// Probabilistic evaluation leaves every:N:for errors alone
+	if shouldErr, _ := b.ShouldErrorForTrace("trace123"); shouldErr {
+		t.Error("expected ShouldErrorForTrace to ignore every:N:for errors")
+	}
+}
@@ -0,0 +1,116 @@
+package behavior
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultErrorWindow is the number of requests per window when none is given
+const defaultErrorWindow = 100
+
+// ErrorWindowBehavior fails an exact share of requests in every window of N requests,
+// spreading the errors evenly so the observed error rate is stable rather than random
+type ErrorWindowBehavior struct {
+	Code   int     // HTTP status code to return
+	Rate   float64 // Share of requests (0.0-1.0) failing in each window
+	Window int64   // Number of requests per window
+}
+
+// String returns the string representation of error-window behavior
+func (ew *ErrorWindowBehavior) String() string {
+	return fmt.Sprintf("error-window=%d:rate:%v:window:%d", ew.Code, ew.Rate, ew.Window)
+}
+
+// Errors returns how many requests fail in each window (rate * window, rounded)
+func (ew *ErrorWindowBehavior) Errors() int64 {
+	return int64(math.Round(ew.Rate * float64(ew.Window)))
+}
+
+// parseErrorWindow parses error-window specifications
+// Format: code:rate:R[:window:N]
+// Examples: "503:rate:0.1", "503:rate:0.1:window:100", "500:window:20:rate:0.25"
+func parseErrorWindow(value string) (*ErrorWindowBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 3 || len(parts)%2 == 0 {
+		return nil, fmt.Errorf("invalid format: %s (expected code:rate:R[:window:N])", value)
+	}
+
+	code, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+	if code < 100 || code > 599 {
+		return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+	}
+
+	ew := &ErrorWindowBehavior{Code: code, Rate: -1, Window: defaultErrorWindow}
+	for i := 1; i < len(parts); i += 2 {
+		key, val := parts[i], parts[i+1]
+		switch key {
+		case "rate":
+			rate, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rate: %w", err)
+			}
+			if rate < 0 || rate > 1 {
+				return nil, fmt.Errorf("rate must be between 0 and 1, got %v", rate)
+			}
+			ew.Rate = rate
+		case "window":
+			window, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid window: %w", err)
+			}
+			if window < 1 {
+				return nil, fmt.Errorf("window must be at least 1, got %d", window)
+			}
+			ew.Window = window
+		default:
+			return nil, fmt.Errorf("unknown key %q (expected rate or window)", key)
+		}
+	}
+
+	if ew.Rate < 0 {
+		return nil, fmt.Errorf("rate is required")
+	}
+
+	return ew, nil
+}
+
+// failsAt reports whether the request at position n (0-based) of the stream fails.
+// Errors are spread evenly across the window, exactly Errors() per window.
+func (ew *ErrorWindowBehavior) failsAt(n int64) bool {
+	i := n % ew.Window
+	perWindow := ew.Errors()
+	return (i+1)*perWindow/ew.Window > i*perWindow/ew.Window
+}
+
+// ShouldErrorInWindow counts this request against the service's window counter and
+// reports whether it is one of the window's errors
+func (b *Behavior) ShouldErrorInWindow(serviceName string) (bool, int) {
+	if b.ErrorWindow == nil {
+		return false, 0
+	}
+
+	// Keyed by the spec too, so changing rate or window starts a fresh window
+	counter := loadState(serviceName+"/"+b.ErrorWindow.String(), func() *atomic.Int64 { return &atomic.Int64{} })
+	n := counter.Add(1) - 1
+	if !b.ErrorWindow.failsAt(n) {
+		return false, 0
+	}
+	return true, b.ErrorWindow.Code
+}
+
+func init() {
+	registerParser("error-window", func(b *Behavior, value string) error {
+		errorWindow, err := parseErrorWindow(value)
+		if err != nil {
+			return fmt.Errorf("invalid error-window: %w", err)
+		}
+		b.ErrorWindow = errorWindow
+		return nil
+	})
+}
@@ -0,0 +1,135 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseErrorWindow(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantError  bool
+		wantCode   int
+		wantRate   float64
+		wantWindow int64
+	}{
+		{name: "rate and window", input: "error-window=503:rate:0.1:window:100", wantCode: 503, wantRate: 0.1, wantWindow: 100},
+		{name: "default window", input: "error-window=500:rate:0.25", wantCode: 500, wantRate: 0.25, wantWindow: 100},
+		{name: "window first", input: "error-window=503:window:20:rate:0.5", wantCode: 503, wantRate: 0.5, wantWindow: 20},
+		{name: "missing rate", input: "error-window=503:window:100", wantError: true},
+		{name: "rate out of range", input: "error-window=503:rate:1.5", wantError: true},
+		{name: "zero window", input: "error-window=503:rate:0.1:window:0", wantError: true},
+		{name: "invalid code", input: "error-window=700:rate:0.1", wantError: true},
+		{name: "unknown key", input: "error-window=503:ratio:0.1", wantError: true},
+		{name: "dangling key", input: "error-window=503:rate:0.1:window", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			ew := b.ErrorWindow
+			if ew.Code != tt.wantCode || ew.Rate != tt.wantRate || ew.Window != tt.wantWindow {
+				t.Errorf("got %d:%v:%d, want %d:%v:%d", ew.Code, ew.Rate, ew.Window, tt.wantCode, tt.wantRate, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestErrorWindowString(t *testing.T) {
+	b, err := Parse("error-window=503:rate:0.1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "error-window=503:rate:0.1:window:100" {
+		t.Errorf("String() = %s, want error-window=503:rate:0.1:window:100", result)
+	}
+}
+
+func TestExecutor_ErrorWindowExactRate(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("error-window=503:rate:0.1:window:100")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Every window of 100 requests sees exactly 10 errors
+	for window := 0; window < 3; window++ {
+		errors := 0
+		for i := 0; i < 100; i++ {
+			result, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result != nil && result.ShouldReturn {
+				if result.StatusCode != 503 || result.BehaviorType != "error-window" {
+					t.Fatalf("got %d (%s), want 503 (error-window)", result.StatusCode, result.BehaviorType)
+				}
+				errors++
+			}
+		}
+		if errors != 10 {
+			t.Errorf("window %d: got %d errors, want exactly 10", window, errors)
+		}
+	}
+}
+
+func TestErrorWindowSpreadsErrors(t *testing.T) {
+	ew := &ErrorWindowBehavior{Code: 503, Rate: 0.5, Window: 4}
+
+	// Half the requests fail, alternating rather than bunched at the start
+	var got []bool
+	for n := int64(0); n < 8; n++ {
+		got = append(got, ew.failsAt(n))
+	}
+	want := []bool{false, true, false, true, false, true, false, true}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("failsAt sequence = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestShouldErrorInWindowPerService(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b := &Behavior{ErrorWindow: &ErrorWindowBehavior{Code: 500, Rate: 1.0 / 3, Window: 3}}
+
+	count := func(svc string, n int) int {
+		errors := 0
+		for i := 0; i < n; i++ {
+			if shouldErr, _ := b.ShouldErrorInWindow(svc); shouldErr {
+				errors++
+			}
+		}
+		return errors
+	}
+
+	if got := count("svc-a", 2); got != 0 {
+		t.Errorf("svc-a: expected no error in the first 2 requests, got %d", got)
+	}
+	// svc-b has its own window, so its first 2 requests don't complete svc-a's
+	if got := count("svc-b", 2); got != 0 {
+		t.Errorf("svc-b: expected no error in the first 2 requests, got %d", got)
+	}
+	if got := count("svc-a", 1); got != 1 {
+		t.Errorf("svc-a: expected the 3rd request to fail, got %d errors", got)
+	}
+}
+
+func TestShouldErrorInWindowNilBehavior(t *testing.T) {
+	b := &Behavior{}
+	if shouldErr, code := b.ShouldErrorInWindow("api"); shouldErr || code != 0 {
+		t.Errorf("expected no error, got %v/%d", shouldErr, code)
+	}
+}
@@ -3,16 +3,21 @@ package behavior
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 // ExecutionResult indicates what action to take after behavior execution
 type ExecutionResult struct {
-	ShouldReturn bool   // Whether to return early (before calling upstreams)
-	StatusCode   int    // HTTP status code to return
-	ErrorMessage string // Error message for response body
-	BehaviorType string // Type of behavior that triggered the result (for telemetry)
+	ShouldReturn    bool   // Whether to return early (before calling upstreams)
+	StatusCode      int    // HTTP status code to return
+	ErrorMessage    string // Error message for response body
+	BehaviorType    string // Type of behavior that triggered the result (for telemetry)
+	ResetConnection bool   // Abort the connection instead of responding, where the transport allows
+	CloseGracefully bool   // With ResetConnection, close with a FIN instead of a TCP RST
+	Location        string // Location header of a redirect
 }
 
 // TelemetryLogger is the interface for logging warnings
@@ -27,6 +32,14 @@ type Executor struct {
 	traceID     string
 	serviceName string
 	telemetry   TelemetryLogger
+	body        []byte      // Request body, inspected by poison-on
+	host        string      // Request Host (gRPC :authority), compared by sni-mismatch
+	serverName  string      // TLS SNI of the connection (empty for plaintext)
+	headers     http.Header // Request headers, inspected by priority
+
+	lockWait   time.Duration // Time spent queueing for the lock behavior
+	lockWaited bool          // The lock behavior was queued for
+	fdsOpened  int           // File handles opened by the fd-leak behavior
 }
 
 // NewExecutor creates a behavior executor
@@ -39,23 +52,88 @@ func NewExecutor(b *Behavior, traceID string, serviceName string, tel TelemetryL
 	}
 }
 
+// WithRequestBody sets the request body inspected by body-based behaviors
+func (e *Executor) WithRequestBody(body []byte) *Executor {
+	e.body = body
+	return e
+}
+
+// WithHeaders sets the request headers inspected by header-based behaviors
+func (e *Executor) WithHeaders(headers http.Header) *Executor {
+	e.headers = headers
+	return e
+}
+
+// WithTLS sets the request host and TLS SNI inspected by sni-mismatch
+func (e *Executor) WithTLS(host, serverName string) *Executor {
+	e.host = host
+	e.serverName = serverName
+	return e
+}
+
 // Execute runs behaviors in the required order, returning early if needed
 // Execution phases (explicit ordering):
-//  1. Apply non-terminating behaviors (latency/CPU/memory via existing Apply)
+//  1. Apply non-terminating behaviors (latency/CPU/memory/goroutine leaks via existing Apply, then fd leaks),
+//     then stateful cache latency (stampede/single-flight/cache-warmup), load-dependent delays, lock contention and liveness state
 //  2. Disk behavior (returns 507 on failure)
-//  3. Crash-if-file (panics)
-//  4. Error-if-file (returns configured error code)
-//  5. Panic injection (panics)
-//  6. Error injection (returns error code)
+//  3. Crash-if-file and poison-on request body (panic)
+//  4. Error-if-file and request body validation (return configured error code)
+//  5. Panic injection (panics, probabilistic or after N requests)
+//  6. Quorum loss (returns 503), SNI mismatch (returns 421), rolling-restart and connection-reset
+//     connection aborts, per-pod and general error injection (returns error code)
+//     and redirects (returns 3xx)
+//  7. Business KPIs (only counted for requests that were not failed or redirected above)
 func (e *Executor) Execute(ctx context.Context) (*ExecutionResult, error) {
 	if e.behavior == nil {
 		return nil, nil
 	}
 
-	// Phase 1: Apply non-terminating behaviors (latency, CPU, memory)
+	// Phase 1: Apply non-terminating behaviors (latency, CPU, memory, fd/goroutine leaks)
 	if err := e.behavior.Apply(ctx); err != nil {
 		return nil, fmt.Errorf("apply behavior: %w", err)
 	}
+	if e.behavior.FDLeak != nil {
+		e.fdsOpened = e.behavior.applyFDLeak(ctx)
+	}
+
+	// Phase 1b: Cache simulation (stateful latency shared across requests)
+	if err := sleepContext(ctx, e.behavior.stampedeDelay(e.serviceName, time.Now())); err != nil {
+		return nil, fmt.Errorf("stampede: %w", err)
+	}
+	if _, err := e.behavior.applySingleFlight(ctx, e.serviceName); err != nil {
+		return nil, fmt.Errorf("single-flight: %w", err)
+	}
+	if err := sleepContext(ctx, e.behavior.cacheWarmupDelay(e.serviceName)); err != nil {
+		return nil, fmt.Errorf("cache-warmup: %w", err)
+	}
+
+	// Phase 1c: Load-dependent delays (low-priority requests and sidecar overhead under contention)
+	if err := sleepContext(ctx, e.behavior.priorityDelay(e.headers)); err != nil {
+		return nil, fmt.Errorf("priority: %w", err)
+	}
+	if err := sleepContext(ctx, e.behavior.sidecarDelay()); err != nil {
+		return nil, fmt.Errorf("sidecar-overhead: %w", err)
+	}
+
+	// Phase 1d: Lock contention (queue for a slot, then hold it)
+	if e.behavior.Lock != nil {
+		wait, err := e.behavior.applyLock(ctx, e.serviceName)
+		e.lockWait, e.lockWaited = wait, true
+		if err != nil {
+			return nil, fmt.Errorf("lock: %w", err)
+		}
+	}
+
+	// Phase 1e: Liveness (can get the pod restarted, so log before flipping)
+	if e.behavior.Liveness != nil {
+		if !e.behavior.Liveness.Healthy {
+			e.telemetry.Warn("Liveness behavior triggered - /health will fail and kubelet may restart the pod",
+				zap.String("service", e.serviceName),
+				zap.Duration("duration", e.behavior.Liveness.Duration),
+			)
+		}
+		e.behavior.applyLiveness()
+	}
 
 	// Phase 2: Disk behavior (can fail with 507)
 	if e.behavior.Disk != nil {
@@ -91,6 +169,16 @@ func (e *Executor) Execute(ctx context.Context) (*ExecutionResult, error) {
 		)
 	}
 
+	// Phase 3b: Poison message (terminates process, trigger is redacted from logs)
+	if poisoned, matched := e.behavior.ShouldPoison(e.body); poisoned {
+		e.telemetry.Fatal("Request body contains poison trigger - crashing as configured",
+			zap.String("service", e.serviceName),
+			zap.String("matched_content", redact(matched)),
+			zap.Int("body_size", len(e.body)),
+		)
+		panic(fmt.Sprintf("Poison message received in service %s", e.serviceName))
+	}
+
 	// Phase 4: Error-if-file (returns error response)
 	if shouldErr, errCode, matched, msg := e.behavior.ShouldErrorOnFile(); shouldErr {
 		e.telemetry.Warn("File contains invalid content - returning error as configured",
@@ -114,6 +202,16 @@ func (e *Executor) Execute(ctx context.Context) (*ExecutionResult, error) {
 		)
 	}
 
+	// Phase 4b: Request body validation (returns configured error code)
+	if reject, code, reason := e.behavior.ShouldRejectBody(e.body); reject {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   code,
+			ErrorMessage: fmt.Sprintf("Request validation failed: %s", reason),
+			BehaviorType: "require-json-field",
+		}, nil
+	}
+
 	// Phase 5: Panic injection
 	if e.behavior.ShouldPanic() {
 		e.telemetry.Fatal("Panic behavior triggered - crashing pod",
@@ -123,8 +221,93 @@ func (e *Executor) Execute(ctx context.Context) (*ExecutionResult, error) {
 		panic(fmt.Sprintf("Panic behavior triggered in service %s", e.serviceName))
 	}
 
-	// Phase 6: Error injection
-	if shouldErr, errCode := e.behavior.ShouldError(); shouldErr {
+	if shouldPanic, n := e.behavior.ShouldPanicAfter(e.serviceName); shouldPanic {
+		e.telemetry.Fatal("Panic-after request count reached - crashing pod",
+			zap.String("service", e.serviceName),
+			zap.Int64("request_count", n),
+			zap.Int64("panic_after", e.behavior.PanicAfter.Count),
+		)
+		panic(fmt.Sprintf("Panic-after triggered in service %s after %d requests", e.serviceName, n))
+	}
+
+	// Phase 6: Quorum loss, SNI mismatch, rollout resets, per-pod, windowed and general error injection
+	if e.behavior.ShouldRejectNoQuorum() {
+		q := e.behavior.Quorum
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   503,
+			ErrorMessage: fmt.Sprintf("No quorum: %d of %d replicas available (need %d)", q.Available(), q.Replicas, q.Majority()),
+			BehaviorType: "quorum",
+		}, nil
+	}
+
+	if shouldReject, code := e.behavior.ShouldRejectSNI(e.serverName, e.host); shouldReject {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   code,
+			ErrorMessage: fmt.Sprintf("Misdirected request: TLS server name %q does not match the requested host", e.serverName),
+			BehaviorType: "sni-mismatch",
+		}, nil
+	}
+
+	if e.behavior.ShouldResetForRollout(e.serviceName, time.Now()) {
+		return &ExecutionResult{
+			ShouldReturn:    true,
+			StatusCode:      503,
+			ErrorMessage:    "Connection reset: rolling restart in progress",
+			BehaviorType:    "rolling-restart",
+			ResetConnection: true,
+		}, nil
+	}
+
+	if reset, graceful := e.behavior.ShouldResetConnection(); reset {
+		return &ExecutionResult{
+			ShouldReturn:    true,
+			StatusCode:      503,
+			ErrorMessage:    "Connection reset: injected connection-reset",
+			BehaviorType:    "connection-reset",
+			ResetConnection: true,
+			CloseGracefully: graceful,
+		}, nil
+	}
+
+	if shouldErr, errCode := e.behavior.ShouldErrorOnPod(); shouldErr {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   errCode,
+			ErrorMessage: fmt.Sprintf("Injected error on pod %d: %d", e.behavior.ErrorOnPod.Ordinal, errCode),
+			BehaviorType: "error-on-pod",
+		}, nil
+	}
+
+	if shouldErr, errCode := e.behavior.ShouldErrorInWindow(e.serviceName); shouldErr {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   errCode,
+			ErrorMessage: fmt.Sprintf("Injected error: %d", errCode),
+			BehaviorType: "error-window",
+		}, nil
+	}
+
+	if shouldErr, errCode := e.behavior.ShouldErrorAfter(e.serviceName); shouldErr {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   errCode,
+			ErrorMessage: fmt.Sprintf("Injected error after %d requests: %d", e.behavior.ErrorAfter.Count, errCode),
+			BehaviorType: "error-after",
+		}, nil
+	}
+
+	if shouldErr, errCode := e.behavior.ShouldErrorBurst(e.serviceName, time.Now()); shouldErr {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   errCode,
+			ErrorMessage: fmt.Sprintf("Injected error: %d", errCode),
+			BehaviorType: "error",
+		}, nil
+	}
+
+	if shouldErr, errCode := e.behavior.ShouldErrorForTrace(e.traceID); shouldErr {
 		return &ExecutionResult{
 			ShouldReturn: true,
 			StatusCode:   errCode,
@@ -133,9 +316,40 @@ func (e *Executor) Execute(ctx context.Context) (*ExecutionResult, error) {
 		}, nil
 	}
 
+	// Phase 6b: Redirect (returns 3xx with a Location)
+	if e.behavior.Redirect != nil {
+		return &ExecutionResult{
+			ShouldReturn: true,
+			StatusCode:   e.behavior.Redirect.Code,
+			ErrorMessage: fmt.Sprintf("Redirecting to %s", e.behavior.Redirect.Location),
+			BehaviorType: "redirect",
+			Location:     e.behavior.Redirect.Location,
+		}, nil
+	}
+
+	// Phase 7: Business KPIs
+	if err := e.behavior.applyKPI(); err != nil {
+		e.telemetry.Warn("Failed to record kpi",
+			zap.String("service", e.serviceName),
+			zap.Error(err),
+		)
+	}
+
 	return nil, nil
 }
 
+// LockWait returns how long Execute queued for the lock behavior, and false if it
+// didn't get to the lock
+func (e *Executor) LockWait() (time.Duration, bool) {
+	return e.lockWait, e.lockWaited
+}
+
+// FDsOpened returns how many file handles Execute opened for the fd-leak behavior,
+// which is fewer than requested when the fd limit was reached
+func (e *Executor) FDsOpened() int {
+	return e.fdsOpened
+}
+
 // String returns the behavior string for propagation
 func (e *Executor) String() string {
 	if e.behavior == nil {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
 	"go.uber.org/zap"
 )
 
@@ -302,6 +303,67 @@ func TestExecutor_PanicBehavior(t *testing.T) {
 	executor.Execute(context.Background())
 }
 
+func TestExecutor_PanicAfterBehavior(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	tel := &mockTelemetry{}
+	behavior := &Behavior{
+		PanicAfter: &PanicAfterBehavior{Count: 3},
+	}
+
+	// First two requests are served normally
+	for i := 0; i < 2; i++ {
+		executor := NewExecutor(behavior, "trace123", "test-service", tel)
+		if _, err := executor.Execute(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	// Third request panics
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("Expected panic on 3rd request, but did not panic")
+		} else if len(tel.fatals) == 0 {
+			t.Error("Expected Fatal to be called before panic")
+		}
+	}()
+
+	executor := NewExecutor(behavior, "trace123", "test-service", tel)
+	executor.Execute(context.Background())
+}
+
+func TestExecutor_LivenessBehavior(t *testing.T) {
+	defer service.Liveness.SetHealthy()
+	tel := &mockTelemetry{}
+
+	unhealthy := &Behavior{
+		Liveness: &LivenessBehavior{Duration: time.Minute},
+	}
+	executor := NewExecutor(unhealthy, "trace123", "test-service", tel)
+	if _, err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if service.Liveness.Healthy() {
+		t.Error("Expected liveness to be unhealthy")
+	}
+	if len(tel.warnings) == 0 {
+		t.Error("Expected a warning to be logged before failing liveness")
+	}
+
+	// liveness=healthy clears the flag
+	healthy := &Behavior{
+		Liveness: &LivenessBehavior{Healthy: true},
+	}
+	executor = NewExecutor(healthy, "trace123", "test-service", tel)
+	if _, err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !service.Liveness.Healthy() {
+		t.Error("Expected liveness to be restored")
+	}
+}
+
 func TestExecutor_ErrorBehavior(t *testing.T) {
 	tel := &mockTelemetry{}
 	
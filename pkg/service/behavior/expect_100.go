@@ -0,0 +1,49 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Expect100Behavior simulates servers that mishandle "Expect: 100-continue"
+type Expect100Behavior struct {
+	Mode string // "ignore": never send the interim 100 Continue response
+}
+
+// String returns the string representation of expect-100 behavior
+func (eb *Expect100Behavior) String() string {
+	return fmt.Sprintf("expect-100=%s", eb.Mode)
+}
+
+// parseExpect100 parses expect-100 specifications
+// Example: "ignore"
+func parseExpect100(value string) (*Expect100Behavior, error) {
+	switch value {
+	case "ignore":
+		return &Expect100Behavior{Mode: value}, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q (expected ignore)", value)
+	}
+}
+
+// SuppressesContinue reports whether the request body must be left unread so that
+// net/http never sends the interim 100 Continue the client is waiting for.
+// The server responds as if the request had no body.
+func (b *Behavior) SuppressesContinue(r *http.Request) bool {
+	if b.Expect100 == nil || b.Expect100.Mode != "ignore" {
+		return false
+	}
+	return strings.EqualFold(r.Header.Get("Expect"), "100-continue")
+}
+
+func init() {
+	registerParser("expect-100", func(b *Behavior, value string) error {
+		eb, err := parseExpect100(value)
+		if err != nil {
+			return fmt.Errorf("invalid expect-100: %w", err)
+		}
+		b.Expect100 = eb
+		return nil
+	})
+}
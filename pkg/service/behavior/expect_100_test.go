@@ -0,0 +1,120 @@
+package behavior
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExpect100(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{name: "ignore", input: "expect-100=ignore", wantError: false},
+		{name: "unknown mode", input: "expect-100=reject", wantError: true},
+		{name: "empty mode", input: "expect-100=", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && (b.Expect100 == nil || b.Expect100.Mode != "ignore") {
+				t.Errorf("expected expect-100 ignore, got %+v", b.Expect100)
+			}
+		})
+	}
+}
+
+func TestExpect100String(t *testing.T) {
+	b, err := Parse("expect-100=ignore")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := b.String(); got != "expect-100=ignore" {
+		t.Errorf("String() = %q, want %q", got, "expect-100=ignore")
+	}
+}
+
+func TestSuppressesContinue(t *testing.T) {
+	ignore, _ := Parse("expect-100=ignore")
+	other, _ := Parse("latency=1ms")
+
+	withExpect := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+	withExpect.Header.Set("Expect", "100-continue")
+	withoutExpect := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x"))
+
+	if !ignore.SuppressesContinue(withExpect) {
+		t.Error("expected expect-100=ignore to suppress 100 Continue")
+	}
+	if ignore.SuppressesContinue(withoutExpect) {
+		t.Error("expected no suppression without Expect header")
+	}
+	if other.SuppressesContinue(withExpect) {
+		t.Error("expected no suppression without expect-100 behavior")
+	}
+}
+
+// Got100Continue only fires if the server sends the interim response, which
+// net/http does on the handler's first body read
+func TestSuppressesContinue_NoInterimResponse(t *testing.T) {
+	tests := []struct {
+		name         string
+		behavior     string
+		wantContinue bool
+	}{
+		{name: "expect-100=ignore", behavior: "expect-100=ignore", wantContinue: false},
+		{name: "default handling", behavior: "latency=1ms", wantContinue: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.behavior)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !b.SuppressesContinue(r) {
+					io.Copy(io.Discard, r.Body)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			got100 := false
+			trace := &httptrace.ClientTrace{Got100Continue: func() { got100 = true }}
+			ctx := httptrace.WithClientTrace(context.Background(), trace)
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(`{"order":1}`))
+			if err != nil {
+				t.Fatalf("NewRequest() error = %v", err)
+			}
+			req.Header.Set("Expect", "100-continue")
+
+			client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: 5 * time.Second}}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("expected 200, got %d", resp.StatusCode)
+			}
+			if got100 != tt.wantContinue {
+				t.Errorf("received 100 Continue = %v, want %v", got100, tt.wantContinue)
+			}
+		})
+	}
+}
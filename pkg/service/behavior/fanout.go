@@ -0,0 +1,43 @@
+package behavior
+
+import (
+	"fmt"
+)
+
+// FanoutBehavior controls how matched upstreams are called
+type FanoutBehavior struct {
+	Mode string // "sequential" (default, fail-fast) or "parallel"
+}
+
+// String returns the string representation of fanout behavior
+func (fb *FanoutBehavior) String() string {
+	return fmt.Sprintf("fanout=%s", fb.Mode)
+}
+
+// parseFanout parses fanout specifications
+// Examples: "parallel", "sequential"
+func parseFanout(value string) (*FanoutBehavior, error) {
+	switch value {
+	case "parallel", "sequential":
+		return &FanoutBehavior{Mode: value}, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q (expected parallel or sequential)", value)
+	}
+}
+
+// ParallelFanout reports whether upstreams should be called concurrently,
+// as with fanout=parallel or an aggregate that needs every result
+func (b *Behavior) ParallelFanout() bool {
+	return b != nil && (b.Aggregate != nil || b.Fanout != nil && b.Fanout.Mode == "parallel")
+}
+
+func init() {
+	registerParser("fanout", func(b *Behavior, value string) error {
+		fanout, err := parseFanout(value)
+		if err != nil {
+			return fmt.Errorf("invalid fanout: %w", err)
+		}
+		b.Fanout = fanout
+		return nil
+	})
+}
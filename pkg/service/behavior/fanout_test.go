@@ -0,0 +1,48 @@
+package behavior
+
+import (
+	"testing"
+)
+
+func TestParseFanout(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantError    bool
+		wantParallel bool
+	}{
+		{name: "parallel", input: "fanout=parallel", wantParallel: true},
+		{name: "sequential", input: "fanout=sequential", wantParallel: false},
+		{name: "unknown mode", input: "fanout=random", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && b.ParallelFanout() != tt.wantParallel {
+				t.Errorf("ParallelFanout() = %v, want %v", b.ParallelFanout(), tt.wantParallel)
+			}
+		})
+	}
+}
+
+func TestFanoutString(t *testing.T) {
+	b, err := Parse("fanout=parallel")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "fanout=parallel" {
+		t.Errorf("String() = %s, want fanout=parallel", result)
+	}
+}
+
+func TestParallelFanoutNilBehavior(t *testing.T) {
+	var b *Behavior
+	if b.ParallelFanout() {
+		t.Error("expected nil behavior to use sequential fan-out")
+	}
+}
@@ -0,0 +1,95 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxFDLeak bounds how many file descriptors a single fd-leak behavior may open
+const maxFDLeak = 100000
+
+// FDLeakBehavior controls file descriptor exhaustion
+type FDLeakBehavior struct {
+	Count    int // Number of file handles to open
+	Duration time.Duration
+}
+
+// String returns the string representation of fd-leak behavior
+func (fb *FDLeakBehavior) String() string {
+	return fmt.Sprintf("fd-leak=%d:%s", fb.Count, fb.Duration)
+}
+
+// parseFDLeak parses fd-leak specifications
+// Examples: "1000", "1000:30s"
+func parseFDLeak(value string) (*FDLeakBehavior, error) {
+	parts := strings.Split(value, ":")
+	fb := &FDLeakBehavior{
+		Duration: 1 * time.Minute,
+	}
+
+	count, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid count: %w", err)
+	}
+	if count <= 0 || count > maxFDLeak {
+		return nil, fmt.Errorf("count must be between 1 and %d, got %d", maxFDLeak, count)
+	}
+	fb.Count = count
+
+	if len(parts) > 1 {
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		fb.Duration = d
+	}
+
+	return fb, nil
+}
+
+// applyFDLeak opens up to the configured number of file handles, stopping early at the
+// fd limit, and holds them in the background for the configured duration. It returns
+// the number of handles opened.
+func (b *Behavior) applyFDLeak(ctx context.Context) int {
+	files := make([]*os.File, 0, b.FDLeak.Count)
+	for i := 0; i < b.FDLeak.Count; i++ {
+		f, err := os.Open(os.DevNull)
+		if err != nil {
+			// Hitting the fd limit is the condition under test - keep what we have
+			fmt.Fprintf(os.Stderr, "Warning: fd-leak stopped after %d of %d handles: %v\n", len(files), b.FDLeak.Count, err)
+			break
+		}
+		files = append(files, f)
+	}
+	recordResource(ResourceFDs, int64(len(files)))
+
+	go func() {
+		// Hold handles until context is done or duration expires
+		select {
+		case <-ctx.Done():
+		case <-time.After(b.FDLeak.Duration):
+		}
+
+		for _, f := range files {
+			f.Close()
+		}
+		recordResource(ResourceFDs, -int64(len(files)))
+	}()
+
+	return len(files)
+}
+
+func init() {
+	registerParser("fd-leak", func(b *Behavior, value string) error {
+		fdLeak, err := parseFDLeak(value)
+		if err != nil {
+			return fmt.Errorf("invalid fd-leak: %w", err)
+		}
+		b.FDLeak = fdLeak
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package behavior
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseFDLeak(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "count only",
+			input:     "fd-leak=100",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.FDLeak == nil {
+					t.Fatal("expected fd-leak behavior")
+				}
+				if b.FDLeak.Count != 100 {
+					t.Errorf("expected count 100, got %d", b.FDLeak.Count)
+				}
+				if b.FDLeak.Duration != time.Minute {
+					t.Errorf("expected default duration 1m, got %s", b.FDLeak.Duration)
+				}
+			},
+		},
+		{
+			name:      "count with duration",
+			input:     "fd-leak=500:30s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.FDLeak.Count != 500 {
+					t.Errorf("expected count 500, got %d", b.FDLeak.Count)
+				}
+				if b.FDLeak.Duration != 30*time.Second {
+					t.Errorf("expected duration 30s, got %s", b.FDLeak.Duration)
+				}
+			},
+		},
+		{
+			name:      "zero count",
+			input:     "fd-leak=0",
+			wantError: true,
+		},
+		{
+			name:      "count above bound",
+			input:     "fd-leak=1000000",
+			wantError: true,
+		},
+		{
+			name:      "invalid duration",
+			input:     "fd-leak=10:forever",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestFDLeakString(t *testing.T) {
+	b, err := Parse("fd-leak=100:30s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	expected := "fd-leak=100:30s"
+	if result := b.String(); result != expected {
+		t.Errorf("String() = %s, want %s", result, expected)
+	}
+}
+
+func TestApplyFDLeak(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("fd-leak=20:10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if opened := b.applyFDLeak(ctx); opened != 20 {
+		t.Errorf("expected 20 handles opened, got %d", opened)
+	}
+	r.waitFor(t, ResourceFDs, 20)
+
+	// Cancelling the context releases the handles
+	cancel()
+	r.waitFor(t, ResourceFDs, 0)
+}
+
+func TestApplyFDLeak_FDLimit(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		t.Fatalf("Getrlimit() failed: %v", err)
+	}
+	lowered := limit
+	lowered.Cur = 256
+	if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &lowered); err != nil {
+		t.Fatalf("Setrlimit() failed: %v", err)
+	}
+	defer syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit)
+
+	b, err := Parse("fd-leak=1000:10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	executor := NewExecutor(b, "trace123", "test-service", &mockTelemetry{})
+	if _, err := executor.Execute(ctx); err != nil {
+		t.Fatalf("Execute() failed: %v", err)
+	}
+
+	// Only the handles opened under the limit are reported
+	opened := executor.FDsOpened()
+	if opened <= 0 || opened >= 256 {
+		t.Errorf("expected fewer handles opened than the 256 fd limit, got %d", opened)
+	}
+	r.waitFor(t, ResourceFDs, float64(opened))
+
+	cancel()
+	r.waitFor(t, ResourceFDs, 0)
+}
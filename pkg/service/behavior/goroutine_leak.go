@@ -0,0 +1,91 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxGoroutineLeak bounds how many goroutines a single goroutine-leak behavior may spawn
+const maxGoroutineLeak = 100000
+
+// GoroutineLeakBehavior controls runaway goroutine growth
+type GoroutineLeakBehavior struct {
+	Count    int // Number of goroutines to spawn
+	Duration time.Duration
+}
+
+// String returns the string representation of goroutine-leak behavior
+func (gb *GoroutineLeakBehavior) String() string {
+	return fmt.Sprintf("goroutine-leak=%d:%s", gb.Count, gb.Duration)
+}
+
+// parseGoroutineLeak parses goroutine-leak specifications
+// Examples: "1000", "1000:30s"
+func parseGoroutineLeak(value string) (*GoroutineLeakBehavior, error) {
+	parts := strings.Split(value, ":")
+	gb := &GoroutineLeakBehavior{
+		Duration: 1 * time.Minute,
+	}
+
+	count, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid count: %w", err)
+	}
+	if count <= 0 || count > maxGoroutineLeak {
+		return nil, fmt.Errorf("count must be between 1 and %d, got %d", maxGoroutineLeak, count)
+	}
+	gb.Count = count
+
+	if len(parts) > 1 {
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration: %w", err)
+		}
+		gb.Duration = d
+	}
+
+	return gb, nil
+}
+
+// applyGoroutineLeak spawns goroutines that block until the duration elapses or ctx is done
+func (b *Behavior) applyGoroutineLeak(ctx context.Context) {
+	go func() {
+		// A single release channel guarantees every spawned goroutine exits together
+		release := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(b.GoroutineLeak.Count)
+		for i := 0; i < b.GoroutineLeak.Count; i++ {
+			go func() {
+				defer wg.Done()
+				<-release
+			}()
+		}
+
+		recordResource(ResourceGoroutines, int64(b.GoroutineLeak.Count))
+
+		// Hold goroutines until context is done or duration expires
+		select {
+		case <-ctx.Done():
+		case <-time.After(b.GoroutineLeak.Duration):
+		}
+
+		close(release)
+		wg.Wait()
+		recordResource(ResourceGoroutines, -int64(b.GoroutineLeak.Count))
+	}()
+}
+
+func init() {
+	registerParser("goroutine-leak", func(b *Behavior, value string) error {
+		goroutineLeak, err := parseGoroutineLeak(value)
+		if err != nil {
+			return fmt.Errorf("invalid goroutine-leak: %w", err)
+		}
+		b.GoroutineLeak = goroutineLeak
+		return nil
+	})
+}
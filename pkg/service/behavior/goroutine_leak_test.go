@@ -0,0 +1,110 @@
+package behavior
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestParseGoroutineLeak(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "count only",
+			input:     "goroutine-leak=1000",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.GoroutineLeak == nil {
+					t.Fatal("expected goroutine-leak behavior")
+				}
+				if b.GoroutineLeak.Count != 1000 {
+					t.Errorf("expected count 1000, got %d", b.GoroutineLeak.Count)
+				}
+				if b.GoroutineLeak.Duration != time.Minute {
+					t.Errorf("expected default duration 1m, got %s", b.GoroutineLeak.Duration)
+				}
+			},
+		},
+		{
+			name:      "count with duration",
+			input:     "goroutine-leak=200:30s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.GoroutineLeak.Duration != 30*time.Second {
+					t.Errorf("expected duration 30s, got %s", b.GoroutineLeak.Duration)
+				}
+			},
+		},
+		{
+			name:      "negative count",
+			input:     "goroutine-leak=-1",
+			wantError: true,
+		},
+		{
+			name:      "count above bound",
+			input:     "goroutine-leak=1000000",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestGoroutineLeakString(t *testing.T) {
+	b, err := Parse("goroutine-leak=500:30s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	expected := "goroutine-leak=500:30s"
+	if result := b.String(); result != expected {
+		t.Errorf("String() = %s, want %s", result, expected)
+	}
+}
+
+func TestApplyGoroutineLeak(t *testing.T) {
+	baseline := runtime.NumGoroutine()
+
+	b, err := Parse("goroutine-leak=500:10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := b.Apply(ctx); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+
+	waitForGoroutines(t, func(n int) bool { return n >= baseline+500 })
+
+	// Cancelling the context releases every goroutine
+	cancel()
+	waitForGoroutines(t, func(n int) bool { return n <= baseline })
+}
+
+func waitForGoroutines(t *testing.T, cond func(n int) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond(runtime.NumGoroutine()) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("goroutine count condition not met, NumGoroutine=%d", runtime.NumGoroutine())
+}
@@ -0,0 +1,268 @@
+package behavior
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonServiceBehavior is an entry of the JSON behavior specification
+type jsonServiceBehavior struct {
+	Service  string                     `json:"service,omitempty"`
+	Behavior map[string]json.RawMessage `json:"behavior"`
+}
+
+// ParseJSON parses a behavior chain from its JSON specification, a list of entries
+// targeting a service (or all services without "service"):
+//
+//	[{"behavior": {"latency": "50ms"}},
+//	 {"service": "orders", "behavior": {"error": 0.5, "baggage": ["tenant=acme", "user=42"]}}]
+//
+// Each behavior key takes the value of its string syntax as a string, number or boolean;
+// an array repeats the directive. error and when also take an object (see
+// jsonObjectParsers). Entries for all services are ordered first, so the chain's
+// String() parses back to the same chain when propagated.
+func ParseJSON(data []byte) (*BehaviorChain, error) {
+	var entries []jsonServiceBehavior
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&entries); err != nil {
+		return nil, fmt.Errorf("invalid behavior JSON: %w", err)
+	}
+
+	chain := &BehaviorChain{Behaviors: []ServiceBehavior{}}
+	var targeted []ServiceBehavior
+	for i, entry := range entries {
+		if strings.ContainsAny(entry.Service, ":,=") {
+			return nil, fmt.Errorf("entry %d: invalid service name %q", i, entry.Service)
+		}
+		b, err := parseJSONBehavior(entry.Behavior)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i, err)
+		}
+
+		sb := ServiceBehavior{Service: entry.Service, Behavior: b}
+		if entry.Service == "" {
+			chain.Behaviors = append(chain.Behaviors, sb)
+		} else {
+			targeted = append(targeted, sb)
+		}
+	}
+
+	// ParseChain attributes unprefixed behaviors following a service prefix to that service
+	chain.Behaviors = append(chain.Behaviors, targeted...)
+	return chain, nil
+}
+
+// parseJSONBehavior parses the behavior object of a JSON specification entry
+func parseJSONBehavior(params map[string]json.RawMessage) (*Behavior, error) {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b := &Behavior{}
+	for _, key := range keys {
+		parser, ok := parsers[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown behavior key: %s", key)
+		}
+
+		if bytes.HasPrefix(bytes.TrimSpace(params[key]), []byte("{")) {
+			parseObject, ok := jsonObjectParsers[key]
+			if !ok {
+				return nil, fmt.Errorf("invalid %s: object values are only accepted for error and when", key)
+			}
+			if err := parseObject(b, params[key]); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			continue
+		}
+
+		values, err := jsonValues(params[key])
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+		for _, value := range values {
+			// Commas separate directives in the string form that propagates upstream
+			if strings.Contains(value, ",") {
+				return nil, fmt.Errorf("invalid %s: value %q contains ','", key, value)
+			}
+			if err := parser(b, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return b, nil
+}
+
+// jsonValues returns the directive values of a JSON behavior value: a string, number
+// or boolean is a single directive, an array of them repeats it
+func jsonValues(raw json.RawMessage) ([]string, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(raw), []byte("[")) {
+		value, err := jsonScalar(raw)
+		if err != nil {
+			return nil, err
+		}
+		return []string{value}, nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(list))
+	for _, item := range list {
+		value, err := jsonScalar(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// jsonScalar returns the string syntax of a JSON string, number or boolean
+func jsonScalar(raw json.RawMessage) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("expected a string, number or boolean, got %s", raw)
+	}
+}
+
+// jsonObjectParsers parse the object values of behaviors with several fields:
+//
+//	{"error": {"code": 503, "prob": 0.5, "correlated": true}}
+//	{"error": {"code": 503, "every": 100, "for": "10s"}}
+//	{"when": {"header": {"X-Debug": "true", "X-Canary": ""}}}
+var jsonObjectParsers = map[string]func(b *Behavior, raw json.RawMessage) error{
+	"error": func(b *Behavior, raw json.RawMessage) error {
+		var obj jsonError
+		if err := decodeJSONObject(raw, &obj); err != nil {
+			return err
+		}
+		eb, err := obj.toBehavior()
+		if err != nil {
+			return err
+		}
+		b.Error = eb
+		return nil
+	},
+	"when": func(b *Behavior, raw json.RawMessage) error {
+		var obj jsonWhen
+		if err := decodeJSONObject(raw, &obj); err != nil {
+			return err
+		}
+		wb, err := obj.toBehavior()
+		if err != nil {
+			return err
+		}
+		b.When = wb
+		return nil
+	},
+}
+
+// decodeJSONObject decodes raw into v, rejecting fields v doesn't have
+func decodeJSONObject(raw json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// jsonError is the object form of an error behavior
+type jsonError struct {
+	Code       *int     `json:"code"` // Defaults to 500
+	Prob       *float64 `json:"prob"` // Defaults to 1
+	Correlated bool     `json:"correlated"`
+	Every      int64    `json:"every"`
+	For        string   `json:"for"`
+}
+
+// toBehavior validates the object the way parseError validates the string syntax
+func (obj jsonError) toBehavior() (*ErrorBehavior, error) {
+	eb := &ErrorBehavior{Rate: 500, Prob: 1.0, Correlated: obj.Correlated}
+	if obj.Code != nil {
+		if err := checkErrorCode(*obj.Code); err != nil {
+			return nil, err
+		}
+		eb.Rate = *obj.Code
+	}
+
+	if obj.Every == 0 && obj.For == "" {
+		if obj.Prob != nil {
+			if err := checkErrorProb(*obj.Prob); err != nil {
+				return nil, err
+			}
+			eb.Prob = *obj.Prob
+		}
+		return eb, nil
+	}
+
+	// Count-triggered error window
+	if obj.Prob != nil || obj.Correlated {
+		return nil, fmt.Errorf("every and for can't be combined with prob or correlated")
+	}
+	if obj.Every < 1 {
+		return nil, fmt.Errorf("every must be at least 1, got %d", obj.Every)
+	}
+	window, err := time.ParseDuration(obj.For)
+	if err != nil {
+		return nil, fmt.Errorf("invalid for: %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("for must be positive")
+	}
+	eb.Every = obj.Every
+	eb.For = window
+	return eb, nil
+}
+
+// jsonWhen is the object form of a when behavior
+type jsonWhen struct {
+	Header map[string]string `json:"header"` // Header name to expected value (empty = present)
+}
+
+// toBehavior validates the object, rejecting names and values the propagated string
+// form can't carry
+func (obj jsonWhen) toBehavior() (*WhenBehavior, error) {
+	names := make([]string, 0, len(obj.Header))
+	for name := range obj.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	wb := &WhenBehavior{}
+	for _, name := range names {
+		value := obj.Header[name]
+		if name == "" || strings.ContainsAny(name, ":;,= ") {
+			return nil, fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, ";,") || strings.TrimSpace(value) != value {
+			return nil, fmt.Errorf("invalid value %q for header %s", value, name)
+		}
+		wb.Conditions = append(wb.Conditions, Condition{Type: "header", Name: name, Value: value})
+	}
+
+	if len(wb.Conditions) == 0 {
+		return nil, fmt.Errorf("no valid conditions found")
+	}
+	return wb, nil
+}
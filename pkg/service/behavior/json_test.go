@@ -0,0 +1,106 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		want      string // String() of the parsed chain
+	}{
+		{name: "global behavior", input: `[{"behavior": {"latency": "50ms"}}]`, want: "latency=50ms"},
+		{name: "number and boolean values", input: `[{"behavior": {"error": 0.5, "ready-from-upstreams": true}}]`, want: "error=500:0.5,ready-from-upstreams=true"},
+		{name: "array repeats the directive", input: `[{"behavior": {"baggage": ["tenant=acme", "user=42"]}}]`, want: "baggage=tenant=acme,baggage=user=42"},
+		{name: "targeted behavior", input: `[{"service": "orders", "behavior": {"error": "503:0.5"}}]`, want: "orders:error=503:0.5"},
+		{name: "global entries ordered first", input: `[{"service": "orders", "behavior": {"latency": "10ms"}}, {"behavior": {"latency": "50ms"}}]`, want: "latency=50ms,orders:latency=10ms"},
+		{name: "empty list", input: `[]`, want: ""},
+		{name: "invalid JSON", input: `[{"behavior": `, wantError: true},
+		{name: "not a list", input: `{"behavior": {"latency": "50ms"}}`, wantError: true},
+		{name: "unknown field", input: `[{"target": "orders", "behavior": {"latency": "50ms"}}]`, wantError: true},
+		{name: "unknown behavior key", input: `[{"behavior": {"slowness": "50ms"}}]`, wantError: true},
+		{name: "invalid behavior value", input: `[{"behavior": {"latency": "soon"}}]`, wantError: true},
+		{name: "object value", input: `[{"behavior": {"latency": {"min": "10ms"}}}]`, wantError: true},
+		{name: "error object", input: `[{"behavior": {"error": {"code": 503, "prob": 0.5}}}]`, want: "error=503:0.5"},
+		{name: "error object defaults", input: `[{"behavior": {"error": {}}}]`, want: "error=500"},
+		{name: "error object correlated", input: `[{"behavior": {"error": {"code": 503, "prob": 0.3, "correlated": true}}}]`, want: "error=503:0.3:correlated"},
+		{name: "error object window", input: `[{"behavior": {"error": {"code": 503, "every": 100, "for": "10s"}}}]`, want: "error=503:every:100:for:10s"},
+		{name: "error object invalid code", input: `[{"behavior": {"error": {"code": 42}}}]`, wantError: true},
+		{name: "error object invalid prob", input: `[{"behavior": {"error": {"prob": 1.5}}}]`, wantError: true},
+		{name: "error object window with prob", input: `[{"behavior": {"error": {"every": 100, "for": "10s", "prob": 0.5}}}]`, wantError: true},
+		{name: "error object window without for", input: `[{"behavior": {"error": {"every": 100}}}]`, wantError: true},
+		{name: "error object unknown field", input: `[{"behavior": {"error": {"status": 503}}}]`, wantError: true},
+		{name: "when object", input: `[{"behavior": {"when": {"header": {"X-Debug": "true", "X-Canary": ""}}, "latency": "10ms"}}]`, want: "latency=10ms,when=header=X-Canary;header=X-Debug:true"},
+		{name: "when object without conditions", input: `[{"behavior": {"when": {"header": {}}}}]`, wantError: true},
+		{name: "when object value with semicolon", input: `[{"behavior": {"when": {"header": {"X-Debug": "a;b"}}}}]`, wantError: true},
+		{name: "when object name with colon", input: `[{"behavior": {"when": {"header": {"X:Debug": "true"}}}}]`, wantError: true},
+		{name: "value with comma", input: `[{"behavior": {"latency": "10ms,error=1"}}]`, wantError: true},
+		{name: "service with colon", input: `[{"service": "orders:v2", "behavior": {"latency": "50ms"}}]`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseJSON([]byte(tt.input))
+			if (err != nil) != tt.wantError {
+				t.Errorf("ParseJSON() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if result := chain.String(); result != tt.want {
+				t.Errorf("String() = %s, want %s", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSON_RoundTrip(t *testing.T) {
+	input := `[
+		{"service": "orders", "behavior": {"latency": "10ms", "when": "header=X-Canary"}},
+		{"behavior": {"latency": "50ms", "upstreamWeights": "v2:10"}},
+		{"service": "payments", "behavior": {"error": 503}},
+		{"service": "shipping", "behavior": {"error": {"code": 502, "prob": 0.25}, "when": {"header": {"X-Debug": "true"}}}}
+	]`
+
+	chain, err := ParseJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("ParseJSON() failed: %v", err)
+	}
+
+	// The propagated string form parses back to the same chain
+	reparsed, err := ParseChain(chain.String())
+	if err != nil {
+		t.Fatalf("ParseChain(%q) failed: %v", chain.String(), err)
+	}
+	if reparsed.String() != chain.String() {
+		t.Errorf("round trip = %s, want %s", reparsed.String(), chain.String())
+	}
+
+	for _, service := range []string{"orders", "payments", "inventory", "shipping"} {
+		if got, want := reparsed.ForService(service).String(), chain.ForService(service).String(); got != want {
+			t.Errorf("ForService(%s) = %s, want %s", service, got, want)
+		}
+	}
+
+	orders := chain.ForService("orders")
+	if orders.Latency == nil || orders.Latency.Value != 10*time.Millisecond || orders.When == nil {
+		t.Errorf("expected orders latency and when condition, got %s", orders)
+	}
+	if inventory := chain.ForService("inventory"); inventory.UpstreamWeights.GetWeight("v2") != 10 {
+		t.Errorf("expected global upstream weights for inventory, got %s", inventory)
+	}
+
+	// Object values map to the typed behaviors
+	shipping := chain.ForService("shipping")
+	if shipping.Error == nil || shipping.Error.Rate != 502 || shipping.Error.Prob != 0.25 {
+		t.Errorf("expected shipping error 502 with probability 0.25, got %s", shipping)
+	}
+	wantCond := Condition{Type: "header", Name: "X-Debug", Value: "true"}
+	if shipping.When == nil || len(shipping.When.Conditions) != 1 || shipping.When.Conditions[0] != wantCond {
+		t.Errorf("expected shipping when condition %s, got %s", wantCond, shipping)
+	}
+}
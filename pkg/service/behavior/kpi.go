@@ -0,0 +1,179 @@
+package behavior
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// kpiNamespace is the Prometheus namespace business KPI metrics are registered under
+const kpiNamespace = "business"
+
+// maxKPINames bounds how many distinct KPI metrics the process will register
+const maxKPINames = 50
+
+var kpiNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// kpiRegistry holds the lazily registered KPI counters, keyed by KPI name
+var kpiRegistry = struct {
+	sync.Mutex
+	counters map[string]prometheus.Counter
+}{counters: make(map[string]prometheus.Counter)}
+
+// KPIBehavior updates synthetic business metrics for each request
+type KPIBehavior struct {
+	Entries []KPIEntry
+}
+
+// KPIEntry is a single metric update
+type KPIEntry struct {
+	Name   string  // Metric name without namespace or _total suffix (e.g. "orders")
+	Op     string  // "inc" or "add"
+	Amount float64 // Amount to add per request
+}
+
+// String returns the string representation of a single KPI entry
+func (e KPIEntry) String() string {
+	return fmt.Sprintf("%s:%s:%v", e.Name, e.Op, e.Amount)
+}
+
+// String returns the string representation of kpi behavior
+// Format: kpi=orders:inc:1|revenue:add:29.99
+func (kb *KPIBehavior) String() string {
+	var parts []string
+	for _, e := range kb.Entries {
+		parts = append(parts, e.String())
+	}
+	return fmt.Sprintf("kpi=%s", strings.Join(parts, "|"))
+}
+
+// parseKPI parses kpi specifications
+// Format: name:op[:amount]|name:op[:amount]
+// Examples: "orders:inc", "orders:inc:1|revenue:add:29.99"
+func parseKPI(value string) (*KPIBehavior, error) {
+	kb := &KPIBehavior{}
+
+	// Split by pipe (using | to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("invalid kpi format: %s (expected name:op[:amount])", part)
+		}
+
+		name := strings.TrimSpace(fields[0])
+		if !kpiNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid kpi name: %q", name)
+		}
+
+		entry := KPIEntry{Name: name, Op: strings.TrimSpace(fields[1]), Amount: 1}
+		switch entry.Op {
+		case "inc":
+			if len(fields) == 3 {
+				amount, err := strconv.ParseFloat(fields[2], 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid amount for %s: %w", name, err)
+				}
+				entry.Amount = amount
+			}
+		case "add":
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("kpi %s: add requires an amount", name)
+			}
+			amount, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid amount for %s: %w", name, err)
+			}
+			entry.Amount = amount
+		default:
+			return nil, fmt.Errorf("unknown kpi op: %s (expected inc or add)", entry.Op)
+		}
+
+		// Counters can only go up
+		if entry.Amount < 0 {
+			return nil, fmt.Errorf("amount for %s cannot be negative", name)
+		}
+
+		kb.Entries = append(kb.Entries, entry)
+	}
+
+	if len(kb.Entries) == 0 {
+		return nil, fmt.Errorf("no valid kpi entries found")
+	}
+
+	return kb, nil
+}
+
+// kpiCounter returns the counter for the given KPI name, registering it on first use
+func kpiCounter(name string) (prometheus.Counter, error) {
+	kpiRegistry.Lock()
+	defer kpiRegistry.Unlock()
+
+	if c, ok := kpiRegistry.counters[name]; ok {
+		return c, nil
+	}
+	if len(kpiRegistry.counters) >= maxKPINames {
+		return nil, fmt.Errorf("kpi %s not registered: limit of %d distinct kpis reached", name, maxKPINames)
+	}
+
+	c := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: kpiNamespace,
+		Name:      name + "_total",
+		Help:      fmt.Sprintf("Synthetic business KPI %s driven by the kpi behavior", name),
+	})
+	if err := prometheus.DefaultRegisterer.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if !errors.As(err, &are) {
+			return nil, fmt.Errorf("register kpi %s: %w", name, err)
+		}
+		existing, ok := are.ExistingCollector.(prometheus.Counter)
+		if !ok {
+			return nil, fmt.Errorf("register kpi %s: conflicting metric already registered", name)
+		}
+		c = existing
+	}
+
+	kpiRegistry.counters[name] = c
+	return c, nil
+}
+
+// applyKPI updates the configured business metrics. Entries that cannot be
+// registered are skipped; the first such error is returned for logging.
+func (b *Behavior) applyKPI() error {
+	if b.KPI == nil {
+		return nil
+	}
+
+	var firstErr error
+	for _, e := range b.KPI.Entries {
+		c, err := kpiCounter(e.Name)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		c.Add(e.Amount)
+	}
+	return firstErr
+}
+
+func init() {
+	registerParser("kpi", func(b *Behavior, value string) error {
+		kpi, err := parseKPI(value)
+		if err != nil {
+			return fmt.Errorf("invalid kpi: %w", err)
+		}
+		b.KPI = kpi
+		return nil
+	})
+}
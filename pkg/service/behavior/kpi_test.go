@@ -0,0 +1,126 @@
+package behavior
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestParseKPI(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "inc and add",
+			input:     "kpi=orders:inc:1|revenue:add:29.99",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.KPI == nil {
+					t.Fatal("expected kpi behavior")
+				}
+				if len(b.KPI.Entries) != 2 {
+					t.Fatalf("expected 2 entries, got %d", len(b.KPI.Entries))
+				}
+				if e := b.KPI.Entries[1]; e.Name != "revenue" || e.Op != "add" || e.Amount != 29.99 {
+					t.Errorf("unexpected second entry %+v", e)
+				}
+			},
+		},
+		{
+			name:      "inc defaults to one",
+			input:     "kpi=orders:inc",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.KPI.Entries[0].Amount != 1 {
+					t.Errorf("expected amount 1, got %v", b.KPI.Entries[0].Amount)
+				}
+			},
+		},
+		{
+			name:      "add without amount",
+			input:     "kpi=revenue:add",
+			wantError: true,
+		},
+		{
+			name:      "unknown op",
+			input:     "kpi=orders:set:1",
+			wantError: true,
+		},
+		{
+			name:      "invalid name",
+			input:     "kpi=order-count:inc",
+			wantError: true,
+		},
+		{
+			name:      "negative amount",
+			input:     "kpi=revenue:add:-5",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestKPIString(t *testing.T) {
+	input := "kpi=orders:inc:1|revenue:add:29.99"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestApplyKPI(t *testing.T) {
+	b, err := Parse("kpi=test_orders:inc:1|test_revenue:add:29.99")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := b.applyKPI(); err != nil {
+			t.Fatalf("applyKPI() failed: %v", err)
+		}
+	}
+
+	orders, err := kpiCounter("test_orders")
+	if err != nil {
+		t.Fatalf("kpiCounter() failed: %v", err)
+	}
+	if got := testutil.ToFloat64(orders); got != 3 {
+		t.Errorf("business_test_orders_total = %v, want 3", got)
+	}
+
+	revenue, err := kpiCounter("test_revenue")
+	if err != nil {
+		t.Fatalf("kpiCounter() failed: %v", err)
+	}
+	if got := testutil.ToFloat64(revenue); got < 89.96 || got > 89.98 {
+		t.Errorf("business_test_revenue_total = %v, want 89.97", got)
+	}
+
+	// Metrics are exposed under the business namespace
+	count, err := testutil.GatherAndCount(prometheus.DefaultGatherer, "business_test_orders_total", "business_test_revenue_total")
+	if err != nil {
+		t.Fatalf("GatherAndCount() failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 registered kpi metrics, got %d", count)
+	}
+}
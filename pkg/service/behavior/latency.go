@@ -3,33 +3,80 @@ package behavior
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
 )
 
 // LatencyBehavior controls request latency
 type LatencyBehavior struct {
-	Type  string // "fixed", "range", "percentile"
-	Min   time.Duration
-	Max   time.Duration
-	Value time.Duration
+	Type   string // "fixed", "range", "percentile", "per-kb-out" (empty if only jitter is set)
+	Min    time.Duration
+	Max    time.Duration
+	Value  time.Duration
+	Jitter float64 // Percentage each delay is randomly perturbed by, up or down (jitter=<percent>)
 }
 
-// String returns the string representation of latency behavior
+// String returns the string representation of latency behavior, followed by its jitter if set
 func (lb *LatencyBehavior) String() string {
-	if lb.Type == "fixed" {
-		return fmt.Sprintf("latency=%s", lb.Value)
+	var parts []string
+	switch lb.Type {
+	case "":
+	case "fixed":
+		parts = append(parts, fmt.Sprintf("latency=%s", lb.Value))
+	case "per-kb-out":
+		parts = append(parts, fmt.Sprintf("latency=per-kb-out:%s", lb.Value))
+	default:
+		parts = append(parts, fmt.Sprintf("latency=%s-%s", lb.Min, lb.Max))
+	}
+	// Without the % sign, which a URL query can't carry unescaped
+	if lb.Jitter > 0 {
+		parts = append(parts, fmt.Sprintf("jitter=%s", strconv.FormatFloat(lb.Jitter, 'f', -1, 64)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseJitter parses jitter specifications
+// Examples: "20", "20%"
+func parseJitter(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percent: %w", err)
+	}
+	if percent < 0 || percent > 100 {
+		return 0, fmt.Errorf("percent must be between 0 and 100, got %v", percent)
 	}
-	return fmt.Sprintf("latency=%s-%s", lb.Min, lb.Max)
+	return percent, nil
+}
+
+// jittered perturbs d uniformly by up to ±Jitter percent, drawing from rnd
+func (lb *LatencyBehavior) jittered(d time.Duration, rnd *service.Rand) time.Duration {
+	if lb.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	spread := float64(d) * lb.Jitter / 100
+	return d + time.Duration(spread*(2*rnd.Float64()-1))
 }
 
 // parseLatency parses latency specifications
-// Examples: "100ms", "50-200ms", "50ms-200ms", "5-20ms"
+// Examples: "100ms", "50-200ms", "50ms-200ms", "5-20ms", "per-kb-out:5ms"
 func parseLatency(value string) (*LatencyBehavior, error) {
 	lb := &LatencyBehavior{}
 
-	if strings.Contains(value, "-") {
+	if perKB, ok := strings.CutPrefix(value, "per-kb-out:"); ok {
+		// Per KB of response body: "per-kb-out:5ms"
+		d, err := time.ParseDuration(perKB)
+		if err != nil {
+			return nil, fmt.Errorf("invalid per-kb-out duration: %w", err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("per-kb-out duration cannot be negative")
+		}
+		lb.Type = "per-kb-out"
+		lb.Value = d
+	} else if strings.Contains(value, "-") {
 		// Range: "50-200ms" or "50ms-200ms"
 		parts := strings.Split(value, "-")
 		if len(parts) != 2 {
@@ -74,6 +121,9 @@ func parseLatency(value string) (*LatencyBehavior, error) {
 			lb.Min = min
 			lb.Max = max
 		}
+		if lb.Min > lb.Max {
+			return nil, fmt.Errorf("range min %s is greater than max %s", lb.Min, lb.Max)
+		}
 	} else {
 		// Fixed: "100ms"
 		d, err := time.ParseDuration(value)
@@ -95,13 +145,19 @@ func (b *Behavior) applyLatency(ctx context.Context) error {
 	case "fixed":
 		delay = b.Latency.Value
 	case "range":
-		// Random duration between min and max
-		diff := b.Latency.Max - b.Latency.Min
-		delay = b.Latency.Min + time.Duration(rand.Int63n(int64(diff)))
+		// Random duration between min and max (Int63n panics on an empty range)
+		delay = b.Latency.Min
+		if diff := b.Latency.Max - b.Latency.Min; diff > 0 {
+			delay += time.Duration(b.Random().Int63n(int64(diff)))
+		}
+	case "per-kb-out":
+		// Depends on the response body, applied when the response is sent
+		return nil
 	default:
 		delay = b.Latency.Value
 	}
 
+	delay = b.Latency.jittered(delay, b.Random())
 	if delay > 0 {
 		select {
 		case <-time.After(delay):
@@ -114,14 +170,44 @@ func (b *Behavior) applyLatency(ctx context.Context) error {
 	return nil
 }
 
+// OutputLatency returns the delay for sending a response body of the given size
+// under latency=per-kb-out, or 0 for any other latency mode
+func (b *Behavior) OutputLatency(bodyBytes int) time.Duration {
+	if b.Latency == nil || b.Latency.Type != "per-kb-out" {
+		return 0
+	}
+	return b.Latency.jittered(time.Duration(float64(b.Latency.Value)*float64(bodyBytes)/1024), b.Random())
+}
+
+// ApplyOutputLatency delays sending a response body of the given size
+func (b *Behavior) ApplyOutputLatency(ctx context.Context, bodyBytes int) error {
+	return sleepContext(ctx, b.OutputLatency(bodyBytes))
+}
+
 func init() {
 	registerParser("latency", func(b *Behavior, value string) error {
 		latency, err := parseLatency(value)
 		if err != nil {
 			return fmt.Errorf("invalid latency: %w", err)
 		}
+		// Keep a jitter given before the latency
+		if b.Latency != nil {
+			latency.Jitter = b.Latency.Jitter
+		}
 		b.Latency = latency
 		return nil
 	})
+
+	registerParser("jitter", func(b *Behavior, value string) error {
+		jitter, err := parseJitter(value)
+		if err != nil {
+			return fmt.Errorf("invalid jitter: %w", err)
+		}
+		if b.Latency == nil {
+			b.Latency = &LatencyBehavior{}
+		}
+		b.Latency.Jitter = jitter
+		return nil
+	})
 }
 
@@ -67,6 +67,44 @@ func TestParseLatency(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:      "per-kb-out latency",
+			input:     "latency=per-kb-out:5ms",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Latency.Type != "per-kb-out" {
+					t.Errorf("expected per-kb-out type, got %s", b.Latency.Type)
+				}
+				if b.Latency.Value != 5*time.Millisecond {
+					t.Errorf("expected 5ms per KB, got %v", b.Latency.Value)
+				}
+			},
+		},
+		{
+			name:      "per-kb-out invalid duration",
+			input:     "latency=per-kb-out:fast",
+			wantError: true,
+		},
+		{
+			name:      "range latency with equal bounds",
+			input:     "latency=100ms-100ms",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Latency.Min != 100*time.Millisecond || b.Latency.Max != 100*time.Millisecond {
+					t.Errorf("expected 100ms-100ms, got %v-%v", b.Latency.Min, b.Latency.Max)
+				}
+			},
+		},
+		{
+			name:      "range latency with inverted bounds",
+			input:     "latency=200ms-50ms",
+			wantError: true,
+		},
+		{
+			name:      "range latency with inverted bounds and unit only on max",
+			input:     "latency=200-50ms",
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -115,6 +153,19 @@ func TestApplyLatency(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "range latency with equal bounds",
+			input: "latency=50ms-50ms",
+			validate: func(t *testing.T, start time.Time, b *Behavior) {
+				elapsed := time.Since(start)
+				if elapsed < 50*time.Millisecond {
+					t.Errorf("expected at least 50ms delay, got %v", elapsed)
+				}
+				if elapsed > 100*time.Millisecond {
+					t.Errorf("expected around 50ms delay, got %v (too long)", elapsed)
+				}
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -152,6 +203,11 @@ func TestLatencyString(t *testing.T) {
 			input:    "latency=50ms-200ms",
 			expected: "latency=50ms-200ms",
 		},
+		{
+			name:     "latency per kb out",
+			input:    "latency=per-kb-out:5ms",
+			expected: "latency=per-kb-out:5ms",
+		},
 	}
 
 	for _, tt := range tests {
@@ -168,3 +224,142 @@ func TestLatencyString(t *testing.T) {
 	}
 }
 
+func TestApplyOutputLatency(t *testing.T) {
+	b, err := Parse("latency=per-kb-out:5ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Nothing is applied up front - the delay depends on the response body
+	start := time.Now()
+	if err := b.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("expected no up-front delay, got %v", elapsed)
+	}
+
+	if d := b.OutputLatency(10 * 1024); d != 50*time.Millisecond {
+		t.Errorf("OutputLatency(10KB) = %v, want 50ms", d)
+	}
+
+	measure := func(size int) time.Duration {
+		start := time.Now()
+		if err := b.ApplyOutputLatency(context.Background(), size); err != nil {
+			t.Fatalf("ApplyOutputLatency() failed: %v", err)
+		}
+		return time.Since(start)
+	}
+
+	small := measure(4 * 1024)  // ~20ms
+	large := measure(40 * 1024) // ~200ms
+	if small < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms for 4KB, got %v", small)
+	}
+	if large < 200*time.Millisecond || large > 300*time.Millisecond {
+		t.Errorf("expected ~200ms for 40KB, got %v", large)
+	}
+	if large < 5*small {
+		t.Errorf("expected 10x body to take proportionally longer: small=%v large=%v", small, large)
+	}
+
+	// Other latency modes don't add output latency
+	fixed, _ := Parse("latency=100ms")
+	if d := fixed.OutputLatency(1024 * 1024); d != 0 {
+		t.Errorf("expected no output latency for fixed latency, got %v", d)
+	}
+}
+
+func TestParseJitter(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantError  bool
+		wantJitter float64
+		wantValue  time.Duration
+	}{
+		{name: "with latency", input: "latency=100ms,jitter=20%", wantJitter: 20, wantValue: 100 * time.Millisecond},
+		{name: "jitter first", input: "jitter=20%,latency=100ms", wantJitter: 20, wantValue: 100 * time.Millisecond},
+		{name: "without percent sign", input: "latency=100ms,jitter=5", wantJitter: 5, wantValue: 100 * time.Millisecond},
+		{name: "fractional", input: "latency=100ms,jitter=2.5%", wantJitter: 2.5, wantValue: 100 * time.Millisecond},
+		{name: "above 100", input: "latency=100ms,jitter=150%", wantError: true},
+		{name: "negative", input: "latency=100ms,jitter=-5%", wantError: true},
+		{name: "invalid", input: "latency=100ms,jitter=lots", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Latency.Jitter != tt.wantJitter || b.Latency.Value != tt.wantValue {
+				t.Errorf("got %s ±%v%%, want %s ±%v%%", b.Latency.Value, b.Latency.Jitter, tt.wantValue, tt.wantJitter)
+			}
+		})
+	}
+}
+
+func TestJitterString(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"latency=100ms,jitter=20%", "latency=100ms,jitter=20"},
+		{"jitter=2.5%,latency=50ms-200ms", "latency=50ms-200ms,jitter=2.5"},
+		{"jitter=20", "jitter=20"},
+	}
+	for _, tt := range tests {
+		b, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+		}
+		if result := b.String(); result != tt.want {
+			t.Errorf("String() = %s, want %s", result, tt.want)
+		}
+	}
+}
+
+func TestJitter_StaysWithinBounds(t *testing.T) {
+	b, err := Parse("latency=100ms,jitter=20%")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	var below, above int
+	for i := 0; i < 1000; i++ {
+		d := b.Latency.jittered(b.Latency.Value, b.Random())
+		if d < 80*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("jittered delay %v outside [80ms,120ms]", d)
+		}
+		if d < 100*time.Millisecond {
+			below++
+		} else if d > 100*time.Millisecond {
+			above++
+		}
+	}
+
+	// Centered: delays land on both sides of the base latency
+	if below < 400 || above < 400 {
+		t.Errorf("expected delays spread around 100ms, got %d below and %d above", below, above)
+	}
+}
+
+func TestApplyLatency_Jitter(t *testing.T) {
+	b, err := Parse("latency=50ms,jitter=20%")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	if err := b.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 100*time.Millisecond {
+		t.Errorf("expected ~40-60ms delay, got %v", elapsed)
+	}
+}
@@ -0,0 +1,52 @@
+package behavior
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// LivenessBehavior flips the /health endpoint, causing kubelet to restart the pod
+type LivenessBehavior struct {
+	Healthy  bool
+	Duration time.Duration // How long /health fails when unhealthy
+}
+
+// String returns the string representation of liveness behavior
+func (lb *LivenessBehavior) String() string {
+	if lb.Healthy {
+		return "liveness=healthy"
+	}
+	return fmt.Sprintf("liveness=unhealthy:%s", lb.Duration)
+}
+
+// parseLiveness parses liveness specifications
+// Examples: "unhealthy", "unhealthy:60s", "healthy"
+func parseLiveness(value string) (*LivenessBehavior, error) {
+	healthy, d, err := parseProbeValue(value, 60*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &LivenessBehavior{Healthy: healthy, Duration: d}, nil
+}
+
+// applyLiveness updates the shared liveness state read by the /health endpoint
+func (b *Behavior) applyLiveness() {
+	if b.Liveness.Healthy {
+		service.Liveness.SetHealthy()
+		return
+	}
+	service.Liveness.SetUnhealthy(b.Liveness.Duration)
+}
+
+func init() {
+	registerParser("liveness", func(b *Behavior, value string) error {
+		liveness, err := parseLiveness(value)
+		if err != nil {
+			return fmt.Errorf("invalid liveness: %w", err)
+		}
+		b.Liveness = liveness
+		return nil
+	})
+}
@@ -0,0 +1,82 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLiveness(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "unhealthy with duration",
+			input:     "liveness=unhealthy:2m",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Liveness == nil {
+					t.Fatal("expected liveness behavior")
+				}
+				if b.Liveness.Healthy {
+					t.Error("expected unhealthy")
+				}
+				if b.Liveness.Duration != 2*time.Minute {
+					t.Errorf("expected duration 2m, got %s", b.Liveness.Duration)
+				}
+			},
+		},
+		{
+			name:      "unhealthy default duration",
+			input:     "liveness=unhealthy",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Liveness.Duration != 60*time.Second {
+					t.Errorf("expected default duration 60s, got %s", b.Liveness.Duration)
+				}
+			},
+		},
+		{
+			name:      "healthy",
+			input:     "liveness=healthy",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if !b.Liveness.Healthy {
+					t.Error("expected healthy")
+				}
+			},
+		},
+		{
+			name:      "invalid duration",
+			input:     "liveness=unhealthy:soon",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestLivenessString(t *testing.T) {
+	for _, input := range []string{"liveness=unhealthy:1m0s", "liveness=healthy"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LockBehavior simulates a hot distributed lock: up to Holders requests hold
+// it for Hold each while the rest queue behind them
+type LockBehavior struct {
+	Holders int           // Number of requests that may hold the lock at once
+	Hold    time.Duration // How long each holder keeps the lock
+}
+
+// String returns the string representation of lock behavior
+func (lb *LockBehavior) String() string {
+	return fmt.Sprintf("lock=contend:%d:hold:%s", lb.Holders, lb.Hold)
+}
+
+// parseLock parses lock specifications
+// Format: contend:N:hold:duration
+// Example: "contend:5:hold:500ms"
+func parseLock(value string) (*LockBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || parts[0] != "contend" || parts[2] != "hold" {
+		return nil, fmt.Errorf("invalid lock format: %s (expected contend:N:hold:duration)", value)
+	}
+
+	holders, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid holder count: %w", err)
+	}
+	if holders < 1 {
+		return nil, fmt.Errorf("holder count must be at least 1, got %d", holders)
+	}
+
+	hold, err := time.ParseDuration(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hold duration: %w", err)
+	}
+	if hold <= 0 {
+		return nil, fmt.Errorf("hold duration must be positive")
+	}
+
+	return &LockBehavior{Holders: holders, Hold: hold}, nil
+}
+
+// lockSemaphore is the shared lock for a service and lock spec
+type lockSemaphore struct {
+	slots chan struct{}
+}
+
+// applyLock waits for a lock slot, holds it for the configured duration and
+// releases it. Returns the time spent queueing.
+func (b *Behavior) applyLock(ctx context.Context, serviceName string) (time.Duration, error) {
+	if b.Lock == nil {
+		return 0, nil
+	}
+
+	holders := b.Lock.Holders
+	sem := loadState(serviceName+"/"+b.Lock.String(), func() *lockSemaphore {
+		return &lockSemaphore{slots: make(chan struct{}, holders)}
+	})
+
+	start := time.Now()
+	select {
+	case sem.slots <- struct{}{}:
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+	defer func() { <-sem.slots }()
+
+	return time.Since(start), sleepContext(ctx, b.Lock.Hold)
+}
+
+func init() {
+	registerParser("lock", func(b *Behavior, value string) error {
+		lock, err := parseLock(value)
+		if err != nil {
+			return fmt.Errorf("invalid lock: %w", err)
+		}
+		b.Lock = lock
+		return nil
+	})
+}
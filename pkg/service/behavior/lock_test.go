@@ -0,0 +1,116 @@
+package behavior
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseLock(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantError   bool
+		wantHolders int
+		wantHold    time.Duration
+	}{
+		{name: "valid", input: "lock=contend:5:hold:500ms", wantHolders: 5, wantHold: 500 * time.Millisecond},
+		{name: "single holder", input: "lock=contend:1:hold:2s", wantHolders: 1, wantHold: 2 * time.Second},
+		{name: "zero holders", input: "lock=contend:0:hold:500ms", wantError: true},
+		{name: "invalid holders", input: "lock=contend:many:hold:500ms", wantError: true},
+		{name: "invalid hold", input: "lock=contend:5:hold:long", wantError: true},
+		{name: "zero hold", input: "lock=contend:5:hold:0s", wantError: true},
+		{name: "missing hold", input: "lock=contend:5", wantError: true},
+		{name: "unknown keyword", input: "lock=share:5:hold:500ms", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Lock.Holders != tt.wantHolders || b.Lock.Hold != tt.wantHold {
+				t.Errorf("got %d:%s, want %d:%s", b.Lock.Holders, b.Lock.Hold, tt.wantHolders, tt.wantHold)
+			}
+		})
+	}
+}
+
+func TestLockString(t *testing.T) {
+	input := "lock=contend:5:hold:500ms"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestExecutor_LockSaturated(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("lock=contend:1:hold:50ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	const requests = 4
+	var wg sync.WaitGroup
+	waits := make([]time.Duration, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			executor := NewExecutor(b, "trace123", "lock-svc", &mockTelemetry{})
+			if _, err := executor.Execute(context.Background()); err != nil {
+				t.Errorf("Execute() error = %v", err)
+			}
+			wait, ok := executor.LockWait()
+			if !ok {
+				t.Errorf("expected the executor to report its lock wait")
+			}
+			waits[i] = wait
+		}()
+	}
+	wg.Wait()
+
+	// With a single holder, requests queue behind each other: waits of roughly 0, 50, 100 and 150ms
+	var sum time.Duration
+	for _, wait := range waits {
+		sum += wait
+	}
+	if sum < 250*time.Millisecond {
+		t.Errorf("expected waiters to accumulate at least 250ms of wait, got %s", sum)
+	}
+}
+
+func TestExecutor_LockUncontended(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("lock=contend:5:hold:10ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	executor := NewExecutor(b, "trace123", "lock-free-svc", &mockTelemetry{})
+	if _, err := executor.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("expected the lock to be held for 10ms, took %v", elapsed)
+	}
+
+	if wait, ok := executor.LockWait(); !ok || wait > 5*time.Millisecond {
+		t.Errorf("expected no lock wait when uncontended, got %s (reported %v)", wait, ok)
+	}
+}
@@ -0,0 +1,47 @@
+package behavior
+
+import (
+	"fmt"
+)
+
+// logLevels are the levels a request may log at
+var logLevels = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+
+// LogLevelBehavior overrides the log level for the requests of a single trace,
+// so a traced request can be inspected in detail while the rest stay sampled
+type LogLevelBehavior struct {
+	Level string // "debug", "info", "warn" or "error"
+}
+
+// String returns the string representation of loglevel behavior
+func (lb *LogLevelBehavior) String() string {
+	return fmt.Sprintf("loglevel=%s", lb.Level)
+}
+
+// parseLogLevel parses loglevel specifications
+// Examples: "debug", "warn"
+func parseLogLevel(value string) (*LogLevelBehavior, error) {
+	if !logLevels[value] {
+		return nil, fmt.Errorf("unknown level %q (expected debug, info, warn or error)", value)
+	}
+	return &LogLevelBehavior{Level: value}, nil
+}
+
+// LogLevelOverride returns the level the request should log at, or "" for the default
+func (b *Behavior) LogLevelOverride() string {
+	if b == nil || b.LogLevel == nil {
+		return ""
+	}
+	return b.LogLevel.Level
+}
+
+func init() {
+	registerParser("loglevel", func(b *Behavior, value string) error {
+		lb, err := parseLogLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid loglevel: %w", err)
+		}
+		b.LogLevel = lb
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package behavior
+
+import "testing"
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantLevel string
+	}{
+		{name: "debug", input: "loglevel=debug", wantLevel: "debug"},
+		{name: "error", input: "loglevel=error", wantLevel: "error"},
+		{name: "unknown level", input: "loglevel=verbose", wantError: true},
+		{name: "upper case", input: "loglevel=DEBUG", wantError: true},
+		{name: "empty", input: "loglevel=", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && b.LogLevelOverride() != tt.wantLevel {
+				t.Errorf("LogLevelOverride() = %q, want %q", b.LogLevelOverride(), tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestLogLevelString(t *testing.T) {
+	input := "latency=10ms,loglevel=debug"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestLogLevelOverride_Unset(t *testing.T) {
+	var nilBehavior *Behavior
+	if level := nilBehavior.LogLevelOverride(); level != "" {
+		t.Errorf("expected no override for nil behavior, got %q", level)
+	}
+	if level := (&Behavior{}).LogLevelOverride(); level != "" {
+		t.Errorf("expected no override without loglevel, got %q", level)
+	}
+}
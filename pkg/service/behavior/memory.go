@@ -12,16 +12,22 @@ import (
 
 // MemoryBehavior controls memory usage patterns
 type MemoryBehavior struct {
-	Pattern    string // "leak-slow", "leak-fast", "steady", "spike"
+	Pattern    string // "leak-slow", "leak-fast", "leak-rate", "steady", "spike"
 	Amount     int64  // Bytes to allocate
 	Duration   time.Duration
-	Percentage int // If >0, use percentage of container limit instead of Amount
+	Percentage int   // If >0, use percentage of container limit instead of Amount
+	Rate       int64 // Bytes per second for the leak-rate pattern
 }
 
 // String returns the string representation of memory behavior
 func (mb *MemoryBehavior) String() string {
 	memStr := ""
-	if strings.HasPrefix(mb.Pattern, "leak") {
+	if mb.Pattern == "leak-rate" {
+		memStr = fmt.Sprintf("memory=leak-rate:%s/s", formatBytes(mb.Rate))
+		if mb.Duration > 0 {
+			memStr += fmt.Sprintf(":%s", mb.Duration)
+		}
+	} else if strings.HasPrefix(mb.Pattern, "leak") {
 		memStr = fmt.Sprintf("memory=%s", mb.Pattern)
 		if mb.Duration > 0 {
 			memStr += fmt.Sprintf(":%s", mb.Duration)
@@ -43,7 +49,7 @@ func (mb *MemoryBehavior) String() string {
 }
 
 // parseMemory parses memory behavior specifications
-// Examples: "leak-slow", "leak-slow:10m", "10Mi", "1Gi", "spike:500Mi", "spike:80%:30s"
+// Examples: "leak-slow", "leak-slow:10m", "leak-rate:5Mi/s:10m", "10Mi", "1Gi", "spike:500Mi", "spike:80%:30s"
 func parseMemory(value string) (*MemoryBehavior, error) {
 	parts := strings.Split(value, ":")
 	mb := &MemoryBehavior{
@@ -89,6 +95,29 @@ func parseMemory(value string) (*MemoryBehavior, error) {
 			}
 			mb.Duration = d
 		}
+	} else if parts[0] == "leak-rate" {
+		// Rate pattern: leak-rate:5Mi/s or leak-rate:5Mi/s:10m
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("leak-rate requires a rate: leak-rate:5Mi/s")
+		}
+
+		rate, err := parseBytes(strings.TrimSuffix(parts[1], "/s"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid leak rate: %w", err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("leak rate must be positive")
+		}
+		mb.Rate = rate
+
+		// Parse optional duration
+		if len(parts) > 2 {
+			d, err := time.ParseDuration(parts[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid leak-rate duration: %w", err)
+			}
+			mb.Duration = d
+		}
 	} else if strings.HasPrefix(parts[0], "leak") {
 		// It's a leak pattern like "leak-slow" or "leak-fast"
 		if len(parts) > 1 {
@@ -117,7 +146,9 @@ func parseMemory(value string) (*MemoryBehavior, error) {
 
 // applyMemory applies memory allocation
 func (b *Behavior) applyMemory(ctx context.Context) {
+	endLoad := beginLoad()
 	go func() {
+		defer endLoad()
 		var memHog [][]byte
 		deadline := time.Now().Add(b.Memory.Duration)
 
@@ -126,33 +157,23 @@ func (b *Behavior) applyMemory(ctx context.Context) {
 
 		switch b.Memory.Pattern {
 		case "leak-slow":
-			interval := b.Memory.Duration / time.Duration(b.Memory.Amount/int64(allocSize))
-			ticker := time.NewTicker(interval)
-			defer ticker.Stop()
+			memHog = leakSlow(ctx, b.Memory.Amount, b.Memory.Duration)
+			if ctx.Err() != nil {
+				releaseMemory(&memHog)
+				return
+			}
 
-			for time.Now().Before(deadline) && totalAllocated < b.Memory.Amount {
-				select {
-				case <-ctx.Done():
-					return
-				case <-ticker.C:
-					chunk := make([]byte, allocSize)
-					// Touch the memory to ensure it's allocated
-					for i := 0; i < len(chunk); i += 4096 {
-						chunk[i] = byte(i)
-					}
-					memHog = append(memHog, chunk)
-					totalAllocated += int64(allocSize)
-				}
+		case "leak-rate":
+			memHog = leakAtRate(ctx, b.Memory.Rate, b.Memory.Duration)
+			if ctx.Err() != nil {
+				releaseMemory(&memHog)
+				return
 			}
 
 		case "leak-fast":
 			// Allocate quickly
 			for totalAllocated < b.Memory.Amount {
-				chunk := make([]byte, allocSize)
-				for i := 0; i < len(chunk); i += 4096 {
-					chunk[i] = byte(i)
-				}
-				memHog = append(memHog, chunk)
+				memHog = append(memHog, allocateChunk(int64(allocSize)))
 				totalAllocated += int64(allocSize)
 			}
 			time.Sleep(b.Memory.Duration)
@@ -171,31 +192,20 @@ func (b *Behavior) applyMemory(ctx context.Context) {
 				targetAmount = limit * int64(b.Memory.Percentage) / 100
 			}
 
-			// Allocate memory immediately in large chunks for faster allocation
-			largeChunkSize := 10 * 1024 * 1024 // 10MB chunks for speed
-			for totalAllocated < targetAmount {
-				// Allocate the remaining or one chunk, whichever is smaller
-				remaining := targetAmount - totalAllocated
-				chunkSize := largeChunkSize
-				if remaining < int64(chunkSize) {
-					chunkSize = int(remaining)
-				}
-
-				chunk := make([]byte, chunkSize)
-				// Touch all pages to ensure physical allocation
-				for i := 0; i < len(chunk); i += 4096 {
-					chunk[i] = byte(i)
-				}
-				memHog = append(memHog, chunk)
-				totalAllocated += int64(chunkSize)
+			var peak int64
+			memHog, peak = allocateSpike(ctx, targetAmount)
+			recordMemorySpike(peak)
+			if ctx.Err() != nil {
+				// Cancelled mid-allocation: release what was allocated so far
+				releaseMemory(&memHog)
+				return
 			}
 
 			// Hold for the specified duration
 			select {
 			case <-ctx.Done():
 				// Release and return early
-				memHog = nil
-				runtime.GC()
+				releaseMemory(&memHog)
 				return
 			case <-time.After(b.Memory.Duration):
 				// Duration elapsed, will release below
@@ -209,11 +219,122 @@ func (b *Behavior) applyMemory(ctx context.Context) {
 		}
 
 		// Allow GC to clean up
-		memHog = nil
-		runtime.GC()
+		releaseMemory(&memHog)
 	}()
 }
 
+// allocateChunk allocates size bytes, touching every page so the memory is
+// physically allocated, and records them as held
+func allocateChunk(size int64) []byte {
+	chunk := make([]byte, size)
+	for i := 0; i < len(chunk); i += 4096 {
+		chunk[i] = byte(i)
+	}
+	recordResource(ResourceMemory, size)
+	return chunk
+}
+
+// releaseMemory drops the held chunks, records their release and lets GC reclaim them
+func releaseMemory(memHog *[][]byte) {
+	var held int64
+	for _, chunk := range *memHog {
+		held += int64(len(chunk))
+	}
+	recordResource(ResourceMemory, -held)
+
+	*memHog = nil
+	runtime.GC()
+}
+
+// spikeChunk is the chunk size spikes allocate in, large for speed
+const spikeChunk = 10 * 1024 * 1024 // 10MB
+
+// allocateSpike allocates target bytes immediately in large chunks, stopping early
+// if ctx is done. Returns the chunks and the number of bytes allocated.
+func allocateSpike(ctx context.Context, target int64) ([][]byte, int64) {
+	var memHog [][]byte
+	var allocated int64
+	for allocated < target && ctx.Err() == nil {
+		// Allocate the remaining or one chunk, whichever is smaller
+		chunk := allocateChunk(min(int64(spikeChunk), target-allocated))
+		memHog = append(memHog, chunk)
+		allocated += int64(len(chunk))
+	}
+	return memHog, allocated
+}
+
+// leakSlowChunk is the largest chunk leakSlow allocates at a time
+const leakSlowChunk = 1024 * 1024 // 1MB
+
+// leakSlowSchedule splits amount into chunks of at most leakSlowChunk spread evenly
+// over duration, returning the chunk size, the number of chunks and the interval
+// between them. Amounts below one chunk are a single chunk; an interval of zero
+// (a zero duration) means allocate everything at once.
+func leakSlowSchedule(amount int64, duration time.Duration) (int64, int64, time.Duration) {
+	if amount <= 0 {
+		return 0, 0, 0
+	}
+
+	chunkSize := min(int64(leakSlowChunk), amount)
+	chunks := (amount + chunkSize - 1) / chunkSize
+	interval := max(duration/time.Duration(chunks), 0)
+	return chunkSize, chunks, interval
+}
+
+// leakSlow allocates amount bytes in chunks spread evenly over duration, or until
+// ctx is done, returning the allocated chunks so the caller can hold them
+func leakSlow(ctx context.Context, amount int64, duration time.Duration) [][]byte {
+	var memHog [][]byte
+
+	chunkSize, chunks, interval := leakSlowSchedule(amount, duration)
+	remaining := amount
+	for i := int64(0); i < chunks; i++ {
+		if interval > 0 {
+			select {
+			case <-ctx.Done():
+				return memHog
+			case <-time.After(interval):
+			}
+		}
+
+		chunk := allocateChunk(min(chunkSize, remaining))
+		memHog = append(memHog, chunk)
+		remaining -= int64(len(chunk))
+	}
+	return memHog
+}
+
+// leakRateTick is how often leakAtRate allocates its share of the per-second rate
+const leakRateTick = 100 * time.Millisecond
+
+// leakAtRate allocates memory at the given rate (bytes/second) until duration
+// elapses or ctx is done, returning the allocated chunks so the caller can hold them
+func leakAtRate(ctx context.Context, rate int64, duration time.Duration) [][]byte {
+	var memHog [][]byte
+
+	chunkSize := rate * int64(leakRateTick) / int64(time.Second)
+	if chunkSize <= 0 {
+		chunkSize = 1
+	}
+
+	ticker := time.NewTicker(leakRateTick)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return memHog
+		case <-timer.C:
+			return memHog
+		case <-ticker.C:
+			memHog = append(memHog, allocateChunk(chunkSize))
+		}
+	}
+}
+
 func init() {
 	registerParser("memory", func(b *Behavior, value string) error {
 		mem, err := parseMemory(value)
@@ -1,6 +1,7 @@
 package behavior
 
 import (
+	"context"
 	"testing"
 	"time"
 )
@@ -83,6 +84,36 @@ func TestParseMemory(t *testing.T) {
 			input:     "memory=spike",
 			wantError: true,
 		},
+		{
+			name:      "memory leak rate with duration",
+			input:     "memory=leak-rate:5Mi/s:10m",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Memory == nil {
+					t.Fatal("expected memory behavior")
+				}
+				if b.Memory.Pattern != "leak-rate" {
+					t.Errorf("expected leak-rate pattern, got %s", b.Memory.Pattern)
+				}
+				expectedRate := int64(5 * 1024 * 1024)
+				if b.Memory.Rate != expectedRate {
+					t.Errorf("expected rate %d, got %d", expectedRate, b.Memory.Rate)
+				}
+				if b.Memory.Duration != 10*time.Minute {
+					t.Errorf("expected duration 10m, got %s", b.Memory.Duration)
+				}
+			},
+		},
+		{
+			name:      "memory leak rate without rate",
+			input:     "memory=leak-rate",
+			wantError: true,
+		},
+		{
+			name:      "memory leak rate with invalid rate",
+			input:     "memory=leak-rate:fast/s",
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -99,3 +130,176 @@ func TestParseMemory(t *testing.T) {
 	}
 }
 
+func TestMemoryLeakRateString(t *testing.T) {
+	input := "memory=leak-rate:5Mi/s:10m0s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := b.String(); got != input {
+		t.Errorf("String() = %q, want %q", got, input)
+	}
+
+	reparsed, err := Parse(b.String())
+	if err != nil {
+		t.Fatalf("Parse(String()) error = %v", err)
+	}
+	if reparsed.Memory.Rate != b.Memory.Rate || reparsed.Memory.Duration != b.Memory.Duration {
+		t.Errorf("round trip mismatch: got %+v, want %+v", reparsed.Memory, b.Memory)
+	}
+}
+
+func TestLeakAtRate(t *testing.T) {
+	rate := int64(10 * 1024 * 1024) // 10Mi/s
+	duration := 500 * time.Millisecond
+
+	start := time.Now()
+	memHog := leakAtRate(context.Background(), rate, duration)
+	elapsed := time.Since(start)
+
+	var allocated int64
+	for _, chunk := range memHog {
+		allocated += int64(len(chunk))
+	}
+
+	expected := rate * int64(elapsed) / int64(time.Second)
+	tolerance := rate * int64(2*leakRateTick) / int64(time.Second)
+	if allocated < expected-tolerance || allocated > expected+tolerance {
+		t.Errorf("allocated %d bytes in %s, expected ~%d (±%d)", allocated, elapsed, expected, tolerance)
+	}
+}
+
+func TestLeakAtRateContextCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	leakAtRate(ctx, 1024*1024, time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("leakAtRate did not stop on context cancel, took %s", elapsed)
+	}
+}
+
+func TestLeakSlowSchedule(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       int64
+		duration     time.Duration
+		wantChunk    int64
+		wantChunks   int64
+		wantInterval time.Duration
+	}{
+		{name: "default", amount: 10 * 1024 * 1024, duration: 10 * time.Minute, wantChunk: 1024 * 1024, wantChunks: 10, wantInterval: time.Minute},
+		{name: "below one chunk", amount: 512 * 1024, duration: 5 * time.Minute, wantChunk: 512 * 1024, wantChunks: 1, wantInterval: 5 * time.Minute},
+		{name: "partial last chunk", amount: 1536 * 1024, duration: time.Minute, wantChunk: 1024 * 1024, wantChunks: 2, wantInterval: 30 * time.Second},
+		{name: "one byte", amount: 1, duration: time.Second, wantChunk: 1, wantChunks: 1, wantInterval: time.Second},
+		{name: "zero duration", amount: 10 * 1024 * 1024, duration: 0, wantChunk: 1024 * 1024, wantChunks: 10, wantInterval: 0},
+		{name: "negative duration", amount: 1024, duration: -time.Second, wantChunk: 1024, wantChunks: 1, wantInterval: 0},
+		{name: "zero amount", amount: 0, duration: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk, chunks, interval := leakSlowSchedule(tt.amount, tt.duration)
+			if chunk != tt.wantChunk || chunks != tt.wantChunks || interval != tt.wantInterval {
+				t.Errorf("leakSlowSchedule() = %d x %d every %s, want %d x %d every %s",
+					chunks, chunk, interval, tt.wantChunks, tt.wantChunk, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestLeakSlow(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		duration time.Duration
+	}{
+		{name: "tiny amount", amount: 512 * 1024, duration: 20 * time.Millisecond},
+		{name: "partial last chunk", amount: 1536 * 1024, duration: 20 * time.Millisecond},
+		{name: "zero duration", amount: 3 * 1024 * 1024, duration: 0},
+		{name: "tiny amount, zero duration", amount: 100, duration: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memHog := leakSlow(context.Background(), tt.amount, tt.duration)
+
+			var allocated int64
+			for _, chunk := range memHog {
+				allocated += int64(len(chunk))
+			}
+			if allocated != tt.amount {
+				t.Errorf("allocated %d bytes, want %d", allocated, tt.amount)
+			}
+		})
+	}
+}
+
+func TestLeakSlowContextCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	leakSlow(ctx, 10*1024*1024, time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("leakSlow did not stop on context cancel, took %s", elapsed)
+	}
+}
+
+func TestAllocateSpike(t *testing.T) {
+	target := int64(25 * 1024 * 1024)
+	memHog, allocated := allocateSpike(context.Background(), target)
+	if allocated != target {
+		t.Errorf("allocated %d bytes, want %d", allocated, target)
+	}
+	if len(memHog) != 3 {
+		t.Errorf("expected 3 chunks (10Mi, 10Mi, 5Mi), got %d", len(memHog))
+	}
+}
+
+func TestAllocateSpikeContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled spike stops before allocating the rest of a huge target
+	memHog, allocated := allocateSpike(ctx, 1<<40)
+	if allocated != 0 || len(memHog) != 0 {
+		t.Errorf("expected no allocation after cancel, got %d bytes", allocated)
+	}
+}
+
+func TestMemorySpikeReportsPeak(t *testing.T) {
+	b, err := Parse("memory=spike:3Mi:10ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	r := newFakeRecorder(t)
+	b.applyMemory(context.Background())
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.mu.Lock()
+		spikes := append([]float64(nil), r.spikes...)
+		r.mu.Unlock()
+		if len(spikes) > 0 {
+			if len(spikes) != 1 || spikes[0] != 3*1024*1024 {
+				t.Fatalf("expected one spike peak of %d bytes, got %v", 3*1024*1024, spikes)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the spike peak to be recorded")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Let the spike release before other tests run
+	for LoadActive() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the spike to be released")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
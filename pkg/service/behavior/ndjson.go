@@ -0,0 +1,121 @@
+package behavior
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NDJSONBehavior streams the response as newline-delimited JSON, one line per interval
+type NDJSONBehavior struct {
+	Lines    int           // Number of lines to stream
+	Interval time.Duration // Pause between lines
+}
+
+// String returns the string representation of ndjson behavior
+func (nb *NDJSONBehavior) String() string {
+	if nb.Interval == 0 {
+		return fmt.Sprintf("ndjson=lines:%d", nb.Lines)
+	}
+	return fmt.Sprintf("ndjson=lines:%d:interval:%s", nb.Lines, nb.Interval)
+}
+
+// ndjsonLine is a single streamed line; Response is the service response, repeated on every line
+type ndjsonLine struct {
+	Seq      int             `json:"seq"`
+	Lines    int             `json:"lines"`
+	Response json.RawMessage `json:"response"`
+}
+
+// parseNDJSON parses ndjson specifications
+// Format: lines:N[:interval:duration]
+// Examples: "lines:100", "lines:100:interval:50ms"
+func parseNDJSON(value string) (*NDJSONBehavior, error) {
+	parts := strings.Split(value, ":")
+	if (len(parts) != 2 && len(parts) != 4) || parts[0] != "lines" {
+		return nil, fmt.Errorf("invalid ndjson format: %s (expected lines:N[:interval:duration])", value)
+	}
+
+	lines, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid line count: %w", err)
+	}
+	if lines < 1 {
+		return nil, fmt.Errorf("line count must be at least 1, got %d", lines)
+	}
+
+	nb := &NDJSONBehavior{Lines: lines}
+	if len(parts) == 4 {
+		if parts[2] != "interval" {
+			return nil, fmt.Errorf("unknown ndjson option: %s (expected interval)", parts[2])
+		}
+		interval, err := time.ParseDuration(parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		if interval < 0 {
+			return nil, fmt.Errorf("interval cannot be negative")
+		}
+		nb.Interval = interval
+	}
+
+	return nb, nil
+}
+
+// StreamsNDJSON reports whether the response should be streamed as NDJSON
+func (b *Behavior) StreamsNDJSON() bool {
+	return b != nil && b.NDJSON != nil
+}
+
+// StreamNDJSON writes the configured number of NDJSON lines, each wrapping the
+// JSON response, flushing after every line and pausing for the interval in
+// between. The Content-Type must already be set to application/x-ndjson and
+// the status written. Stops early if ctx is cancelled.
+func (b *Behavior) StreamNDJSON(ctx context.Context, w http.ResponseWriter, response []byte) error {
+	if b.NDJSON == nil {
+		return nil
+	}
+
+	// Each line must be a single line of JSON
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, response); err != nil {
+		return fmt.Errorf("compact response: %w", err)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for seq := 1; seq <= b.NDJSON.Lines; seq++ {
+		if seq > 1 {
+			if err := sleepContext(ctx, b.NDJSON.Interval); err != nil {
+				return err
+			}
+		}
+
+		line, err := json.Marshal(ndjsonLine{Seq: seq, Lines: b.NDJSON.Lines, Response: compact.Bytes()})
+		if err != nil {
+			return fmt.Errorf("marshal line %d: %w", seq, err)
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
+
+func init() {
+	registerParser("ndjson", func(b *Behavior, value string) error {
+		nb, err := parseNDJSON(value)
+		if err != nil {
+			return fmt.Errorf("invalid ndjson: %w", err)
+		}
+		b.NDJSON = nb
+		return nil
+	})
+}
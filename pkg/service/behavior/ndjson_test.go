@@ -0,0 +1,122 @@
+package behavior
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNDJSON(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantError    bool
+		wantLines    int
+		wantInterval time.Duration
+	}{
+		{name: "lines and interval", input: "ndjson=lines:100:interval:50ms", wantLines: 100, wantInterval: 50 * time.Millisecond},
+		{name: "lines only", input: "ndjson=lines:10", wantLines: 10},
+		{name: "zero lines", input: "ndjson=lines:0", wantError: true},
+		{name: "invalid lines", input: "ndjson=lines:many", wantError: true},
+		{name: "invalid interval", input: "ndjson=lines:10:interval:soon", wantError: true},
+		{name: "negative interval", input: "ndjson=lines:10:interval:-1s", wantError: true},
+		{name: "unknown option", input: "ndjson=lines:10:delay:50ms", wantError: true},
+		{name: "missing lines keyword", input: "ndjson=100", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.NDJSON.Lines != tt.wantLines || b.NDJSON.Interval != tt.wantInterval {
+				t.Errorf("got %d:%s, want %d:%s", b.NDJSON.Lines, b.NDJSON.Interval, tt.wantLines, tt.wantInterval)
+			}
+		})
+	}
+}
+
+func TestNDJSONString(t *testing.T) {
+	for _, input := range []string{"ndjson=lines:100:interval:50ms", "ndjson=lines:10"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestStreamNDJSON(t *testing.T) {
+	b, err := Parse("ndjson=lines:5:interval:20ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	if err := b.StreamNDJSON(context.Background(), rec, []byte("{\n  \"code\": 200\n}")); err != nil {
+		t.Fatalf("StreamNDJSON() error = %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// 4 pauses between 5 lines
+	if elapsed < 80*time.Millisecond {
+		t.Errorf("expected lines to be paced over at least 80ms, took %v", elapsed)
+	}
+	if !rec.Flushed {
+		t.Error("expected lines to be flushed")
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(rec.Body.String()))
+	seq := 0
+	for scanner.Scan() {
+		seq++
+		var line struct {
+			Seq      int `json:"seq"`
+			Lines    int `json:"lines"`
+			Response struct {
+				Code int `json:"code"`
+			} `json:"response"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v (%q)", seq, err, scanner.Text())
+		}
+		if line.Seq != seq || line.Lines != 5 || line.Response.Code != 200 {
+			t.Errorf("line %d = %+v", seq, line)
+		}
+	}
+	if seq != 5 {
+		t.Errorf("expected 5 lines, got %d", seq)
+	}
+}
+
+func TestStreamNDJSON_Cancelled(t *testing.T) {
+	b, err := Parse("ndjson=lines:100:interval:50ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 120*time.Millisecond)
+	defer cancel()
+
+	rec := httptest.NewRecorder()
+	err = b.StreamNDJSON(ctx, rec, []byte(`{}`))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if lines := strings.Count(rec.Body.String(), "\n"); lines < 1 || lines > 4 {
+		t.Errorf("expected the stream to stop after a few lines, got %d", lines)
+	}
+}
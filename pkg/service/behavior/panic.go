@@ -2,7 +2,6 @@ package behavior
 
 import (
 	"fmt"
-	"math/rand"
 	"strconv"
 )
 
@@ -32,7 +31,7 @@ func (b *Behavior) ShouldPanic() bool {
 		return false
 	}
 
-	return rand.Float64() < b.Panic.Prob
+	return b.Random().Float64() < b.Panic.Prob
 }
 
 func init() {
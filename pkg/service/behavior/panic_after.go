@@ -0,0 +1,53 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// PanicAfterBehavior crashes the pod deterministically after N requests
+type PanicAfterBehavior struct {
+	Count int64 // Number of requests served before panicking
+}
+
+// String returns the string representation of panic-after behavior
+func (pb *PanicAfterBehavior) String() string {
+	return fmt.Sprintf("panic-after=%d", pb.Count)
+}
+
+// parsePanicAfter parses panic-after specifications
+// Examples: "10", "100"
+func parsePanicAfter(value string) (*PanicAfterBehavior, error) {
+	count, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("count must be at least 1, got %d", count)
+	}
+	return &PanicAfterBehavior{Count: count}, nil
+}
+
+// ShouldPanicAfter counts this request against the service's shared counter and
+// reports whether the Nth request has been reached, along with the current count
+func (b *Behavior) ShouldPanicAfter(serviceName string) (bool, int64) {
+	if b.PanicAfter == nil {
+		return false, 0
+	}
+
+	counter := loadState(serviceName+"/panic-after", func() *atomic.Int64 { return &atomic.Int64{} })
+	n := counter.Add(1)
+	return n >= b.PanicAfter.Count, n
+}
+
+func init() {
+	registerParser("panic-after", func(b *Behavior, value string) error {
+		panicAfter, err := parsePanicAfter(value)
+		if err != nil {
+			return fmt.Errorf("invalid panic-after: %w", err)
+		}
+		b.PanicAfter = panicAfter
+		return nil
+	})
+}
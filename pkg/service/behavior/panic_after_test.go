@@ -0,0 +1,83 @@
+package behavior
+
+import (
+	"testing"
+)
+
+func TestParsePanicAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "panic after 10 requests",
+			input:     "panic-after=10",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.PanicAfter == nil {
+					t.Fatal("expected panic-after behavior")
+				}
+				if b.PanicAfter.Count != 10 {
+					t.Errorf("expected count 10, got %d", b.PanicAfter.Count)
+				}
+			},
+		},
+		{
+			name:      "zero count",
+			input:     "panic-after=0",
+			wantError: true,
+		},
+		{
+			name:      "invalid count",
+			input:     "panic-after=ten",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestPanicAfterString(t *testing.T) {
+	b, err := Parse("panic-after=25")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "panic-after=25" {
+		t.Errorf("String() = %s, want panic-after=25", result)
+	}
+}
+
+func TestShouldPanicAfterCountsPerService(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b := &Behavior{PanicAfter: &PanicAfterBehavior{Count: 3}}
+
+	for i := 1; i <= 2; i++ {
+		if shouldPanic, n := b.ShouldPanicAfter("svc-a"); shouldPanic {
+			t.Fatalf("did not expect panic on request %d (count %d)", i, n)
+		}
+	}
+
+	// Another service has its own counter
+	if shouldPanic, _ := b.ShouldPanicAfter("svc-b"); shouldPanic {
+		t.Error("did not expect panic for svc-b on its first request")
+	}
+
+	if shouldPanic, n := b.ShouldPanicAfter("svc-a"); !shouldPanic || n != 3 {
+		t.Errorf("expected panic on 3rd request, got %v (count %d)", shouldPanic, n)
+	}
+}
@@ -0,0 +1,78 @@
+package behavior
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PoisonBehavior crashes the process when a request body matches a trigger,
+// modelling a poison-pill message taking down a consumer
+type PoisonBehavior struct {
+	Type     string   // "body-contains"
+	Triggers []string // Any match crashes
+}
+
+// String returns the string representation of poison-on behavior
+func (pb *PoisonBehavior) String() string {
+	return fmt.Sprintf("poison-on=%s:%s", pb.Type, strings.Join(pb.Triggers, ";"))
+}
+
+// parsePoison parses poison-on specifications
+// Format: "body-contains:trigger1;trigger2"
+// Examples: "body-contains:DROP_TABLE", "body-contains:DROP_TABLE;<script>"
+// Note: Uses semicolon to separate multiple triggers (comma is used for behavior separation)
+func parsePoison(value string) (*PoisonBehavior, error) {
+	colonIdx := strings.Index(value, ":")
+	if colonIdx < 0 {
+		return nil, fmt.Errorf("invalid format: expected 'body-contains:trigger'")
+	}
+
+	matchType := strings.TrimSpace(value[:colonIdx])
+	if matchType != "body-contains" {
+		return nil, fmt.Errorf("unknown match type %q (expected body-contains)", matchType)
+	}
+
+	var triggers []string
+	for _, trigger := range strings.Split(value[colonIdx+1:], ";") {
+		if trimmed := strings.TrimSpace(trigger); trimmed != "" {
+			triggers = append(triggers, trimmed)
+		}
+	}
+	if len(triggers) == 0 {
+		return nil, fmt.Errorf("at least one trigger string required")
+	}
+
+	return &PoisonBehavior{Type: matchType, Triggers: triggers}, nil
+}
+
+// ShouldPoison checks the request body against the poison triggers
+// Returns true and the matched trigger if the body is poisonous
+func (b *Behavior) ShouldPoison(body []byte) (bool, string) {
+	if b.Poison == nil || len(body) == 0 {
+		return false, ""
+	}
+
+	for _, trigger := range b.Poison.Triggers {
+		if bytes.Contains(body, []byte(trigger)) {
+			return true, trigger
+		}
+	}
+	return false, ""
+}
+
+// redact hides message content in logs, keeping only its length
+func redact(s string) string {
+	return fmt.Sprintf("[REDACTED %d bytes]", len(s))
+}
+
+func init() {
+	registerParser("poison-on", func(b *Behavior, value string) error {
+		poison, err := parsePoison(value)
+		if err != nil {
+			return fmt.Errorf("invalid poison-on: %w", err)
+		}
+		b.Poison = poison
+		return nil
+	})
+}
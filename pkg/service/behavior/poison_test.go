@@ -0,0 +1,125 @@
+package behavior
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParsePoison(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "single trigger",
+			input:     "poison-on=body-contains:DROP_TABLE",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Poison == nil {
+					t.Fatal("expected poison behavior")
+				}
+				if len(b.Poison.Triggers) != 1 || b.Poison.Triggers[0] != "DROP_TABLE" {
+					t.Errorf("unexpected triggers %v", b.Poison.Triggers)
+				}
+			},
+		},
+		{
+			name:      "multiple triggers",
+			input:     "poison-on=body-contains:DROP_TABLE;<script>",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.Poison.Triggers) != 2 {
+					t.Errorf("expected 2 triggers, got %v", b.Poison.Triggers)
+				}
+			},
+		},
+		{
+			name:      "unknown match type",
+			input:     "poison-on=header-contains:DROP_TABLE",
+			wantError: true,
+		},
+		{
+			name:      "missing trigger",
+			input:     "poison-on=body-contains:",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestPoisonString(t *testing.T) {
+	input := "poison-on=body-contains:DROP_TABLE;<script>"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestExecutor_PoisonMessage(t *testing.T) {
+	tel := &mockTelemetry{}
+	behavior := &Behavior{
+		Poison: &PoisonBehavior{Type: "body-contains", Triggers: []string{"DROP_TABLE"}},
+	}
+	executor := NewExecutor(behavior, "trace123", "test-service", tel).
+		WithRequestBody([]byte(`{"query": "DROP_TABLE users"}`))
+
+	// Should panic
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic, but did not panic")
+		}
+		if len(tel.fatals) == 0 {
+			t.Error("Expected Fatal to be called before panic")
+		}
+		if strings.Contains(r.(string), "DROP_TABLE") {
+			t.Errorf("Expected trigger to be redacted from panic message, got %q", r)
+		}
+	}()
+
+	executor.Execute(context.Background())
+}
+
+func TestExecutor_PoisonCleanBody(t *testing.T) {
+	tel := &mockTelemetry{}
+	behavior := &Behavior{
+		Poison: &PoisonBehavior{Type: "body-contains", Triggers: []string{"DROP_TABLE"}},
+	}
+	executor := NewExecutor(behavior, "trace123", "test-service", tel).
+		WithRequestBody([]byte(`{"query": "SELECT * FROM users"}`))
+
+	result, err := executor.Execute(context.Background())
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("Expected nil result for clean body, got %+v", result)
+	}
+	if len(tel.fatals) != 0 {
+		t.Errorf("Expected no Fatal for clean body, got %v", tel.fatals)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	if got := redact("DROP_TABLE"); strings.Contains(got, "DROP") {
+		t.Errorf("redact() leaked content: %s", got)
+	}
+}
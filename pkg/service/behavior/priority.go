@@ -0,0 +1,110 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// defaultPriorityThreshold is the in-flight request count above which low-priority requests are delayed
+const defaultPriorityThreshold = 1
+
+// PriorityBehavior delays low-priority requests while the service is under contention,
+// modelling a scheduler that serves high-priority work first
+type PriorityBehavior struct {
+	Header    string        // Request header carrying the priority
+	Delay     time.Duration // Latency added to low-priority requests under contention
+	Threshold int64         // Contention means more than this many requests in flight
+}
+
+// String returns the string representation of priority behavior
+func (pb *PriorityBehavior) String() string {
+	s := fmt.Sprintf("priority=header:%s:low-latency:%s", pb.Header, pb.Delay)
+	if pb.Threshold != defaultPriorityThreshold {
+		s += fmt.Sprintf(":over:%d", pb.Threshold)
+	}
+	return s
+}
+
+// parsePriority parses priority specifications
+// Format: header:<name>:low-latency:<delay>[:over:<inFlight>]
+// Examples: "header:X-Priority:low-latency:2s", "header:X-Priority:low-latency:500ms:over:10"
+func parsePriority(value string) (*PriorityBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts)%2 != 0 {
+		return nil, fmt.Errorf("invalid format: %s (expected header:<name>:low-latency:<delay>[:over:<n>])", value)
+	}
+
+	pb := &PriorityBehavior{Threshold: defaultPriorityThreshold}
+	for i := 0; i < len(parts); i += 2 {
+		key, val := parts[i], parts[i+1]
+		switch key {
+		case "header":
+			if val == "" {
+				return nil, fmt.Errorf("header name is required")
+			}
+			pb.Header = val
+		case "low-latency":
+			delay, err := time.ParseDuration(val)
+			if err != nil {
+				return nil, fmt.Errorf("invalid low-latency: %w", err)
+			}
+			if delay <= 0 {
+				return nil, fmt.Errorf("low-latency must be positive")
+			}
+			pb.Delay = delay
+		case "over":
+			threshold, err := strconv.ParseInt(val, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid over: %w", err)
+			}
+			if threshold < 0 {
+				return nil, fmt.Errorf("over cannot be negative, got %d", threshold)
+			}
+			pb.Threshold = threshold
+		default:
+			return nil, fmt.Errorf("unknown key %q (expected header, low-latency or over)", key)
+		}
+	}
+
+	if pb.Header == "" {
+		return nil, fmt.Errorf("header is required")
+	}
+	if pb.Delay == 0 {
+		return nil, fmt.Errorf("low-latency is required")
+	}
+
+	return pb, nil
+}
+
+// lowPriority reports whether the request headers mark it as low priority
+func (pb *PriorityBehavior) lowPriority(headers http.Header) bool {
+	return strings.EqualFold(strings.TrimSpace(headers.Get(pb.Header)), "low")
+}
+
+// priorityDelay returns the latency to add to this request: the configured delay for
+// low-priority requests while more than Threshold requests are in flight, otherwise 0
+func (b *Behavior) priorityDelay(headers http.Header) time.Duration {
+	if b.Priority == nil || !b.Priority.lowPriority(headers) {
+		return 0
+	}
+	if service.InFlight.Count() <= b.Priority.Threshold {
+		return 0
+	}
+	return b.Priority.Delay
+}
+
+func init() {
+	registerParser("priority", func(b *Behavior, value string) error {
+		priority, err := parsePriority(value)
+		if err != nil {
+			return fmt.Errorf("invalid priority: %w", err)
+		}
+		b.Priority = priority
+		return nil
+	})
+}
@@ -0,0 +1,101 @@
+package behavior
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantError     bool
+		wantHeader    string
+		wantDelay     time.Duration
+		wantThreshold int64
+	}{
+		{name: "header and delay", input: "priority=header:X-Priority:low-latency:2s", wantHeader: "X-Priority", wantDelay: 2 * time.Second, wantThreshold: 1},
+		{name: "with threshold", input: "priority=header:X-Priority:low-latency:500ms:over:10", wantHeader: "X-Priority", wantDelay: 500 * time.Millisecond, wantThreshold: 10},
+		{name: "missing header", input: "priority=low-latency:2s", wantError: true},
+		{name: "missing delay", input: "priority=header:X-Priority", wantError: true},
+		{name: "invalid delay", input: "priority=header:X-Priority:low-latency:slow", wantError: true},
+		{name: "negative threshold", input: "priority=header:X-Priority:low-latency:2s:over:-1", wantError: true},
+		{name: "unknown key", input: "priority=header:X-Priority:high-latency:2s", wantError: true},
+		{name: "dangling key", input: "priority=header:X-Priority:low-latency", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			p := b.Priority
+			if p.Header != tt.wantHeader || p.Delay != tt.wantDelay || p.Threshold != tt.wantThreshold {
+				t.Errorf("got %s:%s:%d, want %s:%s:%d", p.Header, p.Delay, p.Threshold, tt.wantHeader, tt.wantDelay, tt.wantThreshold)
+			}
+		})
+	}
+}
+
+func TestPriorityString(t *testing.T) {
+	for _, input := range []string{
+		"priority=header:X-Priority:low-latency:2s",
+		"priority=header:X-Priority:low-latency:500ms:over:10",
+	} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestExecutor_PriorityUnderContention(t *testing.T) {
+	b, err := Parse("priority=header:X-Priority:low-latency:100ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	run := func(priority string) time.Duration {
+		headers := http.Header{}
+		if priority != "" {
+			headers.Set("X-Priority", priority)
+		}
+		start := time.Now()
+		if _, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).WithHeaders(headers).Execute(context.Background()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return time.Since(start)
+	}
+
+	// Uncontended: only this request is in flight, so nothing is delayed
+	done := service.InFlight.Begin()
+	if elapsed := run("low"); elapsed >= 100*time.Millisecond {
+		t.Errorf("expected uncontended low-priority request to be fast, took %v", elapsed)
+	}
+
+	// Contended: another request is in flight
+	doneOther := service.InFlight.Begin()
+	if elapsed := run("low"); elapsed < 100*time.Millisecond {
+		t.Errorf("expected low-priority request to be delayed under contention, took %v", elapsed)
+	}
+	if elapsed := run("high"); elapsed >= 100*time.Millisecond {
+		t.Errorf("expected high-priority request to be fast under contention, took %v", elapsed)
+	}
+	if elapsed := run(""); elapsed >= 100*time.Millisecond {
+		t.Errorf("expected request without priority to be fast under contention, took %v", elapsed)
+	}
+	doneOther()
+	done()
+}
@@ -0,0 +1,147 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// grpcStatusToHTTP maps gRPC status names to the HTTP codes the gRPC server translates back
+var grpcStatusToHTTP = map[string]int{
+	"INVALID_ARGUMENT":   400,
+	"UNAUTHENTICATED":    401,
+	"PERMISSION_DENIED":  403,
+	"NOT_FOUND":          404,
+	"ALREADY_EXISTS":     409,
+	"RESOURCE_EXHAUSTED": 429,
+	"CANCELLED":          499,
+	"INTERNAL":           500,
+	"UNIMPLEMENTED":      501,
+	"UNAVAILABLE":        503,
+	"DEADLINE_EXCEEDED":  504,
+}
+
+// ProtocolDivergenceBehavior returns different outcomes for the same call depending
+// on the protocol it arrived over, modelling a partial protocol outage
+type ProtocolDivergenceBehavior struct {
+	Outcomes []ProtocolOutcome
+}
+
+// ProtocolOutcome is the result for requests over one protocol
+type ProtocolOutcome struct {
+	Protocol string // "http" or "grpc"
+	Code     int    // HTTP status code to fail with, 0 = ok
+	Status   string // gRPC status name the code was given as, if any
+}
+
+// String returns the string representation of a single protocol outcome
+func (o ProtocolOutcome) String() string {
+	switch {
+	case o.Code == 0:
+		return o.Protocol + ":ok"
+	case o.Status != "":
+		return fmt.Sprintf("%s:error=%s", o.Protocol, o.Status)
+	default:
+		return fmt.Sprintf("%s:error=%d", o.Protocol, o.Code)
+	}
+}
+
+// String returns the string representation of protocol-divergence behavior
+// Format: protocol-divergence=http:ok:grpc:error=UNAVAILABLE
+func (pd *ProtocolDivergenceBehavior) String() string {
+	var parts []string
+	for _, o := range pd.Outcomes {
+		parts = append(parts, o.String())
+	}
+	return fmt.Sprintf("protocol-divergence=%s", strings.Join(parts, ":"))
+}
+
+// parseProtocolDivergence parses protocol-divergence specifications
+// Format: protocol:outcome[:protocol:outcome], outcome is "ok" or "error=<code>"
+// where code is an HTTP status or a gRPC status name
+// Examples: "http:ok:grpc:error=UNAVAILABLE", "grpc:error=503"
+func parseProtocolDivergence(value string) (*ProtocolDivergenceBehavior, error) {
+	fields := strings.Split(value, ":")
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("invalid format: %s (expected protocol:outcome[:protocol:outcome])", value)
+	}
+
+	pd := &ProtocolDivergenceBehavior{}
+	seen := make(map[string]bool)
+	for i := 0; i < len(fields); i += 2 {
+		protocol := strings.TrimSpace(fields[i])
+		if protocol != "http" && protocol != "grpc" {
+			return nil, fmt.Errorf("unknown protocol %q (expected http or grpc)", protocol)
+		}
+		if seen[protocol] {
+			return nil, fmt.Errorf("protocol %s given more than once", protocol)
+		}
+		seen[protocol] = true
+
+		outcome, err := parseProtocolOutcome(protocol, strings.TrimSpace(fields[i+1]))
+		if err != nil {
+			return nil, err
+		}
+		pd.Outcomes = append(pd.Outcomes, outcome)
+	}
+
+	if len(pd.Outcomes) == 0 {
+		return nil, fmt.Errorf("no protocol outcomes found")
+	}
+
+	return pd, nil
+}
+
+// parseProtocolOutcome parses "ok" or "error=<code>" for a protocol
+func parseProtocolOutcome(protocol, value string) (ProtocolOutcome, error) {
+	o := ProtocolOutcome{Protocol: protocol}
+	if value == "ok" {
+		return o, nil
+	}
+
+	code, ok := strings.CutPrefix(value, "error=")
+	if !ok {
+		return o, fmt.Errorf("invalid outcome for %s: %q (expected ok or error=<code>)", protocol, value)
+	}
+
+	if httpCode, known := grpcStatusToHTTP[strings.ToUpper(code)]; known {
+		o.Code = httpCode
+		o.Status = strings.ToUpper(code)
+		return o, nil
+	}
+
+	httpCode, err := strconv.Atoi(code)
+	if err != nil {
+		return o, fmt.Errorf("invalid error code for %s: %q (expected HTTP status or gRPC status name)", protocol, code)
+	}
+	if httpCode < 400 || httpCode > 599 {
+		return o, fmt.Errorf("error code for %s must be between 400 and 599, got %d", protocol, httpCode)
+	}
+	o.Code = httpCode
+	return o, nil
+}
+
+// ShouldDiverge determines if a request over the given protocol should fail.
+// Returns true and the HTTP status code to return if so.
+func (b *Behavior) ShouldDiverge(protocol string) (bool, int) {
+	if b.ProtocolDivergence == nil {
+		return false, 0
+	}
+	for _, o := range b.ProtocolDivergence.Outcomes {
+		if o.Protocol == protocol && o.Code != 0 {
+			return true, o.Code
+		}
+	}
+	return false, 0
+}
+
+func init() {
+	registerParser("protocol-divergence", func(b *Behavior, value string) error {
+		pd, err := parseProtocolDivergence(value)
+		if err != nil {
+			return fmt.Errorf("invalid protocol-divergence: %w", err)
+		}
+		b.ProtocolDivergence = pd
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package behavior
+
+import "testing"
+
+func TestParseProtocolDivergence(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantHTTP  int
+		wantGRPC  int
+	}{
+		{name: "grpc status name", input: "protocol-divergence=http:ok:grpc:error=UNAVAILABLE", wantGRPC: 503},
+		{name: "lowercase status name", input: "protocol-divergence=grpc:error=deadline_exceeded", wantGRPC: 504},
+		{name: "http status code", input: "protocol-divergence=http:error=502:grpc:ok", wantHTTP: 502},
+		{name: "both fail", input: "protocol-divergence=http:error=500:grpc:error=INTERNAL", wantHTTP: 500, wantGRPC: 500},
+		{name: "unknown protocol", input: "protocol-divergence=tcp:ok", wantError: true},
+		{name: "duplicate protocol", input: "protocol-divergence=http:ok:http:error=503", wantError: true},
+		{name: "missing outcome", input: "protocol-divergence=http:ok:grpc", wantError: true},
+		{name: "unknown outcome", input: "protocol-divergence=grpc:fail", wantError: true},
+		{name: "unknown status name", input: "protocol-divergence=grpc:error=BROKEN", wantError: true},
+		{name: "non-error code", input: "protocol-divergence=http:error=200", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if _, code := b.ShouldDiverge("http"); code != tt.wantHTTP {
+				t.Errorf("http code = %d, want %d", code, tt.wantHTTP)
+			}
+			if _, code := b.ShouldDiverge("grpc"); code != tt.wantGRPC {
+				t.Errorf("grpc code = %d, want %d", code, tt.wantGRPC)
+			}
+		})
+	}
+}
+
+func TestProtocolDivergenceString(t *testing.T) {
+	for _, input := range []string{
+		"protocol-divergence=http:ok:grpc:error=UNAVAILABLE",
+		"protocol-divergence=http:error=502",
+	} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestShouldDiverge(t *testing.T) {
+	b, err := Parse("protocol-divergence=http:ok:grpc:error=UNAVAILABLE")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if diverge, _ := b.ShouldDiverge("http"); diverge {
+		t.Error("expected http requests to succeed")
+	}
+	if diverge, code := b.ShouldDiverge("grpc"); !diverge || code != 503 {
+		t.Errorf("expected grpc requests to fail with 503, got %v/%d", diverge, code)
+	}
+
+	var none Behavior
+	if diverge, _ := none.ShouldDiverge("grpc"); diverge {
+		t.Error("expected no divergence without the behavior")
+	}
+}
@@ -0,0 +1,100 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueueBehavior models admission control: up to Size requests are served at once,
+// the rest wait up to MaxWait for a slot before being rejected with 503
+type QueueBehavior struct {
+	Size    int           // Requests served at once
+	MaxWait time.Duration // How long an excess request waits for a slot (0 = reject immediately)
+}
+
+// String returns the string representation of queue behavior
+func (qb *QueueBehavior) String() string {
+	return fmt.Sprintf("queue=%d:%s", qb.Size, qb.MaxWait)
+}
+
+// parseQueue parses queue specifications
+// Format: size:maxWait
+// Examples: "10:500ms", "4:0s"
+func parseQueue(value string) (*QueueBehavior, error) {
+	sizeStr, waitStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid queue format: %s (expected size:maxWait)", value)
+	}
+
+	size, err := strconv.Atoi(sizeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid size: %w", err)
+	}
+	if size < 1 {
+		return nil, fmt.Errorf("size must be at least 1, got %d", size)
+	}
+
+	maxWait, err := time.ParseDuration(waitStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max wait: %w", err)
+	}
+	if maxWait < 0 {
+		return nil, fmt.Errorf("max wait cannot be negative")
+	}
+
+	return &QueueBehavior{Size: size, MaxWait: maxWait}, nil
+}
+
+// queueSemaphore holds the slots of a service's queue
+type queueSemaphore struct {
+	slots chan struct{}
+}
+
+// AcquireQueueSlot waits up to MaxWait for a slot of the service's queue. When admitted
+// it returns a func releasing the slot, to be called once the request completes; when no
+// slot frees up in time, admitted is false. Without a queue behavior every request is
+// admitted. An error is returned if ctx is done while waiting.
+func (b *Behavior) AcquireQueueSlot(ctx context.Context, serviceName string) (release func(), admitted bool, err error) {
+	if b == nil || b.Queue == nil {
+		return func() {}, true, nil
+	}
+
+	size := b.Queue.Size
+	sem := loadState(serviceName+"/"+b.Queue.String(), func() *queueSemaphore {
+		return &queueSemaphore{slots: make(chan struct{}, size)}
+	})
+	release = func() { <-sem.slots }
+
+	// A free slot admits the request without waiting
+	select {
+	case sem.slots <- struct{}{}:
+		return release, true, nil
+	default:
+	}
+
+	timer := time.NewTimer(b.Queue.MaxWait)
+	defer timer.Stop()
+
+	select {
+	case sem.slots <- struct{}{}:
+		return release, true, nil
+	case <-timer.C:
+		return nil, false, nil
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+func init() {
+	registerParser("queue", func(b *Behavior, value string) error {
+		qb, err := parseQueue(value)
+		if err != nil {
+			return fmt.Errorf("invalid queue: %w", err)
+		}
+		b.Queue = qb
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseQueue(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantError   bool
+		wantSize    int
+		wantMaxWait time.Duration
+	}{
+		{name: "size and wait", input: "queue=10:500ms", wantSize: 10, wantMaxWait: 500 * time.Millisecond},
+		{name: "no waiting", input: "queue=4:0s", wantSize: 4, wantMaxWait: 0},
+		{name: "missing wait", input: "queue=10", wantError: true},
+		{name: "zero size", input: "queue=0:1s", wantError: true},
+		{name: "invalid size", input: "queue=many:1s", wantError: true},
+		{name: "invalid wait", input: "queue=10:soon", wantError: true},
+		{name: "negative wait", input: "queue=10:-1s", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Queue.Size != tt.wantSize {
+				t.Errorf("Size = %d, want %d", b.Queue.Size, tt.wantSize)
+			}
+			if b.Queue.MaxWait != tt.wantMaxWait {
+				t.Errorf("MaxWait = %v, want %v", b.Queue.MaxWait, tt.wantMaxWait)
+			}
+		})
+	}
+}
+
+func TestQueueString(t *testing.T) {
+	input := "queue=10:500ms"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestAcquireQueueSlot(t *testing.T) {
+	defer resetState()
+	ctx := context.Background()
+
+	var nilBehavior *Behavior
+	if release, admitted, err := nilBehavior.AcquireQueueSlot(ctx, "svc"); !admitted || err != nil {
+		t.Fatalf("expected nil behavior to admit, got %v, %v", admitted, err)
+	} else {
+		release()
+	}
+
+	b, err := Parse("queue=2:50ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	release1, admitted, _ := b.AcquireQueueSlot(ctx, "svc")
+	if !admitted {
+		t.Fatal("expected first request to be admitted")
+	}
+	release2, admitted, _ := b.AcquireQueueSlot(ctx, "svc")
+	if !admitted {
+		t.Fatal("expected second request to be admitted")
+	}
+
+	// Queue is full: the next request waits maxWait, then is rejected
+	start := time.Now()
+	if _, admitted, err := b.AcquireQueueSlot(ctx, "svc"); admitted || err != nil {
+		t.Fatalf("expected request over the queue size to time out, got %v, %v", admitted, err)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected to wait at least 50ms, waited %v", elapsed)
+	}
+
+	// Other services have their own queue
+	if release, admitted, _ := b.AcquireQueueSlot(ctx, "other"); !admitted {
+		t.Error("expected another service's queue to admit")
+	} else {
+		release()
+	}
+
+	// A waiting request is admitted once a slot is released
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		release1()
+	}()
+	release3, admitted, err := b.AcquireQueueSlot(ctx, "svc")
+	if !admitted || err != nil {
+		t.Fatalf("expected waiting request to be admitted after a release, got %v, %v", admitted, err)
+	}
+	release2()
+	release3()
+}
+
+func TestAcquireQueueSlot_ContextCanceled(t *testing.T) {
+	defer resetState()
+
+	b, err := Parse("queue=1:10s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	release, _, _ := b.AcquireQueueSlot(context.Background(), "svc")
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, admitted, err := b.AcquireQueueSlot(ctx, "svc"); admitted || err == nil {
+		t.Fatalf("expected canceled wait to fail, got %v, %v", admitted, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected wait to end with the context, waited %v", elapsed)
+	}
+}
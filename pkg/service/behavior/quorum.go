@@ -0,0 +1,101 @@
+package behavior
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// QuorumBehavior simulates quorum loss in a StatefulSet-backed cluster
+type QuorumBehavior struct {
+	Replicas int // Cluster size the quorum is computed from
+	Lose     int // Replicas treated as unavailable (the highest ordinals)
+}
+
+// String returns the string representation of quorum behavior
+func (qb *QuorumBehavior) String() string {
+	return fmt.Sprintf("quorum=%d:lose:%d", qb.Replicas, qb.Lose)
+}
+
+// parseQuorum parses quorum specifications
+// Format: replicas:lose:N
+// Example: "3:lose:2" (3-replica cluster with 2 replicas lost)
+func parseQuorum(value string) (*QuorumBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 || parts[1] != "lose" {
+		return nil, fmt.Errorf("invalid format: %s (expected replicas:lose:N)", value)
+	}
+
+	replicas, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid replicas: %w", err)
+	}
+	if replicas <= 0 {
+		return nil, fmt.Errorf("replicas must be positive, got %d", replicas)
+	}
+
+	lose, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lose count: %w", err)
+	}
+	if lose < 0 || lose > replicas {
+		return nil, fmt.Errorf("lose must be between 0 and %d, got %d", replicas, lose)
+	}
+
+	return &QuorumBehavior{Replicas: replicas, Lose: lose}, nil
+}
+
+// Majority returns the number of replicas needed for quorum
+func (qb *QuorumBehavior) Majority() int {
+	return qb.Replicas/2 + 1
+}
+
+// Available returns the number of replicas that are still reachable
+func (qb *QuorumBehavior) Available() int {
+	return qb.Replicas - qb.Lose
+}
+
+// hasQuorum reports whether the pod with the given ordinal can reach a quorum.
+// Lost replicas are the highest ordinals, so a pod with ordinal >= Available()
+// is on the minority side even if the rest of the cluster still has quorum.
+// A negative ordinal (unknown) decides on the available count alone.
+func (qb *QuorumBehavior) hasQuorum(ordinal int) bool {
+	if ordinal >= qb.Available() {
+		return false
+	}
+	return qb.Available() >= qb.Majority()
+}
+
+// podOrdinal extracts the StatefulSet ordinal from a pod name ("db-2" -> 2), or -1 if there is none
+func podOrdinal(podName string) int {
+	idx := strings.LastIndex(podName, "-")
+	if idx == -1 {
+		return -1
+	}
+	ordinal, err := strconv.Atoi(podName[idx+1:])
+	if err != nil || ordinal < 0 {
+		return -1
+	}
+	return ordinal
+}
+
+// ShouldRejectNoQuorum determines if the request should fail with 503 because
+// this pod (identified by POD_NAME) cannot reach a quorum of replicas
+func (b *Behavior) ShouldRejectNoQuorum() bool {
+	if b.Quorum == nil {
+		return false
+	}
+	return !b.Quorum.hasQuorum(podOrdinal(os.Getenv("POD_NAME")))
+}
+
+func init() {
+	registerParser("quorum", func(b *Behavior, value string) error {
+		quorum, err := parseQuorum(value)
+		if err != nil {
+			return fmt.Errorf("invalid quorum: %w", err)
+		}
+		b.Quorum = quorum
+		return nil
+	})
+}
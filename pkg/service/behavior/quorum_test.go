@@ -0,0 +1,135 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseQuorum(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "lose two of three",
+			input:     "quorum=3:lose:2",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Quorum == nil {
+					t.Fatal("expected quorum behavior")
+				}
+				if b.Quorum.Replicas != 3 || b.Quorum.Lose != 2 {
+					t.Errorf("expected 3:lose:2, got %+v", b.Quorum)
+				}
+			},
+		},
+		{
+			name:      "lose none",
+			input:     "quorum=5:lose:0",
+			wantError: false,
+		},
+		{
+			name:      "missing lose keyword",
+			input:     "quorum=3:2",
+			wantError: true,
+		},
+		{
+			name:      "lose more than replicas",
+			input:     "quorum=3:lose:4",
+			wantError: true,
+		},
+		{
+			name:      "zero replicas",
+			input:     "quorum=0:lose:0",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestQuorumString(t *testing.T) {
+	b, err := Parse("quorum=3:lose:2")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := b.String(); got != "quorum=3:lose:2" {
+		t.Errorf("String() = %q, want %q", got, "quorum=3:lose:2")
+	}
+}
+
+func TestPodOrdinal(t *testing.T) {
+	tests := map[string]int{
+		"db-0":                0,
+		"db-2":                2,
+		"my-stateful-set-10":  10,
+		"db":                  -1,
+		"web-7d9f8b6c4-xk2p9": -1,
+		"":                    -1,
+	}
+	for name, want := range tests {
+		if got := podOrdinal(name); got != want {
+			t.Errorf("podOrdinal(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestExecutor_Quorum(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		podName  string
+		wantCode int // 0 = request proceeds
+	}{
+		{name: "below quorum", spec: "quorum=3:lose:2", podName: "db-0", wantCode: 503},
+		{name: "at quorum", spec: "quorum=3:lose:1", podName: "db-0", wantCode: 0},
+		{name: "above quorum", spec: "quorum=5:lose:1", podName: "db-1", wantCode: 0},
+		{name: "lost replica with cluster at quorum", spec: "quorum=3:lose:1", podName: "db-2", wantCode: 503},
+		{name: "no ordinal below quorum", spec: "quorum=3:lose:2", podName: "", wantCode: 503},
+		{name: "no ordinal at quorum", spec: "quorum=3:lose:1", podName: "", wantCode: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("POD_NAME", tt.podName)
+			b, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			result, err := NewExecutor(b, "trace123", "db", &mockTelemetry{}).Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if tt.wantCode == 0 {
+				if result != nil {
+					t.Errorf("expected request to proceed, got %+v", result)
+				}
+				return
+			}
+			if result == nil || !result.ShouldReturn {
+				t.Fatalf("expected early return, got %+v", result)
+			}
+			if result.StatusCode != tt.wantCode {
+				t.Errorf("StatusCode = %d, want %d", result.StatusCode, tt.wantCode)
+			}
+			if result.BehaviorType != "quorum" {
+				t.Errorf("BehaviorType = %q, want quorum", result.BehaviorType)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ReadinessBehavior flips the /ready endpoint
+type ReadinessBehavior struct {
+	Healthy  bool
+	Duration time.Duration // How long /ready fails when unhealthy
+}
+
+// String returns the string representation of readiness behavior
+func (rb *ReadinessBehavior) String() string {
+	if rb.Healthy {
+		return "readiness=healthy"
+	}
+	return fmt.Sprintf("readiness=unhealthy:%s", rb.Duration)
+}
+
+// parseReadiness parses readiness specifications
+// Examples: "unhealthy", "unhealthy:30s", "healthy"
+func parseReadiness(value string) (*ReadinessBehavior, error) {
+	healthy, d, err := parseProbeValue(value, 30*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	return &ReadinessBehavior{Healthy: healthy, Duration: d}, nil
+}
+
+// parseProbeValue parses "healthy" or "unhealthy[:duration]" for probe behaviors
+func parseProbeValue(value string, defaultDuration time.Duration) (bool, time.Duration, error) {
+	parts := strings.Split(value, ":")
+
+	switch parts[0] {
+	case "healthy":
+		return true, 0, nil
+	case "unhealthy":
+		d := defaultDuration
+		if len(parts) > 1 {
+			parsed, err := time.ParseDuration(parts[1])
+			if err != nil {
+				return false, 0, fmt.Errorf("invalid duration: %w", err)
+			}
+			d = parsed
+		}
+		return false, d, nil
+	default:
+		return false, 0, fmt.Errorf("unknown state %q (expected healthy or unhealthy)", parts[0])
+	}
+}
+
+// applyReadiness updates the shared readiness state read by the /ready endpoint
+func (b *Behavior) applyReadiness() {
+	if b.Readiness.Healthy {
+		service.Readiness.SetHealthy()
+		return
+	}
+	service.Readiness.SetUnhealthy(b.Readiness.Duration)
+}
+
+func init() {
+	registerParser("readiness", func(b *Behavior, value string) error {
+		readiness, err := parseReadiness(value)
+		if err != nil {
+			return fmt.Errorf("invalid readiness: %w", err)
+		}
+		b.Readiness = readiness
+		return nil
+	})
+}
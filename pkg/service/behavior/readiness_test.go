@@ -0,0 +1,105 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseReadiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "unhealthy with duration",
+			input:     "readiness=unhealthy:45s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Readiness == nil {
+					t.Fatal("expected readiness behavior")
+				}
+				if b.Readiness.Healthy {
+					t.Error("expected unhealthy")
+				}
+				if b.Readiness.Duration != 45*time.Second {
+					t.Errorf("expected duration 45s, got %s", b.Readiness.Duration)
+				}
+			},
+		},
+		{
+			name:      "unhealthy default duration",
+			input:     "readiness=unhealthy",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Readiness.Duration != 30*time.Second {
+					t.Errorf("expected default duration 30s, got %s", b.Readiness.Duration)
+				}
+			},
+		},
+		{
+			name:      "healthy",
+			input:     "readiness=healthy",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if !b.Readiness.Healthy {
+					t.Error("expected healthy")
+				}
+			},
+		},
+		{
+			name:      "unknown state",
+			input:     "readiness=sick",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestReadinessString(t *testing.T) {
+	for _, input := range []string{"readiness=unhealthy:30s", "readiness=healthy"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestApplyReadiness(t *testing.T) {
+	defer service.Readiness.SetHealthy()
+
+	unhealthy, _ := Parse("readiness=unhealthy:1m")
+	if err := unhealthy.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if service.Readiness.Healthy() {
+		t.Error("expected readiness to be unhealthy")
+	}
+
+	healthy, _ := Parse("readiness=healthy")
+	if err := healthy.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if !service.Readiness.Healthy() {
+		t.Error("expected readiness to be restored")
+	}
+}
@@ -0,0 +1,47 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ReadyFromUpstreamsBehavior makes /ready reflect the reachability of the service's upstreams
+type ReadyFromUpstreamsBehavior struct {
+	Enabled bool
+}
+
+// String returns the string representation of ready-from-upstreams behavior
+func (rb *ReadyFromUpstreamsBehavior) String() string {
+	return fmt.Sprintf("ready-from-upstreams=%t", rb.Enabled)
+}
+
+// parseReadyFromUpstreams parses ready-from-upstreams specifications
+// Examples: "true", "false"
+func parseReadyFromUpstreams(value string) (*ReadyFromUpstreamsBehavior, error) {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("expected true or false, got %q", value)
+	}
+	return &ReadyFromUpstreamsBehavior{Enabled: enabled}, nil
+}
+
+// ApplyReadyFromUpstreams updates whether the /ready endpoint checks upstream reachability
+func (b *Behavior) ApplyReadyFromUpstreams() {
+	if b.ReadyFromUpstreams == nil {
+		return
+	}
+	service.UpstreamReadiness.SetEnabled(b.ReadyFromUpstreams.Enabled)
+}
+
+func init() {
+	registerParser("ready-from-upstreams", func(b *Behavior, value string) error {
+		readyFromUpstreams, err := parseReadyFromUpstreams(value)
+		if err != nil {
+			return fmt.Errorf("invalid ready-from-upstreams: %w", err)
+		}
+		b.ReadyFromUpstreams = readyFromUpstreams
+		return nil
+	})
+}
@@ -0,0 +1,55 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseReadyFromUpstreams(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantValue bool
+	}{
+		{name: "enabled", input: "ready-from-upstreams=true", wantValue: true},
+		{name: "disabled", input: "ready-from-upstreams=false", wantValue: false},
+		{name: "invalid", input: "ready-from-upstreams=sometimes", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.ReadyFromUpstreams == nil || b.ReadyFromUpstreams.Enabled != tt.wantValue {
+				t.Errorf("expected enabled=%v, got %+v", tt.wantValue, b.ReadyFromUpstreams)
+			}
+			if result := b.String(); result != tt.input {
+				t.Errorf("String() = %s, want %s", result, tt.input)
+			}
+		})
+	}
+}
+
+func TestApplyReadyFromUpstreams(t *testing.T) {
+	defer service.UpstreamReadiness.SetEnabled(false)
+
+	b, err := Parse("ready-from-upstreams=true")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if err := b.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if !service.UpstreamReadiness.Enabled() {
+		t.Error("expected upstream readiness to be enabled")
+	}
+}
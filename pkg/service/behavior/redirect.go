@@ -0,0 +1,49 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RedirectBehavior answers every request with a 3xx redirect
+type RedirectBehavior struct {
+	Code     int    // 3xx status code
+	Location string // Location header value, absolute or relative
+}
+
+// String returns the string representation of redirect behavior
+func (rb *RedirectBehavior) String() string {
+	return fmt.Sprintf("redirect=%d:%s", rb.Code, rb.Location)
+}
+
+// parseRedirect parses redirect specifications
+// Format: code:location (the location may itself contain colons)
+// Examples: "302:/login", "308:https://other/"
+func parseRedirect(value string) (*RedirectBehavior, error) {
+	codeStr, location, ok := strings.Cut(value, ":")
+	if !ok || location == "" {
+		return nil, fmt.Errorf("invalid format: %s (expected code:location)", value)
+	}
+
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid code: %w", err)
+	}
+	if code < 300 || code > 399 {
+		return nil, fmt.Errorf("code must be a 3xx redirect, got %d", code)
+	}
+
+	return &RedirectBehavior{Code: code, Location: location}, nil
+}
+
+func init() {
+	registerParser("redirect", func(b *Behavior, value string) error {
+		rb, err := parseRedirect(value)
+		if err != nil {
+			return fmt.Errorf("invalid redirect: %w", err)
+		}
+		b.Redirect = rb
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRedirect(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantError    bool
+		wantCode     int
+		wantLocation string
+	}{
+		{name: "relative location", input: "redirect=302:/login", wantCode: 302, wantLocation: "/login"},
+		{name: "absolute location", input: "redirect=308:https://other/", wantCode: 308, wantLocation: "https://other/"},
+		{name: "location with port", input: "redirect=301:http://other:8080/path", wantCode: 301, wantLocation: "http://other:8080/path"},
+		{name: "not a redirect code", input: "redirect=200:/login", wantError: true},
+		{name: "error code", input: "redirect=404:/login", wantError: true},
+		{name: "invalid code", input: "redirect=moved:/login", wantError: true},
+		{name: "missing location", input: "redirect=302", wantError: true},
+		{name: "empty location", input: "redirect=302:", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Redirect.Code != tt.wantCode || b.Redirect.Location != tt.wantLocation {
+				t.Errorf("got %d:%s, want %d:%s", b.Redirect.Code, b.Redirect.Location, tt.wantCode, tt.wantLocation)
+			}
+		})
+	}
+}
+
+func TestRedirectString(t *testing.T) {
+	input := "redirect=308:https://other/"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestRedirect_ServiceTargeting(t *testing.T) {
+	bc, err := ParseChain("latency=10ms,api:redirect=302:/login")
+	if err != nil {
+		t.Fatalf("ParseChain() failed: %v", err)
+	}
+
+	if b := bc.ForService("api"); b.Redirect == nil || b.Redirect.Location != "/login" {
+		t.Errorf("expected api to redirect to /login, got %+v", b.Redirect)
+	}
+	if b := bc.ForService("orders"); b == nil || b.Redirect != nil {
+		t.Errorf("expected only the global behavior for orders, got %+v", b)
+	}
+}
+
+func TestExecutor_Redirect(t *testing.T) {
+	b, err := Parse("redirect=302:/login")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	result, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result == nil || !result.ShouldReturn {
+		t.Fatalf("expected early exit, got %+v", result)
+	}
+	if result.StatusCode != 302 || result.Location != "/login" || result.BehaviorType != "redirect" {
+		t.Errorf("expected 302 redirect to /login, got %d %q %s", result.StatusCode, result.Location, result.BehaviorType)
+	}
+}
@@ -0,0 +1,84 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultReplicaStaleAfter is the lag above which replica reads are flagged stale
+const defaultReplicaStaleAfter = time.Second
+
+// ReplicaLagBehavior simulates a read replica serving data that trails the primary
+type ReplicaLagBehavior struct {
+	Lag        time.Duration // How far behind the primary the replica is
+	StaleAfter time.Duration // Lag above which responses are flagged stale
+}
+
+// ReplicaRead describes the data a lagging replica serves
+type ReplicaRead struct {
+	Lag   time.Duration
+	AsOf  time.Time // Point in time the data reflects (now minus lag)
+	Stale bool      // Lag exceeds the staleness threshold
+}
+
+// String returns the string representation of replica-lag behavior
+func (rl *ReplicaLagBehavior) String() string {
+	if rl.StaleAfter == defaultReplicaStaleAfter {
+		return fmt.Sprintf("replica-lag=%s", rl.Lag)
+	}
+	return fmt.Sprintf("replica-lag=%s:%s", rl.Lag, rl.StaleAfter)
+}
+
+// parseReplicaLag parses replica-lag specifications
+// Format: lag[:stale-after]
+// Examples: "5s", "500ms:100ms"
+func parseReplicaLag(value string) (*ReplicaLagBehavior, error) {
+	rl := &ReplicaLagBehavior{StaleAfter: defaultReplicaStaleAfter}
+
+	lagStr, staleStr, hasStale := strings.Cut(value, ":")
+	lag, err := time.ParseDuration(lagStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lag: %w", err)
+	}
+	if lag < 0 {
+		return nil, fmt.Errorf("lag cannot be negative")
+	}
+	rl.Lag = lag
+
+	if hasStale {
+		staleAfter, err := time.ParseDuration(staleStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stale threshold: %w", err)
+		}
+		if staleAfter < 0 {
+			return nil, fmt.Errorf("stale threshold cannot be negative")
+		}
+		rl.StaleAfter = staleAfter
+	}
+
+	return rl, nil
+}
+
+// ReplicaRead returns what a lagging replica serves at now, or nil if replica-lag is not set
+func (b *Behavior) ReplicaRead(now time.Time) *ReplicaRead {
+	if b.ReplicaLag == nil {
+		return nil
+	}
+	return &ReplicaRead{
+		Lag:   b.ReplicaLag.Lag,
+		AsOf:  now.Add(-b.ReplicaLag.Lag),
+		Stale: b.ReplicaLag.Lag > b.ReplicaLag.StaleAfter,
+	}
+}
+
+func init() {
+	registerParser("replica-lag", func(b *Behavior, value string) error {
+		rl, err := parseReplicaLag(value)
+		if err != nil {
+			return fmt.Errorf("invalid replica-lag: %w", err)
+		}
+		b.ReplicaLag = rl
+		return nil
+	})
+}
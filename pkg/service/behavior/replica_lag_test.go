@@ -0,0 +1,122 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReplicaLag(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "lag only",
+			input:     "replica-lag=5s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.ReplicaLag == nil {
+					t.Fatal("expected replica-lag behavior")
+				}
+				if b.ReplicaLag.Lag != 5*time.Second {
+					t.Errorf("expected lag 5s, got %v", b.ReplicaLag.Lag)
+				}
+				if b.ReplicaLag.StaleAfter != defaultReplicaStaleAfter {
+					t.Errorf("expected default stale threshold, got %v", b.ReplicaLag.StaleAfter)
+				}
+			},
+		},
+		{
+			name:      "lag with stale threshold",
+			input:     "replica-lag=500ms:100ms",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.ReplicaLag.Lag != 500*time.Millisecond || b.ReplicaLag.StaleAfter != 100*time.Millisecond {
+					t.Errorf("unexpected replica-lag %+v", b.ReplicaLag)
+				}
+			},
+		},
+		{
+			name:      "invalid lag",
+			input:     "replica-lag=soon",
+			wantError: true,
+		},
+		{
+			name:      "negative lag",
+			input:     "replica-lag=-1s",
+			wantError: true,
+		},
+		{
+			name:      "invalid threshold",
+			input:     "replica-lag=5s:x",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestReplicaLagString(t *testing.T) {
+	for _, input := range []string{"replica-lag=5s", "replica-lag=500ms:100ms"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+		if got := b.String(); got != input {
+			t.Errorf("String() = %q, want %q", got, input)
+		}
+	}
+}
+
+func TestReplicaRead(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		input     string
+		wantStale bool
+	}{
+		{name: "lag above threshold", input: "replica-lag=5s", wantStale: true},
+		{name: "lag at threshold", input: "replica-lag=1s", wantStale: false},
+		{name: "lag below custom threshold", input: "replica-lag=5s:10s", wantStale: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			read := b.ReplicaRead(now)
+			if read == nil {
+				t.Fatal("expected replica read")
+			}
+			if read.Lag != b.ReplicaLag.Lag {
+				t.Errorf("Lag = %v, want %v", read.Lag, b.ReplicaLag.Lag)
+			}
+			if !read.AsOf.Equal(now.Add(-b.ReplicaLag.Lag)) {
+				t.Errorf("AsOf = %v, want now minus lag", read.AsOf)
+			}
+			if read.Stale != tt.wantStale {
+				t.Errorf("Stale = %v, want %v", read.Stale, tt.wantStale)
+			}
+		})
+	}
+
+	if (&Behavior{}).ReplicaRead(now) != nil {
+		t.Error("expected nil replica read without replica-lag")
+	}
+}
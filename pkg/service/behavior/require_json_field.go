@@ -0,0 +1,88 @@
+package behavior
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RequireJSONFieldBehavior rejects requests whose JSON body lacks a field,
+// modelling gateway or schema validation
+type RequireJSONFieldBehavior struct {
+	Field string // Dotted path to the field (e.g. "order.id")
+	Code  int    // HTTP status code returned when the field is absent or the body is not JSON
+}
+
+// String returns the string representation of require-json-field behavior
+func (rb *RequireJSONFieldBehavior) String() string {
+	return fmt.Sprintf("require-json-field=%s:%d", rb.Field, rb.Code)
+}
+
+// parseRequireJSONField parses require-json-field specifications
+// Format: field[:code], code defaults to 400
+// Examples: "order_id:400", "customer.address.zip:422"
+func parseRequireJSONField(value string) (*RequireJSONFieldBehavior, error) {
+	field, codeStr, hasCode := strings.Cut(value, ":")
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return nil, fmt.Errorf("field is required")
+	}
+	for _, segment := range strings.Split(field, ".") {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid field path %q", field)
+		}
+	}
+
+	rb := &RequireJSONFieldBehavior{Field: field, Code: 400}
+	if hasCode {
+		code, err := strconv.Atoi(strings.TrimSpace(codeStr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code: %w", err)
+		}
+		if code < 100 || code > 599 {
+			return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+		}
+		rb.Code = code
+	}
+
+	return rb, nil
+}
+
+// ShouldRejectBody checks the request body for the required JSON field.
+// Returns true, the status code and the reason if the body is not JSON or lacks the field.
+func (b *Behavior) ShouldRejectBody(body []byte) (bool, int, string) {
+	if b.RequireJSONField == nil {
+		return false, 0, ""
+	}
+	rb := b.RequireJSONField
+
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return true, rb.Code, fmt.Sprintf("request body is not valid JSON: %v", err)
+	}
+
+	// Walk the dotted path through nested objects
+	current := doc
+	for _, segment := range strings.Split(rb.Field, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return true, rb.Code, fmt.Sprintf("required field %q is missing", rb.Field)
+		}
+		if current, ok = obj[segment]; !ok {
+			return true, rb.Code, fmt.Sprintf("required field %q is missing", rb.Field)
+		}
+	}
+	return false, 0, ""
+}
+
+func init() {
+	registerParser("require-json-field", func(b *Behavior, value string) error {
+		rb, err := parseRequireJSONField(value)
+		if err != nil {
+			return fmt.Errorf("invalid require-json-field: %w", err)
+		}
+		b.RequireJSONField = rb
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseRequireJSONField(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantField string
+		wantCode  int
+	}{
+		{name: "field and code", input: "require-json-field=order_id:400", wantField: "order_id", wantCode: 400},
+		{name: "default code", input: "require-json-field=order_id", wantField: "order_id", wantCode: 400},
+		{name: "nested field", input: "require-json-field=customer.address.zip:422", wantField: "customer.address.zip", wantCode: 422},
+		{name: "empty field", input: "require-json-field=:400", wantError: true},
+		{name: "empty path segment", input: "require-json-field=customer..zip", wantError: true},
+		{name: "invalid code", input: "require-json-field=order_id:bad", wantError: true},
+		{name: "code out of range", input: "require-json-field=order_id:600", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.RequireJSONField.Field != tt.wantField || b.RequireJSONField.Code != tt.wantCode {
+				t.Errorf("got %s:%d, want %s:%d", b.RequireJSONField.Field, b.RequireJSONField.Code, tt.wantField, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestRequireJSONFieldString(t *testing.T) {
+	b, err := Parse("require-json-field=customer.id")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result, want := b.String(), "require-json-field=customer.id:400"; result != want {
+		t.Errorf("String() = %s, want %s", result, want)
+	}
+}
+
+func TestExecutor_RequireJSONField(t *testing.T) {
+	tests := []struct {
+		name       string
+		behavior   string
+		body       string
+		wantReject bool
+	}{
+		{name: "field present", behavior: "require-json-field=order_id:400", body: `{"order_id": "o-1"}`},
+		{name: "field null still present", behavior: "require-json-field=order_id:400", body: `{"order_id": null}`},
+		{name: "field missing", behavior: "require-json-field=order_id:400", body: `{"customer": "c-1"}`, wantReject: true},
+		{name: "malformed json", behavior: "require-json-field=order_id:400", body: `{"order_id": `, wantReject: true},
+		{name: "empty body", behavior: "require-json-field=order_id:400", body: ``, wantReject: true},
+		{name: "not an object", behavior: "require-json-field=order_id:400", body: `["order_id"]`, wantReject: true},
+		{name: "nested field present", behavior: "require-json-field=customer.address.zip:400", body: `{"customer": {"address": {"zip": "0150"}}}`},
+		{name: "nested field missing", behavior: "require-json-field=customer.address.zip:400", body: `{"customer": {"address": {}}}`, wantReject: true},
+		{name: "nested parent not an object", behavior: "require-json-field=customer.address.zip:400", body: `{"customer": {"address": "Main St"}}`, wantReject: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.behavior)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+
+			result, err := NewExecutor(b, "trace123", "test-service", &mockTelemetry{}).
+				WithRequestBody([]byte(tt.body)).
+				Execute(context.Background())
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if !tt.wantReject {
+				if result != nil {
+					t.Errorf("expected request to proceed, got %+v", result)
+				}
+				return
+			}
+			if result == nil || !result.ShouldReturn || result.StatusCode != 400 {
+				t.Fatalf("expected 400 rejection, got %+v", result)
+			}
+			if result.BehaviorType != "require-json-field" {
+				t.Errorf("BehaviorType = %s, want require-json-field", result.BehaviorType)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package behavior
+
+import "sync"
+
+// Resources held by resource-exhaustion behaviors, as reported to the ResourceRecorder
+const (
+	ResourceMemory     = "memory"     // Bytes allocated by memory behaviors
+	ResourceDisk       = "disk"       // Bytes of disk filled by disk behaviors
+	ResourceGoroutines = "goroutines" // Goroutines spawned by goroutine-leak
+	ResourceFDs        = "fds"        // File descriptors opened by fd-leak
+)
+
+// ResourceRecorder is notified as behaviors acquire and release resources,
+// so the live footprint of injected faults can be exported as gauges
+type ResourceRecorder interface {
+	AddBehaviorResource(resource string, delta float64)
+
+	// ObserveMemorySpike records the bytes a memory spike allocated at its peak, so the
+	// spike size can be checked against its target
+	ObserveMemorySpike(bytes float64)
+}
+
+var (
+	recorderMu       sync.RWMutex
+	resourceRecorder ResourceRecorder
+)
+
+// SetResourceRecorder sets the recorder notified of resource changes; nil disables recording
+func SetResourceRecorder(r ResourceRecorder) {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	resourceRecorder = r
+}
+
+// recordResource reports that delta units of resource were acquired (or released, if negative)
+func recordResource(resource string, delta int64) {
+	recorderMu.RLock()
+	r := resourceRecorder
+	recorderMu.RUnlock()
+
+	if r != nil && delta != 0 {
+		r.AddBehaviorResource(resource, float64(delta))
+	}
+}
+
+// recordMemorySpike reports the bytes a memory spike allocated at its peak
+func recordMemorySpike(bytes int64) {
+	recorderMu.RLock()
+	r := resourceRecorder
+	recorderMu.RUnlock()
+
+	if r != nil {
+		r.ObserveMemorySpike(float64(bytes))
+	}
+}
@@ -0,0 +1,129 @@
+package behavior
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRecorder sums the resource changes reported by behaviors
+type fakeRecorder struct {
+	mu     sync.Mutex
+	values map[string]float64
+	peaks  map[string]float64
+	spikes []float64
+}
+
+func newFakeRecorder(t *testing.T) *fakeRecorder {
+	r := &fakeRecorder{values: make(map[string]float64), peaks: make(map[string]float64)}
+	SetResourceRecorder(r)
+	t.Cleanup(func() { SetResourceRecorder(nil) })
+	return r
+}
+
+func (r *fakeRecorder) AddBehaviorResource(resource string, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.values[resource] += delta
+	r.peaks[resource] = max(r.peaks[resource], r.values[resource])
+}
+
+func (r *fakeRecorder) ObserveMemorySpike(bytes float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spikes = append(r.spikes, bytes)
+}
+
+// waitFor polls until the resource reaches want or fails the test after a timeout
+func (r *fakeRecorder) waitFor(t *testing.T, resource string, want float64) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		r.mu.Lock()
+		got := r.values[resource]
+		r.mu.Unlock()
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s gauge at %v, got %v", resource, want, got)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestResourceRecorder_MemorySpike(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("memory=spike:3Mi:100ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	b.applyMemory(context.Background())
+	r.waitFor(t, ResourceMemory, 3*1024*1024)
+	r.waitFor(t, ResourceMemory, 0)
+}
+
+func TestResourceRecorder_MemoryCancel(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("memory=leak-rate:1Mi/s:1m")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.applyMemory(ctx)
+	time.Sleep(250 * time.Millisecond)
+	cancel()
+
+	r.waitFor(t, ResourceMemory, 0)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.peaks[ResourceMemory] == 0 {
+		t.Error("expected the gauge to rise while leaking")
+	}
+}
+
+func TestResourceRecorder_GoroutineLeak(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("goroutine-leak=50:100ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	b.applyGoroutineLeak(context.Background())
+	r.waitFor(t, ResourceGoroutines, 50)
+	r.waitFor(t, ResourceGoroutines, 0)
+}
+
+func TestResourceRecorder_FDLeak(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("fd-leak=10:100ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	b.applyFDLeak(context.Background())
+	r.waitFor(t, ResourceFDs, 10)
+	r.waitFor(t, ResourceFDs, 0)
+}
+
+func TestResourceRecorder_Disk(t *testing.T) {
+	r := newFakeRecorder(t)
+
+	b, err := Parse("disk=fill:1Mi:" + t.TempDir() + ":100ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if err := b.ApplyDisk(context.Background(), "trace123"); err != nil {
+		t.Fatalf("ApplyDisk() error = %v", err)
+	}
+	r.waitFor(t, ResourceDisk, 1024*1024)
+	r.waitFor(t, ResourceDisk, 0)
+}
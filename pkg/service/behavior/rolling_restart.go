@@ -0,0 +1,82 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RollingRestartBehavior resets a fraction of connections for a window after the
+// behavior is first seen, modelling pods cycling during a rollout
+type RollingRestartBehavior struct {
+	Window    time.Duration // How long the rollout lasts
+	ResetRate float64       // Fraction of requests reset while it does (0.0-1.0)
+}
+
+// String returns the string representation of rolling-restart behavior
+func (rb *RollingRestartBehavior) String() string {
+	return fmt.Sprintf("rolling-restart=window:%s:reset-rate:%v", rb.Window, rb.ResetRate)
+}
+
+// rolloutState records when the rollout started for a service
+type rolloutState struct {
+	once  sync.Once
+	start time.Time
+}
+
+// parseRollingRestart parses rolling-restart specifications
+// Format: window:duration:reset-rate:fraction
+// Example: "window:30s:reset-rate:0.2"
+func parseRollingRestart(value string) (*RollingRestartBehavior, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 || parts[0] != "window" || parts[2] != "reset-rate" {
+		return nil, fmt.Errorf("invalid format: %s (expected window:duration:reset-rate:fraction)", value)
+	}
+
+	window, err := time.ParseDuration(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid window: %w", err)
+	}
+	if window <= 0 {
+		return nil, fmt.Errorf("window must be positive")
+	}
+
+	rate, err := strconv.ParseFloat(parts[3], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reset-rate: %w", err)
+	}
+	if rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("reset-rate must be between 0 and 1, got %v", rate)
+	}
+
+	return &RollingRestartBehavior{Window: window, ResetRate: rate}, nil
+}
+
+// ShouldResetForRollout determines if the request's connection should be reset.
+// The rollout window starts with the first request that carries the behavior.
+func (b *Behavior) ShouldResetForRollout(serviceName string, now time.Time) bool {
+	if b.RollingRestart == nil {
+		return false
+	}
+
+	s := loadState(serviceName+"/"+b.RollingRestart.String(), func() *rolloutState { return &rolloutState{} })
+	s.once.Do(func() { s.start = now })
+
+	if now.Sub(s.start) >= b.RollingRestart.Window {
+		return false
+	}
+	return b.Random().Float64() < b.RollingRestart.ResetRate
+}
+
+func init() {
+	registerParser("rolling-restart", func(b *Behavior, value string) error {
+		rb, err := parseRollingRestart(value)
+		if err != nil {
+			return fmt.Errorf("invalid rolling-restart: %w", err)
+		}
+		b.RollingRestart = rb
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseRollingRestart(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantError  bool
+		wantWindow time.Duration
+		wantRate   float64
+	}{
+		{name: "valid", input: "rolling-restart=window:30s:reset-rate:0.2", wantWindow: 30 * time.Second, wantRate: 0.2},
+		{name: "reset everything", input: "rolling-restart=window:1m:reset-rate:1", wantWindow: time.Minute, wantRate: 1},
+		{name: "zero window", input: "rolling-restart=window:0s:reset-rate:0.2", wantError: true},
+		{name: "invalid window", input: "rolling-restart=window:soon:reset-rate:0.2", wantError: true},
+		{name: "rate above 1", input: "rolling-restart=window:30s:reset-rate:1.5", wantError: true},
+		{name: "negative rate", input: "rolling-restart=window:30s:reset-rate:-0.1", wantError: true},
+		{name: "missing rate", input: "rolling-restart=window:30s", wantError: true},
+		{name: "unknown keyword", input: "rolling-restart=window:30s:error-rate:0.2", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.RollingRestart.Window != tt.wantWindow || b.RollingRestart.ResetRate != tt.wantRate {
+				t.Errorf("got %s:%v, want %s:%v", b.RollingRestart.Window, b.RollingRestart.ResetRate, tt.wantWindow, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestRollingRestartString(t *testing.T) {
+	input := "rolling-restart=window:30s:reset-rate:0.2"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestShouldResetForRollout_Window(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("rolling-restart=window:30s:reset-rate:0.2")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	const requests = 5000
+
+	// Within the window roughly 20% of requests are reset
+	resets := 0
+	for i := 0; i < requests; i++ {
+		if b.ShouldResetForRollout("api", start.Add(time.Duration(i)*time.Millisecond)) {
+			resets++
+		}
+	}
+	if fraction := float64(resets) / requests; fraction < 0.15 || fraction > 0.25 {
+		t.Errorf("expected ~20%% of requests reset within the window, got %.1f%%", fraction*100)
+	}
+
+	// Once the rollout is over, none are
+	for i := 0; i < requests; i++ {
+		if b.ShouldResetForRollout("api", start.Add(30*time.Second+time.Duration(i)*time.Millisecond)) {
+			t.Fatalf("expected no resets after the window, request %d was reset", i)
+		}
+	}
+}
+
+func TestShouldResetForRollout_PerService(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("rolling-restart=window:30s:reset-rate:1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	if !b.ShouldResetForRollout("api", start) {
+		t.Fatal("expected reset at the start of the rollout")
+	}
+
+	// A service first seeing the behavior later starts its own window
+	if !b.ShouldResetForRollout("orders", start.Add(time.Minute)) {
+		t.Error("expected a new rollout window for another service")
+	}
+	if b.ShouldResetForRollout("api", start.Add(time.Minute)) {
+		t.Error("expected the first service's rollout to be over")
+	}
+}
+
+func TestExecutor_RollingRestart(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("rolling-restart=window:30s:reset-rate:1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	result, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result == nil || !result.ShouldReturn || !result.ResetConnection {
+		t.Fatalf("expected connection reset, got %+v", result)
+	}
+	if result.StatusCode != 503 || result.BehaviorType != "rolling-restart" {
+		t.Errorf("expected 503 rolling-restart fallback, got %d %s", result.StatusCode, result.BehaviorType)
+	}
+}
@@ -0,0 +1,74 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteBehavior pins the weighted selection of upstream groups to named upstreams,
+// for reproducible demos of canary routing
+type RouteBehavior struct {
+	Pins []RoutePin
+}
+
+// RoutePin selects Upstream whenever Group is selected from
+type RoutePin struct {
+	Group    string
+	Upstream string
+}
+
+// String returns the string representation of route behavior, one directive per pin
+func (rb *RouteBehavior) String() string {
+	var parts []string
+	for _, p := range rb.Pins {
+		parts = append(parts, fmt.Sprintf("route=%s:%s", p.Group, p.Upstream))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseRoute parses a route pin
+// Format: group:upstream
+// Example: "checkout:checkout-v2"
+func parseRoute(value string) (RoutePin, error) {
+	group, upstream, ok := strings.Cut(value, ":")
+	group, upstream = strings.TrimSpace(group), strings.TrimSpace(upstream)
+	if !ok || group == "" || upstream == "" {
+		return RoutePin{}, fmt.Errorf("invalid format: %s (expected group:upstream)", value)
+	}
+	return RoutePin{Group: group, Upstream: upstream}, nil
+}
+
+// RoutePins returns the pinned upstream of each group (group -> upstream ID), or nil
+// if no group is pinned
+func (b *Behavior) RoutePins() map[string]string {
+	if b == nil || b.Route == nil {
+		return nil
+	}
+
+	pins := make(map[string]string, len(b.Route.Pins))
+	for _, p := range b.Route.Pins {
+		pins[p.Group] = p.Upstream
+	}
+	return pins
+}
+
+func init() {
+	registerParser("route", func(b *Behavior, value string) error {
+		pin, err := parseRoute(value)
+		if err != nil {
+			return fmt.Errorf("invalid route: %w", err)
+		}
+		// Repeated route= directives pin several groups, a repeated group keeps the last pin
+		if b.Route == nil {
+			b.Route = &RouteBehavior{}
+		}
+		for i, existing := range b.Route.Pins {
+			if existing.Group == pin.Group {
+				b.Route.Pins[i] = pin
+				return nil
+			}
+		}
+		b.Route.Pins = append(b.Route.Pins, pin)
+		return nil
+	})
+}
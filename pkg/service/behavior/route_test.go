@@ -0,0 +1,67 @@
+package behavior
+
+import "testing"
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantPins  []RoutePin
+	}{
+		{name: "single pin", input: "route=checkout:checkout-v2", wantPins: []RoutePin{{"checkout", "checkout-v2"}}},
+		{name: "repeated pins accumulate", input: "route=checkout:checkout-v2,route=payment:payment-fail", wantPins: []RoutePin{{"checkout", "checkout-v2"}, {"payment", "payment-fail"}}},
+		{name: "repeated group keeps last pin", input: "route=checkout:checkout-v2,route=checkout:checkout-v1", wantPins: []RoutePin{{"checkout", "checkout-v1"}}},
+		{name: "missing upstream", input: "route=checkout", wantError: true},
+		{name: "empty upstream", input: "route=checkout:", wantError: true},
+		{name: "empty group", input: "route=:checkout-v2", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if len(b.Route.Pins) != len(tt.wantPins) {
+				t.Fatalf("got pins %v, want %v", b.Route.Pins, tt.wantPins)
+			}
+			for i, p := range tt.wantPins {
+				if b.Route.Pins[i] != p {
+					t.Errorf("pin %d = %v, want %v", i, b.Route.Pins[i], p)
+				}
+			}
+		})
+	}
+}
+
+func TestRouteString(t *testing.T) {
+	input := "route=checkout:checkout-v2,route=payment:payment-fail"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestRoutePins(t *testing.T) {
+	var nilBehavior *Behavior
+	if pins := nilBehavior.RoutePins(); pins != nil {
+		t.Errorf("expected no pins for nil behavior, got %v", pins)
+	}
+
+	b, err := Parse("route=checkout:checkout-v2,route=payment:payment-fail")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	pins := b.RoutePins()
+	if len(pins) != 2 || pins["checkout"] != "checkout-v2" || pins["payment"] != "payment-fail" {
+		t.Errorf("RoutePins() = %v", pins)
+	}
+}
@@ -0,0 +1,52 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// SeedBehavior draws the behavior's random decisions from a source seeded with Value,
+// shared by all requests with the same seed, so a run of requests is reproducible
+type SeedBehavior struct {
+	Value int64
+}
+
+// String returns the string representation of seed behavior
+func (sb *SeedBehavior) String() string {
+	return fmt.Sprintf("seed=%d", sb.Value)
+}
+
+// parseSeed parses seed specifications
+// Examples: "42", "-7"
+func parseSeed(value string) (*SeedBehavior, error) {
+	seed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed value: %w", err)
+	}
+	return &SeedBehavior{Value: seed}, nil
+}
+
+// Random returns the source the behavior's random decisions are drawn from: the seeded
+// source of its seed= directive, or the process-wide source (seeded from RAND_SEED)
+func (b *Behavior) Random() *service.Rand {
+	if b == nil || b.Seed == nil {
+		return service.Random
+	}
+	seed := b.Seed.Value
+	return loadState(fmt.Sprintf("seed/%d", seed), func() *service.Rand {
+		return service.NewRand(seed)
+	})
+}
+
+func init() {
+	registerParser("seed", func(b *Behavior, value string) error {
+		sb, err := parseSeed(value)
+		if err != nil {
+			return fmt.Errorf("invalid seed: %w", err)
+		}
+		b.Seed = sb
+		return nil
+	})
+}
@@ -0,0 +1,103 @@
+package behavior
+
+import (
+	"testing"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseSeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantSeed  int64
+	}{
+		{name: "positive", input: "seed=42", wantSeed: 42},
+		{name: "negative", input: "seed=-7", wantSeed: -7},
+		{name: "not a number", input: "seed=abc", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Seed.Value != tt.wantSeed {
+				t.Errorf("Value = %d, want %d", b.Seed.Value, tt.wantSeed)
+			}
+		})
+	}
+}
+
+func TestSeedString(t *testing.T) {
+	input := "error=500:0.5,seed=42"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	defer resetState()
+
+	var nilBehavior *Behavior
+	if nilBehavior.Random() != service.Random {
+		t.Error("expected nil behavior to use the process-wide source")
+	}
+	if (&Behavior{}).Random() != service.Random {
+		t.Error("expected behavior without seed to use the process-wide source")
+	}
+
+	// Requests with the same seed share one source
+	b1, _ := Parse("seed=42")
+	b2, _ := Parse("error=0.5,seed=42")
+	b3, _ := Parse("seed=43")
+	if b1.Random() != b2.Random() {
+		t.Error("expected behaviors with the same seed to share a source")
+	}
+	if b1.Random() == b3.Random() {
+		t.Error("expected behaviors with different seeds to have their own source")
+	}
+}
+
+func TestSeed_ReproducibleDecisions(t *testing.T) {
+	defer resetState()
+
+	run := func() []bool {
+		resetState()
+		var decisions []bool
+		for i := 0; i < 50; i++ {
+			b, err := Parse("error=0.5,seed=42")
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+			shouldError, _ := b.ShouldError()
+			decisions = append(decisions, shouldError)
+		}
+		return decisions
+	}
+
+	first, second := run(), run()
+	failures := 0
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("decision %d differs between runs with the same seed", i)
+		}
+		if first[i] {
+			failures++
+		}
+	}
+	// The seeded sequence still varies between requests
+	if failures == 0 || failures == len(first) {
+		t.Errorf("expected a mix of errors and successes, got %d errors in %d requests", failures, len(first))
+	}
+}
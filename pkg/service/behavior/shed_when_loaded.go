@@ -0,0 +1,65 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// activeLoad counts CPU and memory behaviors currently running on this process
+var activeLoad atomic.Int64
+
+// beginLoad marks an injected CPU/memory load as active and returns a func that clears it
+func beginLoad() func() {
+	activeLoad.Add(1)
+	return func() {
+		activeLoad.Add(-1)
+	}
+}
+
+// LoadActive reports whether a CPU or memory behavior is currently running on this process
+func LoadActive() bool {
+	return activeLoad.Load() > 0
+}
+
+// ShedWhenLoadedBehavior rejects requests while injected load is active (self-protection)
+type ShedWhenLoadedBehavior struct {
+	Code int // HTTP status code returned while loaded
+}
+
+// String returns the string representation of shed-when-loaded behavior
+func (sb *ShedWhenLoadedBehavior) String() string {
+	return fmt.Sprintf("shed-when-loaded=%d", sb.Code)
+}
+
+// parseShedWhenLoaded parses shed-when-loaded specifications
+// Examples: "503", "429"
+func parseShedWhenLoaded(value string) (*ShedWhenLoadedBehavior, error) {
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+	if code < 100 || code > 599 {
+		return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+	}
+	return &ShedWhenLoadedBehavior{Code: code}, nil
+}
+
+// ShouldShed determines if the request should be shed because injected load is active
+func (b *Behavior) ShouldShed() (bool, int) {
+	if b.ShedWhenLoaded == nil || !LoadActive() {
+		return false, 0
+	}
+	return true, b.ShedWhenLoaded.Code
+}
+
+func init() {
+	registerParser("shed-when-loaded", func(b *Behavior, value string) error {
+		shed, err := parseShedWhenLoaded(value)
+		if err != nil {
+			return fmt.Errorf("invalid shed-when-loaded: %w", err)
+		}
+		b.ShedWhenLoaded = shed
+		return nil
+	})
+}
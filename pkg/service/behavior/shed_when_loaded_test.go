@@ -0,0 +1,82 @@
+package behavior
+
+import (
+	"testing"
+)
+
+func TestParseShedWhenLoaded(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "503",
+			input:     "shed-when-loaded=503",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.ShedWhenLoaded == nil {
+					t.Fatal("expected shed-when-loaded behavior")
+				}
+				if b.ShedWhenLoaded.Code != 503 {
+					t.Errorf("expected code 503, got %d", b.ShedWhenLoaded.Code)
+				}
+			},
+		},
+		{
+			name:      "invalid code",
+			input:     "shed-when-loaded=abc",
+			wantError: true,
+		},
+		{
+			name:      "code out of range",
+			input:     "shed-when-loaded=700",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestShedWhenLoadedString(t *testing.T) {
+	b, err := Parse("shed-when-loaded=503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != "shed-when-loaded=503" {
+		t.Errorf("String() = %s, want shed-when-loaded=503", result)
+	}
+}
+
+func TestShouldShed(t *testing.T) {
+	b, err := Parse("shed-when-loaded=503")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if shed, _ := b.ShouldShed(); shed {
+		t.Error("expected no shedding without active load")
+	}
+
+	endLoad := beginLoad()
+	if shed, code := b.ShouldShed(); !shed || code != 503 {
+		t.Errorf("expected shedding with 503 during active load, got %v/%d", shed, code)
+	}
+
+	endLoad()
+	if shed, _ := b.ShouldShed(); shed {
+		t.Error("expected shedding to stop once load clears")
+	}
+}
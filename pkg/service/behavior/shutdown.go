@@ -0,0 +1,49 @@
+package behavior
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// ShutdownBehavior sets how long the service drains on SIGTERM: readiness fails while
+// requests are still served, before the servers close
+type ShutdownBehavior struct {
+	Delay time.Duration
+}
+
+// String returns the string representation of shutdown behavior
+func (sb *ShutdownBehavior) String() string {
+	return fmt.Sprintf("shutdown=%s", sb.Delay)
+}
+
+// parseShutdown parses shutdown specifications
+// Format: delay
+// Examples: "15s", "0s"
+func parseShutdown(value string) (*ShutdownBehavior, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delay: %w", err)
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("delay cannot be negative")
+	}
+	return &ShutdownBehavior{Delay: d}, nil
+}
+
+// applyShutdown updates the drain delay used when the process is asked to stop
+func (b *Behavior) applyShutdown() {
+	service.Drain.SetDelay(b.Shutdown.Delay)
+}
+
+func init() {
+	registerParser("shutdown", func(b *Behavior, value string) error {
+		sb, err := parseShutdown(value)
+		if err != nil {
+			return fmt.Errorf("invalid shutdown: %w", err)
+		}
+		b.Shutdown = sb
+		return nil
+	})
+}
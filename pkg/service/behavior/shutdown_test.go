@@ -0,0 +1,68 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseShutdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantDelay time.Duration
+	}{
+		{name: "seconds", input: "shutdown=15s", wantDelay: 15 * time.Second},
+		{name: "disabled", input: "shutdown=0s", wantDelay: 0},
+		{name: "negative", input: "shutdown=-1s", wantError: true},
+		{name: "no unit", input: "shutdown=15", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Shutdown.Delay != tt.wantDelay {
+				t.Errorf("Delay = %v, want %v", b.Shutdown.Delay, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestShutdownString(t *testing.T) {
+	input := "shutdown=15s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestShutdownApply(t *testing.T) {
+	defer service.Drain.SetDelay(0)
+
+	b, err := Parse("shutdown=20s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if err := b.Apply(context.Background()); err != nil {
+		t.Fatalf("Apply() failed: %v", err)
+	}
+	if got := service.Drain.Delay(); got != 20*time.Second {
+		t.Errorf("expected drain delay 20s, got %v", got)
+	}
+	if service.Drain.Draining() {
+		t.Error("expected applying the behavior not to start draining")
+	}
+}
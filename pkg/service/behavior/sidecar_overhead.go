@@ -0,0 +1,90 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+// SidecarOverheadBehavior adds latency that grows with concurrent requests,
+// modelling a mesh proxy sidecar contending for CPU under load
+type SidecarOverheadBehavior struct {
+	Min  time.Duration // Overhead with no other requests in flight
+	Max  time.Duration // Overhead once Load requests are in flight
+	Load int64         // In-flight request count at which overhead reaches Max
+}
+
+// String returns the string representation of sidecar-overhead behavior
+func (sb *SidecarOverheadBehavior) String() string {
+	return fmt.Sprintf("sidecar-overhead=%s..%s:load:%d", sb.Min, sb.Max, sb.Load)
+}
+
+// parseSidecarOverhead parses sidecar-overhead specifications
+// Format: min..max:load:N
+// Example: "5ms..50ms:load:100"
+func parseSidecarOverhead(value string) (*SidecarOverheadBehavior, error) {
+	fields := strings.Split(value, ":")
+	if len(fields) != 3 || fields[1] != "load" {
+		return nil, fmt.Errorf("invalid format: %s (expected min..max:load:N)", value)
+	}
+
+	bounds := strings.Split(fields[0], "..")
+	if len(bounds) != 2 {
+		return nil, fmt.Errorf("invalid latency range: %s (expected min..max)", fields[0])
+	}
+	minLatency, err := time.ParseDuration(bounds[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid min latency: %w", err)
+	}
+	maxLatency, err := time.ParseDuration(bounds[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid max latency: %w", err)
+	}
+	if minLatency < 0 || maxLatency < minLatency {
+		return nil, fmt.Errorf("latency range must satisfy 0 <= min <= max, got %s..%s", minLatency, maxLatency)
+	}
+
+	load, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid load: %w", err)
+	}
+	if load < 1 {
+		return nil, fmt.Errorf("load must be at least 1, got %d", load)
+	}
+
+	return &SidecarOverheadBehavior{Min: minLatency, Max: maxLatency, Load: load}, nil
+}
+
+// Overhead returns the added latency with inFlight concurrent requests,
+// interpolated linearly from Min to Max and capped at Max from Load upwards
+func (sb *SidecarOverheadBehavior) Overhead(inFlight int64) time.Duration {
+	if inFlight >= sb.Load {
+		return sb.Max
+	}
+	if inFlight < 0 {
+		inFlight = 0
+	}
+	return sb.Min + time.Duration(int64(sb.Max-sb.Min)*inFlight/sb.Load)
+}
+
+// sidecarDelay returns the sidecar overhead for the current in-flight request count
+func (b *Behavior) sidecarDelay() time.Duration {
+	if b.SidecarOverhead == nil {
+		return 0
+	}
+	return b.SidecarOverhead.Overhead(service.InFlight.Count())
+}
+
+func init() {
+	registerParser("sidecar-overhead", func(b *Behavior, value string) error {
+		sb, err := parseSidecarOverhead(value)
+		if err != nil {
+			return fmt.Errorf("invalid sidecar-overhead: %w", err)
+		}
+		b.SidecarOverhead = sb
+		return nil
+	})
+}
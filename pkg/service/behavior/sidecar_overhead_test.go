@@ -0,0 +1,117 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+)
+
+func TestParseSidecarOverhead(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantMin   time.Duration
+		wantMax   time.Duration
+		wantLoad  int64
+	}{
+		{name: "valid", input: "sidecar-overhead=5ms..50ms:load:100", wantMin: 5 * time.Millisecond, wantMax: 50 * time.Millisecond, wantLoad: 100},
+		{name: "constant overhead", input: "sidecar-overhead=10ms..10ms:load:1", wantMin: 10 * time.Millisecond, wantMax: 10 * time.Millisecond, wantLoad: 1},
+		{name: "max below min", input: "sidecar-overhead=50ms..5ms:load:100", wantError: true},
+		{name: "negative min", input: "sidecar-overhead=-5ms..5ms:load:100", wantError: true},
+		{name: "missing range", input: "sidecar-overhead=5ms:load:100", wantError: true},
+		{name: "invalid latency", input: "sidecar-overhead=fast..50ms:load:100", wantError: true},
+		{name: "zero load", input: "sidecar-overhead=5ms..50ms:load:0", wantError: true},
+		{name: "invalid load", input: "sidecar-overhead=5ms..50ms:load:many", wantError: true},
+		{name: "missing load", input: "sidecar-overhead=5ms..50ms", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			s := b.SidecarOverhead
+			if s.Min != tt.wantMin || s.Max != tt.wantMax || s.Load != tt.wantLoad {
+				t.Errorf("got %s..%s:%d, want %s..%s:%d", s.Min, s.Max, s.Load, tt.wantMin, tt.wantMax, tt.wantLoad)
+			}
+		})
+	}
+}
+
+func TestSidecarOverheadString(t *testing.T) {
+	input := "sidecar-overhead=5ms..50ms:load:100"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestSidecarOverhead_ScalesWithConcurrency(t *testing.T) {
+	s := &SidecarOverheadBehavior{Min: 5 * time.Millisecond, Max: 50 * time.Millisecond, Load: 100}
+
+	tests := []struct {
+		inFlight int64
+		want     time.Duration
+	}{
+		{0, 5 * time.Millisecond},
+		{10, 9500 * time.Microsecond},
+		{50, 27500 * time.Microsecond},
+		{100, 50 * time.Millisecond},
+		{500, 50 * time.Millisecond},
+	}
+
+	prev := time.Duration(0)
+	for _, tt := range tests {
+		got := s.Overhead(tt.inFlight)
+		if got != tt.want {
+			t.Errorf("Overhead(%d) = %v, want %v", tt.inFlight, got, tt.want)
+		}
+		if got < prev {
+			t.Errorf("Overhead(%d) = %v decreased from %v", tt.inFlight, got, prev)
+		}
+		prev = got
+	}
+}
+
+func TestExecutor_SidecarOverheadUnderLoad(t *testing.T) {
+	b, err := Parse("sidecar-overhead=0s..100ms:load:4")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	run := func() time.Duration {
+		start := time.Now()
+		if _, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).Execute(context.Background()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		return time.Since(start)
+	}
+
+	// Nothing in flight: no overhead
+	if elapsed := run(); elapsed >= 25*time.Millisecond {
+		t.Errorf("expected no overhead without load, took %v", elapsed)
+	}
+
+	// Simulate 4 concurrent requests: overhead reaches the max
+	var done []func()
+	for i := 0; i < 4; i++ {
+		done = append(done, service.InFlight.Begin())
+	}
+	if elapsed := run(); elapsed < 100*time.Millisecond {
+		t.Errorf("expected max overhead under load, took %v", elapsed)
+	}
+	for _, d := range done {
+		d()
+	}
+}
@@ -0,0 +1,89 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// SingleFlightBehavior simulates cache misses coalesced with single-flight:
+// concurrent misses for the same key share one slow recompute
+type SingleFlightBehavior struct {
+	Key         string        // Cache key name
+	MissLatency time.Duration // Latency of a recompute
+}
+
+// String returns the string representation of single-flight behavior
+func (sb *SingleFlightBehavior) String() string {
+	return fmt.Sprintf("single-flight=%s:miss-latency:%s", sb.Key, sb.MissLatency)
+}
+
+// singleFlightEntry coalesces recomputes for a simulated cache key
+type singleFlightEntry struct {
+	group        singleflight.Group
+	computations atomic.Int64 // Number of recomputes actually performed
+}
+
+// parseSingleFlight parses single-flight specifications
+// Example: "products:miss-latency:1s"
+func parseSingleFlight(value string) (*SingleFlightBehavior, error) {
+	key, opts, err := parseCacheKeyOptions(value, "miss-latency")
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &SingleFlightBehavior{
+		Key:         key,
+		MissLatency: opts["miss-latency"],
+	}
+	if sb.MissLatency <= 0 {
+		return nil, fmt.Errorf("miss-latency must be positive")
+	}
+
+	return sb, nil
+}
+
+// singleFlightEntryFor returns the shared coalescing state for this service and key
+func (b *Behavior) singleFlightEntryFor(serviceName string) *singleFlightEntry {
+	return loadState(serviceName+"/single-flight/"+b.SingleFlight.Key, func() *singleFlightEntry { return &singleFlightEntry{} })
+}
+
+// applySingleFlight waits for the key's recompute, joining one already in flight if any.
+// Returns true if this request shared another request's recompute.
+func (b *Behavior) applySingleFlight(ctx context.Context, serviceName string) (bool, error) {
+	if b.SingleFlight == nil {
+		return false, nil
+	}
+
+	entry := b.singleFlightEntryFor(serviceName)
+	missLatency := b.SingleFlight.MissLatency
+
+	// The recompute runs independently of any single request's context so a
+	// cancelled leader doesn't fail the requests waiting on it
+	ch := entry.group.DoChan(b.SingleFlight.Key, func() (interface{}, error) {
+		entry.computations.Add(1)
+		time.Sleep(missLatency)
+		return nil, nil
+	})
+
+	select {
+	case res := <-ch:
+		return res.Shared, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func init() {
+	registerParser("single-flight", func(b *Behavior, value string) error {
+		singleFlight, err := parseSingleFlight(value)
+		if err != nil {
+			return fmt.Errorf("invalid single-flight: %w", err)
+		}
+		b.SingleFlight = singleFlight
+		return nil
+	})
+}
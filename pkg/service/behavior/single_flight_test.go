@@ -0,0 +1,100 @@
+package behavior
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSingleFlight(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "key with miss latency",
+			input:     "single-flight=products:miss-latency:1s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.SingleFlight == nil {
+					t.Fatal("expected single-flight behavior")
+				}
+				if b.SingleFlight.Key != "products" {
+					t.Errorf("expected key products, got %s", b.SingleFlight.Key)
+				}
+				if b.SingleFlight.MissLatency != time.Second {
+					t.Errorf("expected miss-latency 1s, got %s", b.SingleFlight.MissLatency)
+				}
+			},
+		},
+		{
+			name:      "missing miss latency",
+			input:     "single-flight=products",
+			wantError: true,
+		},
+		{
+			name:      "ttl is not supported",
+			input:     "single-flight=products:ttl:10s",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestSingleFlightString(t *testing.T) {
+	input := "single-flight=products:miss-latency:1s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestSingleFlightCoalescesConcurrentMisses(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("single-flight=products:miss-latency:200ms")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	const concurrent = 10
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := b.applySingleFlight(context.Background(), "test-service"); err != nil {
+				t.Errorf("applySingleFlight() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if n := b.singleFlightEntryFor("test-service").computations.Load(); n != 1 {
+		t.Errorf("expected 1 slow computation for concurrent misses, got %d", n)
+	}
+	if elapsed < 200*time.Millisecond || elapsed > 390*time.Millisecond {
+		t.Errorf("expected all requests to finish after one recompute (~200ms), took %s", elapsed)
+	}
+}
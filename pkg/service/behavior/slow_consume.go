@@ -0,0 +1,95 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSlowConsumeChunk is how much of the body is read per delayed chunk by default
+const defaultSlowConsumeChunk = 4096
+
+// SlowConsumeBehavior reads the request body slowly, in small delayed chunks, so that
+// upstream proxies have to buffer and may apply backpressure
+type SlowConsumeBehavior struct {
+	Delay     time.Duration // Pause before each chunk
+	ChunkSize int           // Bytes read per chunk
+}
+
+// String returns the string representation of slow-consume behavior
+func (sc *SlowConsumeBehavior) String() string {
+	if sc.ChunkSize == defaultSlowConsumeChunk {
+		return fmt.Sprintf("slow-consume=%s", sc.Delay)
+	}
+	return fmt.Sprintf("slow-consume=%s:%d", sc.Delay, sc.ChunkSize)
+}
+
+// parseSlowConsume parses slow-consume specifications
+// Format: delay[:chunkBytes]
+// Examples: "100ms", "50ms:1024"
+func parseSlowConsume(value string) (*SlowConsumeBehavior, error) {
+	delayStr, chunkStr, hasChunk := strings.Cut(value, ":")
+
+	delay, err := time.ParseDuration(delayStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid delay: %w", err)
+	}
+	if delay <= 0 {
+		return nil, fmt.Errorf("delay must be positive")
+	}
+
+	sc := &SlowConsumeBehavior{Delay: delay, ChunkSize: defaultSlowConsumeChunk}
+	if hasChunk {
+		chunk, err := strconv.Atoi(chunkStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chunk size: %w", err)
+		}
+		if chunk <= 0 {
+			return nil, fmt.Errorf("chunk size must be positive, got %d", chunk)
+		}
+		sc.ChunkSize = chunk
+	}
+
+	return sc, nil
+}
+
+// slowReader reads at most chunk bytes per Read, pausing before each one
+type slowReader struct {
+	ctx   context.Context
+	r     io.Reader
+	chunk int
+	delay time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if len(p) > s.chunk {
+		p = p[:s.chunk]
+	}
+	if err := sleepContext(s.ctx, s.delay); err != nil {
+		return 0, err
+	}
+	return s.r.Read(p)
+}
+
+// SlowBody wraps a request body so it is consumed in delayed chunks under slow-consume.
+// Returns body unchanged when the behavior is absent.
+func (b *Behavior) SlowBody(ctx context.Context, body io.Reader) io.Reader {
+	if b == nil || b.SlowConsume == nil {
+		return body
+	}
+	return &slowReader{ctx: ctx, r: body, chunk: b.SlowConsume.ChunkSize, delay: b.SlowConsume.Delay}
+}
+
+func init() {
+	registerParser("slow-consume", func(b *Behavior, value string) error {
+		sc, err := parseSlowConsume(value)
+		if err != nil {
+			return fmt.Errorf("invalid slow-consume: %w", err)
+		}
+		b.SlowConsume = sc
+		return nil
+	})
+}
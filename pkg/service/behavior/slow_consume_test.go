@@ -0,0 +1,115 @@
+package behavior
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSlowConsume(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		wantDelay time.Duration
+		wantChunk int
+	}{
+		{name: "delay only", input: "slow-consume=100ms", wantDelay: 100 * time.Millisecond, wantChunk: 4096},
+		{name: "delay and chunk", input: "slow-consume=50ms:1024", wantDelay: 50 * time.Millisecond, wantChunk: 1024},
+		{name: "invalid delay", input: "slow-consume=slow", wantError: true},
+		{name: "zero delay", input: "slow-consume=0s", wantError: true},
+		{name: "invalid chunk", input: "slow-consume=100ms:big", wantError: true},
+		{name: "zero chunk", input: "slow-consume=100ms:0", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.SlowConsume.Delay != tt.wantDelay || b.SlowConsume.ChunkSize != tt.wantChunk {
+				t.Errorf("got %s:%d, want %s:%d", b.SlowConsume.Delay, b.SlowConsume.ChunkSize, tt.wantDelay, tt.wantChunk)
+			}
+		})
+	}
+}
+
+func TestSlowConsumeString(t *testing.T) {
+	for _, input := range []string{"slow-consume=100ms", "slow-consume=50ms:1024"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestSlowBodyReadTimeScales(t *testing.T) {
+	b, err := Parse("slow-consume=10ms:1024")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	readAll := func(size int) time.Duration {
+		body := bytes.Repeat([]byte("x"), size)
+		start := time.Now()
+		got, err := io.ReadAll(b.SlowBody(context.Background(), bytes.NewReader(body)))
+		if err != nil {
+			t.Fatalf("ReadAll() error = %v", err)
+		}
+		if len(got) != size {
+			t.Fatalf("read %d bytes, want %d", len(got), size)
+		}
+		return time.Since(start)
+	}
+
+	// 8 and 32 chunks of 1KiB at 10ms per chunk (plus the read that hits EOF)
+	small := readAll(8 * 1024)
+	large := readAll(32 * 1024)
+
+	if small < 80*time.Millisecond {
+		t.Errorf("8 chunks took %v, want at least 80ms", small)
+	}
+	if large < 320*time.Millisecond {
+		t.Errorf("32 chunks took %v, want at least 320ms", large)
+	}
+	if large < 2*small {
+		t.Errorf("expected read time to scale with body size: 8KiB took %v, 32KiB took %v", small, large)
+	}
+}
+
+func TestSlowBodyCancelled(t *testing.T) {
+	b, err := Parse("slow-consume=1s:1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := io.ReadAll(b.SlowBody(ctx, strings.NewReader("payload"))); err == nil {
+		t.Error("expected read to fail once the context is done")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected cancellation to interrupt the delay, took %v", elapsed)
+	}
+}
+
+func TestSlowBodyNilBehavior(t *testing.T) {
+	var b *Behavior
+	body := strings.NewReader("payload")
+	if r := b.SlowBody(context.Background(), body); r != body {
+		t.Error("expected nil behavior to return the body unchanged")
+	}
+}
@@ -0,0 +1,70 @@
+package behavior
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// SNIMismatchBehavior rejects requests whose Host doesn't match the TLS SNI of the connection
+type SNIMismatchBehavior struct {
+	Code int    // HTTP status code returned on mismatch (421 Misdirected Request)
+	Host string // Expected SNI (empty = the request's Host)
+}
+
+// String returns the string representation of sni-mismatch behavior
+func (sb *SNIMismatchBehavior) String() string {
+	if sb.Host != "" {
+		return fmt.Sprintf("sni-mismatch=%d:%s", sb.Code, sb.Host)
+	}
+	return fmt.Sprintf("sni-mismatch=%d", sb.Code)
+}
+
+// parseSNIMismatch parses sni-mismatch specifications
+// Format: code[:expected-host]
+// Examples: "421", "421:api.example.com"
+func parseSNIMismatch(value string) (*SNIMismatchBehavior, error) {
+	codeStr, host, _ := strings.Cut(value, ":")
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid status code: %w", err)
+	}
+	if code < 100 || code > 599 {
+		return nil, fmt.Errorf("status code must be between 100 and 599, got %d", code)
+	}
+	return &SNIMismatchBehavior{Code: code, Host: host}, nil
+}
+
+// ShouldRejectSNI determines if the request should be rejected because the TLS SNI
+// (ClientHelloInfo.ServerName of the connection) doesn't match the expected host.
+// Plaintext connections and clients that sent no SNI are never rejected.
+func (b *Behavior) ShouldRejectSNI(serverName, host string) (bool, int) {
+	if b.SNIMismatch == nil || serverName == "" {
+		return false, 0
+	}
+
+	expected := b.SNIMismatch.Host
+	if expected == "" {
+		expected = host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			expected = h
+		}
+	}
+
+	if strings.EqualFold(strings.TrimSuffix(serverName, "."), strings.TrimSuffix(expected, ".")) {
+		return false, 0
+	}
+	return true, b.SNIMismatch.Code
+}
+
+func init() {
+	registerParser("sni-mismatch", func(b *Behavior, value string) error {
+		sni, err := parseSNIMismatch(value)
+		if err != nil {
+			return fmt.Errorf("invalid sni-mismatch: %w", err)
+		}
+		b.SNIMismatch = sni
+		return nil
+	})
+}
@@ -0,0 +1,157 @@
+package behavior
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSNIMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "code only",
+			input:     "sni-mismatch=421",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.SNIMismatch == nil {
+					t.Fatal("expected sni-mismatch behavior")
+				}
+				if b.SNIMismatch.Code != 421 || b.SNIMismatch.Host != "" {
+					t.Errorf("unexpected sni-mismatch %+v", b.SNIMismatch)
+				}
+			},
+		},
+		{
+			name:      "code with expected host",
+			input:     "sni-mismatch=421:api.example.com",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.SNIMismatch.Host != "api.example.com" {
+					t.Errorf("expected host api.example.com, got %q", b.SNIMismatch.Host)
+				}
+			},
+		},
+		{
+			name:      "invalid code",
+			input:     "sni-mismatch=abc",
+			wantError: true,
+		},
+		{
+			name:      "code out of range",
+			input:     "sni-mismatch=999",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestSNIMismatchString(t *testing.T) {
+	for _, input := range []string{"sni-mismatch=421", "sni-mismatch=421:api.example.com"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", input, err)
+		}
+		if got := b.String(); got != input {
+			t.Errorf("String() = %q, want %q", got, input)
+		}
+	}
+}
+
+func TestShouldRejectSNI(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		serverName string
+		host       string
+		want       bool
+	}{
+		{name: "matching host", input: "sni-mismatch=421", serverName: "api.example.com", host: "api.example.com:8443", want: false},
+		{name: "case insensitive", input: "sni-mismatch=421", serverName: "API.example.com", host: "api.example.com", want: false},
+		{name: "mismatched host", input: "sni-mismatch=421", serverName: "web.example.com", host: "api.example.com", want: true},
+		{name: "plaintext", input: "sni-mismatch=421", serverName: "", host: "api.example.com", want: false},
+		{name: "expected host matches", input: "sni-mismatch=421:api.example.com", serverName: "api.example.com", host: "other", want: false},
+		{name: "expected host mismatches", input: "sni-mismatch=421:api.example.com", serverName: "web.example.com", host: "web.example.com", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, code := b.ShouldRejectSNI(tt.serverName, tt.host)
+			if got != tt.want {
+				t.Errorf("ShouldRejectSNI() = %v, want %v", got, tt.want)
+			}
+			if got && code != 421 {
+				t.Errorf("expected code 421, got %d", code)
+			}
+		})
+	}
+}
+
+func TestExecutor_SNIMismatchOverTLS(t *testing.T) {
+	b, err := Parse("sni-mismatch=421")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result, err := NewExecutor(b, "trace123", "api", &mockTelemetry{}).
+			WithTLS(r.Host, r.TLS.ServerName).
+			Execute(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if result != nil && result.ShouldReturn {
+			http.Error(w, result.ErrorMessage, result.StatusCode)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	request := func(serverName, host string) int {
+		t.Helper()
+		client := &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+		}}
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequest() error = %v", err)
+		}
+		req.Host = host
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request error = %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := request("web.example.com", "api.example.com"); code != 421 {
+		t.Errorf("expected 421 for mismatched SNI, got %d", code)
+	}
+	if code := request("api.example.com", "api.example.com"); code != 200 {
+		t.Errorf("expected 200 for matching SNI, got %d", code)
+	}
+}
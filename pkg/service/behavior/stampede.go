@@ -0,0 +1,120 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StampedeBehavior simulates a thundering herd on cache expiry: every request arriving
+// while the cached value is being recomputed does its own slow recompute
+type StampedeBehavior struct {
+	Key         string        // Cache key name
+	TTL         time.Duration // How long the recomputed value stays fresh
+	MissLatency time.Duration // Latency of a recompute
+}
+
+// String returns the string representation of stampede behavior
+func (sb *StampedeBehavior) String() string {
+	return fmt.Sprintf("stampede=%s:ttl:%s:miss-latency:%s", sb.Key, sb.TTL, sb.MissLatency)
+}
+
+// cacheEntry tracks expiry and in-progress recompute for a simulated cache key
+type cacheEntry struct {
+	mu             sync.Mutex
+	expiresAt      time.Time
+	recomputeUntil time.Time
+}
+
+// parseCacheKeyOptions parses "<key>:<name>:<duration>..." into the key and named durations
+func parseCacheKeyOptions(value string, allowed ...string) (string, map[string]time.Duration, error) {
+	parts := strings.Split(value, ":")
+	if len(parts)%2 != 1 || parts[0] == "" {
+		return "", nil, fmt.Errorf("invalid format: %s (expected key:option:value...)", value)
+	}
+
+	opts := make(map[string]time.Duration)
+	for i := 1; i < len(parts); i += 2 {
+		name := parts[i]
+		known := false
+		for _, a := range allowed {
+			if name == a {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return "", nil, fmt.Errorf("unknown option: %s", name)
+		}
+
+		d, err := time.ParseDuration(parts[i+1])
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		opts[name] = d
+	}
+
+	return parts[0], opts, nil
+}
+
+// parseStampede parses stampede specifications
+// Example: "products:ttl:10s:miss-latency:1s"
+func parseStampede(value string) (*StampedeBehavior, error) {
+	key, opts, err := parseCacheKeyOptions(value, "ttl", "miss-latency")
+	if err != nil {
+		return nil, err
+	}
+
+	sb := &StampedeBehavior{
+		Key:         key,
+		TTL:         opts["ttl"],
+		MissLatency: opts["miss-latency"],
+	}
+	if sb.TTL <= 0 {
+		return nil, fmt.Errorf("ttl must be positive")
+	}
+	if sb.MissLatency <= 0 {
+		return nil, fmt.Errorf("miss-latency must be positive")
+	}
+
+	return sb, nil
+}
+
+// stampedeDelay returns the latency this request incurs for the simulated cache at time now.
+// The first request after expiry opens a recompute window; requests arriving inside the
+// window also miss (no coalescing); requests after the window hit until the TTL expires.
+func (b *Behavior) stampedeDelay(serviceName string, now time.Time) time.Duration {
+	if b.Stampede == nil {
+		return 0
+	}
+
+	entry := loadState(serviceName+"/stampede/"+b.Stampede.Key, func() *cacheEntry { return &cacheEntry{} })
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if now.Before(entry.recomputeUntil) {
+		// Concurrent miss while another request is recomputing
+		return b.Stampede.MissLatency
+	}
+
+	if !now.Before(entry.expiresAt) {
+		// Expired: this request starts the recompute
+		entry.recomputeUntil = now.Add(b.Stampede.MissLatency)
+		entry.expiresAt = entry.recomputeUntil.Add(b.Stampede.TTL)
+		return b.Stampede.MissLatency
+	}
+
+	return 0
+}
+
+func init() {
+	registerParser("stampede", func(b *Behavior, value string) error {
+		stampede, err := parseStampede(value)
+		if err != nil {
+			return fmt.Errorf("invalid stampede: %w", err)
+		}
+		b.Stampede = stampede
+		return nil
+	})
+}
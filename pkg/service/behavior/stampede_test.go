@@ -0,0 +1,106 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseStampede(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "key with ttl and miss latency",
+			input:     "stampede=products:ttl:10s:miss-latency:1s",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Stampede == nil {
+					t.Fatal("expected stampede behavior")
+				}
+				if b.Stampede.Key != "products" {
+					t.Errorf("expected key products, got %s", b.Stampede.Key)
+				}
+				if b.Stampede.TTL != 10*time.Second {
+					t.Errorf("expected ttl 10s, got %s", b.Stampede.TTL)
+				}
+				if b.Stampede.MissLatency != time.Second {
+					t.Errorf("expected miss-latency 1s, got %s", b.Stampede.MissLatency)
+				}
+			},
+		},
+		{
+			name:      "missing miss latency",
+			input:     "stampede=products:ttl:10s",
+			wantError: true,
+		},
+		{
+			name:      "unknown option",
+			input:     "stampede=products:ttl:10s:jitter:1s",
+			wantError: true,
+		},
+		{
+			name:      "dangling option",
+			input:     "stampede=products:ttl",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestStampedeString(t *testing.T) {
+	input := "stampede=products:ttl:10s:miss-latency:1s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestStampedeDelay(t *testing.T) {
+	resetState()
+	defer resetState()
+
+	b, err := Parse("stampede=products:ttl:10s:miss-latency:1s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	t0 := time.Now()
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   time.Duration
+	}{
+		{"first request misses", 0, time.Second},
+		{"clustered request during recompute misses", 100 * time.Millisecond, time.Second},
+		{"another clustered request misses", 900 * time.Millisecond, time.Second},
+		{"mid-ttl request is fast", 5 * time.Second, 0},
+		{"late-ttl request is fast", 10 * time.Second, 0},
+		{"request at ttl boundary misses", 11 * time.Second, time.Second},
+		{"clustered request at boundary misses", 11*time.Second + 500*time.Millisecond, time.Second},
+		{"next mid-ttl request is fast", 16 * time.Second, 0},
+	}
+
+	for _, tt := range tests {
+		if got := b.stampedeDelay("test-service", t0.Add(tt.offset)); got != tt.want {
+			t.Errorf("%s: delay = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package behavior
+
+import "sync"
+
+// state holds per-key state for behaviors that span multiple requests
+// (counters, windows, queues). Keys should include the service name so
+// behaviors targeting different services never share state.
+var state sync.Map
+
+// loadState returns the state stored under key, creating it with init on first use
+func loadState[T any](key string, init func() *T) *T {
+	if v, ok := state.Load(key); ok {
+		return v.(*T)
+	}
+	v, _ := state.LoadOrStore(key, init())
+	return v.(*T)
+}
+
+// resetState clears all stateful behavior state
+func resetState() {
+	state.Range(func(key, _ any) bool {
+		state.Delete(key)
+		return true
+	})
+}
@@ -0,0 +1,90 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TrailersBehavior sets HTTP response trailers after the body is written
+type TrailersBehavior struct {
+	Trailers []Trailer
+}
+
+// Trailer is a single response trailer
+type Trailer struct {
+	Name  string
+	Value string
+}
+
+// String returns the string representation of trailers behavior
+// Format: trailers=X-Result:ok;X-Checksum:abc
+func (tb *TrailersBehavior) String() string {
+	var parts []string
+	for _, t := range tb.Trailers {
+		parts = append(parts, fmt.Sprintf("%s:%s", t.Name, t.Value))
+	}
+	return fmt.Sprintf("trailers=%s", strings.Join(parts, ";"))
+}
+
+// parseTrailers parses trailers specifications
+// Format: name:value;name:value
+// Example: "X-Result:ok;X-Checksum:abc"
+func parseTrailers(value string) (*TrailersBehavior, error) {
+	tb := &TrailersBehavior{}
+
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, val, ok := strings.Cut(part, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid trailer format: %s (expected name:value)", part)
+		}
+		if strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid trailer name: %q", name)
+		}
+
+		tb.Trailers = append(tb.Trailers, Trailer{Name: http.CanonicalHeaderKey(name), Value: strings.TrimSpace(val)})
+	}
+
+	if len(tb.Trailers) == 0 {
+		return nil, fmt.Errorf("no trailers found")
+	}
+
+	return tb, nil
+}
+
+// DeclareTrailers announces the trailer names in the Trailer header; must be called before WriteHeader
+func (b *Behavior) DeclareTrailers(h http.Header) {
+	if b.Trailers == nil {
+		return
+	}
+	for _, t := range b.Trailers.Trailers {
+		h.Add("Trailer", t.Name)
+	}
+}
+
+// SetTrailers sets the declared trailer values; must be called after the body is written
+func (b *Behavior) SetTrailers(h http.Header) {
+	if b.Trailers == nil {
+		return
+	}
+	for _, t := range b.Trailers.Trailers {
+		h.Set(t.Name, t.Value)
+	}
+}
+
+func init() {
+	registerParser("trailers", func(b *Behavior, value string) error {
+		tb, err := parseTrailers(value)
+		if err != nil {
+			return fmt.Errorf("invalid trailers: %w", err)
+		}
+		b.Trailers = tb
+		return nil
+	})
+}
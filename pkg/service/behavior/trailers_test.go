@@ -0,0 +1,120 @@
+package behavior
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrailers(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "two trailers",
+			input:     "trailers=X-Result:ok;X-Checksum:abc",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Trailers == nil {
+					t.Fatal("expected trailers behavior")
+				}
+				want := []Trailer{{Name: "X-Result", Value: "ok"}, {Name: "X-Checksum", Value: "abc"}}
+				if len(b.Trailers.Trailers) != len(want) {
+					t.Fatalf("expected %d trailers, got %v", len(want), b.Trailers.Trailers)
+				}
+				for i, tr := range want {
+					if b.Trailers.Trailers[i] != tr {
+						t.Errorf("trailer %d = %+v, want %+v", i, b.Trailers.Trailers[i], tr)
+					}
+				}
+			},
+		},
+		{
+			name:      "canonicalizes names",
+			input:     "trailers=x-result:ok",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.Trailers.Trailers[0].Name != "X-Result" {
+					t.Errorf("expected canonical name X-Result, got %q", b.Trailers.Trailers[0].Name)
+				}
+			},
+		},
+		{
+			name:      "missing value separator",
+			input:     "trailers=X-Result",
+			wantError: true,
+		},
+		{
+			name:      "empty",
+			input:     "trailers=",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestTrailersString(t *testing.T) {
+	input := "trailers=X-Result:ok;X-Checksum:abc"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := b.String(); got != input {
+		t.Errorf("String() = %q, want %q", got, input)
+	}
+}
+
+func TestTrailers_SentAfterBody(t *testing.T) {
+	b, err := Parse("trailers=X-Result:ok;X-Checksum:abc")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.DeclareTrailers(w.Header())
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"code":200}`))
+		b.SetTrailers(w.Header())
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Trailers are announced up front but only valued once the body is consumed
+	if _, ok := resp.Trailer["X-Result"]; !ok {
+		t.Errorf("expected X-Result to be declared, got %v", resp.Trailer)
+	}
+	if got := resp.Trailer.Get("X-Result"); got != "" {
+		t.Errorf("expected no trailer value before reading the body, got %q", got)
+	}
+
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatalf("read body error = %v", err)
+	}
+
+	for name, want := range map[string]string{"X-Result": "ok", "X-Checksum": "abc"} {
+		if got := resp.Trailer.Get(name); got != want {
+			t.Errorf("trailer %s = %q, want %q", name, got, want)
+		}
+	}
+}
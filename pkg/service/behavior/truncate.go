@@ -0,0 +1,51 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// TruncateBehavior sends the response headers and only part of the body, then closes
+// the connection, simulating a connection that dies mid-response
+type TruncateBehavior struct {
+	Percent float64 // Share of the body sent before closing (0-100, exclusive)
+}
+
+// String returns the string representation of truncate behavior
+func (tb *TruncateBehavior) String() string {
+	return fmt.Sprintf("truncate=%v", tb.Percent)
+}
+
+// parseTruncate parses truncate specifications
+// Format: percent
+// Examples: "50", "0", "99.5"
+func parseTruncate(value string) (*TruncateBehavior, error) {
+	p, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid percent: %w", err)
+	}
+	if p < 0 || p >= 100 {
+		return nil, fmt.Errorf("percent must be at least 0 and below 100, got %v", p)
+	}
+	return &TruncateBehavior{Percent: p}, nil
+}
+
+// TruncateLength returns how many bytes of a body of the given size are sent before
+// the connection is closed, and whether the response is truncated at all
+func (b *Behavior) TruncateLength(size int) (int, bool) {
+	if b == nil || b.Truncate == nil || size == 0 {
+		return size, false
+	}
+	return int(float64(size) * b.Truncate.Percent / 100), true
+}
+
+func init() {
+	registerParser("truncate", func(b *Behavior, value string) error {
+		tb, err := parseTruncate(value)
+		if err != nil {
+			return fmt.Errorf("invalid truncate: %w", err)
+		}
+		b.Truncate = tb
+		return nil
+	})
+}
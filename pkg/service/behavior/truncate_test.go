@@ -0,0 +1,77 @@
+package behavior
+
+import "testing"
+
+func TestParseTruncate(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantError   bool
+		wantPercent float64
+	}{
+		{name: "half", input: "truncate=50", wantPercent: 50},
+		{name: "headers only", input: "truncate=0", wantPercent: 0},
+		{name: "fractional", input: "truncate=99.5", wantPercent: 99.5},
+		{name: "whole body", input: "truncate=100", wantError: true},
+		{name: "negative", input: "truncate=-1", wantError: true},
+		{name: "not a number", input: "truncate=half", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if b.Truncate.Percent != tt.wantPercent {
+				t.Errorf("Percent = %v, want %v", b.Truncate.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	for _, input := range []string{"truncate=50", "truncate=12.5"} {
+		b, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if result := b.String(); result != input {
+			t.Errorf("String() = %s, want %s", result, input)
+		}
+	}
+}
+
+func TestTruncateLength(t *testing.T) {
+	var nilBehavior *Behavior
+	if n, ok := nilBehavior.TruncateLength(100); ok || n != 100 {
+		t.Errorf("expected nil behavior not to truncate, got %d, %v", n, ok)
+	}
+
+	tests := []struct {
+		input    string
+		size     int
+		wantSent int
+		wantOK   bool
+	}{
+		{input: "truncate=50", size: 200, wantSent: 100, wantOK: true},
+		{input: "truncate=0", size: 200, wantSent: 0, wantOK: true},
+		{input: "truncate=99", size: 10, wantSent: 9, wantOK: true},
+		{input: "truncate=50", size: 0, wantSent: 0, wantOK: false},
+		{input: "latency=1ms", size: 200, wantSent: 200, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		b, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse() failed: %v", err)
+		}
+		if n, ok := b.TruncateLength(tt.size); n != tt.wantSent || ok != tt.wantOK {
+			t.Errorf("%s TruncateLength(%d) = %d, %v, want %d, %v", tt.input, tt.size, n, ok, tt.wantSent, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package behavior
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UpstreamCertFailBehavior fails a fraction of TLS handshakes to specific upstreams
+// with a certificate verification error, simulating a cert rotation in progress
+type UpstreamCertFailBehavior struct {
+	Probs map[string]float64 // Upstream name -> probability (0.0-1.0) a handshake fails
+}
+
+// String returns the string representation of upstream cert fail behavior
+// Format: upstream-cert-fail=payment:0.3;inventory:1
+func (uc *UpstreamCertFailBehavior) String() string {
+	if len(uc.Probs) == 0 {
+		return ""
+	}
+
+	// Sort keys for deterministic output
+	names := make([]string, 0, len(uc.Probs))
+	for name := range uc.Probs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s:%v", name, uc.Probs[name]))
+	}
+	return fmt.Sprintf("upstream-cert-fail=%s", strings.Join(parts, ";"))
+}
+
+// parseUpstreamCertFail parses upstream cert failure specifications
+// Format: upstream:prob[;upstream:prob]
+// Example: "payment:0.3"
+func parseUpstreamCertFail(value string) (*UpstreamCertFailBehavior, error) {
+	uc := &UpstreamCertFailBehavior{Probs: make(map[string]float64)}
+
+	// Split by semicolon (using ; to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid format: %s (expected upstream:prob)", part)
+		}
+
+		upstream := strings.TrimSpace(fields[0])
+		if upstream == "" {
+			return nil, fmt.Errorf("upstream name is required: %s", part)
+		}
+
+		prob, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probability for %s: %w", upstream, err)
+		}
+		if prob < 0 || prob > 1 {
+			return nil, fmt.Errorf("probability for %s must be between 0 and 1", upstream)
+		}
+
+		uc.Probs[upstream] = prob
+	}
+
+	if len(uc.Probs) == 0 {
+		return nil, fmt.Errorf("no valid upstream cert fail targets found")
+	}
+
+	return uc, nil
+}
+
+// UpstreamCertError returns a synthetic certificate verification error for a TLS
+// handshake to the named upstream, or nil if the handshake should proceed.
+// Each call is an independent roll against the upstream's configured probability.
+func (b *Behavior) UpstreamCertError(upstream string) error {
+	if b == nil || b.UpstreamCertFail == nil {
+		return nil
+	}
+
+	prob, ok := b.UpstreamCertFail.Probs[upstream]
+	if !ok || b.Random().Float64() >= prob {
+		return nil
+	}
+
+	// Same shape crypto/tls returns when the peer's chain fails verification
+	return &tls.CertificateVerificationError{
+		Err: x509.CertificateInvalidError{
+			Reason: x509.Expired,
+			Detail: fmt.Sprintf("injected by upstream-cert-fail for %s", upstream),
+		},
+	}
+}
+
+func init() {
+	registerParser("upstream-cert-fail", func(b *Behavior, value string) error {
+		certFail, err := parseUpstreamCertFail(value)
+		if err != nil {
+			return fmt.Errorf("invalid upstream-cert-fail: %w", err)
+		}
+		b.UpstreamCertFail = certFail
+		return nil
+	})
+}
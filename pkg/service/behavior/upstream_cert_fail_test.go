@@ -0,0 +1,124 @@
+package behavior
+
+import (
+	"crypto/tls"
+	"errors"
+	"testing"
+)
+
+func TestParseUpstreamCertFail(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "single upstream",
+			input:     "upstream-cert-fail=payment:0.3",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.UpstreamCertFail == nil {
+					t.Fatal("expected upstream-cert-fail behavior")
+				}
+				if prob := b.UpstreamCertFail.Probs["payment"]; prob != 0.3 {
+					t.Errorf("expected probability 0.3 for payment, got %v", prob)
+				}
+			},
+		},
+		{
+			name:      "multiple upstreams",
+			input:     "upstream-cert-fail=payment:0.3;inventory:1",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.UpstreamCertFail.Probs) != 2 {
+					t.Errorf("expected 2 upstreams, got %d", len(b.UpstreamCertFail.Probs))
+				}
+			},
+		},
+		{
+			name:      "missing probability",
+			input:     "upstream-cert-fail=payment",
+			wantError: true,
+		},
+		{
+			name:      "missing upstream",
+			input:     "upstream-cert-fail=:0.3",
+			wantError: true,
+		},
+		{
+			name:      "probability out of range",
+			input:     "upstream-cert-fail=payment:1.5",
+			wantError: true,
+		},
+		{
+			name:      "invalid probability",
+			input:     "upstream-cert-fail=payment:often",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestUpstreamCertFailString(t *testing.T) {
+	input := "upstream-cert-fail=inventory:1;payment:0.3"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestUpstreamCertErrorFraction(t *testing.T) {
+	b, err := Parse("upstream-cert-fail=payment:0.3")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	const calls = 10000
+	failures := 0
+	for i := 0; i < calls; i++ {
+		err := b.UpstreamCertError("payment")
+		if err == nil {
+			continue
+		}
+		var certErr *tls.CertificateVerificationError
+		if !errors.As(err, &certErr) {
+			t.Fatalf("expected a certificate verification error, got %T: %v", err, err)
+		}
+		failures++
+	}
+
+	// 30% of 10000 with a wide margin for randomness
+	if failures < 2500 || failures > 3500 {
+		t.Errorf("expected ~3000 cert failures out of %d, got %d", calls, failures)
+	}
+
+	// Other upstreams always succeed
+	for i := 0; i < 100; i++ {
+		if err := b.UpstreamCertError("inventory"); err != nil {
+			t.Fatalf("expected no cert error for other upstream, got %v", err)
+		}
+	}
+}
+
+func TestUpstreamCertErrorNilBehavior(t *testing.T) {
+	var b *Behavior
+	if err := b.UpstreamCertError("payment"); err != nil {
+		t.Errorf("expected no cert error for nil behavior, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UpstreamDegradeBehavior adds gradually increasing latency to calls to specific upstreams
+type UpstreamDegradeBehavior struct {
+	Targets []DegradeTarget
+}
+
+// DegradeTarget describes the latency ramp for a single upstream
+type DegradeTarget struct {
+	Upstream string        // Upstream name
+	Start    time.Duration // Latency at process start
+	End      time.Duration // Latency once Duration has elapsed
+	Duration time.Duration // Time over which latency ramps from Start to End
+}
+
+// String returns the string representation of a single degrade target
+func (dt DegradeTarget) String() string {
+	return fmt.Sprintf("%s:%s..%s:%s", dt.Upstream, dt.Start, dt.End, dt.Duration)
+}
+
+// String returns the string representation of upstream degrade behavior
+// Format: upstream-degrade=payment:10ms..2s:5m;inventory:0s..500ms:1m
+func (ud *UpstreamDegradeBehavior) String() string {
+	if len(ud.Targets) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, t := range ud.Targets {
+		parts = append(parts, t.String())
+	}
+	return fmt.Sprintf("upstream-degrade=%s", strings.Join(parts, ";"))
+}
+
+// parseUpstreamDegrade parses upstream degrade specifications
+// Format: upstream:start..end:duration[;upstream:start..end:duration]
+// Example: "payment:10ms..2s:5m"
+func parseUpstreamDegrade(value string) (*UpstreamDegradeBehavior, error) {
+	ud := &UpstreamDegradeBehavior{}
+
+	// Split by semicolon (using ; to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid format: %s (expected upstream:start..end:duration)", part)
+		}
+
+		upstream := strings.TrimSpace(fields[0])
+		if upstream == "" {
+			return nil, fmt.Errorf("upstream name is required: %s", part)
+		}
+
+		bounds := strings.Split(fields[1], "..")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid latency range for %s: %s (expected start..end)", upstream, fields[1])
+		}
+		start, err := time.ParseDuration(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start latency for %s: %w", upstream, err)
+		}
+		end, err := time.ParseDuration(bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end latency for %s: %w", upstream, err)
+		}
+
+		duration, err := time.ParseDuration(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for %s: %w", upstream, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration for %s must be positive", upstream)
+		}
+
+		ud.Targets = append(ud.Targets, DegradeTarget{
+			Upstream: upstream,
+			Start:    start,
+			End:      end,
+			Duration: duration,
+		})
+	}
+
+	if len(ud.Targets) == 0 {
+		return nil, fmt.Errorf("no valid upstream degrade targets found")
+	}
+
+	return ud, nil
+}
+
+// UpstreamDelay returns the latency to inject before calling the named upstream.
+// Latency is interpolated linearly between Start and End by elapsed process time,
+// and stays at End once the window has passed.
+func (b *Behavior) UpstreamDelay(upstream string) time.Duration {
+	if b == nil || b.UpstreamDegrade == nil {
+		return 0
+	}
+
+	for _, t := range b.UpstreamDegrade.Targets {
+		if t.Upstream != upstream {
+			continue
+		}
+
+		elapsed := time.Since(processStart)
+		if elapsed >= t.Duration {
+			return t.End
+		}
+		fraction := float64(elapsed) / float64(t.Duration)
+		return t.Start + time.Duration(fraction*float64(t.End-t.Start))
+	}
+
+	return 0
+}
+
+func init() {
+	registerParser("upstream-degrade", func(b *Behavior, value string) error {
+		degrade, err := parseUpstreamDegrade(value)
+		if err != nil {
+			return fmt.Errorf("invalid upstream-degrade: %w", err)
+		}
+		b.UpstreamDegrade = degrade
+		return nil
+	})
+}
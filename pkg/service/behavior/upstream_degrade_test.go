@@ -0,0 +1,130 @@
+package behavior
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUpstreamDegrade(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "single upstream",
+			input:     "upstream-degrade=payment:10ms..2s:5m",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.UpstreamDegrade == nil {
+					t.Fatal("expected upstream-degrade behavior")
+				}
+				if len(b.UpstreamDegrade.Targets) != 1 {
+					t.Fatalf("expected 1 target, got %d", len(b.UpstreamDegrade.Targets))
+				}
+				target := b.UpstreamDegrade.Targets[0]
+				if target.Upstream != "payment" {
+					t.Errorf("expected upstream payment, got %s", target.Upstream)
+				}
+				if target.Start != 10*time.Millisecond || target.End != 2*time.Second {
+					t.Errorf("expected 10ms..2s, got %s..%s", target.Start, target.End)
+				}
+				if target.Duration != 5*time.Minute {
+					t.Errorf("expected duration 5m, got %s", target.Duration)
+				}
+			},
+		},
+		{
+			name:      "multiple upstreams",
+			input:     "upstream-degrade=payment:10ms..2s:5m;inventory:0s..500ms:1m",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.UpstreamDegrade.Targets) != 2 {
+					t.Errorf("expected 2 targets, got %d", len(b.UpstreamDegrade.Targets))
+				}
+			},
+		},
+		{
+			name:      "missing duration",
+			input:     "upstream-degrade=payment:10ms..2s",
+			wantError: true,
+		},
+		{
+			name:      "invalid range",
+			input:     "upstream-degrade=payment:10ms-2s:5m",
+			wantError: true,
+		},
+		{
+			name:      "zero duration",
+			input:     "upstream-degrade=payment:10ms..2s:0s",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestUpstreamDegradeString(t *testing.T) {
+	input := "upstream-degrade=payment:10ms..2s:5m0s;inventory:0s..500ms:1m0s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestUpstreamDelayIncreasesOverWindow(t *testing.T) {
+	orig := processStart
+	defer func() { processStart = orig }()
+
+	b, err := Parse("upstream-degrade=payment:10ms..2s:5m")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Simulate successive calls at increasing process uptime
+	var previous time.Duration
+	for i, uptime := range []time.Duration{0, time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		processStart = time.Now().Add(-uptime)
+		delay := b.UpstreamDelay("payment")
+		if i > 0 && delay <= previous {
+			t.Errorf("expected delay to increase at uptime %s: got %s, previous %s", uptime, delay, previous)
+		}
+		if delay < 10*time.Millisecond || delay > 2*time.Second {
+			t.Errorf("delay %s outside configured range at uptime %s", delay, uptime)
+		}
+		previous = delay
+	}
+
+	// After the window the delay holds at the end value
+	processStart = time.Now().Add(-10 * time.Minute)
+	if delay := b.UpstreamDelay("payment"); delay != 2*time.Second {
+		t.Errorf("expected delay 2s after window, got %s", delay)
+	}
+
+	// Other upstreams are unaffected
+	if delay := b.UpstreamDelay("inventory"); delay != 0 {
+		t.Errorf("expected no delay for other upstream, got %s", delay)
+	}
+}
+
+func TestUpstreamDelayNilBehavior(t *testing.T) {
+	var b *Behavior
+	if delay := b.UpstreamDelay("payment"); delay != 0 {
+		t.Errorf("expected no delay for nil behavior, got %s", delay)
+	}
+}
@@ -0,0 +1,155 @@
+package behavior
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UpstreamGrowBehavior makes specific upstreams return increasingly large responses,
+// by propagating a growing body-size behavior on calls to them
+type UpstreamGrowBehavior struct {
+	Targets []GrowTarget
+}
+
+// GrowTarget describes the response size ramp for a single upstream
+type GrowTarget struct {
+	Upstream string        // Upstream name
+	Start    int64         // Response size in bytes at process start
+	End      int64         // Response size in bytes once Duration has elapsed
+	Duration time.Duration // Time over which size ramps from Start to End
+}
+
+// String returns the string representation of a single grow target
+func (gt GrowTarget) String() string {
+	return fmt.Sprintf("%s:%s..%s:%s", gt.Upstream, formatBytes(gt.Start), formatBytes(gt.End), gt.Duration)
+}
+
+// String returns the string representation of upstream grow behavior
+// Format: upstream-grow=inventory:1Ki..1Mi:5m;payment:0..64Ki:1m
+func (ug *UpstreamGrowBehavior) String() string {
+	if len(ug.Targets) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, t := range ug.Targets {
+		parts = append(parts, t.String())
+	}
+	return fmt.Sprintf("upstream-grow=%s", strings.Join(parts, ";"))
+}
+
+// parseUpstreamGrow parses upstream grow specifications
+// Format: upstream:start..end:duration[;upstream:start..end:duration]
+// Example: "inventory:1Ki..1Mi:5m"
+func parseUpstreamGrow(value string) (*UpstreamGrowBehavior, error) {
+	ug := &UpstreamGrowBehavior{}
+
+	// Split by semicolon (using ; to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid format: %s (expected upstream:start..end:duration)", part)
+		}
+
+		upstream := strings.TrimSpace(fields[0])
+		if upstream == "" {
+			return nil, fmt.Errorf("upstream name is required: %s", part)
+		}
+
+		bounds := strings.Split(fields[1], "..")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid size range for %s: %s (expected start..end)", upstream, fields[1])
+		}
+		start, err := parseBytes(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start size for %s: %w", upstream, err)
+		}
+		end, err := parseBytes(bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid end size for %s: %w", upstream, err)
+		}
+		if start < 0 || end < 0 {
+			return nil, fmt.Errorf("sizes for %s cannot be negative", upstream)
+		}
+		if start > maxBodySize || end > maxBodySize {
+			return nil, fmt.Errorf("sizes for %s must be at most %s", upstream, formatBytes(maxBodySize))
+		}
+
+		duration, err := time.ParseDuration(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for %s: %w", upstream, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration for %s must be positive", upstream)
+		}
+
+		ug.Targets = append(ug.Targets, GrowTarget{
+			Upstream: upstream,
+			Start:    start,
+			End:      end,
+			Duration: duration,
+		})
+	}
+
+	if len(ug.Targets) == 0 {
+		return nil, fmt.Errorf("no valid upstream grow targets found")
+	}
+
+	return ug, nil
+}
+
+// UpstreamBodySize returns the response size to request from the named upstream, or 0 if
+// it isn't targeted. Size is interpolated linearly between Start and End by elapsed
+// process time, and stays at End once the window has passed.
+func (b *Behavior) UpstreamBodySize(upstream string) int64 {
+	if b == nil || b.UpstreamGrow == nil {
+		return 0
+	}
+
+	for _, t := range b.UpstreamGrow.Targets {
+		if t.Upstream != upstream {
+			continue
+		}
+
+		elapsed := time.Since(processStart)
+		if elapsed >= t.Duration {
+			return t.End
+		}
+		fraction := float64(elapsed) / float64(t.Duration)
+		return t.Start + int64(fraction*float64(t.End-t.Start))
+	}
+
+	return 0
+}
+
+// PropagateTo returns the behavior string to send to the named upstream: behaviorStr with
+// the upstream's current body-size appended when upstream-grow targets it
+func (b *Behavior) PropagateTo(upstream, behaviorStr string) string {
+	size := b.UpstreamBodySize(upstream)
+	if size <= 0 {
+		return behaviorStr
+	}
+
+	bodySize := (&BodySizeBehavior{Size: size}).String()
+	if behaviorStr == "" {
+		return bodySize
+	}
+	return behaviorStr + "," + bodySize
+}
+
+func init() {
+	registerParser("upstream-grow", func(b *Behavior, value string) error {
+		grow, err := parseUpstreamGrow(value)
+		if err != nil {
+			return fmt.Errorf("invalid upstream-grow: %w", err)
+		}
+		b.UpstreamGrow = grow
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package behavior
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseUpstreamGrow(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:  "single upstream",
+			input: "upstream-grow=inventory:1Ki..1Mi:5m",
+			validate: func(t *testing.T, b *Behavior) {
+				if b.UpstreamGrow == nil || len(b.UpstreamGrow.Targets) != 1 {
+					t.Fatalf("expected 1 target, got %+v", b.UpstreamGrow)
+				}
+				target := b.UpstreamGrow.Targets[0]
+				if target.Upstream != "inventory" || target.Start != 1024 || target.End != 1<<20 || target.Duration != 5*time.Minute {
+					t.Errorf("unexpected target %+v", target)
+				}
+			},
+		},
+		{
+			name:  "multiple upstreams",
+			input: "upstream-grow=inventory:1Ki..1Mi:5m;payment:0..64Ki:1m",
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.UpstreamGrow.Targets) != 2 {
+					t.Fatalf("expected 2 targets, got %d", len(b.UpstreamGrow.Targets))
+				}
+			},
+		},
+		{name: "missing duration", input: "upstream-grow=inventory:1Ki..1Mi", wantError: true},
+		{name: "missing range", input: "upstream-grow=inventory:1Ki:5m", wantError: true},
+		{name: "invalid size", input: "upstream-grow=inventory:1Ki..big:5m", wantError: true},
+		{name: "size above the body-size maximum", input: "upstream-grow=inventory:1Ki..1Gi:5m", wantError: true},
+		{name: "zero duration", input: "upstream-grow=inventory:1Ki..1Mi:0s", wantError: true},
+		{name: "missing upstream", input: "upstream-grow=:1Ki..1Mi:5m", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestUpstreamGrowString(t *testing.T) {
+	input := "upstream-grow=inventory:1Ki..1Mi:5m0s;payment:0..64Ki:1m0s"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestPropagatedBodySizeIncreasesOverWindow(t *testing.T) {
+	orig := processStart
+	defer func() { processStart = orig }()
+
+	b, err := Parse("upstream-grow=inventory:1Ki..1Mi:5m")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	// Simulate successive calls at increasing process uptime
+	var previous int64
+	for i, uptime := range []time.Duration{0, time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		processStart = time.Now().Add(-uptime)
+		propagated := b.PropagateTo("inventory", "latency=10ms")
+
+		if !strings.HasPrefix(propagated, "latency=10ms,body-size=") {
+			t.Fatalf("expected body-size appended to propagated behavior, got %q", propagated)
+		}
+		downstream, err := Parse(propagated)
+		if err != nil {
+			t.Fatalf("upstream failed to parse propagated behavior %q: %v", propagated, err)
+		}
+		size := downstream.BodySize.Size
+		if i > 0 && size <= previous {
+			t.Errorf("expected size to increase at uptime %s: got %d, previous %d", uptime, size, previous)
+		}
+		if size < 1024 || size > 1<<20 {
+			t.Errorf("size %d outside configured range at uptime %s", size, uptime)
+		}
+		previous = size
+	}
+
+	// After the window the size holds at the end value
+	processStart = time.Now().Add(-10 * time.Minute)
+	if propagated := b.PropagateTo("inventory", ""); propagated != "body-size=1Mi" {
+		t.Errorf("expected body-size=1Mi after window, got %q", propagated)
+	}
+
+	// Other upstreams get the behavior unchanged
+	if propagated := b.PropagateTo("payment", "latency=10ms"); propagated != "latency=10ms" {
+		t.Errorf("expected unchanged behavior for other upstream, got %q", propagated)
+	}
+}
+
+func TestPropagateToNilBehavior(t *testing.T) {
+	var b *Behavior
+	if propagated := b.PropagateTo("inventory", "latency=10ms"); propagated != "latency=10ms" {
+		t.Errorf("expected unchanged behavior, got %q", propagated)
+	}
+}
@@ -1,12 +1,31 @@
 package behavior
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// processStart is when the process started; time-based behaviors interpolate from it
+var processStart = time.Now()
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // extractUnit extracts the unit suffix from a duration string
 // e.g., "200ms" -> "ms", "5s" -> "s"
 func extractUnit(s string) string {
@@ -131,3 +150,52 @@ func getContainerMemoryLimit() (int64, error) {
 	return 0, fmt.Errorf("unable to determine container memory limit: GOMEMBALLAST not set and cgroup files not accessible")
 }
 
+
+// cgroupRoot is where the cgroup filesystem is mounted
+const cgroupRoot = "/sys/fs/cgroup"
+
+// getContainerCPUQuota returns the container CPU limit in cores (e.g. 1.5) using
+// the following fallback chain:
+// 1. cgroup v2: /sys/fs/cgroup/cpu.max
+// 2. cgroup v1: /sys/fs/cgroup/cpu/cpu.cfs_quota_us and cpu.cfs_period_us
+// Returns error if neither is readable or the container has no CPU limit
+func getContainerCPUQuota() (float64, error) {
+	return readCPUQuota(cgroupRoot)
+}
+
+// readCPUQuota reads the CPU quota from the cgroup filesystem mounted at root
+func readCPUQuota(root string) (float64, error) {
+	// Try cgroup v2: "<quota> <period>", quota is "max" when unlimited
+	if data, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			if cores, ok := cpuQuotaCores(fields[0], fields[1]); ok {
+				return cores, nil
+			}
+		}
+	}
+
+	// Try cgroup v1: quota is -1 when unlimited
+	quota, quotaErr := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	period, periodErr := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if quotaErr == nil && periodErr == nil {
+		if cores, ok := cpuQuotaCores(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period))); ok {
+			return cores, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unable to determine container CPU quota: no CPU limit set or cgroup files not accessible")
+}
+
+// cpuQuotaCores converts a CFS quota and period in microseconds to cores
+func cpuQuotaCores(quotaStr, periodStr string) (float64, bool) {
+	quota, err := strconv.ParseInt(quotaStr, 10, 64)
+	if err != nil || quota <= 0 {
+		return 0, false
+	}
+	period, err := strconv.ParseInt(periodStr, 10, 64)
+	if err != nil || period <= 0 {
+		return 0, false
+	}
+	return float64(quota) / float64(period), true
+}
@@ -0,0 +1,104 @@
+package behavior
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionMixBehavior reports one of several versions by weight, simulating a mixed-version fleet
+type VersionMixBehavior struct {
+	Versions []WeightedVersion
+}
+
+// WeightedVersion is a version with its relative weight
+type WeightedVersion struct {
+	Version string
+	Weight  float64
+}
+
+// String returns the string representation of version-mix behavior
+// Format: version-mix=1.0.0:0.7|2.0.0:0.3
+func (vm *VersionMixBehavior) String() string {
+	var parts []string
+	for _, v := range vm.Versions {
+		parts = append(parts, fmt.Sprintf("%s:%v", v.Version, v.Weight))
+	}
+	return fmt.Sprintf("version-mix=%s", strings.Join(parts, "|"))
+}
+
+// parseVersionMix parses version-mix specifications
+// Format: version:weight|version:weight
+// Example: "1.0.0:0.7|2.0.0:0.3"
+func parseVersionMix(value string) (*VersionMixBehavior, error) {
+	vm := &VersionMixBehavior{}
+
+	// Split by pipe (using | to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		// Split on the last colon so versions may contain colons
+		idx := strings.LastIndex(part, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid version weight format: %s (expected version:weight)", part)
+		}
+
+		version := strings.TrimSpace(part[:idx])
+		weight, err := strconv.ParseFloat(strings.TrimSpace(part[idx+1:]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid weight for %s: %w", version, err)
+		}
+		if weight < 0 {
+			return nil, fmt.Errorf("weight for %s cannot be negative", version)
+		}
+
+		vm.Versions = append(vm.Versions, WeightedVersion{Version: version, Weight: weight})
+	}
+
+	if len(vm.Versions) == 0 {
+		return nil, fmt.Errorf("no valid versions found")
+	}
+
+	return vm, nil
+}
+
+// PickVersion returns a version selected by weight, or "" if version-mix is not set
+func (b *Behavior) PickVersion() string {
+	if b.VersionMix == nil {
+		return ""
+	}
+
+	total := 0.0
+	for _, v := range b.VersionMix.Versions {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return ""
+	}
+
+	r := b.Random().Float64() * total
+	cumulative := 0.0
+	for _, v := range b.VersionMix.Versions {
+		cumulative += v.Weight
+		if r < cumulative {
+			return v.Version
+		}
+	}
+
+	// Fallback
+	return b.VersionMix.Versions[len(b.VersionMix.Versions)-1].Version
+}
+
+func init() {
+	registerParser("version-mix", func(b *Behavior, value string) error {
+		versionMix, err := parseVersionMix(value)
+		if err != nil {
+			return fmt.Errorf("invalid version-mix: %w", err)
+		}
+		b.VersionMix = versionMix
+		return nil
+	})
+}
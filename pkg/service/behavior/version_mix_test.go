@@ -0,0 +1,81 @@
+package behavior
+
+import (
+	"testing"
+)
+
+func TestParseVersionMix(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "two versions",
+			input:     "version-mix=1.0.0:0.7|2.0.0:0.3",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.VersionMix == nil {
+					t.Fatal("expected version-mix behavior")
+				}
+				if len(b.VersionMix.Versions) != 2 {
+					t.Fatalf("expected 2 versions, got %d", len(b.VersionMix.Versions))
+				}
+				if b.VersionMix.Versions[0].Version != "1.0.0" || b.VersionMix.Versions[0].Weight != 0.7 {
+					t.Errorf("unexpected first version %+v", b.VersionMix.Versions[0])
+				}
+			},
+		},
+		{
+			name:      "missing weight",
+			input:     "version-mix=1.0.0",
+			wantError: true,
+		},
+		{
+			name:      "negative weight",
+			input:     "version-mix=1.0.0:-1",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestVersionMixString(t *testing.T) {
+	input := "version-mix=1.0.0:0.7|2.0.0:0.3"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestPickVersion(t *testing.T) {
+	b, err := Parse("version-mix=1.0.0:1|2.0.0:0")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if v := b.PickVersion(); v != "1.0.0" {
+			t.Fatalf("expected only 1.0.0 with zero weight on 2.0.0, got %s", v)
+		}
+	}
+
+	if v := (&Behavior{}).PickVersion(); v != "" {
+		t.Errorf("expected empty version without version-mix, got %s", v)
+	}
+}
@@ -0,0 +1,74 @@
+package behavior
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// WSCloseAfterBehavior closes WebSocket connections once a number of messages have
+// been echoed, to exercise client reconnection through gateways and meshes
+type WSCloseAfterBehavior struct {
+	Messages int // Messages echoed before the server closes the socket
+}
+
+// String returns the string representation of ws-close-after behavior
+func (wb *WSCloseAfterBehavior) String() string {
+	return fmt.Sprintf("ws-close-after=%d", wb.Messages)
+}
+
+// parseWSCloseAfter parses ws-close-after specifications
+// Format: count
+// Example: "10"
+func parseWSCloseAfter(value string) (*WSCloseAfterBehavior, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message count: %w", err)
+	}
+	if n <= 0 {
+		return nil, fmt.Errorf("message count must be positive, got %d", n)
+	}
+	return &WSCloseAfterBehavior{Messages: n}, nil
+}
+
+// CloseAfterMessages returns how many messages a WebSocket connection echoes before
+// it is closed, or 0 to keep it open
+func (b *Behavior) CloseAfterMessages() int {
+	if b == nil || b.WSCloseAfter == nil {
+		return 0
+	}
+	return b.WSCloseAfter.Messages
+}
+
+// ApplyToMessage applies latency and error behaviors to a single WebSocket message of
+// the given size. Returns the injected error code, or 0 if the message is echoed.
+func (b *Behavior) ApplyToMessage(ctx context.Context, size int) (int, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	if b.Latency != nil {
+		if err := b.applyLatency(ctx); err != nil {
+			return 0, err
+		}
+		if err := b.ApplyOutputLatency(ctx, size); err != nil {
+			return 0, err
+		}
+	}
+
+	if shouldError, code := b.ShouldError(); shouldError {
+		return code, nil
+	}
+	return 0, nil
+}
+
+func init() {
+	registerParser("ws-close-after", func(b *Behavior, value string) error {
+		wb, err := parseWSCloseAfter(value)
+		if err != nil {
+			return fmt.Errorf("invalid ws-close-after: %w", err)
+		}
+		b.WSCloseAfter = wb
+		return nil
+	})
+}
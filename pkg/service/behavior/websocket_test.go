@@ -0,0 +1,97 @@
+package behavior
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseWSCloseAfter(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		wantError    bool
+		wantMessages int
+	}{
+		{name: "valid", input: "ws-close-after=10", wantMessages: 10},
+		{name: "single message", input: "ws-close-after=1", wantMessages: 1},
+		{name: "zero", input: "ws-close-after=0", wantError: true},
+		{name: "negative", input: "ws-close-after=-3", wantError: true},
+		{name: "not a number", input: "ws-close-after=ten", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if tt.wantError {
+				return
+			}
+			if got := b.CloseAfterMessages(); got != tt.wantMessages {
+				t.Errorf("CloseAfterMessages() = %d, want %d", got, tt.wantMessages)
+			}
+		})
+	}
+}
+
+func TestWSCloseAfterString(t *testing.T) {
+	input := "ws-close-after=10"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if result := b.String(); result != input {
+		t.Errorf("String() = %s, want %s", result, input)
+	}
+}
+
+func TestCloseAfterMessages_NoBehavior(t *testing.T) {
+	var b *Behavior
+	if got := b.CloseAfterMessages(); got != 0 {
+		t.Errorf("expected nil behavior to keep sockets open, got %d", got)
+	}
+	if got := (&Behavior{}).CloseAfterMessages(); got != 0 {
+		t.Errorf("expected empty behavior to keep sockets open, got %d", got)
+	}
+}
+
+func TestApplyToMessage(t *testing.T) {
+	b, err := Parse("latency=20ms,error=503:1")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	start := time.Now()
+	code, err := b.ApplyToMessage(context.Background(), 64)
+	if err != nil {
+		t.Fatalf("ApplyToMessage() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected at least 20ms latency, got %s", elapsed)
+	}
+	if code != 503 {
+		t.Errorf("expected injected 503, got %d", code)
+	}
+
+	// Without behaviors messages are echoed straight away
+	var none *Behavior
+	if code, err := none.ApplyToMessage(context.Background(), 64); code != 0 || err != nil {
+		t.Errorf("expected no effect without behaviors, got %d, %v", code, err)
+	}
+}
+
+func TestApplyToMessage_Cancelled(t *testing.T) {
+	b, err := Parse("latency=1s")
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := b.ApplyToMessage(ctx, 64); err == nil {
+		t.Error("expected error when the connection context is done")
+	}
+}
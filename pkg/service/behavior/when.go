@@ -0,0 +1,152 @@
+package behavior
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// WhenBehavior gates the other behaviors on properties of the incoming request
+type WhenBehavior struct {
+	Conditions []Condition // All conditions must match (AND)
+}
+
+// Condition is a single request predicate
+type Condition struct {
+	Type  string // "header"
+	Name  string // Header name
+	Value string // Expected value (empty = header must be present)
+}
+
+// String returns the string representation of a single condition
+func (c Condition) String() string {
+	if c.Value == "" {
+		return fmt.Sprintf("%s=%s", c.Type, c.Name)
+	}
+	return fmt.Sprintf("%s=%s:%s", c.Type, c.Name, c.Value)
+}
+
+// Matches reports whether the condition holds for the given request headers
+func (c Condition) Matches(headers http.Header) bool {
+	switch c.Type {
+	case "header":
+		values := headers.Values(c.Name)
+		if len(values) == 0 {
+			return false
+		}
+		if c.Value == "" {
+			return true
+		}
+		for _, v := range values {
+			if v == c.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// String returns the string representation of when behavior
+// Format: when=header=X-Debug:true;header=X-Canary
+func (wb *WhenBehavior) String() string {
+	if len(wb.Conditions) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, c := range wb.Conditions {
+		parts = append(parts, c.String())
+	}
+	return fmt.Sprintf("when=%s", strings.Join(parts, ";"))
+}
+
+// parseWhen parses condition specifications
+// Examples: "header=X-Debug:true", "header=X-Canary", "header=X-Debug:true;header=X-User:alice"
+func parseWhen(value string) (*WhenBehavior, error) {
+	wb := &WhenBehavior{}
+
+	// Split by semicolon (using ; to avoid conflict with , in behavior chain)
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid condition format: %s (expected type=value)", part)
+		}
+
+		condType := strings.TrimSpace(kv[0])
+		switch condType {
+		case "header":
+			nameValue := strings.SplitN(kv[1], ":", 2)
+			name := strings.TrimSpace(nameValue[0])
+			if name == "" {
+				return nil, fmt.Errorf("header condition requires a header name")
+			}
+			cond := Condition{Type: condType, Name: name}
+			if len(nameValue) == 2 {
+				cond.Value = strings.TrimSpace(nameValue[1])
+			}
+			wb.Conditions = append(wb.Conditions, cond)
+		default:
+			return nil, fmt.Errorf("unknown condition type: %s", condType)
+		}
+	}
+
+	if len(wb.Conditions) == 0 {
+		return nil, fmt.Errorf("no valid conditions found")
+	}
+
+	return wb, nil
+}
+
+// ConditionsMet reports whether all when conditions hold for the given request headers.
+// A behavior without conditions always applies.
+func (b *Behavior) ConditionsMet(headers http.Header) bool {
+	if b.When == nil {
+		return true
+	}
+
+	for _, c := range b.When.Conditions {
+		if !c.Matches(headers) {
+			return false
+		}
+	}
+	return true
+}
+
+// ResolveConditions returns the chain with its when= conditions evaluated against the
+// request headers: behaviors whose conditions hold are kept without them, the others
+// are dropped. Used before propagating, since upstreams don't receive these headers.
+func (bc *BehaviorChain) ResolveConditions(headers http.Header) *BehaviorChain {
+	resolved := &BehaviorChain{Behaviors: make([]ServiceBehavior, 0, len(bc.Behaviors))}
+	for _, sb := range bc.Behaviors {
+		if sb.Behavior.When != nil {
+			if !sb.Behavior.ConditionsMet(headers) {
+				continue
+			}
+			unconditional := *sb.Behavior
+			unconditional.When = nil
+			sb = ServiceBehavior{Service: sb.Service, Behavior: &unconditional}
+		}
+		resolved.Behaviors = append(resolved.Behaviors, sb)
+	}
+	return resolved
+}
+
+func init() {
+	registerParser("when", func(b *Behavior, value string) error {
+		when, err := parseWhen(value)
+		if err != nil {
+			return fmt.Errorf("invalid when: %w", err)
+		}
+		// Repeated when= directives AND together
+		if b.When != nil {
+			when.Conditions = append(b.When.Conditions, when.Conditions...)
+		}
+		b.When = when
+		return nil
+	})
+}
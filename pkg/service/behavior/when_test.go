@@ -0,0 +1,237 @@
+package behavior
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseWhen(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		validate  func(t *testing.T, b *Behavior)
+	}{
+		{
+			name:      "header with value",
+			input:     "error=503,when=header=X-Debug:true",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.When == nil {
+					t.Fatal("expected when behavior")
+				}
+				if len(b.When.Conditions) != 1 {
+					t.Fatalf("expected 1 condition, got %d", len(b.When.Conditions))
+				}
+				c := b.When.Conditions[0]
+				if c.Type != "header" || c.Name != "X-Debug" || c.Value != "true" {
+					t.Errorf("unexpected condition %+v", c)
+				}
+			},
+		},
+		{
+			name:      "header presence only",
+			input:     "when=header=X-Canary",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if b.When.Conditions[0].Value != "" {
+					t.Errorf("expected empty value, got %s", b.When.Conditions[0].Value)
+				}
+			},
+		},
+		{
+			name:      "multiple conditions with semicolon",
+			input:     "when=header=X-Debug:true;header=X-User:alice",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.When.Conditions) != 2 {
+					t.Errorf("expected 2 conditions, got %d", len(b.When.Conditions))
+				}
+			},
+		},
+		{
+			name:      "repeated when directives",
+			input:     "when=header=X-Debug:true,when=header=X-User:alice",
+			wantError: false,
+			validate: func(t *testing.T, b *Behavior) {
+				if len(b.When.Conditions) != 2 {
+					t.Errorf("expected 2 conditions, got %d", len(b.When.Conditions))
+				}
+			},
+		},
+		{
+			name:      "unknown condition type",
+			input:     "when=cookie=session:abc",
+			wantError: true,
+		},
+		{
+			name:      "missing header name",
+			input:     "when=header=:true",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Parse() error = %v, wantError %v", err, tt.wantError)
+				return
+			}
+			if !tt.wantError && tt.validate != nil {
+				tt.validate(t, b)
+			}
+		})
+	}
+}
+
+func TestWhenString(t *testing.T) {
+	input := "error=503:1,when=header=X-Debug:true;header=X-Canary"
+	b, err := Parse(input)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if got := b.String(); got != input {
+		t.Errorf("String() = %s, want %s", got, input)
+	}
+}
+
+func TestWhenChainRoundTrip(t *testing.T) {
+	input := "svc-a:error=503:1,when=header=X-Debug:true"
+	chain, err := ParseChain(input)
+	if err != nil {
+		t.Fatalf("ParseChain() failed: %v", err)
+	}
+	if got := chain.String(); got != input {
+		t.Errorf("String() = %s, want %s", got, input)
+	}
+
+	b := chain.ForService("svc-a")
+	if b == nil || b.When == nil {
+		t.Fatal("expected when condition for svc-a")
+	}
+}
+
+func TestConditionsMet(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		headers  http.Header
+		expected bool
+	}{
+		{
+			name:     "no conditions",
+			input:    "error=503",
+			headers:  nil,
+			expected: true,
+		},
+		{
+			name:     "matching header",
+			input:    "when=header=X-Debug:true",
+			headers:  http.Header{"X-Debug": []string{"true"}},
+			expected: true,
+		},
+		{
+			name:     "header name is case insensitive",
+			input:    "when=header=x-debug:true",
+			headers:  http.Header{"X-Debug": []string{"true"}},
+			expected: true,
+		},
+		{
+			name:     "wrong value",
+			input:    "when=header=X-Debug:true",
+			headers:  http.Header{"X-Debug": []string{"false"}},
+			expected: false,
+		},
+		{
+			name:     "missing header",
+			input:    "when=header=X-Debug:true",
+			headers:  http.Header{},
+			expected: false,
+		},
+		{
+			name:     "nil headers",
+			input:    "when=header=X-Debug",
+			headers:  nil,
+			expected: false,
+		},
+		{
+			name:     "presence only",
+			input:    "when=header=X-Canary",
+			headers:  http.Header{"X-Canary": []string{"anything"}},
+			expected: true,
+		},
+		{
+			name:  "all conditions must match",
+			input: "when=header=X-Debug:true;header=X-User:alice",
+			headers: http.Header{
+				"X-Debug": []string{"true"},
+				"X-User":  []string{"bob"},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+			if got := b.ConditionsMet(tt.headers); got != tt.expected {
+				t.Errorf("ConditionsMet() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestResolveConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		headers http.Header
+		want    string
+	}{
+		{
+			name:    "met condition is dropped",
+			input:   "orders:error=503,when=header=X-Debug:true",
+			headers: http.Header{"X-Debug": []string{"true"}},
+			want:    "orders:error=503:1",
+		},
+		{
+			name:    "unmet condition drops the behavior",
+			input:   "orders:error=503,when=header=X-Debug:true",
+			headers: http.Header{},
+			want:    "",
+		},
+		{
+			name:    "other entries are kept",
+			input:   "latency=10ms,orders:error=503,when=header=X-Debug:true;header=X-User:alice",
+			headers: http.Header{"X-Debug": []string{"true"}, "X-User": []string{"bob"}},
+			want:    "latency=10ms",
+		},
+		{
+			name:    "no conditions",
+			input:   "latency=10ms,orders:error=503",
+			headers: nil,
+			want:    "latency=10ms,orders:error=503:1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, err := ParseChain(tt.input)
+			if err != nil {
+				t.Fatalf("ParseChain() failed: %v", err)
+			}
+			if got := chain.ResolveConditions(tt.headers).String(); got != tt.want {
+				t.Errorf("ResolveConditions() = %q, want %q", got, tt.want)
+			}
+			// The parsed chain itself is unchanged
+			if got := chain.ForService("orders"); got.When == nil && strings.Contains(tt.input, "when=") {
+				t.Errorf("ResolveConditions() modified the original chain")
+			}
+		})
+	}
+}
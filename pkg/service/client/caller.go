@@ -8,13 +8,16 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service/behavior"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
 	pb "github.com/aslakknutsen/kkbase/testapp/proto/testservice"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -24,6 +27,13 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+// Behavior propagation channels, see SetBehaviorPropagation
+const (
+	PropagateQuery  = "query"  // HTTP behavior query parameter, gRPC request field
+	PropagateHeader = "header" // HTTP X-Behavior header, gRPC x-behavior metadata
+	PropagateBoth   = "both"   // Both of the above
+)
+
 // Result represents the standardized result of an upstream call
 type Result struct {
 	Name             string
@@ -40,21 +50,62 @@ type Result struct {
 type Caller struct {
 	httpClient *http.Client
 	telemetry  *telemetry.Telemetry
+	grpcConns  sync.Map // target -> *grpc.ClientConn, reused across calls
+
+	retries      int           // Extra attempts for retryable failures
+	retryBackoff time.Duration // Initial backoff, doubled per attempt
+
+	defaultTimeout time.Duration // Per-call timeout for upstreams without their own
+
+	propagation string // Behavior propagation channel (empty = PropagateQuery)
 }
 
 // NewCaller creates a new upstream caller
 func NewCaller(tel *telemetry.Telemetry) *Caller {
 	return &Caller{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		telemetry: tel,
+		// Timeouts are applied per call from the upstream config, not on the shared client
+		httpClient:     &http.Client{},
+		telemetry:      tel,
+		defaultTimeout: 30 * time.Second,
 	}
 }
 
+// SetRetryPolicy configures retries for retryable upstream failures
+// (connection errors and 502/503/504). retries is the number of extra attempts.
+func (c *Caller) SetRetryPolicy(retries int, backoff time.Duration) {
+	if retries < 0 {
+		retries = 0
+	}
+	c.retries = retries
+	c.retryBackoff = backoff
+}
+
+// SetBehaviorPropagation configures how the behavior string reaches upstreams. The
+// header channel survives gateways and proxies that rewrite or drop query strings.
+// Unknown modes fall back to PropagateQuery.
+func (c *Caller) SetBehaviorPropagation(mode string) {
+	switch mode {
+	case PropagateHeader, PropagateBoth:
+		c.propagation = mode
+	default:
+		c.propagation = PropagateQuery
+	}
+}
+
+// propagatesInRequest reports whether behavior is sent in the HTTP query or gRPC request field
+func (c *Caller) propagatesInRequest() bool {
+	return c.propagation != PropagateHeader
+}
+
+// propagatesInHeader reports whether behavior is sent in the X-Behavior header or metadata
+func (c *Caller) propagatesInHeader() bool {
+	return c.propagation == PropagateHeader || c.propagation == PropagateBoth
+}
+
 // Call makes an upstream call and returns a standardized result
 // behaviorStr is propagated to the upstream service to control its behavior
-func (c *Caller) Call(ctx context.Context, name string, upstream *service.UpstreamConfig, behaviorStr string) Result {
+// beh is this service's effective behavior, used for caller-side faults (may be nil)
+func (c *Caller) Call(ctx context.Context, name string, upstream *service.UpstreamConfig, behaviorStr string, beh *behavior.Behavior) Result {
 	start := time.Now()
 
 	// Start span for upstream call
@@ -64,17 +115,66 @@ func (c *Caller) Call(ctx context.Context, name string, upstream *service.Upstre
 	)
 	defer span.End()
 
+	// Bound the whole call, including retries, by the upstream's timeout
+	timeout := upstream.Timeout
+	if timeout <= 0 {
+		timeout = c.defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	result := Result{
 		Name:     name,
 		URL:      upstream.URL,
 		Protocol: upstream.Protocol,
 	}
 
-	// Route based on protocol
-	if upstream.Protocol == "grpc" {
-		result = c.callGRPC(ctx, name, upstream, behaviorStr, span, start)
-	} else {
-		result = c.callHTTP(ctx, name, upstream, behaviorStr, span, start)
+	// Simulate a degrading dependency by delaying the call
+	if delay := beh.UpstreamDelay(name); delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			result.Error = ctx.Err().Error()
+			result.Duration = time.Since(start)
+			span.RecordError(ctx.Err())
+			span.SetStatus(codes.Error, result.Error)
+			return result
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		// Route based on protocol
+		if upstream.Protocol == "grpc" {
+			result = c.callGRPC(ctx, name, upstream, behaviorStr, span, start)
+		} else if err := c.tlsHandshakeFault(upstream, beh, name); err != nil {
+			result = Result{Name: name, URL: upstream.URL, Protocol: "http", Error: err.Error()}
+		} else {
+			result = c.callHTTP(ctx, name, upstream, behaviorStr, span, start)
+		}
+
+		if attempt >= c.retries || !isRetryable(result) || ctx.Err() != nil {
+			break
+		}
+
+		backoff := c.retryBackoff << attempt
+		span.AddEvent("retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt+1),
+			attribute.Int("status_code", result.Code),
+			attribute.String("error", result.Error),
+			attribute.String("backoff", backoff.String()),
+		))
+
+		// Never back off past the request deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
 	}
 
 	result.Duration = time.Since(start)
@@ -92,6 +192,68 @@ func (c *Caller) Call(ctx context.Context, name string, upstream *service.Upstre
 	return result
 }
 
+// tlsHandshakeFault returns an injected certificate error for an attempt against
+// a TLS upstream (upstream-cert-fail). Every attempt is rolled separately, the same
+// way each new handshake against a mid-rotation backend may or may not succeed.
+func (c *Caller) tlsHandshakeFault(upstream *service.UpstreamConfig, beh *behavior.Behavior, name string) error {
+	if !strings.HasPrefix(upstream.URL, "https://") {
+		return nil
+	}
+	return beh.UpstreamCertError(name)
+}
+
+// isRetryable reports whether a failed call may succeed on another attempt.
+// Connection failures and gateway/unavailable responses are retried; 4xx never are.
+func isRetryable(r Result) bool {
+	if r.Code == 0 {
+		return r.Error != ""
+	}
+	switch r.Code {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// grpcConn returns the pooled connection for target, dialing lazily on first use.
+// Dial does not block, so the first call doesn't stall on connection setup.
+func (c *Caller) grpcConn(target string) (*grpc.ClientConn, error) {
+	if conn, ok := c.grpcConns.Load(target); ok {
+		return conn.(*grpc.ClientConn), nil
+	}
+
+	// Create gRPC connection with Prometheus interceptors
+	conn, err := grpc.Dial(target,
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(grpc_prometheus.StreamClientInterceptor),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Another call may have dialed the same target concurrently - keep the first
+	if existing, loaded := c.grpcConns.LoadOrStore(target, conn); loaded {
+		conn.Close()
+		return existing.(*grpc.ClientConn), nil
+	}
+	return conn, nil
+}
+
+// Close tears down pooled gRPC connections and idle HTTP connections
+func (c *Caller) Close() error {
+	var firstErr error
+	c.grpcConns.Range(func(key, value any) bool {
+		if err := value.(*grpc.ClientConn).Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		c.grpcConns.Delete(key)
+		return true
+	})
+	c.httpClient.CloseIdleConnections()
+	return firstErr
+}
+
 // callHTTP makes an HTTP call to an upstream service
 func (c *Caller) callHTTP(ctx context.Context, name string, upstream *service.UpstreamConfig, behaviorStr string, span trace.Span, start time.Time) Result {
 	// Track active client requests
@@ -110,12 +272,14 @@ func (c *Caller) callHTTP(ctx context.Context, name string, upstream *service.Up
 		urlStr = "http://" + strings.TrimPrefix(urlStr, "http://")
 	}
 
-	// Add behavior as query parameter to propagate to upstream
-	if behaviorStr != "" {
+	// Add behavior as query parameter to propagate to upstream. Escaped, since values
+	// such as jitter=20% or when=...;... are otherwise dropped by the upstream's parser.
+	if behaviorStr != "" && c.propagatesInRequest() {
+		query := url.Values{"behavior": {behaviorStr}}.Encode()
 		if strings.Contains(urlStr, "?") {
-			urlStr = urlStr + "&behavior=" + behaviorStr
+			urlStr = urlStr + "&" + query
 		} else {
-			urlStr = urlStr + "?behavior=" + behaviorStr
+			urlStr = urlStr + "?" + query
 		}
 	}
 
@@ -148,6 +312,11 @@ func (c *Caller) callHTTP(ctx context.Context, name string, upstream *service.Up
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
 
+	// Propagate behavior via header, which survives proxies that drop the query string
+	if behaviorStr != "" && c.propagatesInHeader() {
+		req.Header.Set("X-Behavior", behaviorStr)
+	}
+
 	// Make the call
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -223,18 +392,13 @@ func (c *Caller) callGRPC(ctx context.Context, name string, upstream *service.Up
 		semconv.ServerAddress(target),
 	)
 
-	// Create gRPC connection with Prometheus interceptors
-	conn, err := grpc.Dial(target,
-		grpc.WithInsecure(),
-		grpc.WithUnaryInterceptor(grpc_prometheus.UnaryClientInterceptor),
-		grpc.WithStreamInterceptor(grpc_prometheus.StreamClientInterceptor),
-	)
+	// Get pooled gRPC connection
+	conn, err := c.grpcConn(target)
 	if err != nil {
 		result.Error = err.Error()
 		result.Code = 0
 		return result
 	}
-	defer conn.Close()
 
 	// Create client
 	client := pb.NewTestServiceClient(conn)
@@ -243,12 +407,17 @@ func (c *Caller) callGRPC(ctx context.Context, name string, upstream *service.Up
 	md := metadata.New(nil)
 	propagator := otel.GetTextMapPropagator()
 	propagator.Inject(ctx, metadataCarrier{md: &md})
+	if behaviorStr != "" && c.propagatesInHeader() {
+		md.Set("x-behavior", behaviorStr)
+	}
 	ctx = metadata.NewOutgoingContext(ctx, md)
 
 	// Make the call with behavior propagated
-	resp, err := client.Call(ctx, &pb.CallRequest{
-		Behavior: behaviorStr,
-	})
+	req := &pb.CallRequest{}
+	if c.propagatesInRequest() {
+		req.Behavior = behaviorStr
+	}
+	resp, err := client.Call(ctx, req)
 
 	// Even on error, gRPC can return a response with upstream_calls
 	// Extract what we can from the response first
@@ -22,29 +22,50 @@ type Config struct {
 	GRPCPort    int
 	MetricsPort int
 
+	// MaxConnections caps concurrently accepted connections per listener (0 = unlimited)
+	MaxConnections int
+
+	// ShutdownDelay keeps serving with failing readiness for this long after SIGTERM (0 = none)
+	ShutdownDelay time.Duration
+
+	// Server TLS certificate and key (both empty = plaintext)
+	TLSCertFile string
+	TLSKeyFile  string
+
 	// Upstream services (slice to support multiple entries with same name)
 	Upstreams []*UpstreamConfig
 
 	// Default behavior
 	DefaultBehavior string
 
+	// RandSeed seeds the random source of injected faults for reproducible runs (0 = time-seeded)
+	RandSeed int64
+
 	// Observability
-	OTELEndpoint string
-	LogLevel     string
+	OTELEndpoint        string
+	LogLevel            string
+	LogSampleInitial    int // Identical log messages logged per second before sampling (0 = no sampling)
+	LogSampleThereafter int // Once sampling, log every Nth identical message in that second
 
 	// Client settings
-	ClientTimeout time.Duration
+	ClientTimeout        time.Duration
+	UpstreamRetries      int           // Extra attempts for retryable upstream failures (0 = no retries)
+	UpstreamRetryBackoff time.Duration // Initial backoff between attempts, doubled on each retry
+	BehaviorPropagation  string        // How behavior reaches upstreams: "query" (default), "header" or "both"
 }
 
 // UpstreamConfig defines an upstream service
 type UpstreamConfig struct {
-	Name        string   // Unique ID for this upstream entry (used for behavior targeting)
+	Name        string // Unique ID for this upstream entry (used for behavior targeting)
 	URL         string
-	Protocol    string   // "http" or "grpc"
-	Match       []string // Incoming paths that trigger routing to this upstream (empty = match all)
-	Path        string   // Explicit forward path to call on upstream (empty = "/")
-	Group       string   // Weighted selection group - upstreams in same group are mutually exclusive
-	Probability float64  // Independent call probability (0.0-1.0), only for ungrouped upstreams
+	Protocol    string        // "http" or "grpc"
+	Match       []string      // Incoming paths that trigger routing to this upstream (empty = match all)
+	Path        string        // Explicit forward path to call on upstream (empty = "/")
+	Group       string        // Weighted selection group - upstreams in same group are mutually exclusive
+	Probability float64       // Independent call probability (0.0-1.0), only for ungrouped upstreams
+	Timeout     time.Duration // Per-call timeout (defaults to the global client timeout)
+	Priority    int           // Breaks ties between equally specific matches, highest wins
+	Mirror      bool          // Shadow traffic: called asynchronously, its result never affects the response
 }
 
 // LoadConfigFromEnv loads configuration from environment variables
@@ -58,21 +79,36 @@ func LoadConfigFromEnv() *Config {
 		HTTPPort:        getEnvInt("HTTP_PORT", 8080),
 		GRPCPort:        getEnvInt("GRPC_PORT", 8080),
 		MetricsPort:     getEnvInt("METRICS_PORT", 9091),
+		MaxConnections:  getEnvInt("MAX_CONNECTIONS", 0),
+		ShutdownDelay:   getEnvDuration("SHUTDOWN_DELAY", 0),
 		DefaultBehavior: getEnv("DEFAULT_BEHAVIOR", ""),
+		RandSeed:        int64(getEnvInt("RAND_SEED", 0)),
 		OTELEndpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
 		LogLevel:        getEnv("LOG_LEVEL", "info"),
 		ClientTimeout:   time.Duration(getEnvInt("CLIENT_TIMEOUT_MS", 30000)) * time.Millisecond,
 		Upstreams:       []*UpstreamConfig{},
+
+		UpstreamRetries:      getEnvInt("UPSTREAM_RETRIES", 0),
+		UpstreamRetryBackoff: getEnvDuration("UPSTREAM_RETRY_BACKOFF", 100*time.Millisecond),
+		BehaviorPropagation:  getEnv("BEHAVIOR_PROPAGATION", "query"),
+
+		LogSampleInitial:    getEnvInt("LOG_SAMPLE_INITIAL", 100),
+		LogSampleThereafter: getEnvInt("LOG_SAMPLE_THEREAFTER", 100),
+
+		TLSCertFile: getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:  getEnv("TLS_KEY_FILE", ""),
 	}
 
 	// Parse upstreams: id=url:match=/a,/b:path=/forward:group=name|id2=url2
-	// Format: id=protocol://host:port[:match=/a,/b][:path=/forward][:group=name]
+	// Format: id=protocol://host:port[:match=/a,/b][:path=/forward][:group=name][:timeout=2s][:mirror=true]
 	// Examples:
 	//   - product-api=http://product.ns.svc.cluster.local:8080
 	//   - order-api=http://order.ns.svc.cluster.local:8080:match=/orders,/cart
 	//   - message-bus=http://message-bus.ns.svc.cluster.local:8080:path=/events/OrderCreated
 	//   - gateway=http://gateway:8080:match=/api:path=/v2/api
 	//   - payment-ok=http://bus:8080:path=/events/PaymentProcessed:group=payment-outcome
+	//   - inventory=http://inventory:8080:timeout=500ms
+	//   - orders-v2=http://orders-v2:8080:match=/orders:mirror=true
 	// Old format (backward compat): name:url (no = sign)
 	upstreamsStr := os.Getenv("UPSTREAMS")
 	if upstreamsStr != "" {
@@ -99,18 +135,21 @@ func LoadConfigFromEnv() *Config {
 			var name, url, path, group string
 			var match []string
 			var prob float64
+			var timeout time.Duration
+			var priority int
+			var mirror bool
 
 			// Check for new format (name=url) vs old format (name:url)
 			if strings.Contains(upstream, "=") {
-				// New format: id=url[:match=...][:path=...][:group=...][:prob=0.5]
+				// New format: id=url[:match=...][:path=...][:group=...][:prob=0.5][:timeout=2s][:priority=N][:mirror=true]
 				eqIdx := strings.Index(upstream, "=")
 				name = upstream[:eqIdx]
 				rest := upstream[eqIdx+1:]
 
 				// Parse URL and optional match/path/group/prob parameters
 				// URL format: protocol://host:port
-				// Full format: protocol://host:port:match=/a,/b:path=/forward:group=name:prob=0.5
-				url, match, path, group, prob = parseUpstreamParams(rest)
+				// Full format: protocol://host:port:match=/a,/b:path=/forward:group=name:prob=0.5:timeout=2s:priority=1:mirror=true
+				url, match, path, group, prob, timeout, priority, mirror = parseUpstreamParams(rest)
 			} else {
 				// Old format: name:url
 				parts := strings.SplitN(upstream, ":", 2)
@@ -126,6 +165,10 @@ func LoadConfigFromEnv() *Config {
 				continue
 			}
 
+			if timeout <= 0 {
+				timeout = cfg.ClientTimeout
+			}
+
 			protocol := "http"
 			if strings.HasPrefix(url, "grpc://") {
 				protocol = "grpc"
@@ -139,6 +182,9 @@ func LoadConfigFromEnv() *Config {
 				Path:        path,
 				Group:       group,
 				Probability: prob,
+				Timeout:     timeout,
+				Priority:    priority,
+				Mirror:      mirror,
 			})
 		}
 	}
@@ -163,9 +209,18 @@ func getEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
-// parseUpstreamParams parses URL and optional match/path/group/prob from upstream string
-// Format: protocol://host:port[:match=/a,/b][:path=/forward][:group=name][:prob=0.5]
-func parseUpstreamParams(s string) (url string, match []string, path string, group string, prob float64) {
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
+
+// parseUpstreamParams parses URL and optional match/path/group/prob/timeout/priority/mirror from upstream string
+// Format: protocol://host:port[:match=/a,/b][:path=/forward][:group=name][:prob=0.5][:timeout=2s][:priority=N][:mirror=true]
+func parseUpstreamParams(s string) (url string, match []string, path string, group string, prob float64, timeout time.Duration, priority int, mirror bool) {
 	// Find where URL ends (after port number)
 	// URL format: protocol://host:port
 	// We need to find the port, then check for parameters after
@@ -173,7 +228,7 @@ func parseUpstreamParams(s string) (url string, match []string, path string, gro
 	// Find the :// in the protocol
 	protoEnd := strings.Index(s, "://")
 	if protoEnd == -1 {
-		return s, nil, "", "", 0
+		return s, nil, "", "", 0, 0, 0, false
 	}
 
 	// Find the next colon after ://, which should be the port
@@ -181,14 +236,14 @@ func parseUpstreamParams(s string) (url string, match []string, path string, gro
 	portColonIdx := strings.Index(afterProto, ":")
 	if portColonIdx == -1 {
 		// No port specified, return whole string as URL
-		return s, nil, "", "", 0
+		return s, nil, "", "", 0, 0, 0, false
 	}
 
 	// Find where the port number ends
 	portStart := protoEnd + 3 + portColonIdx + 1
 
 	// Look for all parameter markers after the port
-	paramMarkers := []string{":match=", ":path=", ":group=", ":prob="}
+	paramMarkers := []string{":match=", ":path=", ":group=", ":prob=", ":timeout=", ":priority=", ":mirror="}
 	paramIndices := make(map[string]int)
 
 	for _, marker := range paramMarkers {
@@ -257,5 +312,33 @@ func parseUpstreamParams(s string) (url string, match []string, path string, gro
 		}
 	}
 
-	return url, match, path, group, prob
+	// Parse timeout parameter
+	if idx := paramIndices[":timeout="]; idx != -1 {
+		start := idx + len(":timeout=")
+		end := findParamEnd(start)
+		timeoutStr := strings.TrimSpace(s[start:end])
+		if d, err := time.ParseDuration(timeoutStr); err == nil {
+			timeout = d
+		}
+	}
+
+	// Parse priority parameter
+	if idx := paramIndices[":priority="]; idx != -1 {
+		start := idx + len(":priority=")
+		end := findParamEnd(start)
+		if p, err := strconv.Atoi(strings.TrimSpace(s[start:end])); err == nil {
+			priority = p
+		}
+	}
+
+	// Parse mirror parameter
+	if idx := paramIndices[":mirror="]; idx != -1 {
+		start := idx + len(":mirror=")
+		end := findParamEnd(start)
+		if m, err := strconv.ParseBool(strings.TrimSpace(s[start:end])); err == nil {
+			mirror = m
+		}
+	}
+
+	return url, match, path, group, prob, timeout, priority, mirror
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 // findUpstreamByName finds an upstream by name in the slice
@@ -95,6 +96,23 @@ func TestLoadConfigFromEnv_UpstreamsParsing(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:         "http URL with regex and header matches",
+			upstreamsEnv: "api-v2=http://api-v2:8080:match=~^/api/v[0-9]+,header:X-Tenant=acme:path=/v2",
+			expectedUpstreams: map[string]struct {
+				url      string
+				protocol string
+				match    []string
+				path     string
+			}{
+				"api-v2": {
+					url:      "http://api-v2:8080",
+					protocol: "http",
+					match:    []string{"~^/api/v[0-9]+", "header:X-Tenant=acme"},
+					path:     "/v2",
+				},
+			},
+		},
 		{
 			name:         "http URL with path only",
 			upstreamsEnv: "message-bus=http://message-bus.infra.svc.cluster.local:8080:path=/events/OrderCreated",
@@ -455,6 +473,86 @@ func TestLoadConfigFromEnv_MultipleSameNameUpstreams(t *testing.T) {
 	})
 }
 
+func TestLoadConfigFromEnv_UpstreamTimeout(t *testing.T) {
+	tests := []struct {
+		name            string
+		upstreamsEnv    string
+		clientTimeoutMS string
+		expectedURL     string
+		expectedMatch   []string
+		expectedPath    string
+		expectedTimeout time.Duration
+	}{
+		{
+			name:            "timeout only",
+			upstreamsEnv:    "inventory=http://inventory:8080:timeout=2s",
+			expectedURL:     "http://inventory:8080",
+			expectedTimeout: 2 * time.Second,
+		},
+		{
+			name:            "timeout after match and path",
+			upstreamsEnv:    "api=http://api:8080:match=/orders,/cart:path=/v2:timeout=500ms",
+			expectedURL:     "http://api:8080",
+			expectedMatch:   []string{"/orders", "/cart"},
+			expectedPath:    "/v2",
+			expectedTimeout: 500 * time.Millisecond,
+		},
+		{
+			name:            "timeout before match and path",
+			upstreamsEnv:    "api=grpc://api:9090:timeout=1m:match=/api:path=/forward",
+			expectedURL:     "grpc://api:9090",
+			expectedMatch:   []string{"/api"},
+			expectedPath:    "/forward",
+			expectedTimeout: time.Minute,
+		},
+		{
+			name:            "unset defaults to client timeout",
+			upstreamsEnv:    "api=http://api:8080:match=/api",
+			clientTimeoutMS: "5000",
+			expectedURL:     "http://api:8080",
+			expectedMatch:   []string{"/api"},
+			expectedTimeout: 5 * time.Second,
+		},
+		{
+			name:            "invalid timeout defaults to client timeout",
+			upstreamsEnv:    "api=http://api:8080:timeout=soon",
+			expectedURL:     "http://api:8080",
+			expectedTimeout: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Clearenv()
+			os.Setenv("UPSTREAMS", tt.upstreamsEnv)
+			os.Setenv("SERVICE_NAME", "test-service")
+			if tt.clientTimeoutMS != "" {
+				os.Setenv("CLIENT_TIMEOUT_MS", tt.clientTimeoutMS)
+			}
+
+			cfg := LoadConfigFromEnv()
+
+			if len(cfg.Upstreams) != 1 {
+				t.Fatalf("expected 1 upstream, got %d", len(cfg.Upstreams))
+			}
+			upstream := cfg.Upstreams[0]
+
+			if upstream.URL != tt.expectedURL {
+				t.Errorf("expected URL %q, got %q", tt.expectedURL, upstream.URL)
+			}
+			if !stringSlicesEqual(upstream.Match, tt.expectedMatch) {
+				t.Errorf("expected match %v, got %v", tt.expectedMatch, upstream.Match)
+			}
+			if upstream.Path != tt.expectedPath {
+				t.Errorf("expected path %q, got %q", tt.expectedPath, upstream.Path)
+			}
+			if upstream.Timeout != tt.expectedTimeout {
+				t.Errorf("expected timeout %v, got %v", tt.expectedTimeout, upstream.Timeout)
+			}
+		})
+	}
+}
+
 // Helper function to compare string slices
 func stringSlicesEqual(a, b []string) bool {
 	if len(a) != len(b) {
@@ -473,3 +571,76 @@ func stringSlicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestLoadConfigFromEnv_UpstreamPriority(t *testing.T) {
+	t.Setenv("UPSTREAMS", "orders-v2=http://orders-v2:8080:match=/orders:priority=10|orders=http://orders:8080:match=/orders:priority=-1:timeout=2s|items=http://items:8080:match=/orders/items")
+
+	cfg := LoadConfigFromEnv()
+
+	expected := map[string]int{"orders-v2": 10, "orders": -1, "items": 0}
+	for name, priority := range expected {
+		u := findUpstreamByName(cfg.Upstreams, name)
+		if u == nil {
+			t.Fatalf("Upstream %s not found", name)
+		}
+		if u.Priority != priority {
+			t.Errorf("Expected %s priority %d, got %d", name, priority, u.Priority)
+		}
+		if len(u.Match) != 1 {
+			t.Errorf("Expected %s to keep its single match entry, got %v", name, u.Match)
+		}
+	}
+	if u := findUpstreamByName(cfg.Upstreams, "orders"); u.Timeout != 2*time.Second {
+		t.Errorf("Expected timeout after priority to parse, got %s", u.Timeout)
+	}
+}
+
+func TestLoadConfigFromEnv_UpstreamMirror(t *testing.T) {
+	t.Setenv("UPSTREAMS", "orders=http://orders:8080:match=/orders|orders-v2=http://orders-v2:8080:match=/orders:mirror=true:timeout=1s")
+
+	cfg := LoadConfigFromEnv()
+
+	if u := findUpstreamByName(cfg.Upstreams, "orders"); u == nil || u.Mirror {
+		t.Errorf("Expected orders not to be a mirror, got %+v", u)
+	}
+	u := findUpstreamByName(cfg.Upstreams, "orders-v2")
+	if u == nil || !u.Mirror {
+		t.Fatalf("Expected orders-v2 to be a mirror, got %+v", u)
+	}
+	if u.URL != "http://orders-v2:8080" || u.Timeout != time.Second {
+		t.Errorf("Expected URL and timeout around mirror to parse, got %s %s", u.URL, u.Timeout)
+	}
+}
+
+func TestLoadConfigFromEnv_BehaviorPropagation(t *testing.T) {
+	if cfg := LoadConfigFromEnv(); cfg.BehaviorPropagation != "query" {
+		t.Errorf("Expected default propagation query, got %q", cfg.BehaviorPropagation)
+	}
+
+	t.Setenv("BEHAVIOR_PROPAGATION", "header")
+	if cfg := LoadConfigFromEnv(); cfg.BehaviorPropagation != "header" {
+		t.Errorf("Expected propagation header, got %q", cfg.BehaviorPropagation)
+	}
+}
+
+func TestLoadConfigFromEnv_ShutdownDelay(t *testing.T) {
+	if cfg := LoadConfigFromEnv(); cfg.ShutdownDelay != 0 {
+		t.Errorf("Expected no shutdown delay by default, got %v", cfg.ShutdownDelay)
+	}
+
+	t.Setenv("SHUTDOWN_DELAY", "15s")
+	if cfg := LoadConfigFromEnv(); cfg.ShutdownDelay != 15*time.Second {
+		t.Errorf("Expected shutdown delay 15s, got %v", cfg.ShutdownDelay)
+	}
+}
+
+func TestLoadConfigFromEnv_RandSeed(t *testing.T) {
+	if cfg := LoadConfigFromEnv(); cfg.RandSeed != 0 {
+		t.Errorf("Expected no seed by default, got %d", cfg.RandSeed)
+	}
+
+	t.Setenv("RAND_SEED", "42")
+	if cfg := LoadConfigFromEnv(); cfg.RandSeed != 42 {
+		t.Errorf("Expected seed 42, got %d", cfg.RandSeed)
+	}
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// DrainState holds how long the process keeps serving after it is asked to stop,
+// with readiness failing so the pod is removed from endpoints before its servers close
+type DrainState struct {
+	delay    atomic.Int64 // Nanoseconds between the shutdown signal and closing the servers
+	draining atomic.Bool
+}
+
+// Drain is the shutdown drain state, consulted by the signal handler and the /ready endpoint
+var Drain = &DrainState{}
+
+// SetDelay sets the drain delay applied on the next shutdown (0 = close immediately)
+func (d *DrainState) SetDelay(delay time.Duration) {
+	if delay < 0 {
+		delay = 0
+	}
+	d.delay.Store(int64(delay))
+}
+
+// Delay returns the drain delay applied on shutdown
+func (d *DrainState) Delay() time.Duration {
+	return time.Duration(d.delay.Load())
+}
+
+// Begin starts draining: readiness fails from now on, requests are still served
+func (d *DrainState) Begin() {
+	d.draining.Store(true)
+}
+
+// Draining reports whether shutdown has begun draining
+func (d *DrainState) Draining() bool {
+	return d.draining.Load()
+}
@@ -14,6 +14,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.uber.org/zap"
 	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
@@ -30,6 +31,8 @@ type Server struct {
 // NewServer creates a new gRPC server
 func NewServer(cfg *service.Config, tel *telemetry.Telemetry) *Server {
 	caller := client.NewCaller(tel)
+	caller.SetRetryPolicy(cfg.UpstreamRetries, cfg.UpstreamRetryBackoff)
+	caller.SetBehaviorPropagation(cfg.BehaviorPropagation)
 	return &Server{
 		config:    cfg,
 		telemetry: tel,
@@ -38,6 +41,11 @@ func NewServer(cfg *service.Config, tel *telemetry.Telemetry) *Server {
 	}
 }
 
+// Close releases upstream connections held by the server's caller
+func (s *Server) Close() error {
+	return s.caller.Close()
+}
+
 // Call handles a gRPC call with configurable behavior
 func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResponse, error) {
 	start := time.Now()
@@ -58,6 +66,9 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 	)
 	defer span.End()
 
+	// Track in-flight requests (shared with HTTP, consulted by priority)
+	defer service.InFlight.Begin()()
+
 	// Get trace IDs
 	var traceID, spanID string
 	if spanCtx := span.SpanContext(); spanCtx.IsValid() {
@@ -65,13 +76,25 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 		spanID = spanCtx.SpanID().String()
 	}
 
+	// Behavior from the request field, or x-behavior metadata from header propagation
+	behaviorStr := req.Behavior
+	if behaviorStr == "" {
+		behaviorStr = behaviorFromMetadata(ctx)
+	}
+
 	// Build request context
 	reqCtx := &handler.RequestContext{
 		Ctx:         ctx,
 		StartTime:   start,
 		TraceID:     traceID,
 		SpanID:      spanID,
-		BehaviorStr: req.Behavior,
+		BehaviorStr: behaviorStr,
+		Path:        pb.TestService_Call_FullMethodName,
+		Headers:     headersFromMetadata(ctx),
+		Body:        []byte(req.Body),
+		BodySize:    int64(len(req.Body)),
+		Host:        authorityFromMetadata(ctx),
+		ServerName:  serverNameFromPeer(ctx),
 	}
 
 	// Process request with handler (behavior execution)
@@ -83,12 +106,20 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 		span.SetStatus(codes.Error, err.Error())
 		return nil, status.Errorf(grpc_codes.Internal, "Internal error: %v", err)
 	}
+	defer processResult.Done()
 
 	// If early exit (behavior triggered error), return response
 	if processResult.EarlyExit {
 		statusCode := int(processResult.Response.Code)
-		grpcCode := httpToGRPCCode(statusCode)
 
+		// Cache hits and redirects exit early with a successful response
+		if statusCode < 400 {
+			span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(grpc_codes.OK)))
+			span.SetStatus(codes.Ok, "")
+			return processResult.Response, nil
+		}
+
+		grpcCode := httpToGRPCCode(statusCode)
 		span.SetAttributes(
 			semconv.RPCGRPCStatusCodeKey.Int(int(grpcCode)),
 			semconv.ErrorTypeKey.String(fmt.Sprintf("grpc_%d", statusCode)),
@@ -102,8 +133,8 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 
 	// Call upstreams (all configured upstreams for gRPC)
 	// - behaviorsApplied: used for routing decisions (includes defaults)
-	// - req.Behavior: propagated to downstream (external behavior only)
-	upstreamCalls, err := s.handler.CallUpstreams(ctx, behaviorsApplied, req.Behavior, nil)
+	// - behaviorStr: propagated to downstream (external behavior only, when= resolved here)
+	upstreamCalls, err := s.handler.CallUpstreams(ctx, behaviorsApplied, s.handler.PropagatedBehavior(reqCtx), nil)
 	if err != nil {
 		s.telemetry.Logger.Error("Failed to call upstreams", zap.Error(err))
 		span.RecordError(err)
@@ -114,14 +145,14 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 
 	// Check if any upstream returned non-2xx (excluding connection errors where Code=0)
 	var resp *pb.ServiceResponse
-	if failedCall := s.handler.CheckUpstreamFailures(upstreamCalls); failedCall != nil {
+	if failedCall := s.handler.CheckUpstreamFailures(upstreamCalls, behaviorsApplied); failedCall != nil {
 		resp = s.handler.BuildUpstreamErrorResponse(reqCtx, "grpc", failedCall, behaviorsApplied, upstreamCalls)
 
 		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(grpc_codes.Unavailable)))
 		span.SetStatus(codes.Error, resp.Body)
 
 		// Record application-level metrics (since we're not returning gRPC error)
-		s.telemetry.RecordGRPCRequest("Call", int(resp.Code), time.Since(start))
+		s.telemetry.RecordGRPCRequest("Call", int(resp.Code), time.Since(start), traceID)
 
 		// Return response without gRPC error so upstream_calls are preserved
 		// The error info is in resp.Code and resp.Body
@@ -135,7 +166,7 @@ func (s *Server) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResp
 	span.SetStatus(codes.Ok, "")
 
 	// Record application-level metrics
-	s.telemetry.RecordGRPCRequest("Call", int(resp.Code), time.Since(start))
+	s.telemetry.RecordGRPCRequest("Call", int(resp.Code), time.Since(start), traceID)
 
 	return resp, nil
 }
@@ -148,6 +179,16 @@ func extractClientAddr(ctx context.Context) string {
 	return ""
 }
 
+// serverNameFromPeer returns the TLS SNI of the peer's connection, or "" for plaintext
+func serverNameFromPeer(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			return info.State.ServerName
+		}
+	}
+	return ""
+}
+
 // httpToGRPCCode maps HTTP status codes to gRPC status codes
 func httpToGRPCCode(httpCode int) grpc_codes.Code {
 	switch httpCode {
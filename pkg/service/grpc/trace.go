@@ -2,6 +2,7 @@ package grpc
 
 import (
 	"context"
+	"net/http"
 
 	"go.opentelemetry.io/otel"
 	"google.golang.org/grpc/metadata"
@@ -55,3 +56,41 @@ func InjectTraceContext(ctx context.Context) context.Context {
 
 	return metadata.NewOutgoingContext(ctx, md)
 }
+
+// headersFromMetadata converts incoming gRPC metadata to http.Header so
+// header-based conditions evaluate the same way for both protocols
+func headersFromMetadata(ctx context.Context) http.Header {
+	headers := http.Header{}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return headers
+	}
+
+	for k, values := range md {
+		for _, v := range values {
+			headers.Add(k, v)
+		}
+	}
+	return headers
+}
+
+// behaviorFromMetadata returns the x-behavior metadata of the incoming call, the
+// gRPC counterpart of the X-Behavior header
+func behaviorFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-behavior"); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// authorityFromMetadata returns the :authority pseudo-header of the incoming call
+func authorityFromMetadata(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(":authority"); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+)
+
+// ReadBody reads up to limit bytes of body into memory and drains the rest, so the
+// reported size and digest cover the whole body while memory stays bounded. The
+// SHA-256 is computed only if digest is set.
+func ReadBody(body io.Reader, limit int64, digest bool) (data []byte, size int64, sum string, err error) {
+	var h hash.Hash
+	if digest {
+		h = sha256.New()
+		body = io.TeeReader(body, h)
+	}
+
+	data, err = io.ReadAll(io.LimitReader(body, limit))
+	size = int64(len(data))
+	if err == nil {
+		var rest int64
+		rest, err = io.Copy(io.Discard, body)
+		size += rest
+	}
+
+	if h != nil && err == nil {
+		sum = hex.EncodeToString(h.Sum(nil))
+	}
+	return data, size, sum, err
+}
+
+// bodySHA256 returns the hex SHA-256 of a complete body
+func bodySHA256(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadBody(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	full := sha256.Sum256([]byte(body))
+	wantSum := hex.EncodeToString(full[:])
+
+	tests := []struct {
+		name     string
+		limit    int64
+		digest   bool
+		wantData int
+		wantSum  string
+	}{
+		{name: "within limit", limit: 1024, wantData: 100},
+		{name: "within limit with digest", limit: 1024, digest: true, wantData: 100, wantSum: wantSum},
+		{name: "truncated", limit: 10, wantData: 10},
+		{name: "truncated with digest of whole body", limit: 10, digest: true, wantData: 10, wantSum: wantSum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, size, sum, err := ReadBody(strings.NewReader(body), tt.limit, tt.digest)
+			if err != nil {
+				t.Fatalf("ReadBody() error = %v", err)
+			}
+			if len(data) != tt.wantData {
+				t.Errorf("Expected %d bytes in memory, got %d", tt.wantData, len(data))
+			}
+			if size != 100 {
+				t.Errorf("Expected size of the whole body (100), got %d", size)
+			}
+			if sum != tt.wantSum {
+				t.Errorf("Expected digest %q, got %q", tt.wantSum, sum)
+			}
+		})
+	}
+}
+
+func TestReadBody_Error(t *testing.T) {
+	failing := io.MultiReader(strings.NewReader("partial"), errReader{})
+
+	data, _, sum, err := ReadBody(failing, 1024, true)
+	if err == nil {
+		t.Fatal("Expected read error")
+	}
+	if string(data) != "partial" {
+		t.Errorf("Expected the bytes read before the error, got %q", data)
+	}
+	if sum != "" {
+		t.Errorf("Expected no digest for an incomplete body, got %q", sum)
+	}
+}
+
+// errReader fails every read
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
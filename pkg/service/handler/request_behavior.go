@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service/behavior"
+)
+
+// RequestBehavior returns the behavior string of an HTTP request. A JSON specification,
+// from the base64-encoded behavior-json query parameter or the X-Behavior-JSON header,
+// takes precedence over the behavior query parameter and X-Behavior header; it is
+// converted to the string form, which is what propagates to upstreams. An invalid
+// specification is ignored in favor of the string form and returned as the error.
+func RequestBehavior(r *http.Request) (string, error) {
+	var spec []byte
+	var specErr error
+	if encoded := r.URL.Query().Get("behavior-json"); encoded != "" {
+		data, err := decodeBase64(encoded)
+		if err != nil {
+			specErr = fmt.Errorf("invalid behavior-json encoding: %w", err)
+		} else {
+			spec = data
+		}
+	} else if header := r.Header.Get("X-Behavior-JSON"); header != "" {
+		spec = []byte(header)
+	}
+
+	if len(spec) > 0 {
+		chain, err := behavior.ParseJSON(spec)
+		if err == nil {
+			return chain.String(), nil
+		}
+		specErr = err
+	}
+
+	behaviorStr := r.URL.Query().Get("behavior")
+	if behaviorStr == "" {
+		behaviorStr = r.Header.Get("X-Behavior")
+	}
+	return behaviorStr, specErr
+}
+
+// decodeBase64 decodes standard or URL-safe base64, with or without padding
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "-_") {
+		return base64.RawURLEncoding.DecodeString(s)
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
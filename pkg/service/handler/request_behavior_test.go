@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service/behavior"
+)
+
+func TestRequestBehavior_Trailers(t *testing.T) {
+	const want = "trailers=X-Result:ok;X-Checksum:abc"
+
+	tests := []struct {
+		name    string
+		target  string
+		header  string
+		wantStr string
+	}{
+		{name: "encoded query", target: "/?behavior=trailers=X-Result:ok%3BX-Checksum:abc", wantStr: want},
+		{name: "header", target: "/", header: want, wantStr: want},
+		// An unencoded ; makes the query parameter invalid, so it is dropped entirely
+		{name: "unencoded query", target: "/?behavior=" + want, wantStr: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				r.Header.Set("X-Behavior", tt.header)
+			}
+
+			behaviorStr, err := RequestBehavior(r)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if behaviorStr != tt.wantStr {
+				t.Fatalf("Expected %q, got %q", tt.wantStr, behaviorStr)
+			}
+			if tt.wantStr == "" {
+				return
+			}
+
+			b, err := behavior.Parse(behaviorStr)
+			if err != nil {
+				t.Fatalf("Parse() failed: %v", err)
+			}
+			if b.Trailers == nil || len(b.Trailers.Trailers) != 2 {
+				t.Fatalf("Expected two trailers, got %+v", b.Trailers)
+			}
+			if got := b.Trailers.Trailers[1]; got.Name != "X-Checksum" || got.Value != "abc" {
+				t.Errorf("Expected X-Checksum: abc, got %s: %s", got.Name, got.Value)
+			}
+		})
+	}
+}
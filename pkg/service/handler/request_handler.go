@@ -3,15 +3,21 @@ package handler
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"net/http"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/behavior"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/client"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
 	pb "github.com/aslakknutsen/kkbase/testapp/proto/testservice"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
 )
 
 // RequestContext holds all the data needed to process a request
@@ -21,6 +27,13 @@ type RequestContext struct {
 	TraceID     string
 	SpanID      string
 	BehaviorStr string
+	Path        string      // Request path (the gRPC method for gRPC), used as the cache key
+	Headers     http.Header // Incoming request headers (gRPC metadata for gRPC), used by when= conditions and priority
+	Body        []byte      // Incoming request body, used by poison-on
+	BodySize    int64       // Size of the whole request body, which Body may be truncated from
+	BodySHA256  string      // Hex SHA-256 of the whole request body, if computed while reading it
+	Host        string      // Requested host (gRPC :authority), used by sni-mismatch
+	ServerName  string      // TLS SNI of the connection (empty for plaintext), used by sni-mismatch
 }
 
 // RequestHandler encapsulates common request handling logic for both HTTP and gRPC
@@ -44,11 +57,25 @@ type ProcessResult struct {
 	Response         *pb.ServiceResponse // Non-nil on early exit
 	BehaviorsApplied string              // Effective behaviors applied (includes defaults)
 	EarlyExit        bool                // True if should return immediately
+	ResetConnection  bool                // On early exit, abort the connection instead of sending Response if possible
+	CloseGracefully  bool                // With ResetConnection, close with a FIN instead of a TCP RST
+	Location         string              // On early exit, Location header of a redirect
+
+	release func() // Frees the queue slot held by the request, if any
 }
 
-// ProcessRequest handles the complete request lifecycle
-// Returns ProcessResult with response on early exit, otherwise just BehaviorsApplied
-func (h *RequestHandler) ProcessRequest(reqCtx *RequestContext, protocol string) (*ProcessResult, error) {
+// Done releases what the request holds for its whole lifecycle, such as a queue slot.
+// Servers call it once the response has been sent.
+func (r *ProcessResult) Done() {
+	if r.release != nil {
+		r.release()
+	}
+}
+
+// ResolveBehavior returns the behavior that applies to this service for the request:
+// the request behavior (or the default), layered with active scenarios and gated by
+// when= conditions. Returns nil if no behavior applies.
+func (h *RequestHandler) ResolveBehavior(reqCtx *RequestContext) *behavior.Behavior {
 	// Get default behavior if not provided
 	behaviorStr := reqCtx.BehaviorStr
 	if behaviorStr == "" {
@@ -64,19 +91,176 @@ func (h *RequestHandler) ProcessRequest(reqCtx *RequestContext, protocol string)
 		behaviorChain = &behavior.BehaviorChain{}
 	}
 
+	// Active scenarios layer over the default behavior (explicit request behavior still wins).
+	// Chains are appended rather than joined as strings so service prefixes don't bleed across.
+	if reqCtx.BehaviorStr == "" {
+		for _, scenarioStr := range service.Scenarios.ActiveBehaviors() {
+			scenarioChain, err := behavior.ParseChain(scenarioStr)
+			if err != nil {
+				h.telemetry.Logger.Warn("Failed to parse scenario behavior",
+					zap.String("behavior", scenarioStr),
+					zap.Error(err))
+				continue
+			}
+			behaviorChain.Behaviors = append(behaviorChain.Behaviors, scenarioChain.Behaviors...)
+		}
+	}
+
 	// Extract behavior for this service
 	beh := behaviorChain.ForService(h.config.Name)
 
+	// A behavior whose when= conditions don't match this request is treated as absent
+	if beh != nil && !beh.ConditionsMet(reqCtx.Headers) {
+		beh = nil
+	}
+	return beh
+}
+
+// PropagatedBehavior returns the request behavior passed on to upstreams. Upstreams see
+// only the forwarded request, not this request's headers, so when= conditions are
+// resolved here: behaviors they select propagate unconditionally, the others not at all.
+func (h *RequestHandler) PropagatedBehavior(reqCtx *RequestContext) string {
+	if !strings.Contains(reqCtx.BehaviorStr, "when=") {
+		return reqCtx.BehaviorStr
+	}
+
+	chain, err := behavior.ParseChain(reqCtx.BehaviorStr)
+	if err != nil {
+		// Upstreams log the invalid behavior themselves
+		return reqCtx.BehaviorStr
+	}
+	return chain.ResolveConditions(reqCtx.Headers).String()
+}
+
+// ProcessRequest handles the complete request lifecycle
+// Returns ProcessResult with response on early exit, otherwise just BehaviorsApplied
+func (h *RequestHandler) ProcessRequest(reqCtx *RequestContext, protocol string) (*ProcessResult, error) {
+	beh := h.ResolveBehavior(reqCtx)
+
 	// Execute behaviors with early exit on errors
 	var behaviorsApplied string
+	var release func()
+	defer func() { annotateSpan(reqCtx.Ctx, behaviorsApplied) }()
 	if beh != nil {
-		executor := behavior.NewExecutor(beh, reqCtx.TraceID, h.config.Name, h.telemetry.Logger)
+		// Self-protection: shed new requests while injected CPU/memory load is active.
+		// Checked before execution so the request that starts the load isn't shed.
+		if shouldShed, code := beh.ShouldShed(); shouldShed {
+			behaviorsApplied = beh.String()
+			h.telemetry.RecordBehavior("shed-when-loaded")
+
+			resp := h.buildResponse(reqCtx, protocol, code, fmt.Sprintf("Shedding load: %d", code), behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+
+		// Bounded worker pool: reject requests while too many are in flight
+		overLimit, code, inFlight := beh.ShouldLimitConcurrency()
+		if overLimit {
+			behaviorsApplied = beh.String() + inFlightReport(inFlight)
+			h.telemetry.RecordBehavior("concurrency")
+
+			resp := h.buildResponse(reqCtx, protocol, code, fmt.Sprintf("Concurrency limit exceeded (%d in flight): %d", inFlight, code), behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+
+		// Blocking config reload: unavailable until the triggered reload window passes
+		if reloading, code := beh.ShouldRejectReloading(); reloading {
+			behaviorsApplied = beh.String()
+			h.telemetry.RecordBehavior("config-reload")
+
+			resp := h.buildResponse(reqCtx, protocol, code, fmt.Sprintf("Config reload in progress: %d", code), behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+
+		// Partial protocol outage: the same call fails over one protocol only
+		if diverge, code := beh.ShouldDiverge(protocol); diverge {
+			behaviorsApplied = beh.String()
+			h.telemetry.RecordBehavior("protocol-divergence")
+
+			resp := h.buildResponse(reqCtx, protocol, code, fmt.Sprintf("Protocol %s unavailable: %d", protocol, code), behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+
+		// Response cache: a hit is served without recomputing, so no latency, errors or upstream calls
+		cacheHit := beh.CacheHit(h.config.Name, beh.CacheKey(reqCtx.Path, reqCtx.Headers), time.Now())
+		if beh.Cache != nil {
+			h.telemetry.RecordCacheLookup(cacheHit)
+		}
+		if cacheHit {
+			behaviorsApplied = beh.String() + ",cache:hit"
+			h.telemetry.RecordBehavior("cache:hit")
+
+			resp := h.buildResponse(reqCtx, protocol, 200, "Cached response", behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+
+		// Bounded admission: wait for a queue slot, held until the response has been sent
+		queueStart := time.Now()
+		slotRelease, admitted, err := beh.AcquireQueueSlot(reqCtx.Ctx, h.config.Name)
+		if beh.Queue != nil {
+			h.telemetry.RecordQueueWait(time.Since(queueStart), reqCtx.TraceID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("wait for queue slot: %w", err)
+		}
+		if !admitted {
+			behaviorsApplied = beh.String()
+			h.telemetry.RecordBehavior("queue")
+
+			resp := h.buildResponse(reqCtx, protocol, 503, fmt.Sprintf("Queue wait exceeded %s: 503", beh.Queue.MaxWait), behaviorsApplied, nil)
+			return &ProcessResult{
+				Response:         resp,
+				BehaviorsApplied: behaviorsApplied,
+				EarlyExit:        true,
+			}, nil
+		}
+		release = slotRelease
+
+		executor := behavior.NewExecutor(beh, reqCtx.TraceID, h.config.Name, h.telemetry.Logger).
+			WithRequestBody(reqCtx.Body).
+			WithHeaders(reqCtx.Headers).
+			WithTLS(reqCtx.Host, reqCtx.ServerName)
 		result, err := executor.Execute(reqCtx.Ctx)
+		if wait, ok := executor.LockWait(); ok {
+			h.telemetry.RecordLockWait(wait, reqCtx.TraceID)
+		}
 		if err != nil {
+			release()
 			return nil, fmt.Errorf("execute behavior: %w", err)
 		}
 
 		behaviorsApplied = executor.String()
+		if beh.Cache != nil {
+			behaviorsApplied += ",cache:miss"
+		}
+		// The in-flight count and leaked handles are reported but not recorded, keeping
+		// them out of metric labels
+		recorded := behaviorsApplied
+		if beh.Concurrency != nil {
+			behaviorsApplied += inFlightReport(inFlight)
+		}
+		if beh.FDLeak != nil {
+			behaviorsApplied += fmt.Sprintf(",fd-leak:%d", executor.FDsOpened())
+		}
 
 		// Check for early exit
 		if result != nil && result.ShouldReturn {
@@ -89,12 +273,16 @@ func (h *RequestHandler) ProcessRequest(reqCtx *RequestContext, protocol string)
 				Response:         resp,
 				BehaviorsApplied: behaviorsApplied,
 				EarlyExit:        true,
+				ResetConnection:  result.ResetConnection,
+				CloseGracefully:  result.CloseGracefully,
+				Location:         result.Location,
+				release:          release,
 			}, nil
 		}
 
 		// Record applied behaviors
-		if behaviorsApplied != "" {
-			h.telemetry.RecordBehavior(behaviorsApplied)
+		if recorded != "" {
+			h.telemetry.RecordBehavior(recorded)
 		}
 	}
 
@@ -102,12 +290,42 @@ func (h *RequestHandler) ProcessRequest(reqCtx *RequestContext, protocol string)
 	return &ProcessResult{
 		BehaviorsApplied: behaviorsApplied,
 		EarlyExit:        false,
+		release:          release,
 	}, nil
 }
 
+// inFlightReport formats the in-flight request count seen by a concurrency limit for the
+// applied behaviors. It has no '=', so parsing the applied behaviors skips it.
+func inFlightReport(inFlight int64) string {
+	return fmt.Sprintf(",in-flight:%d", inFlight)
+}
+
+// annotateSpan records the applied behaviors on the server span in ctx, as one
+// behavior.applied event per behavior, so injected faults show up in the trace
+// alongside the latency they cause. Nothing is recorded when no behavior applied.
+func annotateSpan(ctx context.Context, behaviorsApplied string) {
+	if behaviorsApplied == "" || ctx == nil {
+		return
+	}
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.SetAttributes(attribute.String("testservice.behaviors_applied", behaviorsApplied))
+	for _, part := range strings.Split(behaviorsApplied, ",") {
+		behaviorType, params, _ := strings.Cut(part, "=")
+		span.AddEvent("behavior.applied", trace.WithAttributes(
+			attribute.String("behavior.type", behaviorType),
+			attribute.String("behavior.params", params),
+		))
+	}
+}
+
 // CallUpstreams calls upstream services and returns the calls
 // This is called by the server after ProcessRequest if there's no early exit
 // For gRPC (matchedUpstreams == nil), applies weighted selection if groups are configured
+// Upstreams are called sequentially with fail-fast, or concurrently with fanout=parallel
 // Parameters:
 //   - effectiveBehaviorStr: used for routing decisions (includes defaults like upstreamWeights)
 //   - propagateBehaviorStr: passed to downstream services (external behavior only, not defaults)
@@ -119,6 +337,17 @@ func (h *RequestHandler) CallUpstreams(ctx context.Context, effectiveBehaviorStr
 		return calls, nil
 	}
 
+	// Effective behavior drives caller-side faults (e.g. upstream-degrade)
+	var effective *behavior.Behavior
+	if effectiveBehaviorStr != "" {
+		if b, err := behavior.Parse(effectiveBehaviorStr); err == nil {
+			effective = b
+		}
+	}
+
+	// Baggage members from the behavior propagate to upstreams with the received baggage
+	ctx = effective.WithBaggage(ctx)
+
 	// Determine which upstreams to call
 	upstreamsToCall := matchedUpstreams
 	if upstreamsToCall == nil {
@@ -127,44 +356,40 @@ func (h *RequestHandler) CallUpstreams(ctx context.Context, effectiveBehaviorStr
 		upstreamsToCall = h.applyWeightedSelectionForGRPC(effectiveBehaviorStr)
 	}
 
-	// Call each upstream (fail-fast: stop on first failure)
-	for _, upstream := range upstreamsToCall {
-		name := upstream.Name
-		// Build upstream config with path appended to URL (for HTTP upstreams)
-		upstreamWithPath := upstream
-		if upstream.Protocol == "http" && upstream.Path != "" {
-			upstreamWithPath = &service.UpstreamConfig{
-				Name:     upstream.Name,
-				URL:      upstream.URL + upstream.Path,
-				Protocol: upstream.Protocol,
-				Match:    upstream.Match,
-				Path:     upstream.Path,
-			}
-		} else if upstream.Protocol == "http" && upstream.Path == "" {
-			// Default to "/" for HTTP upstreams without explicit path
-			upstreamWithPath = &service.UpstreamConfig{
-				Name:     upstream.Name,
-				URL:      upstream.URL + "/",
-				Protocol: upstream.Protocol,
-				Match:    upstream.Match,
-				Path:     "/",
-			}
-		}
+	calls = h.fanOut(ctx, upstreamsToCall, propagateBehaviorStr, effective)
 
-		// Use shared caller - propagate external behavior only (not defaults)
-		// Each downstream service will apply its own defaults if no behavior targets it
-		result := h.caller.Call(ctx, name, upstreamWithPath, propagateBehaviorStr)
+	// Undeduplicated client retry: the logical request runs its fan-out (and side effects) twice
+	if effective.ShouldDuplicateInbound() {
+		h.telemetry.RecordBehavior("duplicate-inbound")
+		calls = append(calls, h.fanOut(ctx, upstreamsToCall, propagateBehaviorStr, effective)...)
+	}
 
-		// Convert to pb.UpstreamCall and record metrics
-		call := h.ResultToUpstreamCall(result)
+	return calls, nil
+}
 
-		// Determine method for metrics
-		method := "Call"
-		if result.Protocol == "http" {
-			method = "GET"
+// fanOut calls the given upstreams once, sequentially with fail-fast or concurrently with fanout=parallel.
+// Mirror upstreams are called in the background and left out of the returned calls.
+func (h *RequestHandler) fanOut(ctx context.Context, upstreams []*service.UpstreamConfig, propagateBehaviorStr string, effective *behavior.Behavior) []*pb.UpstreamCall {
+	upstreams = h.callMirrors(ctx, upstreams, propagateBehaviorStr, effective)
+
+	// Parallel fan-out: call all upstreams concurrently, keeping response order by index
+	if effective.ParallelFanout() {
+		calls := make([]*pb.UpstreamCall, len(upstreams))
+		var g errgroup.Group
+		for i, upstream := range upstreams {
+			g.Go(func() error {
+				calls[i] = h.callUpstream(ctx, upstream, propagateBehaviorStr, effective)
+				return nil
+			})
 		}
-		h.telemetry.RecordUpstreamCall(method, name, int(call.Code), result.Duration)
+		g.Wait()
+		return calls
+	}
 
+	// Call each upstream (fail-fast: stop on first failure)
+	var calls []*pb.UpstreamCall
+	for _, upstream := range upstreams {
+		call := h.callUpstream(ctx, upstream, propagateBehaviorStr, effective)
 		calls = append(calls, call)
 
 		// Fail-fast: stop on first failure (non-2xx response or error)
@@ -173,21 +398,97 @@ func (h *RequestHandler) CallUpstreams(ctx context.Context, effectiveBehaviorStr
 		}
 	}
 
-	return calls, nil
+	return calls
+}
+
+// callMirrors starts the calls to mirror upstreams without waiting for them and returns
+// the remaining upstreams. Mirrored calls keep the trace context but outlive the request,
+// and their results only reach metrics and logs, so they never fail the request.
+func (h *RequestHandler) callMirrors(ctx context.Context, upstreams []*service.UpstreamConfig, propagateBehaviorStr string, effective *behavior.Behavior) []*service.UpstreamConfig {
+	var primary []*service.UpstreamConfig
+	for _, upstream := range upstreams {
+		if !upstream.Mirror {
+			primary = append(primary, upstream)
+			continue
+		}
+
+		mirrorCtx := context.WithoutCancel(ctx)
+		go func() {
+			call := h.callUpstream(mirrorCtx, upstream, propagateBehaviorStr, effective)
+			h.telemetry.Logger.Debug("Mirrored upstream call completed",
+				zap.String("upstream", call.Name),
+				zap.Int32("code", call.Code),
+				zap.String("error", call.Error))
+		}()
+	}
+	return primary
+}
+
+// callUpstream calls a single upstream, records metrics and converts the result
+func (h *RequestHandler) callUpstream(ctx context.Context, upstream *service.UpstreamConfig, propagateBehaviorStr string, effective *behavior.Behavior) *pb.UpstreamCall {
+	name := upstream.Name
+	// Build upstream config with path appended to URL (for HTTP upstreams)
+	upstreamWithPath := upstream
+	if upstream.Protocol == "http" && upstream.Path != "" {
+		upstreamWithPath = &service.UpstreamConfig{
+			Name:     upstream.Name,
+			URL:      upstream.URL + upstream.Path,
+			Protocol: upstream.Protocol,
+			Match:    upstream.Match,
+			Path:     upstream.Path,
+			Timeout:  upstream.Timeout,
+		}
+	} else if upstream.Protocol == "http" && upstream.Path == "" {
+		// Default to "/" for HTTP upstreams without explicit path
+		upstreamWithPath = &service.UpstreamConfig{
+			Name:     upstream.Name,
+			URL:      upstream.URL + "/",
+			Protocol: upstream.Protocol,
+			Match:    upstream.Match,
+			Path:     "/",
+			Timeout:  upstream.Timeout,
+		}
+	}
+
+	// Use shared caller - propagate external behavior only (not defaults)
+	// Each downstream service will apply its own defaults if no behavior targets it.
+	// upstream-grow adds the growing body-size requested from this upstream.
+	result := h.caller.Call(ctx, name, upstreamWithPath, effective.PropagateTo(name, propagateBehaviorStr), effective)
+
+	// Convert to pb.UpstreamCall and record metrics
+	call := h.ResultToUpstreamCall(result)
+
+	// Determine method for metrics
+	method := "Call"
+	if result.Protocol == "http" {
+		method = "GET"
+	}
+	var traceID string
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		traceID = spanCtx.TraceID().String()
+	}
+	h.telemetry.RecordUpstreamCall(method, name, int(call.Code), result.Duration, traceID)
+	h.telemetry.RecordServiceGraph(h.config.Name, name, upstream.Protocol, int(call.Code))
+
+	return call
 }
 
 // applyWeightedSelectionForGRPC applies weighted selection and probability filtering for gRPC
-// - Groups: select one per group based on weights
+// - Groups: select the pinned upstream (route=) per group, or one based on weights
 // - Ungrouped with Probability: include based on probability roll
 // - Ungrouped without Probability: always include
 func (h *RequestHandler) applyWeightedSelectionForGRPC(behaviorStr string) []*service.UpstreamConfig {
 	upstreams := h.config.Upstreams
 
-	// Extract weights from behavior
+	// Extract weights, route pins and the random source (seed=) from behavior
 	var weights map[string]int
+	var pins map[string]string
+	rnd := service.Random
 	if behaviorStr != "" {
-		if b, err := behavior.Parse(behaviorStr); err == nil && b.UpstreamWeights != nil {
-			weights = b.UpstreamWeights.Weights
+		if b, err := behavior.Parse(behaviorStr); err == nil {
+			weights = b.UpstreamWeightMap()
+			pins = b.RoutePins()
+			rnd = b.Random()
 		}
 	}
 
@@ -205,14 +506,18 @@ func (h *RequestHandler) applyWeightedSelectionForGRPC(behaviorStr string) []*se
 		return upstreams
 	}
 
-	// Group upstreams by their Group field
+	// Group upstreams by their Group field, keeping the order groups first appear in
 	groups := make(map[string][]*service.UpstreamConfig)
+	var groupOrder []string
 	var ungrouped []*service.UpstreamConfig
 
 	for _, u := range upstreams {
 		if u.Group == "" {
 			ungrouped = append(ungrouped, u)
 		} else {
+			if _, ok := groups[u.Group]; !ok {
+				groupOrder = append(groupOrder, u.Group)
+			}
 			groups[u.Group] = append(groups[u.Group], u)
 		}
 	}
@@ -222,7 +527,7 @@ func (h *RequestHandler) applyWeightedSelectionForGRPC(behaviorStr string) []*se
 	for _, u := range ungrouped {
 		if u.Probability > 0 {
 			// Roll probability to decide if included
-			if rand.Float64() < u.Probability {
+			if rnd.Float64() < u.Probability {
 				result = append(result, u)
 			}
 		} else {
@@ -231,9 +536,14 @@ func (h *RequestHandler) applyWeightedSelectionForGRPC(behaviorStr string) []*se
 		}
 	}
 
-	// For each group, select one upstream based on weights
-	for _, groupUpstreams := range groups {
-		selected := selectWeightedUpstream(groupUpstreams, weights)
+	// For each group, select the pinned upstream if it is in the group, otherwise one based on weights.
+	// Groups are visited in a fixed order so a seeded rnd makes the same selections.
+	for _, group := range groupOrder {
+		groupUpstreams := groups[group]
+		selected := selectPinnedUpstream(groupUpstreams, pins[group])
+		if selected == nil {
+			selected = selectWeightedUpstream(groupUpstreams, weights, rnd)
+		}
 		if selected != nil {
 			result = append(result, selected)
 		}
@@ -242,8 +552,21 @@ func (h *RequestHandler) applyWeightedSelectionForGRPC(behaviorStr string) []*se
 	return result
 }
 
+// selectPinnedUpstream returns the group's upstream with the pinned ID, or nil if none has it
+func selectPinnedUpstream(upstreams []*service.UpstreamConfig, pinned string) *service.UpstreamConfig {
+	if pinned == "" {
+		return nil
+	}
+	for _, u := range upstreams {
+		if u.Name == pinned {
+			return u
+		}
+	}
+	return nil
+}
+
 // selectWeightedUpstream selects one upstream from the group based on weights
-func selectWeightedUpstream(upstreams []*service.UpstreamConfig, weights map[string]int) *service.UpstreamConfig {
+func selectWeightedUpstream(upstreams []*service.UpstreamConfig, weights map[string]int, rnd *service.Rand) *service.UpstreamConfig {
 	if len(upstreams) == 0 {
 		return nil
 	}
@@ -299,7 +622,7 @@ func selectWeightedUpstream(upstreams []*service.UpstreamConfig, weights map[str
 	}
 
 	// Random selection based on weights
-	r := randomInt(totalWeight)
+	r := rnd.Intn(totalWeight)
 	cumulative := 0
 	for i, w := range effectiveWeights {
 		cumulative += w
@@ -312,25 +635,50 @@ func selectWeightedUpstream(upstreams []*service.UpstreamConfig, weights map[str
 	return upstreams[len(upstreams)-1]
 }
 
-// randomInt returns a random integer in [0, n)
-func randomInt(n int) int {
-	return rand.Intn(n)
-}
-
 // BuildSuccessResponse builds a successful response
 func (h *RequestHandler) BuildSuccessResponse(reqCtx *RequestContext, protocol string, behaviorsApplied string, upstreamCalls []*pb.UpstreamCall) *pb.ServiceResponse {
 	body := "All ok"
+	// Echo request bodies (e.g. POST payloads) so callers can see what arrived. Binary
+	// bodies can't be carried in the response's string field; their size and digest can.
+	if len(reqCtx.Body) > 0 && utf8.Valid(reqCtx.Body) {
+		body = string(reqCtx.Body)
+	}
+
+	// A successfully recomputed response fills the cache for the requests that follow
+	if behaviorsApplied != "" {
+		if b, err := behavior.Parse(behaviorsApplied); err == nil {
+			b.CacheFill(h.config.Name, b.CacheKey(reqCtx.Path, reqCtx.Headers), time.Now())
+		}
+	}
 	return h.buildResponse(reqCtx, protocol, 200, body, behaviorsApplied, upstreamCalls)
 }
 
 // BuildUpstreamErrorResponse builds a response for upstream failures
 func (h *RequestHandler) BuildUpstreamErrorResponse(reqCtx *RequestContext, protocol string, failedCall *pb.UpstreamCall, behaviorsApplied string, upstreamCalls []*pb.UpstreamCall) *pb.ServiceResponse {
 	body := fmt.Sprintf("Upstream service failure: %s returned %d", failedCall.Name, failedCall.Code)
+	if minSuccess := aggregateMinSuccess(behaviorsApplied); minSuccess > 0 {
+		body = fmt.Sprintf("Aggregate failure: %d of %d upstreams succeeded, %d required", countSucceeded(upstreamCalls), len(upstreamCalls), minSuccess)
+	}
 	return h.buildResponse(reqCtx, protocol, 502, body, behaviorsApplied, upstreamCalls)
 }
 
-// CheckUpstreamFailures checks if any upstream returned non-2xx (excluding connection errors where Code=0)
-func (h *RequestHandler) CheckUpstreamFailures(upstreamCalls []*pb.UpstreamCall) *pb.UpstreamCall {
+// CheckUpstreamFailures checks if any upstream returned non-2xx (excluding connection errors where Code=0).
+// With aggregate=min-success:N in behaviorsApplied, the request instead fails only if fewer
+// than N upstreams succeeded (2xx without error); the first unsuccessful call is returned.
+func (h *RequestHandler) CheckUpstreamFailures(upstreamCalls []*pb.UpstreamCall, behaviorsApplied string) *pb.UpstreamCall {
+	if minSuccess := aggregateMinSuccess(behaviorsApplied); minSuccess > 0 {
+		if countSucceeded(upstreamCalls) >= minSuccess {
+			return nil
+		}
+		for _, call := range upstreamCalls {
+			if !succeeded(call) {
+				return call
+			}
+		}
+		// Fewer upstreams than required, all of them successful
+		return &pb.UpstreamCall{Name: "aggregate", Error: "not enough upstreams"}
+	}
+
 	for _, call := range upstreamCalls {
 		if call.Code >= 300 {
 			return call
@@ -339,14 +687,59 @@ func (h *RequestHandler) CheckUpstreamFailures(upstreamCalls []*pb.UpstreamCall)
 	return nil
 }
 
+// aggregateMinSuccess returns the aggregate min-success of the applied behaviors, 0 if none
+func aggregateMinSuccess(behaviorsApplied string) int {
+	if behaviorsApplied == "" {
+		return 0
+	}
+	b, err := behavior.Parse(behaviorsApplied)
+	if err != nil {
+		return 0
+	}
+	return b.AggregateMinSuccess()
+}
+
+// succeeded reports whether an upstream call returned 2xx without error
+func succeeded(call *pb.UpstreamCall) bool {
+	return call.Error == "" && call.Code >= 200 && call.Code < 300
+}
+
+// countSucceeded counts the successful upstream calls
+func countSucceeded(calls []*pb.UpstreamCall) int {
+	n := 0
+	for _, call := range calls {
+		if succeeded(call) {
+			n++
+		}
+	}
+	return n
+}
+
 // buildResponse constructs a response
 func (h *RequestHandler) buildResponse(reqCtx *RequestContext, protocol string, code int, body string, behaviorsApplied string, upstreamCalls []*pb.UpstreamCall) *pb.ServiceResponse {
 	now := time.Now()
 
-	return &pb.ServiceResponse{
+	// version-mix overrides the reported version to simulate a mixed-version fleet,
+	// replica-lag reports the data as served by a lagging read replica,
+	// body-size pads the body to the requested size
+	version := h.config.Version
+	var b *behavior.Behavior
+	var replica *behavior.ReplicaRead
+	if behaviorsApplied != "" {
+		if parsed, err := behavior.Parse(behaviorsApplied); err == nil {
+			b = parsed
+			if v := b.PickVersion(); v != "" {
+				version = v
+			}
+			replica = b.ReplicaRead(now)
+			body = b.PadBody(body)
+		}
+	}
+
+	resp := &pb.ServiceResponse{
 		Service: &pb.ServiceInfo{
 			Name:      h.config.Name,
-			Version:   h.config.Version,
+			Version:   version,
 			Namespace: h.config.Namespace,
 			Pod:       h.config.PodName,
 			Node:      h.config.NodeName,
@@ -362,6 +755,30 @@ func (h *RequestHandler) buildResponse(reqCtx *RequestContext, protocol string,
 		SpanId:           reqCtx.SpanID,
 		UpstreamCalls:    upstreamCalls,
 	}
+	if replica != nil {
+		resp.ReplicaLagSeconds = replica.Lag.Seconds()
+		resp.DataAsOf = replica.AsOf.Format(time.RFC3339Nano)
+		resp.Stale = replica.Stale
+	}
+	// Report the request body as received, for payload integrity checks through proxies
+	resp.RequestBodyBytes = reqCtx.BodySize
+	if b.EchoesDigest() {
+		resp.RequestBodySha256 = reqCtx.BodySHA256
+		if resp.RequestBodySha256 == "" && reqCtx.BodySize == int64(len(reqCtx.Body)) {
+			resp.RequestBodySha256 = bodySHA256(reqCtx.Body)
+		}
+	}
+
+	// Echo the baggage received with the request
+	if reqCtx.Ctx != nil {
+		if members := baggage.FromContext(reqCtx.Ctx).Members(); len(members) > 0 {
+			resp.Baggage = make(map[string]string, len(members))
+			for _, m := range members {
+				resp.Baggage[m.Key()] = m.Value()
+			}
+		}
+	}
+	return resp
 }
 
 // ResultToUpstreamCall converts a client.Result to pb.UpstreamCall
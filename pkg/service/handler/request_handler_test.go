@@ -3,17 +3,32 @@ package handler
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service/behavior"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/client"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
 	pb "github.com/aslakknutsen/kkbase/testapp/proto/testservice"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func createTestConfig() *service.Config {
@@ -244,53 +259,1231 @@ func TestProcessRequest_TargetedBehavior(t *testing.T) {
 	}
 }
 
+func TestProcessRequest_WhenHeaderCondition(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	tests := []struct {
+		name          string
+		headers       http.Header
+		wantEarlyExit bool
+	}{
+		{"matching header triggers error", http.Header{"X-Debug": []string{"true"}}, true},
+		{"non-matching header is ignored", http.Header{"X-Debug": []string{"false"}}, false},
+		{"missing header is ignored", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := &RequestContext{
+				Ctx:         context.Background(),
+				StartTime:   time.Now(),
+				TraceID:     "trace123",
+				SpanID:      "span456",
+				BehaviorStr: "latency=200ms,error=503,when=header=X-Debug:true",
+				Headers:     tt.headers,
+			}
+
+			start := time.Now()
+			result, err := handler.ProcessRequest(reqCtx, "http")
+			elapsed := time.Since(start)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.EarlyExit != tt.wantEarlyExit {
+				t.Errorf("Expected EarlyExit=%v, got %v", tt.wantEarlyExit, result.EarlyExit)
+			}
+			if !tt.wantEarlyExit {
+				if elapsed >= 200*time.Millisecond {
+					t.Errorf("Expected no latency for unmet condition, took %v", elapsed)
+				}
+				if result.BehaviorsApplied != "" {
+					t.Errorf("Expected no behaviors applied, got %s", result.BehaviorsApplied)
+				}
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_WhenConditionTwoHops(t *testing.T) {
+	tel := createTestTelemetry()
+
+	// Second hop: the orders service, reading the propagated behavior like the HTTP server
+	ordersCfg := createTestConfig()
+	ordersCfg.Name = "orders"
+	orders := NewRequestHandler(ordersCfg, client.NewCaller(tel), tel)
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		behaviorStr, _ := RequestBehavior(r)
+		result, err := orders.ProcessRequest(&RequestContext{
+			Ctx:         r.Context(),
+			StartTime:   time.Now(),
+			BehaviorStr: behaviorStr,
+			Headers:     r.Header,
+		}, "http")
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		defer result.Done()
+		if result.EarlyExit {
+			w.WriteHeader(int(result.Response.Code))
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{{Name: "orders", URL: upstream.URL, Protocol: "http"}}
+	entry := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+	tests := []struct {
+		name     string
+		headers  http.Header
+		wantCode int32
+	}{
+		{"condition met at the entry", http.Header{"X-Debug": []string{"true"}}, 503},
+		{"condition unmet at the entry", http.Header{"X-Debug": []string{"false"}}, 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqCtx := &RequestContext{
+				Ctx:         context.Background(),
+				StartTime:   time.Now(),
+				BehaviorStr: "orders:error=503,when=header=X-Debug:true",
+				Headers:     tt.headers,
+			}
+			result, err := entry.ProcessRequest(reqCtx, "http")
+			if err != nil || result.EarlyExit {
+				t.Fatalf("Expected the entry service to pass the request on, got %+v (%v)", result, err)
+			}
+			defer result.Done()
+
+			calls, err := entry.CallUpstreams(reqCtx.Ctx, result.BehaviorsApplied, entry.PropagatedBehavior(reqCtx), nil)
+			if err != nil || len(calls) != 1 {
+				t.Fatalf("Expected one upstream call, got %+v (%v)", calls, err)
+			}
+			if calls[0].Code != tt.wantCode {
+				t.Errorf("Expected orders to respond %d, got %d", tt.wantCode, calls[0].Code)
+			}
+		})
+	}
+}
+
+func TestProcessRequest_ShedWhenLoaded(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	newReqCtx := func(behaviorStr string) *RequestContext {
+		return &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: behaviorStr,
+		}
+	}
+
+	// Start a short memory spike; the request that starts it is not shed
+	result, err := handler.ProcessRequest(newReqCtx("memory=spike:1Mi:300ms,shed-when-loaded=503"), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatal("Expected request starting the spike to proceed")
+	}
+
+	// Requests during the spike are shed
+	result, err = handler.ProcessRequest(newReqCtx("shed-when-loaded=503"), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 503 {
+		t.Fatalf("Expected 503 during active memory spike, got %+v", result)
+	}
+
+	// Requests resume once the spike is over
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		result, err = handler.ProcessRequest(newReqCtx("shed-when-loaded=503"), "http")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !result.EarlyExit {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected requests to resume after memory spike ended")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestProcessRequest_ConfigReload(t *testing.T) {
+	defer service.ConfigReload.Reset()
+
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:         context.Background(),
+		StartTime:   time.Now(),
+		TraceID:     "trace123",
+		SpanID:      "span456",
+		BehaviorStr: "config-reload=200ms:503",
+	}
+
+	// Nothing happens until a reload is triggered
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatal("Expected request to proceed before reload is triggered")
+	}
+
+	service.ConfigReload.Trigger()
+	result, err = handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 503 {
+		t.Fatalf("Expected 503 during reload, got %+v", result)
+	}
+
+	// The service resumes once the reload window has passed
+	time.Sleep(250 * time.Millisecond)
+	result, err = handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatalf("Expected request to proceed after reload, got %+v", result.Response)
+	}
+}
+
+func TestProcessRequest_ProtocolDivergence(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:         context.Background(),
+		StartTime:   time.Now(),
+		TraceID:     "trace123",
+		SpanID:      "span456",
+		BehaviorStr: "protocol-divergence=http:ok:grpc:error=UNAVAILABLE",
+	}
+
+	// HTTP succeeds
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatalf("Expected HTTP request to proceed, got %+v", result.Response)
+	}
+
+	// gRPC to the same service fails with UNAVAILABLE (503, mapped back by the gRPC server)
+	result, err = handler.ProcessRequest(reqCtx, "grpc")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 503 {
+		t.Fatalf("Expected gRPC request to fail with 503, got %+v", result)
+	}
+	if result.Response.Service.Protocol != "grpc" {
+		t.Errorf("Expected grpc protocol in response, got %s", result.Response.Service.Protocol)
+	}
+}
+
+func TestProcessRequest_ConnectionReset(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:         context.Background(),
+		StartTime:   time.Now(),
+		TraceID:     "trace123",
+		SpanID:      "span456",
+		BehaviorStr: "connection-reset=1:fin",
+	}
+
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || !result.ResetConnection || !result.CloseGracefully {
+		t.Fatalf("Expected a graceful connection close, got %+v", result)
+	}
+
+	// The response is the fallback when the connection can't be hijacked
+	if result.Response == nil || result.Response.Code != 503 {
+		t.Errorf("Expected 503 fallback response, got %+v", result.Response)
+	}
+}
+
+func TestProcessRequest_Redirect(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:         context.Background(),
+		StartTime:   time.Now(),
+		TraceID:     "trace123",
+		SpanID:      "span456",
+		BehaviorStr: "redirect=308:https://other/",
+	}
+
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Location != "https://other/" {
+		t.Fatalf("Expected redirect early exit, got %+v", result)
+	}
+	if result.Response == nil || result.Response.Code != 308 {
+		t.Errorf("Expected 308 response, got %+v", result.Response)
+	}
+}
+
+func TestProcessRequest_Cache(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	tel.Metrics.CacheRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "test_cache_requests_total"},
+		[]string{"service", "result"},
+	)
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	newReq := func(path string) *RequestContext {
+		return &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: "cache=1m,latency=50ms",
+			Path:        path,
+		}
+	}
+
+	// Miss: the response is recomputed, then fills the cache
+	reqCtx := newReq("/cache-test/products")
+	start := time.Now()
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit || time.Since(start) < 50*time.Millisecond {
+		t.Fatalf("Expected a recomputed response on miss, got %+v", result)
+	}
+	if !strings.Contains(result.BehaviorsApplied, "cache:miss") {
+		t.Errorf("Expected cache:miss in behaviors applied, got %s", result.BehaviorsApplied)
+	}
+	handler.BuildSuccessResponse(reqCtx, "http", result.BehaviorsApplied, nil)
+
+	// Hit: served from cache without the latency
+	start = time.Now()
+	result, err = handler.ProcessRequest(newReq("/cache-test/products"), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 200 {
+		t.Fatalf("Expected a cached 200 response, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed >= 50*time.Millisecond {
+		t.Errorf("Expected cache hit to skip latency, took %v", elapsed)
+	}
+	if !strings.Contains(result.BehaviorsApplied, "cache:hit") {
+		t.Errorf("Expected cache:hit in behaviors applied, got %s", result.BehaviorsApplied)
+	}
+
+	// Another path is keyed separately
+	result, err = handler.ProcessRequest(newReq("/cache-test/cart"), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Errorf("Expected a miss for another path, got %+v", result.Response)
+	}
+
+	for resultLabel, want := range map[string]float64{"hit": 1, "miss": 2} {
+		got := testutil.ToFloat64(tel.Metrics.CacheRequestsTotal.WithLabelValues(tel.ServiceName, resultLabel))
+		if got != want {
+			t.Errorf("Expected %v cache %ss recorded, got %v", want, resultLabel, got)
+		}
+	}
+}
+
+func TestProcessRequest_ActiveScenario(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.DefaultBehavior = "latency=1ms"
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	service.Scenarios.Start([]service.Scenario{
+		{Name: "outage", At: 0, Behavior: "test-service:error=503"},
+	}, zap.NewNop())
+	defer service.Scenarios.Stop()
+
+	newReqCtx := func(behaviorStr string) *RequestContext {
+		return &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: behaviorStr,
+		}
+	}
+
+	// The scenario timer fires asynchronously
+	deadline := time.Now().Add(time.Second)
+	for len(service.Scenarios.ActiveBehaviors()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected scenario to become active")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	result, err := handler.ProcessRequest(newReqCtx(""), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 503 {
+		t.Fatalf("Expected active scenario to inject 503, got %+v", result)
+	}
+
+	// Explicit request behavior takes precedence over scenarios, as over defaults
+	result, err = handler.ProcessRequest(newReqCtx("latency=1ms"), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Errorf("Expected explicit behavior to bypass scenario, got %+v", result.Response)
+	}
+
+	service.Scenarios.Stop()
+	result, err = handler.ProcessRequest(newReqCtx(""), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Errorf("Expected no error after scenarios stopped, got %+v", result.Response)
+	}
+}
+
 func TestCallUpstreams_NoUpstreams(t *testing.T) {
 	cfg := createTestConfig()
 	tel := createTestTelemetry()
 	caller := client.NewCaller(tel)
 	handler := NewRequestHandler(cfg, caller, tel)
 
-	calls, err := handler.CallUpstreams(context.Background(), "", "", nil)
+	calls, err := handler.CallUpstreams(context.Background(), "", "", nil)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Expected 0 calls, got %d", len(calls))
+	}
+}
+
+func TestCallUpstreams_WithMatchedUpstreams(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+		Name:     "service-b",
+		URL:      "http://localhost:8081",
+		Protocol: "http",
+	})
+	cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+		Name:     "service-c",
+		URL:      "http://localhost:8082",
+		Protocol: "http",
+	})
+	
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	// Only service-b is matched
+	matchedUpstreams := []*service.UpstreamConfig{
+		cfg.Upstreams[0], // service-b
+	}
+
+	calls, err := handler.CallUpstreams(context.Background(), "", "", matchedUpstreams)
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	// Should only call matched upstream (service-b), even though service-c is configured
+	// However, both will fail to connect, so we expect 1 call with error
+	if len(calls) != 1 {
+		t.Errorf("Expected 1 call, got %d", len(calls))
+	}
+}
+
+func TestCallUpstreams_FanoutParallel(t *testing.T) {
+	const childLatency = 200 * time.Millisecond
+
+	cfg := createTestConfig()
+	for i, code := range []int{http.StatusOK, http.StatusServiceUnavailable, http.StatusOK} {
+		code := code
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(childLatency)
+			w.WriteHeader(code)
+		}))
+		defer srv.Close()
+
+		cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+			Name:     fmt.Sprintf("service-%d", i),
+			URL:      srv.URL,
+			Protocol: "http",
+		})
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	start := time.Now()
+	calls, err := handler.CallUpstreams(context.Background(), "fanout=parallel", "", cfg.Upstreams)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// All upstreams are called, even after a failure
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %d", len(calls))
+	}
+
+	// Response order matches upstream order
+	for i, call := range calls {
+		if expected := fmt.Sprintf("service-%d", i); call.Name != expected {
+			t.Errorf("Expected call %d to be %s, got %s", i, expected, call.Name)
+		}
+	}
+
+	// Total duration ~ max(child), not sum(child)
+	if elapsed >= 2*childLatency {
+		t.Errorf("Expected parallel fan-out to take ~%v, took %v", childLatency, elapsed)
+	}
+
+	// First failure is still reported
+	failed := handler.CheckUpstreamFailures(calls, "")
+	if failed == nil || failed.Name != "service-1" {
+		t.Errorf("Expected service-1 to be reported as failed, got %+v", failed)
+	}
+}
+
+func TestCallUpstreams_Mirror(t *testing.T) {
+	const mirrorLatency = 200 * time.Millisecond
+
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer primary.Close()
+
+	// The mirror is slow and failing, and must finish even after the request is done
+	mirrored := make(chan error, 1)
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(mirrorLatency)
+		mirrored <- r.Context().Err()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer mirror.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{
+		{Name: "orders-v2", URL: mirror.URL, Protocol: "http", Mirror: true},
+		{Name: "orders", URL: primary.URL, Protocol: "http"},
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	start := time.Now()
+	calls, err := handler.CallUpstreams(ctx, "", "", cfg.Upstreams)
+	elapsed := time.Since(start)
+	cancel()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Only the primary call is reported, without waiting for the mirror
+	if len(calls) != 1 || calls[0].Name != "orders" {
+		t.Fatalf("Expected only the orders call, got %+v", calls)
+	}
+	if elapsed >= mirrorLatency {
+		t.Errorf("Expected the mirror not to be waited for, took %v", elapsed)
+	}
+
+	// The mirror's failure doesn't fail the request
+	if failed := handler.CheckUpstreamFailures(calls, ""); failed != nil {
+		t.Errorf("Expected no failure, got %+v", failed)
+	}
+
+	select {
+	case err := <-mirrored:
+		if err != nil {
+			t.Errorf("Expected the mirrored call to outlive the request, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the mirror to be called")
+	}
+}
+
+func TestCallUpstreams_RoutePin(t *testing.T) {
+	cfg := createTestConfig()
+	for _, name := range []string{"checkout-v1", "checkout-v2"} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+			Name:     name,
+			URL:      srv.URL,
+			Protocol: "http",
+			Group:    "checkout",
+		})
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	// Without matched upstreams (gRPC), the pin selects from the group despite the weights
+	for i := 0; i < 20; i++ {
+		calls, err := handler.CallUpstreams(context.Background(), "upstreamWeights=checkout-v1:100,route=checkout:checkout-v2", "", nil)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(calls) != 1 || calls[0].Name != "checkout-v2" {
+			t.Fatalf("Expected only checkout-v2 to be called, got %+v", calls)
+		}
+	}
+
+	// An unknown upstream falls back to the weights
+	calls, err := handler.CallUpstreams(context.Background(), "upstreamWeights=checkout-v1:100,route=checkout:checkout-v3", "", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(calls) != 1 || calls[0].Name != "checkout-v1" {
+		t.Errorf("Expected checkout-v1 to be called, got %+v", calls)
+	}
+}
+
+func TestCallUpstreams_BehaviorPropagationHTTP(t *testing.T) {
+	const behaviorStr = "latency=10ms"
+
+	tests := []struct {
+		mode       string
+		wantQuery  string
+		wantHeader string
+	}{
+		{mode: "", wantQuery: behaviorStr},
+		{mode: client.PropagateQuery, wantQuery: behaviorStr},
+		{mode: client.PropagateHeader, wantHeader: behaviorStr},
+		{mode: client.PropagateBoth, wantQuery: behaviorStr, wantHeader: behaviorStr},
+		{mode: "carrier-pigeon", wantQuery: behaviorStr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var query, header string
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query().Get("behavior")
+				header = r.Header.Get("X-Behavior")
+			}))
+			defer upstream.Close()
+
+			cfg := createTestConfig()
+			cfg.Upstreams = []*service.UpstreamConfig{{Name: "api", URL: upstream.URL, Protocol: "http"}}
+
+			tel := createTestTelemetry()
+			caller := client.NewCaller(tel)
+			caller.SetBehaviorPropagation(tt.mode)
+			handler := NewRequestHandler(cfg, caller, tel)
+
+			if _, err := handler.CallUpstreams(context.Background(), "", behaviorStr, nil); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if query != tt.wantQuery || header != tt.wantHeader {
+				t.Errorf("Expected query %q and header %q, got %q and %q", tt.wantQuery, tt.wantHeader, query, header)
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_BehaviorPropagationEscaping(t *testing.T) {
+	behaviors := []struct {
+		name     string
+		behavior string
+	}{
+		{"percent", "latency=100ms,jitter=20%"},
+		{"semicolon", "trailers=X-Result:ok;X-Checksum:abc"},
+		{"colon", "orders:error=503:0.5"},
+		{"all", "orders:upstream-degrade=payment:10ms..2s:5m;inventory:0s..500ms:1m,jitter=5%"},
+	}
+
+	for _, mode := range []string{client.PropagateQuery, client.PropagateHeader} {
+		for _, bt := range behaviors {
+			t.Run(mode+"/"+bt.name, func(t *testing.T) {
+				var received string
+				var receiveErr error
+				upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					received, receiveErr = RequestBehavior(r)
+				}))
+				defer upstream.Close()
+
+				cfg := createTestConfig()
+				cfg.Upstreams = []*service.UpstreamConfig{{Name: "api", URL: upstream.URL, Protocol: "http"}}
+
+				tel := createTestTelemetry()
+				caller := client.NewCaller(tel)
+				caller.SetBehaviorPropagation(mode)
+				handler := NewRequestHandler(cfg, caller, tel)
+
+				if _, err := handler.CallUpstreams(context.Background(), "", bt.behavior, nil); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if receiveErr != nil {
+					t.Fatalf("Expected no error reading behavior, got %v", receiveErr)
+				}
+				if received != bt.behavior {
+					t.Errorf("Expected upstream to receive %q, got %q", bt.behavior, received)
+				}
+			})
+		}
+	}
+}
+
+func TestCallUpstreams_PropagatesJitter(t *testing.T) {
+	for _, input := range []string{"latency=100ms,jitter=20%", "latency=100ms,jitter=20"} {
+		t.Run(input, func(t *testing.T) {
+			var received *behavior.Behavior
+			upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				behaviorStr, _ := RequestBehavior(r)
+				received, _ = behavior.Parse(behaviorStr)
+			}))
+			defer upstream.Close()
+
+			cfg := createTestConfig()
+			cfg.Upstreams = []*service.UpstreamConfig{{Name: "api", URL: upstream.URL, Protocol: "http"}}
+
+			tel := createTestTelemetry()
+			handler := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+			if _, err := handler.CallUpstreams(context.Background(), "", input, nil); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if received == nil || received.Latency == nil {
+				t.Fatalf("Expected upstream to receive the latency behavior, got %v", received)
+			}
+			if received.Latency.Value != 100*time.Millisecond || received.Latency.Jitter != 20 {
+				t.Errorf("Expected 100ms ±20%%, got %s ±%v%%", received.Latency.Value, received.Latency.Jitter)
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_PropagatesUpstreamDegrade(t *testing.T) {
+	const behaviorStr = "orders:upstream-degrade=payment:10ms..2s:5m;inventory:0s..500ms:1m"
+
+	var received *behavior.Behavior
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		behaviorStr, _ := RequestBehavior(r)
+		if chain, err := behavior.ParseChain(behaviorStr); err == nil {
+			received = chain.ForService("orders")
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{{Name: "orders", URL: upstream.URL, Protocol: "http"}}
+
+	tel := createTestTelemetry()
+	handler := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+	if _, err := handler.CallUpstreams(context.Background(), "", behaviorStr, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if received == nil || received.UpstreamDegrade == nil {
+		t.Fatalf("Expected orders to receive upstream-degrade, got %v", received)
+	}
+	targets := received.UpstreamDegrade.Targets
+	if len(targets) != 2 || targets[0].Upstream != "payment" || targets[1].Upstream != "inventory" {
+		t.Errorf("Expected payment and inventory targets, got %+v", targets)
+	}
+}
+
+// behaviorRecorder is a gRPC upstream recording how behavior was received
+type behaviorRecorder struct {
+	pb.UnimplementedTestServiceServer
+	field    string
+	metadata string
+}
+
+func (r *behaviorRecorder) Call(ctx context.Context, req *pb.CallRequest) (*pb.ServiceResponse, error) {
+	r.field = req.Behavior
+	r.metadata = ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("x-behavior")) > 0 {
+		r.metadata = md.Get("x-behavior")[0]
+	}
+	return &pb.ServiceResponse{Code: 200}, nil
+}
+
+func TestCallUpstreams_BehaviorPropagationGRPC(t *testing.T) {
+	const behaviorStr = "latency=10ms"
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	recorder := &behaviorRecorder{}
+	srv := grpc.NewServer()
+	pb.RegisterTestServiceServer(srv, recorder)
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	tests := []struct {
+		mode         string
+		wantField    string
+		wantMetadata string
+	}{
+		{mode: client.PropagateQuery, wantField: behaviorStr},
+		{mode: client.PropagateHeader, wantMetadata: behaviorStr},
+		{mode: client.PropagateBoth, wantField: behaviorStr, wantMetadata: behaviorStr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			cfg := createTestConfig()
+			cfg.Upstreams = []*service.UpstreamConfig{{Name: "api", URL: "grpc://" + lis.Addr().String(), Protocol: "grpc"}}
+
+			tel := createTestTelemetry()
+			caller := client.NewCaller(tel)
+			defer caller.Close()
+			caller.SetBehaviorPropagation(tt.mode)
+			handler := NewRequestHandler(cfg, caller, tel)
+
+			calls, err := handler.CallUpstreams(context.Background(), "", behaviorStr, nil)
+			if err != nil || len(calls) != 1 || calls[0].Error != "" {
+				t.Fatalf("Expected a successful call, got %+v (%v)", calls, err)
+			}
+			if recorder.field != tt.wantField || recorder.metadata != tt.wantMetadata {
+				t.Errorf("Expected field %q and metadata %q, got %q and %q", tt.wantField, tt.wantMetadata, recorder.field, recorder.metadata)
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_AggregateMinSuccess(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeeded int
+		wantCode  int32
+	}{
+		{name: "three of five succeed", succeeded: 3, wantCode: 200},
+		{name: "two of five succeed", succeeded: 2, wantCode: 502},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createTestConfig()
+			for i := 0; i < 5; i++ {
+				code := http.StatusServiceUnavailable
+				if i < tt.succeeded {
+					code = http.StatusOK
+				}
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(code)
+				}))
+				defer srv.Close()
+
+				cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+					Name:     fmt.Sprintf("service-%d", i),
+					URL:      srv.URL,
+					Protocol: "http",
+				})
+			}
+
+			tel := createTestTelemetry()
+			caller := client.NewCaller(tel)
+			handler := NewRequestHandler(cfg, caller, tel)
+
+			const behaviorsApplied = "aggregate=min-success:3"
+			calls, err := handler.CallUpstreams(context.Background(), behaviorsApplied, "", cfg.Upstreams)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// Every upstream is called, despite failures
+			if len(calls) != 5 {
+				t.Fatalf("Expected 5 calls, got %d", len(calls))
+			}
+
+			reqCtx := &RequestContext{Ctx: context.Background(), StartTime: time.Now()}
+			resp := handler.BuildSuccessResponse(reqCtx, "http", behaviorsApplied, calls)
+			if failed := handler.CheckUpstreamFailures(calls, behaviorsApplied); failed != nil {
+				resp = handler.BuildUpstreamErrorResponse(reqCtx, "http", failed, behaviorsApplied, calls)
+			}
+			if resp.Code != tt.wantCode {
+				t.Errorf("Expected %d, got %d (%s)", tt.wantCode, resp.Code, resp.Body)
+			}
+			if tt.wantCode == 502 && !strings.Contains(resp.Body, "2 of 5 upstreams succeeded, 3 required") {
+				t.Errorf("Expected aggregate failure body, got %q", resp.Body)
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_FanoutSequential(t *testing.T) {
+	const childLatency = 100 * time.Millisecond
+
+	cfg := createTestConfig()
+	for i := 0; i < 3; i++ {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(childLatency)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
+			Name:     fmt.Sprintf("service-%d", i),
+			URL:      srv.URL,
+			Protocol: "http",
+		})
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	start := time.Now()
+	calls, err := handler.CallUpstreams(context.Background(), "", "", cfg.Upstreams)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("Expected 3 calls, got %d", len(calls))
+	}
+
+	// Sequential fan-out takes ~ sum(child)
+	if elapsed < 3*childLatency {
+		t.Errorf("Expected sequential fan-out to take at least %v, took %v", 3*childLatency, elapsed)
+	}
+}
+
+func TestCallUpstreams_DuplicateInbound(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{
+		{Name: "payment", URL: srv.URL, Protocol: "http"},
+		{Name: "inventory", URL: srv.URL, Protocol: "http"},
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	calls, err := handler.CallUpstreams(context.Background(), "duplicate-inbound=1", "", cfg.Upstreams)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Both upstreams are hit once per processing of the logical request
+	if n := requests.Load(); n != 4 {
+		t.Errorf("Expected 4 upstream requests, got %d", n)
+	}
+	if len(calls) != 4 {
+		t.Fatalf("Expected 4 upstream calls, got %d", len(calls))
+	}
+	for i, name := range []string{"payment", "inventory", "payment", "inventory"} {
+		if calls[i].Name != name {
+			t.Errorf("Expected call %d to %s, got %s", i, name, calls[i].Name)
+		}
+	}
+}
+
+func TestCallUpstreams_UpstreamGrow(t *testing.T) {
+	received := make(chan string, 2)
+	newUpstream := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received <- name + "=" + r.URL.Query().Get("behavior")
+			w.WriteHeader(http.StatusOK)
+		}))
+	}
+	inventory := newUpstream("inventory")
+	defer inventory.Close()
+	payment := newUpstream("payment")
+	defer payment.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{
+		{Name: "inventory", URL: inventory.URL, Protocol: "http"},
+		{Name: "payment", URL: payment.URL, Protocol: "http"},
+	}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	// A zero-length window means the end size is requested straight away
+	if _, err := handler.CallUpstreams(context.Background(), "upstream-grow=inventory:1Ki..64Ki:1ns", "latency=1ms", cfg.Upstreams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	close(received)
+
+	got := map[string]bool{}
+	for r := range received {
+		got[r] = true
+	}
+	if !got["inventory=latency=1ms,body-size=64Ki"] {
+		t.Errorf("Expected inventory to be asked for a 64Ki body, got %v", got)
+	}
+	if !got["payment=latency=1ms"] {
+		t.Errorf("Expected payment to receive the behavior unchanged, got %v", got)
+	}
+}
+
+func TestBuildSuccessResponse_BodySize(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:       context.Background(),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+	}
+
+	resp := handler.BuildSuccessResponse(reqCtx, "http", "body-size=4Ki", nil)
+	if len(resp.Body) != 4096 {
+		t.Errorf("Expected 4096-byte body, got %d", len(resp.Body))
+	}
+}
+
+func TestBuildSuccessResponse_EchoesRequestBody(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:       context.Background(),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+		Body:      []byte(`{"order_id": "o-1"}`),
+	}
+
+	resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil)
+	if resp.Body != `{"order_id": "o-1"}` {
+		t.Errorf("Expected request body to be echoed, got %q", resp.Body)
+	}
+
+	reqCtx.Body = nil
+	if resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil); resp.Body != "All ok" {
+		t.Errorf("Expected default body without a request body, got %q", resp.Body)
+	}
+
+	reqCtx.Body = []byte{0xff, 0xfe, 0x00}
+	if resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil); resp.Body != "All ok" {
+		t.Errorf("Expected default body for a binary request body, got %q", resp.Body)
+	}
+}
+
+func TestBuildSuccessResponse_RequestBodySize(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	body := []byte(`{"order_id": "o-1"}`)
+	reqCtx := &RequestContext{
+		Ctx:       context.Background(),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+		Body:      body,
+		BodySize:  int64(len(body)),
+	}
+
+	// The size is always reported, the digest only with echo=sha256
+	resp := handler.BuildSuccessResponse(reqCtx, "grpc", "", nil)
+	if resp.RequestBodyBytes != int64(len(body)) {
+		t.Errorf("Expected request body size %d, got %d", len(body), resp.RequestBodyBytes)
+	}
+	if resp.RequestBodySha256 != "" {
+		t.Errorf("Expected no digest without echo, got %q", resp.RequestBodySha256)
+	}
+
+	resp = handler.BuildSuccessResponse(reqCtx, "grpc", "echo=sha256", nil)
+	if want := bodySHA256(body); resp.RequestBodySha256 != want {
+		t.Errorf("Expected digest %q of the body, got %q", want, resp.RequestBodySha256)
+	}
+
+	// A truncated body reports the digest computed while reading the whole of it
+	reqCtx.BodySize = 4 << 20
+	reqCtx.BodySHA256 = "abc123"
+	resp = handler.BuildSuccessResponse(reqCtx, "http", "echo=sha256", nil)
+	if resp.RequestBodyBytes != 4<<20 || resp.RequestBodySha256 != "abc123" {
+		t.Errorf("Expected size and digest of the whole body, got %d %q", resp.RequestBodyBytes, resp.RequestBodySha256)
+	}
+}
+
+func TestCallUpstreams_RetryFlakyUpstream(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{{Name: "flaky", URL: srv.URL, Protocol: "http"}}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	caller.SetRetryPolicy(2, 10*time.Millisecond)
+	handler := NewRequestHandler(cfg, caller, tel)
 
+	calls, err := handler.CallUpstreams(context.Background(), "", "", cfg.Upstreams)
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("Expected no error, got %v", err)
 	}
-	if len(calls) != 0 {
-		t.Errorf("Expected 0 calls, got %d", len(calls))
+	if len(calls) != 1 || calls[0].Code != http.StatusOK {
+		t.Fatalf("Expected flaky upstream to succeed on retry, got %+v", calls)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("Expected 2 attempts, got %d", n)
 	}
 }
 
-func TestCallUpstreams_WithMatchedUpstreams(t *testing.T) {
+func TestCallUpstreams_NoRetryOnClientError(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
 	cfg := createTestConfig()
-	cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
-		Name:     "service-b",
-		URL:      "http://localhost:8081",
-		Protocol: "http",
-	})
-	cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{
-		Name:     "service-c",
-		URL:      "http://localhost:8082",
-		Protocol: "http",
-	})
-	
+	cfg.Upstreams = []*service.UpstreamConfig{{Name: "missing", URL: srv.URL, Protocol: "http"}}
+
 	tel := createTestTelemetry()
 	caller := client.NewCaller(tel)
+	caller.SetRetryPolicy(2, 10*time.Millisecond)
 	handler := NewRequestHandler(cfg, caller, tel)
 
-	// Only service-b is matched
-	matchedUpstreams := []*service.UpstreamConfig{
-		cfg.Upstreams[0], // service-b
+	calls, err := handler.CallUpstreams(context.Background(), "", "", cfg.Upstreams)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(calls) != 1 || calls[0].Code != http.StatusNotFound {
+		t.Fatalf("Expected 404 to be returned as-is, got %+v", calls)
 	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("Expected 4xx not to be retried, got %d attempts", n)
+	}
+}
 
-	calls, err := handler.CallUpstreams(context.Background(), "", "", matchedUpstreams)
+func TestCallUpstreams_PropagatesBaggage(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.Baggage{})
+	defer otel.SetTextMapPropagator(prev)
+
+	var received atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Store(baggage.FromContext(otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
 
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{{Name: "orders", URL: srv.URL, Protocol: "http"}}
+
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	// Baggage received by this service flows on, with the behavior's members added
+	incoming, err := baggage.Parse("region=eu")
 	if err != nil {
-		t.Errorf("Expected no error, got %v", err)
+		t.Fatalf("baggage.Parse() failed: %v", err)
 	}
-	// Should only call matched upstream (service-b), even though service-c is configured
-	// However, both will fail to connect, so we expect 1 call with error
-	if len(calls) != 1 {
-		t.Errorf("Expected 1 call, got %d", len(calls))
+	ctx := baggage.ContextWithBaggage(context.Background(), incoming)
+
+	if _, err := handler.CallUpstreams(ctx, "baggage=tenant=acme", "", cfg.Upstreams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	bag, _ := received.Load().(baggage.Baggage)
+	if got := bag.Member("tenant").Value(); got != "acme" {
+		t.Errorf("Expected upstream to receive tenant=acme, got baggage %q", bag.String())
+	}
+	if got := bag.Member("region").Value(); got != "eu" {
+		t.Errorf("Expected upstream to receive region=eu, got baggage %q", bag.String())
+	}
+}
+
+func TestBuildSuccessResponse_Baggage(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	bag, err := baggage.Parse("tenant=acme,user=42")
+	if err != nil {
+		t.Fatalf("baggage.Parse() failed: %v", err)
+	}
+	reqCtx := &RequestContext{
+		Ctx:       baggage.ContextWithBaggage(context.Background(), bag),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+	}
+
+	resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil)
+	if len(resp.Baggage) != 2 || resp.Baggage["tenant"] != "acme" || resp.Baggage["user"] != "42" {
+		t.Errorf("Expected received baggage in the response, got %v", resp.Baggage)
+	}
+
+	reqCtx.Ctx = context.Background()
+	if resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil); resp.Baggage != nil {
+		t.Errorf("Expected no baggage without received baggage, got %v", resp.Baggage)
 	}
 }
 
@@ -337,7 +1530,7 @@ func TestCheckUpstreamFailures(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			failed := handler.CheckUpstreamFailures(tt.calls)
+			failed := handler.CheckUpstreamFailures(tt.calls, "")
 			if (failed != nil) != tt.expected {
 				t.Errorf("Expected failure=%v, got %v", tt.expected, failed != nil)
 			}
@@ -374,6 +1567,85 @@ func TestBuildSuccessResponse(t *testing.T) {
 	}
 }
 
+func TestBuildSuccessResponse_VersionMix(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:       context.Background(),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+	}
+
+	const iterations = 5000
+	counts := map[string]int{}
+	for i := 0; i < iterations; i++ {
+		resp := handler.BuildSuccessResponse(reqCtx, "http", "version-mix=1.0.0:0.7|2.0.0:0.3", nil)
+		counts[resp.Service.Version]++
+	}
+
+	if len(counts) != 2 {
+		t.Fatalf("Expected exactly 2 reported versions, got %v", counts)
+	}
+	for version, expected := range map[string]float64{"1.0.0": 0.7, "2.0.0": 0.3} {
+		actual := float64(counts[version]) / iterations
+		if actual < expected-0.05 || actual > expected+0.05 {
+			t.Errorf("Version %s reported %.2f of the time, expected ~%.2f", version, actual, expected)
+		}
+	}
+
+	// Without version-mix the configured version is reported
+	resp := handler.BuildSuccessResponse(reqCtx, "http", "", nil)
+	if resp.Service.Version != cfg.Version {
+		t.Errorf("Expected version %s, got %s", cfg.Version, resp.Service.Version)
+	}
+}
+
+func TestBuildSuccessResponse_ReplicaLag(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:       context.Background(),
+		StartTime: time.Now(),
+		TraceID:   "trace123",
+		SpanID:    "span456",
+	}
+
+	resp := handler.BuildSuccessResponse(reqCtx, "http", "replica-lag=5s", nil)
+	if resp.ReplicaLagSeconds != 5 {
+		t.Errorf("Expected replica lag 5s, got %v", resp.ReplicaLagSeconds)
+	}
+	if !resp.Stale {
+		t.Error("Expected 5s lag to be flagged stale with the default threshold")
+	}
+	asOf, err := time.Parse(time.RFC3339Nano, resp.DataAsOf)
+	if err != nil {
+		t.Fatalf("Expected RFC3339 data_as_of, got %q: %v", resp.DataAsOf, err)
+	}
+	end, _ := time.Parse(time.RFC3339Nano, resp.EndTime)
+	if got := end.Sub(asOf); got != 5*time.Second {
+		t.Errorf("Expected data as of 5s before the response, got %v", got)
+	}
+
+	// Lag within the threshold is reported but not stale
+	resp = handler.BuildSuccessResponse(reqCtx, "http", "replica-lag=500ms:1s", nil)
+	if resp.ReplicaLagSeconds != 0.5 || resp.Stale {
+		t.Errorf("Expected 0.5s lag not stale, got %v stale=%v", resp.ReplicaLagSeconds, resp.Stale)
+	}
+
+	// Without replica-lag no replica metadata is reported
+	resp = handler.BuildSuccessResponse(reqCtx, "http", "", nil)
+	if resp.ReplicaLagSeconds != 0 || resp.DataAsOf != "" || resp.Stale {
+		t.Errorf("Expected no replica metadata, got %+v", resp)
+	}
+}
+
 func TestBuildUpstreamErrorResponse(t *testing.T) {
 	cfg := createTestConfig()
 	tel := createTestTelemetry()
@@ -401,3 +1673,319 @@ func TestBuildUpstreamErrorResponse(t *testing.T) {
 		t.Error("Expected error message in body")
 	}
 }
+
+func TestProcessRequest_SpanEvents(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	tests := []struct {
+		name        string
+		behaviorStr string
+		wantTypes   []string
+	}{
+		{name: "no behavior", behaviorStr: "", wantTypes: nil},
+		{name: "latency", behaviorStr: "latency=5ms", wantTypes: []string{"latency"}},
+		{name: "early exit", behaviorStr: "latency=5ms,error=503", wantTypes: []string{"latency", "error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			ctx, span := provider.Tracer("test").Start(context.Background(), "server")
+
+			reqCtx := &RequestContext{
+				Ctx:         ctx,
+				StartTime:   time.Now(),
+				TraceID:     "trace123",
+				SpanID:      "span456",
+				BehaviorStr: tt.behaviorStr,
+			}
+			if _, err := handler.ProcessRequest(reqCtx, "http"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			span.End()
+
+			ended := recorder.Ended()
+			if len(ended) != 1 {
+				t.Fatalf("Expected 1 span, got %d", len(ended))
+			}
+
+			var gotTypes []string
+			for _, event := range ended[0].Events() {
+				if event.Name != "behavior.applied" {
+					continue
+				}
+				for _, attr := range event.Attributes {
+					if attr.Key == "behavior.type" {
+						gotTypes = append(gotTypes, attr.Value.AsString())
+					}
+				}
+			}
+			if strings.Join(gotTypes, ",") != strings.Join(tt.wantTypes, ",") {
+				t.Errorf("Expected behavior.applied events %v, got %v", tt.wantTypes, gotTypes)
+			}
+
+			var applied string
+			for _, attr := range ended[0].Attributes() {
+				if attr.Key == "testservice.behaviors_applied" {
+					applied = attr.Value.AsString()
+				}
+			}
+			if tt.behaviorStr == "" && applied != "" {
+				t.Errorf("Expected no behaviors_applied attribute, got %q", applied)
+			}
+			if tt.behaviorStr != "" && applied == "" {
+				t.Error("Expected a behaviors_applied attribute")
+			}
+		})
+	}
+}
+
+func TestCallUpstreams_ServiceGraph(t *testing.T) {
+	cfg := createTestConfig()
+	for name, code := range map[string]int{"service-b": http.StatusOK, "service-c": http.StatusServiceUnavailable} {
+		code := code
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		defer srv.Close()
+		cfg.Upstreams = append(cfg.Upstreams, &service.UpstreamConfig{Name: name, URL: srv.URL, Protocol: "http"})
+	}
+
+	tel := createTestTelemetry()
+	tel.Metrics.ServiceGraphTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{Name: "test_service_graph_total"},
+		[]string{"source", "destination", "protocol", "status"},
+	)
+	handler := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+	if _, err := handler.CallUpstreams(context.Background(), "fanout=parallel", "", cfg.Upstreams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	edges := map[[2]string]float64{
+		{"service-b", "200"}: 1,
+		{"service-c", "503"}: 1,
+		{"service-b", "503"}: 0,
+	}
+	for edge, want := range edges {
+		got := testutil.ToFloat64(tel.Metrics.ServiceGraphTotal.WithLabelValues("test-service", edge[0], "http", edge[1]))
+		if got != want {
+			t.Errorf("Expected %v calls test-service -> %s (%s), got %v", want, edge[0], edge[1], got)
+		}
+	}
+}
+
+func TestProcessRequest_Concurrency(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	newReqCtx := func() *RequestContext {
+		return &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: "concurrency=1",
+		}
+	}
+
+	// The servers count each request in flight for its whole lifecycle
+	done := service.InFlight.Begin()
+	result, err := handler.ProcessRequest(newReqCtx(), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatal("Expected the only request in flight to proceed")
+	}
+	if !strings.Contains(result.BehaviorsApplied, "in-flight:1") {
+		t.Errorf("Expected in-flight count in applied behaviors, got %q", result.BehaviorsApplied)
+	}
+
+	// A second concurrent request is over the limit
+	doneSecond := service.InFlight.Begin()
+	result, err = handler.ProcessRequest(newReqCtx(), "http")
+	doneSecond()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 429 {
+		t.Fatalf("Expected 429 over the concurrency limit, got %+v", result)
+	}
+	if !strings.Contains(result.BehaviorsApplied, "in-flight:2") {
+		t.Errorf("Expected in-flight count in applied behaviors, got %q", result.BehaviorsApplied)
+	}
+	done()
+}
+
+func TestProcessRequest_FDLeakReport(t *testing.T) {
+	cfg := createTestConfig()
+	tel := createTestTelemetry()
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	reqCtx := &RequestContext{
+		Ctx:         context.Background(),
+		StartTime:   time.Now(),
+		TraceID:     "trace123",
+		SpanID:      "span456",
+		BehaviorStr: "fd-leak=5:10ms",
+	}
+
+	result, err := handler.ProcessRequest(reqCtx, "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := "fd-leak=5:10ms,fd-leak:5"; result.BehaviorsApplied != want {
+		t.Errorf("Expected applied behaviors %q, got %q", want, result.BehaviorsApplied)
+	}
+
+	// The report has no '=', so the applied behaviors still parse to the same behavior
+	b, err := behavior.Parse(result.BehaviorsApplied)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if b.String() != "fd-leak=5:10ms" {
+		t.Errorf("Expected the report to be skipped when parsing, got %s", b)
+	}
+}
+
+func TestProcessRequest_Queue(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Name = "queue-test-service"
+	tel := createTestTelemetry()
+	tel.Metrics.QueueWaitSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "test_queue_wait_seconds"},
+		[]string{"service"},
+	)
+	caller := client.NewCaller(tel)
+	handler := NewRequestHandler(cfg, caller, tel)
+
+	newReqCtx := func() *RequestContext {
+		return &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: "queue=1:20ms",
+		}
+	}
+
+	// The admitted request holds its slot until Done
+	first, err := handler.ProcessRequest(newReqCtx(), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first.EarlyExit {
+		t.Fatal("Expected the first request to be admitted")
+	}
+
+	result, err := handler.ProcessRequest(newReqCtx(), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.EarlyExit || result.Response.Code != 503 {
+		t.Fatalf("Expected 503 after waiting for a queue slot, got %+v", result)
+	}
+
+	first.Done()
+	result, err = handler.ProcessRequest(newReqCtx(), "http")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.EarlyExit {
+		t.Fatal("Expected a request to be admitted once the slot is released")
+	}
+	result.Done()
+
+	// Every request's wait is recorded, including the rejected one's
+	count, sum := histogramSample(t, tel.Metrics.QueueWaitSeconds.WithLabelValues(tel.ServiceName))
+	if count != 3 {
+		t.Errorf("Expected 3 queue waits recorded, got %d", count)
+	}
+	if sum < 0.02 {
+		t.Errorf("Expected the rejected request's 20ms wait to be recorded, got %vs in total", sum)
+	}
+}
+
+func TestProcessRequest_LockWait(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Name = "lock-test-service"
+	tel := createTestTelemetry()
+	tel.Metrics.LockWaitSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{Name: "test_lock_wait_seconds"},
+		[]string{"service"},
+	)
+	handler := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+	for _, behaviorStr := range []string{"lock=contend:1:hold:10ms", "lock=contend:1:hold:10ms", "latency=1ms"} {
+		reqCtx := &RequestContext{
+			Ctx:         context.Background(),
+			StartTime:   time.Now(),
+			TraceID:     "trace123",
+			SpanID:      "span456",
+			BehaviorStr: behaviorStr,
+		}
+		result, err := handler.ProcessRequest(reqCtx, "http")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		result.Done()
+	}
+
+	// Only the requests with a lock behavior are recorded
+	if count, _ := histogramSample(t, tel.Metrics.LockWaitSeconds.WithLabelValues(tel.ServiceName)); count != 2 {
+		t.Errorf("Expected 2 lock waits recorded, got %d", count)
+	}
+}
+
+// histogramSample returns the observation count and sum of a histogram
+func histogramSample(t *testing.T, o prometheus.Observer) (uint64, float64) {
+	t.Helper()
+	var m dto.Metric
+	if err := o.(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+}
+
+func TestApplyWeightedSelectionForGRPC_SeededGroups(t *testing.T) {
+	cfg := createTestConfig()
+	cfg.Upstreams = []*service.UpstreamConfig{
+		{Name: "v1", URL: "grpc://v1:9090", Group: "api"},
+		{Name: "v2", URL: "grpc://v2:9090", Group: "api"},
+		{Name: "v3", URL: "grpc://v3:9090", Group: "api"},
+		{Name: "primary", URL: "grpc://primary:9090", Group: "db"},
+		{Name: "replica", URL: "grpc://replica:9090", Group: "db"},
+		{Name: "standby", URL: "grpc://standby:9090", Group: "db"},
+	}
+	tel := createTestTelemetry()
+	handler := NewRequestHandler(cfg, client.NewCaller(tel), tel)
+
+	// RAND_SEED seeds the process-wide source both groups draw from
+	run := func() string {
+		service.Random.Seed(42)
+		var picks []string
+		for i := 0; i < 20; i++ {
+			var names []string
+			for _, u := range handler.applyWeightedSelectionForGRPC("") {
+				names = append(names, u.Name)
+			}
+			picks = append(picks, strings.Join(names, "+"))
+		}
+		return strings.Join(picks, ",")
+	}
+
+	want := run()
+	for i := 0; i < 10; i++ {
+		if got := run(); got != want {
+			t.Fatalf("Expected the same seed to select %s every run, got %s on run %d", want, got, i)
+		}
+	}
+}
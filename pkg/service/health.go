@@ -0,0 +1,70 @@
+package service
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ProbeState holds the runtime state of a probe endpoint that behaviors can flip
+type ProbeState struct {
+	unhealthyUntil atomic.Int64 // Unix nanoseconds; 0 means healthy
+}
+
+// Readiness is the state consulted by the /ready endpoint
+var Readiness = &ProbeState{}
+
+// Liveness is the state consulted by the /health endpoint
+var Liveness = &ProbeState{}
+
+// SetUnhealthy marks the probe as failing for the given duration
+func (p *ProbeState) SetUnhealthy(d time.Duration) {
+	p.unhealthyUntil.Store(time.Now().Add(d).UnixNano())
+}
+
+// SetHealthy restores the probe immediately
+func (p *ProbeState) SetHealthy() {
+	p.unhealthyUntil.Store(0)
+}
+
+// Healthy reports whether the probe should currently succeed
+func (p *ProbeState) Healthy() bool {
+	until := p.unhealthyUntil.Load()
+	return until == 0 || time.Now().UnixNano() >= until
+}
+
+// HealthHandler serves the liveness endpoint, returning 500 while Liveness is unhealthy
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	if !Liveness.Healthy() {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Unhealthy"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// ReadyHandler serves the readiness endpoint, returning 503 while Readiness is unhealthy,
+// while draining for shutdown or, when dependency-aware readiness is enabled, while any
+// upstream is unreachable
+func ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	if Drain.Draining() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("Not Ready: shutting down"))
+		return
+	}
+	if !Readiness.Healthy() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("Not Ready"))
+		return
+	}
+	if UpstreamReadiness.Enabled() {
+		if err := UpstreamReadiness.Check(r.Context()); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("Not Ready: " + err.Error()))
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProbeState(t *testing.T) {
+	p := &ProbeState{}
+	if !p.Healthy() {
+		t.Error("expected new probe state to be healthy")
+	}
+
+	p.SetUnhealthy(50 * time.Millisecond)
+	if p.Healthy() {
+		t.Error("expected probe to be unhealthy")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !p.Healthy() {
+		t.Error("expected probe to recover after duration")
+	}
+
+	p.SetUnhealthy(time.Minute)
+	p.SetHealthy()
+	if !p.Healthy() {
+		t.Error("expected SetHealthy to restore immediately")
+	}
+}
+
+func TestReadyHandler(t *testing.T) {
+	defer Readiness.SetHealthy()
+
+	serve := func(h http.HandlerFunc) int {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	if code := serve(ReadyHandler); code != http.StatusOK {
+		t.Errorf("expected 200 when ready, got %d", code)
+	}
+
+	Readiness.SetUnhealthy(time.Minute)
+	if code := serve(ReadyHandler); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 when not ready, got %d", code)
+	}
+
+	// Liveness is unaffected by readiness
+	if code := serve(HealthHandler); code != http.StatusOK {
+		t.Errorf("expected /health to stay 200 while not ready, got %d", code)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	defer Liveness.SetHealthy()
+
+	serve := func(h http.HandlerFunc) int {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		return rec.Code
+	}
+
+	if code := serve(HealthHandler); code != http.StatusOK {
+		t.Errorf("expected 200 when live, got %d", code)
+	}
+
+	Liveness.SetUnhealthy(time.Minute)
+	if code := serve(HealthHandler); code != http.StatusInternalServerError {
+		t.Errorf("expected 500 when not live, got %d", code)
+	}
+
+	Liveness.SetHealthy()
+	if code := serve(HealthHandler); code != http.StatusOK {
+		t.Errorf("expected 200 after reset, got %d", code)
+	}
+}
+
+func TestReadyHandler_FromUpstreams(t *testing.T) {
+	defer UpstreamReadiness.SetEnabled(false)
+	defer UpstreamReadiness.SetUpstreams(nil)
+
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return rec.Code
+	}
+
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer up.Close()
+
+	// Grab a free port and release it so nothing is listening there
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	downURL := down.URL
+	down.Close()
+
+	UpstreamReadiness.SetEnabled(true)
+
+	UpstreamReadiness.SetUpstreams([]*UpstreamConfig{{Name: "up", URL: up.URL, Protocol: "http"}})
+	if code := serve(); code != http.StatusOK {
+		t.Errorf("expected 200 with all upstreams reachable, got %d", code)
+	}
+
+	UpstreamReadiness.SetUpstreams([]*UpstreamConfig{
+		{Name: "up", URL: up.URL, Protocol: "http"},
+		{Name: "down", URL: downURL, Protocol: "http"},
+	})
+	if code := serve(); code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 with an unreachable upstream, got %d", code)
+	}
+
+	// Disabled, upstream reachability is ignored
+	UpstreamReadiness.SetEnabled(false)
+	if code := serve(); code != http.StatusOK {
+		t.Errorf("expected 200 when ready-from-upstreams is disabled, got %d", code)
+	}
+}
+
+func TestUpstreamAddress(t *testing.T) {
+	tests := map[string]string{
+		"http://svc:8080":        "svc:8080",
+		"http://svc:8080/orders": "svc:8080",
+		"grpc://svc.ns.svc:9090": "svc.ns.svc:9090",
+		"http://svc":             "svc:80",
+		"https://svc/path":       "svc:443",
+		"svc:8080":               "svc:8080",
+	}
+	for in, want := range tests {
+		if got := upstreamAddress(in); got != want {
+			t.Errorf("upstreamAddress(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestReadyHandler_Draining(t *testing.T) {
+	defer Drain.draining.Store(false)
+
+	rec := httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 before draining, got %d", rec.Code)
+	}
+
+	Drain.Begin()
+	rec = httptest.NewRecorder()
+	ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 while draining, got %d", rec.Code)
+	}
+
+	// Liveness is unaffected, the process is still serving
+	rec = httptest.NewRecorder()
+	HealthHandler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected /health to stay 200 while draining, got %d", rec.Code)
+	}
+}
+
+func TestDrainState_Delay(t *testing.T) {
+	d := &DrainState{}
+	if d.Delay() != 0 || d.Draining() {
+		t.Error("expected new drain state to have no delay and not be draining")
+	}
+
+	d.SetDelay(10 * time.Second)
+	if d.Delay() != 10*time.Second {
+		t.Errorf("expected delay 10s, got %v", d.Delay())
+	}
+
+	d.SetDelay(-time.Second)
+	if d.Delay() != 0 {
+		t.Errorf("expected negative delay to clamp to 0, got %v", d.Delay())
+	}
+}
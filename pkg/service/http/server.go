@@ -1,7 +1,6 @@
 package http
 
 import (
-	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -16,7 +15,9 @@ import (
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/router"
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
 	pb "github.com/aslakknutsen/kkbase/testapp/proto/testservice"
+	"github.com/gorilla/websocket"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -25,6 +26,14 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
+// maxRequestBodyBytes bounds how much of a request body is read for body-based behaviors
+const maxRequestBodyBytes = 1 << 20
+
+// wsUpgrader upgrades /ws requests; any origin is accepted, as for the rest of the API
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
 // Server handles HTTP requests
 type Server struct {
 	config    *service.Config
@@ -37,6 +46,8 @@ type Server struct {
 // NewServer creates a new HTTP server
 func NewServer(cfg *service.Config, tel *telemetry.Telemetry) *Server {
 	caller := client.NewCaller(tel)
+	caller.SetRetryPolicy(cfg.UpstreamRetries, cfg.UpstreamRetryBackoff)
+	caller.SetBehaviorPropagation(cfg.BehaviorPropagation)
 	return &Server{
 		config:    cfg,
 		telemetry: tel,
@@ -46,6 +57,11 @@ func NewServer(cfg *service.Config, tel *telemetry.Telemetry) *Server {
 	}
 }
 
+// Close releases upstream connections held by the server's caller
+func (s *Server) Close() error {
+	return s.caller.Close()
+}
+
 // ServeHTTP handles HTTP requests
 func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	start := time.Now()
@@ -74,6 +90,7 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Track active requests
 	s.telemetry.IncActiveRequests(r.Method, r.URL.Path)
 	defer s.telemetry.DecActiveRequests(r.Method, r.URL.Path)
+	defer service.InFlight.Begin()()
 
 	// Get trace IDs
 	var traceID, spanID string
@@ -83,9 +100,12 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Parse behavior from query parameters or headers
-	behaviorStr := r.URL.Query().Get("behavior")
-	if behaviorStr == "" {
-		behaviorStr = r.Header.Get("X-Behavior")
+	behaviorStr := s.requestBehavior(r)
+
+	// TLS SNI of the connection, compared with the Host by sni-mismatch
+	var serverName string
+	if r.TLS != nil {
+		serverName = r.TLS.ServerName
 	}
 
 	// Build request context
@@ -95,6 +115,31 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		TraceID:     traceID,
 		SpanID:      spanID,
 		BehaviorStr: behaviorStr,
+		Path:        r.URL.Path,
+		Headers:     r.Header,
+		Host:        r.Host,
+		ServerName:  serverName,
+	}
+
+	// Behaviors shaping how the body is read
+	b := s.handler.ResolveBehavior(reqCtx)
+
+	// expect-100=ignore leaves the body unread: the first read is what makes net/http send 100 Continue
+	suppressContinue := false
+	if b != nil && r.Header.Get("Expect") != "" {
+		suppressContinue = b.SuppressesContinue(r)
+	}
+
+	// Read (bounded) request body for body-based behaviors, slowly under slow-consume.
+	// The rest is drained so the reported size and digest cover the whole body.
+	if r.Body != nil && !suppressContinue {
+		body, size, sum, err := handler.ReadBody(b.SlowBody(ctx, r.Body), maxRequestBodyBytes, b.EchoesDigest())
+		if err != nil {
+			s.telemetry.Logger.Warn("Failed to read request body", zap.Error(err))
+		}
+		reqCtx.Body = body
+		reqCtx.BodySize = size
+		reqCtx.BodySHA256 = sum
 	}
 
 	// Process request with handler (behavior execution)
@@ -105,9 +150,24 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
+	defer processResult.Done()
 
 	// If early exit (behavior triggered error), send response
 	if processResult.EarlyExit {
+		// Connection-level failures abort the connection; the response is the fallback
+		if processResult.ResetConnection {
+			if resetConnection(w, processResult.CloseGracefully) {
+				span.SetStatus(codes.Error, "connection reset")
+				s.telemetry.RecordRequest(r.Method, r.URL.Path, 0, time.Since(start), traceID)
+				return
+			}
+			s.telemetry.Logger.Warn("Connection cannot be hijacked, sending error response instead of reset")
+		}
+
+		if processResult.Location != "" {
+			w.Header().Set("Location", processResult.Location)
+		}
+
 		statusCode := int(processResult.Response.Code)
 		processResult.Response.Url = r.URL.RequestURI()
 		s.sendResponse(w, r, processResult.Response, statusCode, span, start)
@@ -121,16 +181,21 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var resp *pb.ServiceResponse
 	var upstreamCalls []*pb.UpstreamCall
 	if s.router.HasUpstreams() {
-		// Extract upstream weights from effective behavior (includes defaults)
+		// Extract upstream weights, route pins and the random source (seed=) from effective
+		// behavior (includes defaults and canary shift)
 		var upstreamWeights map[string]int
+		var routePins map[string]string
+		var rnd *service.Rand
 		if behaviorsApplied != "" {
-			if b, err := behavior.Parse(behaviorsApplied); err == nil && b.UpstreamWeights != nil {
-				upstreamWeights = b.UpstreamWeights.Weights
+			if b, err := behavior.Parse(behaviorsApplied); err == nil {
+				upstreamWeights = b.UpstreamWeightMap()
+				routePins = b.RoutePins()
+				rnd = b.Random()
 			}
 		}
 
-		// Match upstreams based on request path with weighted selection for groups
-		matchedUpstreams := s.router.MatchWithWeights(r.URL.Path, upstreamWeights)
+		// Match upstreams based on request path and headers with weighted selection for groups
+		matchedUpstreams := s.router.MatchWithWeights(r.URL.Path, r.Header, upstreamWeights, routePins, rnd)
 
 		// If upstreams are configured but none match, return 404
 		if matchedUpstreams == nil {
@@ -146,10 +211,16 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		// Call matched upstreams - propagate original external behavior only (not defaults)
 		// Each downstream service will apply its own defaults if no behavior targets it
-		upstreamCalls = s.callMatchedUpstreams(ctx, matchedUpstreams, r.URL.Path, behaviorStr)
+		upstreamCalls, err = s.handler.CallUpstreams(ctx, behaviorsApplied, s.handler.PropagatedBehavior(reqCtx), matchedUpstreams)
+		if err != nil {
+			s.telemetry.Logger.Error("Failed to call upstreams", zap.Error(err))
+			span.RecordError(err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
 
 		// Check if any upstream returned non-2xx (excluding connection errors where Code=0)
-		if failedCall := s.handler.CheckUpstreamFailures(upstreamCalls); failedCall != nil {
+		if failedCall := s.handler.CheckUpstreamFailures(upstreamCalls, behaviorsApplied); failedCall != nil {
 			resp = s.handler.BuildUpstreamErrorResponse(reqCtx, "http", failedCall, behaviorsApplied, upstreamCalls)
 			resp.Url = r.URL.RequestURI()
 			s.sendResponse(w, r, resp, 502, span, start)
@@ -163,45 +234,106 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	s.sendResponse(w, r, resp, 200, span, start)
 }
 
-// callMatchedUpstreams calls the matched upstreams with explicit forward paths (fail-fast)
-func (s *Server) callMatchedUpstreams(ctx context.Context, upstreams []*service.UpstreamConfig, requestPath string, behaviorStr string) []*pb.UpstreamCall {
-	var calls []*pb.UpstreamCall
-
-	for _, upstream := range upstreams {
-		// Get the explicit forward path (or "/" if not set)
-		forwardPath := s.router.GetForwardPath(upstream)
-
-		// Update upstream URL to include the path
-		upstreamWithPath := &service.UpstreamConfig{
-			Name:     upstream.Name,
-			URL:      upstream.URL + forwardPath,
-			Protocol: upstream.Protocol,
-			Match:    upstream.Match,
-			Path:     upstream.Path,
-		}
+// ServeWebSocket upgrades the connection and echoes each message back. The request's
+// latency and error behaviors apply per message, and ws-close-after closes the socket
+// after a number of messages.
+func (s *Server) ServeWebSocket(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	ctx, span := s.telemetry.StartServerSpan(ctx, fmt.Sprintf("%s %s", r.Method, r.URL.Path),
+		semconv.HTTPRequestMethodOriginal(r.Method),
+		semconv.URLPath(r.URL.Path),
+		semconv.NetworkProtocolName("websocket"),
+		semconv.ClientAddress(extractClientIP(r)),
+	)
+	defer span.End()
 
-		// Use shared caller with behavior propagation
-		result := s.caller.Call(ctx, upstream.Name, upstreamWithPath, behaviorStr)
+	var traceID string
+	if spanCtx := span.SpanContext(); spanCtx.IsValid() {
+		traceID = spanCtx.TraceID().String()
+	}
 
-		// Convert to pb.UpstreamCall using handler's method
-		call := s.handler.ResultToUpstreamCall(result)
-		s.telemetry.RecordUpstreamCall("GET", upstream.Name, int(call.Code), result.Duration)
+	behaviorStr := s.requestBehavior(r)
+	b := s.handler.ResolveBehavior(&handler.RequestContext{
+		Ctx:         ctx,
+		StartTime:   start,
+		TraceID:     traceID,
+		BehaviorStr: behaviorStr,
+		Path:        r.URL.Path,
+		Headers:     r.Header,
+		Host:        r.Host,
+	})
+
+	// Upgrade writes the HTTP error response itself on failure
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		s.telemetry.Logger.Warn("WebSocket upgrade failed", zap.Error(err))
+		span.SetStatus(codes.Error, "upgrade failed")
+		s.telemetry.RecordRequest(r.Method, r.URL.Path, http.StatusBadRequest, time.Since(start), traceID)
+		return
+	}
+	defer conn.Close()
+
+	s.telemetry.IncActiveRequests(r.Method, r.URL.Path)
+	defer s.telemetry.DecActiveRequests(r.Method, r.URL.Path)
+
+	closeAfter := b.CloseAfterMessages()
+	messages := 0
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				s.telemetry.Logger.Debug("WebSocket read ended", zap.Error(err))
+			}
+			break
+		}
+		messages++
 
-		calls = append(calls, call)
+		code, err := b.ApplyToMessage(ctx, len(data))
+		if err != nil {
+			break
+		}
+		if code != 0 {
+			s.telemetry.RecordBehavior("error")
+			msgType = websocket.TextMessage
+			data = []byte(fmt.Sprintf(`{"code":%d,"error":"injected error"}`, code))
+		}
+		if err := conn.WriteMessage(msgType, data); err != nil {
+			span.RecordError(err)
+			break
+		}
 
-		// Fail-fast: stop on first failure (non-2xx response or error)
-		if call.Code >= 300 || call.Error != "" {
+		if closeAfter > 0 && messages >= closeAfter {
+			s.telemetry.RecordBehavior("ws-close-after")
+			reason := websocket.FormatCloseMessage(websocket.CloseNormalClosure, fmt.Sprintf("closing after %d messages", messages))
+			conn.WriteControl(websocket.CloseMessage, reason, time.Now().Add(time.Second))
 			break
 		}
 	}
 
-	return calls
+	duration := time.Since(start)
+	s.telemetry.RecordRequest(r.Method, r.URL.Path, http.StatusSwitchingProtocols, duration, traceID)
+	span.SetAttributes(semconv.HTTPResponseStatusCode(http.StatusSwitchingProtocols))
+	s.telemetry.Logger.Info("websocket_closed",
+		zap.Int("messages", messages),
+		zap.Duration("duration", duration),
+		zap.String("trace_id", traceID),
+	)
+}
+
+// requestBehavior returns the request's behavior string, logging an invalid JSON specification
+func (s *Server) requestBehavior(r *http.Request) string {
+	behaviorStr, err := handler.RequestBehavior(r)
+	if err != nil {
+		s.telemetry.Logger.Warn("Failed to parse behavior JSON", zap.Error(err))
+	}
+	return behaviorStr
 }
 
 // sendResponse sends the JSON response using protojson
 func (s *Server) sendResponse(w http.ResponseWriter, r *http.Request, resp *pb.ServiceResponse, statusCode int, span trace.Span, start time.Time) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
 
 	// Use protojson for marshaling with proper options
 	marshaler := protojson.MarshalOptions{
@@ -211,27 +343,95 @@ func (s *Server) sendResponse(w http.ResponseWriter, r *http.Request, resp *pb.S
 
 	jsonBytes, err := marshaler.Marshal(resp)
 	if err != nil {
+		w.WriteHeader(statusCode)
 		s.telemetry.Logger.Error("Failed to encode response", zap.Error(err))
 		span.RecordError(err)
 		return
 	}
 
-	if _, err := w.Write(jsonBytes); err != nil {
-		s.telemetry.Logger.Error("Failed to write response", zap.Error(err))
+	// Behaviors shaping the response itself
+	var b *behavior.Behavior
+	if resp.BehaviorsApplied != "" {
+		if parsed, err := behavior.Parse(resp.BehaviorsApplied); err == nil {
+			b = parsed
+		}
+	}
+
+	// Per-request log level: the loglevel behavior, or the X-Log-Level header
+	level := r.Header.Get("X-Log-Level")
+	if override := b.LogLevelOverride(); override != "" {
+		level = override
+	}
+	logger := s.telemetry.RequestLogger(level)
+
+	// A truncated response declares the full length, so the client sees an unexpected EOF
+	sendBytes, truncated := b.TruncateLength(len(jsonBytes))
+	if b.StreamsNDJSON() {
+		truncated = false
+	}
+	if truncated {
+		w.Header().Set("Content-Length", strconv.Itoa(len(jsonBytes)))
+	}
+
+	if b != nil {
+		// Simulate serialization/transfer cost now that the body size is known
+		if err := b.ApplyOutputLatency(r.Context(), len(jsonBytes)); err != nil {
+			span.RecordError(err)
+		}
+		// Trailer names must be announced before the header is written
+		b.DeclareTrailers(w.Header())
+		if b.StreamsNDJSON() {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+	}
+
+	w.WriteHeader(statusCode)
+
+	if b.StreamsNDJSON() {
+		if err := b.StreamNDJSON(r.Context(), w, jsonBytes); err != nil {
+			logger.Warn("NDJSON stream ended early", zap.Error(err))
+			span.RecordError(err)
+		}
+	} else if _, err := w.Write(jsonBytes[:sendBytes]); err != nil {
+		logger.Error("Failed to write response", zap.Error(err))
 		span.RecordError(err)
 	}
 
+	if truncated {
+		_ = http.NewResponseController(w).Flush()
+		span.AddEvent("response.truncated", trace.WithAttributes(
+			attribute.Int("bytes_sent", sendBytes),
+			attribute.Int("bytes_declared", len(jsonBytes)),
+		))
+	} else if b != nil {
+		b.SetTrailers(w.Header())
+	}
+
 	// Record metrics
 	duration := time.Since(start)
-	s.telemetry.RecordRequest(r.Method, r.URL.Path, statusCode, duration)
+	s.telemetry.RecordRequest(r.Method, r.URL.Path, statusCode, duration, resp.TraceId)
 
 	// Log request
-	s.telemetry.Logger.Info("request_completed",
+	logger.Info("request_completed",
 		zap.Int("status", statusCode),
 		zap.Duration("duration", duration),
 		zap.String("trace_id", resp.TraceId),
 		zap.Int("upstream_calls", len(resp.UpstreamCalls)),
 	)
+	if ce := logger.Check(zap.DebugLevel, "request_details"); ce != nil {
+		upstreams := make([]string, 0, len(resp.UpstreamCalls))
+		for _, call := range resp.UpstreamCalls {
+			upstreams = append(upstreams, fmt.Sprintf("%s=%d", call.Name, call.Code))
+		}
+		ce.Write(
+			zap.String("trace_id", resp.TraceId),
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.RequestURI()),
+			zap.String("behaviors_applied", resp.BehaviorsApplied),
+			zap.Strings("upstreams", upstreams),
+			zap.String("body", resp.Body),
+		)
+	}
 
 	// Set status code and error attributes
 	span.SetAttributes(semconv.HTTPResponseStatusCode(statusCode))
@@ -241,6 +441,52 @@ func (s *Server) sendResponse(w http.ResponseWriter, r *http.Request, resp *pb.S
 	} else {
 		span.SetStatus(codes.Ok, "")
 	}
+
+	// Close the connection mid-response; HTTP/2 connections can't be hijacked, so
+	// abort the handler instead, which resets the stream
+	if truncated && !resetConnection(w, true) {
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// resetConnection aborts the client connection with a TCP RST instead of responding,
+// or closes it cleanly with a FIN if graceful. Returns false if the connection can't
+// be hijacked (e.g. HTTP/2).
+func resetConnection(w http.ResponseWriter, graceful bool) bool {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		return false
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return false
+	}
+
+	if !graceful {
+		if tcp, ok := tcpConn(conn); ok {
+			// Discard unsent data and send RST on close instead of FIN. Close the socket
+			// itself, as closing a TLS connection would send close_notify first.
+			_ = tcp.SetLinger(0)
+			_ = tcp.Close()
+			return true
+		}
+	}
+	_ = conn.Close()
+	return true
+}
+
+// tcpConn unwraps conn, e.g. from the *tls.Conn of a TLS server, to its TCP socket
+func tcpConn(conn net.Conn) (*net.TCPConn, bool) {
+	for {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			return c, true
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return nil, false
+		}
+	}
 }
 
 // Helper functions for extracting HTTP attributes
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/generator/tlscert"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
+	"github.com/aslakknutsen/kkbase/testapp/pkg/service/telemetry"
+)
+
+// startUnifiedServer serves the HTTP server the way the unified port does, with TLS if
+// tlsConfig is set, and returns its address
+func startUnifiedServer(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	cfg := &service.Config{Name: "test-service", HTTPPort: 8080, GRPCPort: 8080}
+	tel := &telemetry.Telemetry{
+		Logger:      zap.NewNop(),
+		Tracer:      otel.Tracer("test-service"),
+		Metrics:     &telemetry.Metrics{},
+		ServiceName: cfg.Name,
+	}
+	srv := NewServer(cfg, tel)
+	t.Cleanup(func() { srv.Close() })
+
+	httpServer := &http.Server{
+		Handler:   service.UnifiedHandler(grpc.NewServer(), srv),
+		TLSConfig: tlsConfig,
+		Protocols: service.UnifiedProtocols(),
+	}
+	t.Cleanup(func() { httpServer.Close() })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	if tlsConfig != nil {
+		go httpServer.ServeTLS(listener, "", "")
+	} else {
+		go httpServer.Serve(listener)
+	}
+	return listener.Addr().String()
+}
+
+func TestServer_ConnectionResetUnified(t *testing.T) {
+	certPEM, keyPEM, err := tlscert.SelfSigned("localhost", []string{"localhost"}, "")
+	if err != nil {
+		t.Fatalf("SelfSigned() failed: %v", err)
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("Failed to load key pair: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tls      bool
+		behavior string
+		wantRST  bool
+	}{
+		{name: "plaintext rst", behavior: "connection-reset=1", wantRST: true},
+		{name: "plaintext fin", behavior: "connection-reset=1:fin", wantRST: false},
+		{name: "tls rst", tls: true, behavior: "connection-reset=1", wantRST: true},
+		{name: "tls fin", tls: true, behavior: "connection-reset=1:fin", wantRST: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var conn net.Conn
+			if tt.tls {
+				addr := startUnifiedServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+				conn, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true})
+			} else {
+				conn, err = net.Dial("tcp", startUnifiedServer(t, nil))
+			}
+			if err != nil {
+				t.Fatalf("Failed to connect: %v", err)
+			}
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+			if _, err := io.WriteString(conn, "GET /?behavior="+tt.behavior+" HTTP/1.1\r\nHost: localhost\r\n\r\n"); err != nil {
+				t.Fatalf("Failed to send request: %v", err)
+			}
+			resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+			if err == nil {
+				resp.Body.Close()
+				t.Fatalf("Expected the connection to be closed, got %s", resp.Status)
+			}
+
+			if reset := errors.Is(err, syscall.ECONNRESET); reset != tt.wantRST {
+				t.Errorf("Expected RST %v, got error %v", tt.wantRST, err)
+			}
+		})
+	}
+}
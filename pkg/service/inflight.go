@@ -0,0 +1,24 @@
+package service
+
+import "sync/atomic"
+
+// InFlightCounter counts requests currently being served by this process, across protocols
+type InFlightCounter struct {
+	n atomic.Int64
+}
+
+// InFlight is the counter maintained by the HTTP and gRPC servers
+var InFlight = &InFlightCounter{}
+
+// Begin marks a request as in flight and returns a func that marks it done
+func (c *InFlightCounter) Begin() func() {
+	c.n.Add(1)
+	return func() {
+		c.n.Add(-1)
+	}
+}
+
+// Count returns the number of requests in flight
+func (c *InFlightCounter) Count() int64 {
+	return c.n.Load()
+}
@@ -0,0 +1,25 @@
+package service
+
+import "testing"
+
+func TestInFlightCounter(t *testing.T) {
+	c := &InFlightCounter{}
+	if n := c.Count(); n != 0 {
+		t.Fatalf("expected 0 in flight, got %d", n)
+	}
+
+	doneA := c.Begin()
+	doneB := c.Begin()
+	if n := c.Count(); n != 2 {
+		t.Errorf("expected 2 in flight, got %d", n)
+	}
+
+	doneA()
+	if n := c.Count(); n != 1 {
+		t.Errorf("expected 1 in flight after one finished, got %d", n)
+	}
+	doneB()
+	if n := c.Count(); n != 0 {
+		t.Errorf("expected 0 in flight after both finished, got %d", n)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"fmt"
+	"net"
+
+	"golang.org/x/net/netutil"
+)
+
+// Listen opens a TCP listener on port. When maxConnections > 0 at most that many
+// connections are accepted concurrently; further connections wait in the kernel
+// backlog until an accepted one is closed (models OS/proxy connection limits).
+func Listen(port int, maxConnections int) (net.Listener, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return nil, err
+	}
+	if maxConnections > 0 {
+		listener = netutil.LimitListener(listener, maxConnections)
+	}
+	return listener, nil
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestListen_MaxConnections(t *testing.T) {
+	listener, err := Listen(0, 2)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 3)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	addr := listener.Addr().String()
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			t.Fatalf("Dial %d error = %v", i, err)
+		}
+		defer conn.Close()
+	}
+
+	// The first two connections are accepted
+	var held []net.Conn
+	for i := 0; i < 2; i++ {
+		select {
+		case conn := <-accepted:
+			held = append(held, conn)
+		case <-time.After(time.Second):
+			t.Fatalf("expected connection %d to be accepted", i)
+		}
+	}
+
+	// The third waits while the limit is reached
+	select {
+	case <-accepted:
+		t.Fatal("expected third connection to wait for a free slot")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Closing an accepted connection frees a slot
+	held[0].Close()
+	select {
+	case conn := <-accepted:
+		conn.Close()
+	case <-time.After(time.Second):
+		t.Fatal("expected third connection to be accepted after one closed")
+	}
+	held[1].Close()
+}
+
+func TestListen_Unlimited(t *testing.T) {
+	listener, err := Listen(0, 0)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 5)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		if err != nil {
+			t.Fatalf("Dial %d error = %v", i, err)
+		}
+		defer conn.Close()
+	}
+	for i := 0; i < 5; i++ {
+		select {
+		case conn := <-accepted:
+			defer conn.Close()
+		case <-time.After(time.Second):
+			t.Fatalf("expected connection %d to be accepted without a limit", i)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Rand is a random source safe for concurrent use. Injected faults draw from it
+// instead of the global math/rand source, so a seed makes chaos runs reproducible.
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewRand creates a random source seeded with seed
+func NewRand(seed int64) *Rand {
+	return &Rand{r: rand.New(rand.NewSource(seed))}
+}
+
+// Random is the process-wide random source, seeded from RAND_SEED at startup
+// (time-seeded when unset)
+var Random = NewRand(time.Now().UnixNano())
+
+// Seed resets the source to the sequence of seed
+func (r *Rand) Seed(seed int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Seed(seed)
+}
+
+// Float64 returns a random number in [0.0, 1.0)
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// Intn returns a random integer in [0, n); it panics if n <= 0
+func (r *Rand) Intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Intn(n)
+}
+
+// Int63n returns a random integer in [0, n); it panics if n <= 0
+func (r *Rand) Int63n(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Int63n(n)
+}
+
+// Uint32 returns a random 32-bit integer
+func (r *Rand) Uint32() uint32 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Uint32()
+}
@@ -0,0 +1,31 @@
+package service
+
+import "testing"
+
+func TestRand_Seeded(t *testing.T) {
+	draw := func(r *Rand) []int64 {
+		var values []int64
+		for i := 0; i < 10; i++ {
+			values = append(values, r.Int63n(1000))
+		}
+		return values
+	}
+
+	a, b := draw(NewRand(42)), draw(NewRand(42))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected the same seed to produce the same sequence, got %v and %v", a, b)
+		}
+	}
+
+	// Reseeding restarts the sequence
+	r := NewRand(7)
+	first := draw(r)
+	r.Seed(7)
+	again := draw(r)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("expected reseeding to restart the sequence, got %v and %v", first, again)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadState records when a config reload was last triggered on this process
+type ReloadState struct {
+	triggeredAt atomic.Int64 // Unix nanoseconds; 0 means never triggered
+}
+
+// ConfigReload is the state flipped by the /admin/reload endpoint
+var ConfigReload = &ReloadState{}
+
+// Trigger starts a config reload now
+func (r *ReloadState) Trigger() {
+	r.triggeredAt.Store(time.Now().UnixNano())
+}
+
+// Reset forgets any triggered reload
+func (r *ReloadState) Reset() {
+	r.triggeredAt.Store(0)
+}
+
+// Reloading reports whether a reload taking d was triggered less than d ago
+func (r *ReloadState) Reloading(d time.Duration) bool {
+	at := r.triggeredAt.Load()
+	return at != 0 && time.Now().UnixNano() < at+int64(d)
+}
+
+// ReloadHandler serves the admin endpoint that triggers a config reload (POST only)
+func ReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	ConfigReload.Trigger()
+	w.WriteHeader(http.StatusAccepted)
+	w.Write([]byte("Reload triggered"))
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReloadState(t *testing.T) {
+	r := &ReloadState{}
+	if r.Reloading(time.Minute) {
+		t.Error("expected no reload before trigger")
+	}
+
+	r.Trigger()
+	if !r.Reloading(50 * time.Millisecond) {
+		t.Error("expected reload in progress right after trigger")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if r.Reloading(50 * time.Millisecond) {
+		t.Error("expected reload to finish after its duration")
+	}
+
+	r.Trigger()
+	r.Reset()
+	if r.Reloading(time.Minute) {
+		t.Error("expected Reset to clear the reload")
+	}
+}
+
+func TestReloadHandler(t *testing.T) {
+	defer ConfigReload.Reset()
+
+	rec := httptest.NewRecorder()
+	ReloadHandler(rec, httptest.NewRequest(http.MethodGet, "/admin/reload", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+	if ConfigReload.Reloading(time.Minute) {
+		t.Error("expected GET not to trigger a reload")
+	}
+
+	rec = httptest.NewRecorder()
+	ReloadHandler(rec, httptest.NewRequest(http.MethodPost, "/admin/reload", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected 202 for POST, got %d", rec.Code)
+	}
+	if !ConfigReload.Reloading(time.Minute) {
+		t.Error("expected POST to trigger a reload")
+	}
+}
@@ -1,21 +1,23 @@
 package router
 
 import (
-	"math/rand"
+	"net/http"
+	"regexp"
 	"strings"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
 )
 
-// Router handles upstream service routing based on request paths
+// Router handles upstream service routing based on request paths and headers
 type Router interface {
-	// Match returns upstreams that handle the given path
+	// Match returns upstreams that handle the given request
 	// Returns nil if no upstreams match (404 case)
-	Match(path string) []*service.UpstreamConfig
+	Match(path string, headers http.Header) []*service.UpstreamConfig
 
-	// MatchWithWeights returns upstreams that handle the given path,
-	// applying weighted selection for grouped upstreams
-	MatchWithWeights(path string, weights map[string]int) []*service.UpstreamConfig
+	// MatchWithWeights returns upstreams that handle the given request,
+	// applying weighted selection for grouped upstreams unless pins (group -> upstream ID) pin it.
+	// Random selection draws from rnd (nil = the process-wide source)
+	MatchWithWeights(path string, headers http.Header, weights map[string]int, pins map[string]string, rnd *service.Rand) []*service.UpstreamConfig
 
 	// GetForwardPath returns the path to use when calling the upstream
 	// Returns the upstream's explicit Path if set, otherwise "/"
@@ -28,13 +30,80 @@ type Router interface {
 // PathRouter implements path-based routing for HTTP upstreams
 type PathRouter struct {
 	upstreams []*service.UpstreamConfig
+	matchers  [][]matcher // Parsed Match entries, by upstream index
 }
 
+// matcher reports whether a request is routed to an upstream, and how specifically:
+// the length of the path it matched, or more than any path match for a header
+type matcher func(path string, headers http.Header) (specificity int, ok bool)
+
 // NewPathRouter creates a new path-based router
 func NewPathRouter(upstreams []*service.UpstreamConfig) *PathRouter {
+	matchers := make([][]matcher, len(upstreams))
+	for i, upstream := range upstreams {
+		for _, m := range upstream.Match {
+			matchers[i] = append(matchers[i], parseMatch(m))
+		}
+	}
 	return &PathRouter{
 		upstreams: upstreams,
+		matchers:  matchers,
+	}
+}
+
+// parseMatch parses a Match entry. "~<regexp>" matches the path against a regular
+// expression and "header:<name>=<value>" a request header value; anything else is a
+// literal path prefix, even if it contains regexp metacharacters. Invalid entries never match.
+func parseMatch(m string) matcher {
+	never := func(string, http.Header) (int, bool) { return 0, false }
+
+	if expr, ok := strings.CutPrefix(m, "~"); ok {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return never
+		}
+		return func(path string, _ http.Header) (int, bool) {
+			loc := re.FindStringIndex(path)
+			if loc == nil {
+				return 0, false
+			}
+			return loc[1] - loc[0], true
+		}
+	}
+
+	if header, ok := strings.CutPrefix(m, "header:"); ok {
+		name, value, ok := strings.Cut(header, "=")
+		if !ok || name == "" {
+			return never
+		}
+		return func(path string, headers http.Header) (int, bool) {
+			for _, v := range headers.Values(name) {
+				if v == value {
+					return len(path) + 1, true
+				}
+			}
+			return 0, false
+		}
+	}
+
+	return func(path string, _ http.Header) (int, bool) {
+		return len(m), strings.HasPrefix(path, m)
+	}
+}
+
+// rank orders the upstreams a request matched: the most specific match wins,
+// and priority breaks ties between equally specific ones
+type rank struct {
+	specificity int
+	priority    int
+}
+
+// less reports whether r ranks below o
+func (r rank) less(o rank) bool {
+	if r.specificity != o.specificity {
+		return r.specificity < o.specificity
 	}
+	return r.priority < o.priority
 }
 
 // HasUpstreams returns true if any upstreams are configured
@@ -42,37 +111,58 @@ func (r *PathRouter) HasUpstreams() bool {
 	return len(r.upstreams) > 0
 }
 
-// Match returns upstreams that match the given path (no weighted selection)
-func (r *PathRouter) Match(path string) []*service.UpstreamConfig {
-	return r.MatchWithWeights(path, nil)
+// Match returns upstreams that match the given request (no weighted selection)
+func (r *PathRouter) Match(path string, headers http.Header) []*service.UpstreamConfig {
+	return r.MatchWithWeights(path, headers, nil, nil, nil)
 }
 
-// MatchWithWeights returns upstreams that match the given path,
+// MatchWithWeights returns upstreams that match the given request,
 // applying weighted selection for grouped upstreams.
-// For upstreams in the same group, one is selected based on weights.
-// Ungrouped upstreams are always included.
-func (r *PathRouter) MatchWithWeights(path string, weights map[string]int) []*service.UpstreamConfig {
+// An upstream matches if any of its Match entries (path prefix, path regexp or header) does.
+// Of the matching upstreams only the most specific are called (longest path prefix or
+// regexp match, header matches above path matches), with the highest priority breaking
+// ties; upstreams that still tie are all called. Catch-all upstreams are always called.
+// For upstreams in the same group, the pinned upstream is selected if it is one of them,
+// otherwise one is selected based on weights.
+// Ungrouped upstreams are always included. Random selection draws from rnd, or the
+// process-wide source if nil.
+func (r *PathRouter) MatchWithWeights(path string, headers http.Header, weights map[string]int, pins map[string]string, rnd *service.Rand) []*service.UpstreamConfig {
 	if len(r.upstreams) == 0 {
 		return nil
 	}
 
-	var matched []*service.UpstreamConfig
+	// Rank upstreams with match config by their most specific matching entry
+	ranks := make([]rank, len(r.upstreams))
+	hits := make([]bool, len(r.upstreams))
+	var best rank
+	found := false
 	hasAnyMatchConfig := false
 
-	for _, upstream := range r.upstreams {
+	for i, upstream := range r.upstreams {
 		if len(upstream.Match) == 0 {
-			// No match configured = catch-all (always call this upstream)
-			matched = append(matched, upstream)
-		} else {
-			hasAnyMatchConfig = true
-			// Check if path matches any prefix in Match
-			for _, prefix := range upstream.Match {
-				if strings.HasPrefix(path, prefix) {
-					matched = append(matched, upstream)
-					break
+			continue
+		}
+		hasAnyMatchConfig = true
+		for _, m := range r.matchers[i] {
+			if specificity, ok := m(path, headers); ok {
+				if !hits[i] || ranks[i].specificity < specificity {
+					ranks[i] = rank{specificity: specificity, priority: upstream.Priority}
 				}
+				hits[i] = true
 			}
 		}
+		if hits[i] && (!found || best.less(ranks[i])) {
+			best = ranks[i]
+			found = true
+		}
+	}
+
+	// Keep catch-alls and the best-ranked matches, in configuration order
+	var matched []*service.UpstreamConfig
+	for i, upstream := range r.upstreams {
+		if len(upstream.Match) == 0 || (hits[i] && ranks[i] == best) {
+			matched = append(matched, upstream)
+		}
 	}
 
 	// If some upstreams have match config but none matched, return empty (404)
@@ -81,26 +171,33 @@ func (r *PathRouter) MatchWithWeights(path string, weights map[string]int) []*se
 	}
 
 	// Apply weighted selection for grouped upstreams
-	return r.applyWeightedSelection(matched, weights)
+	if rnd == nil {
+		rnd = service.Random
+	}
+	return r.applyWeightedSelection(matched, weights, pins, rnd)
 }
 
 // applyWeightedSelection applies weighted selection for grouped upstreams and probability for ungrouped
-// - Upstreams with the same Group are mutually exclusive (one selected based on weights)
+// - Upstreams with the same Group are mutually exclusive (the pinned one, or one selected based on weights)
 // - Ungrouped upstreams with Probability > 0: included based on probability roll
 // - Ungrouped upstreams with Probability == 0: always included
-func (r *PathRouter) applyWeightedSelection(upstreams []*service.UpstreamConfig, weights map[string]int) []*service.UpstreamConfig {
+func (r *PathRouter) applyWeightedSelection(upstreams []*service.UpstreamConfig, weights map[string]int, pins map[string]string, rnd *service.Rand) []*service.UpstreamConfig {
 	if len(upstreams) == 0 {
 		return nil
 	}
 
-	// Group upstreams by their Group field
+	// Group upstreams by their Group field, keeping the order groups first appear in
 	groups := make(map[string][]*service.UpstreamConfig)
+	var groupOrder []string
 	var ungrouped []*service.UpstreamConfig
 
 	for _, u := range upstreams {
 		if u.Group == "" {
 			ungrouped = append(ungrouped, u)
 		} else {
+			if _, ok := groups[u.Group]; !ok {
+				groupOrder = append(groupOrder, u.Group)
+			}
 			groups[u.Group] = append(groups[u.Group], u)
 		}
 	}
@@ -110,7 +207,7 @@ func (r *PathRouter) applyWeightedSelection(upstreams []*service.UpstreamConfig,
 	for _, u := range ungrouped {
 		if u.Probability > 0 {
 			// Roll probability to decide if included
-			if rand.Float64() < u.Probability {
+			if rnd.Float64() < u.Probability {
 				result = append(result, u)
 			}
 		} else {
@@ -119,9 +216,14 @@ func (r *PathRouter) applyWeightedSelection(upstreams []*service.UpstreamConfig,
 		}
 	}
 
-	// For each group, select one upstream based on weights
-	for _, groupUpstreams := range groups {
-		selected := selectWeighted(groupUpstreams, weights)
+	// For each group, select the pinned upstream if it is in the group, otherwise one based on weights.
+	// Groups are visited in a fixed order so a seeded rnd makes the same selections.
+	for _, group := range groupOrder {
+		groupUpstreams := groups[group]
+		selected := selectPinned(groupUpstreams, pins[group])
+		if selected == nil {
+			selected = selectWeighted(groupUpstreams, weights, rnd)
+		}
 		if selected != nil {
 			result = append(result, selected)
 		}
@@ -130,9 +232,22 @@ func (r *PathRouter) applyWeightedSelection(upstreams []*service.UpstreamConfig,
 	return result
 }
 
+// selectPinned returns the group's upstream with the pinned ID, or nil if none has it
+func selectPinned(upstreams []*service.UpstreamConfig, pinned string) *service.UpstreamConfig {
+	if pinned == "" {
+		return nil
+	}
+	for _, u := range upstreams {
+		if u.Name == pinned {
+			return u
+		}
+	}
+	return nil
+}
+
 // selectWeighted selects one upstream from the group based on weights
 // If weights are not specified for an upstream, it gets an equal share of remaining weight
-func selectWeighted(upstreams []*service.UpstreamConfig, weights map[string]int) *service.UpstreamConfig {
+func selectWeighted(upstreams []*service.UpstreamConfig, weights map[string]int, rnd *service.Rand) *service.UpstreamConfig {
 	if len(upstreams) == 0 {
 		return nil
 	}
@@ -186,11 +301,11 @@ func selectWeighted(upstreams []*service.UpstreamConfig, weights map[string]int)
 
 	if totalWeight <= 0 {
 		// Fallback: pick randomly with equal probability
-		return upstreams[rand.Intn(len(upstreams))]
+		return upstreams[rnd.Intn(len(upstreams))]
 	}
 
 	// Random selection based on weights
-	r := rand.Intn(totalWeight)
+	r := rnd.Intn(totalWeight)
 	cumulative := 0
 	for i, w := range effectiveWeights {
 		cumulative += w
@@ -226,12 +341,12 @@ func (r *NoOpRouter) HasUpstreams() bool {
 }
 
 // Match always returns nil for NoOpRouter
-func (r *NoOpRouter) Match(path string) []*service.UpstreamConfig {
+func (r *NoOpRouter) Match(path string, headers http.Header) []*service.UpstreamConfig {
 	return nil
 }
 
 // MatchWithWeights always returns nil for NoOpRouter
-func (r *NoOpRouter) MatchWithWeights(path string, weights map[string]int) []*service.UpstreamConfig {
+func (r *NoOpRouter) MatchWithWeights(path string, headers http.Header, weights map[string]int, pins map[string]string, rnd *service.Rand) []*service.UpstreamConfig {
 	return nil
 }
 
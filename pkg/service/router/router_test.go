@@ -1,6 +1,8 @@
 package router
 
 import (
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/aslakknutsen/kkbase/testapp/pkg/service"
@@ -53,7 +55,7 @@ func TestPathRouter_Match(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			matched := router.Match(tt.path)
+			matched := router.Match(tt.path, nil)
 
 			if len(matched) != len(tt.expectMatches) {
 				t.Errorf("Expected %d matches, got %d", len(tt.expectMatches), len(matched))
@@ -85,7 +87,7 @@ func TestPathRouter_NoMatch(t *testing.T) {
 	}
 
 	router := NewPathRouter(upstreams)
-	matched := router.Match("/other")
+	matched := router.Match("/other", nil)
 
 	if matched != nil {
 		t.Error("Expected no match for /other, but got matches")
@@ -172,7 +174,7 @@ func TestPathRouter_HasUpstreams(t *testing.T) {
 func TestPathRouter_EmptyUpstreams(t *testing.T) {
 	router := NewPathRouter([]*service.UpstreamConfig{})
 
-	matched := router.Match("/any")
+	matched := router.Match("/any", nil)
 	if matched != nil {
 		t.Error("Expected nil for empty upstreams")
 	}
@@ -185,7 +187,7 @@ func TestNoOpRouter(t *testing.T) {
 		t.Error("NoOpRouter should not have upstreams")
 	}
 
-	matched := router.Match("/any/path")
+	matched := router.Match("/any/path", nil)
 	if matched != nil {
 		t.Error("NoOpRouter should not match any path")
 	}
@@ -209,7 +211,7 @@ func TestPathRouter_MultiplePathsPerUpstream(t *testing.T) {
 
 	testPaths := []string{"/blog/post-1", "/news/latest", "/articles/tech"}
 	for _, path := range testPaths {
-		matched := router.Match(path)
+		matched := router.Match(path, nil)
 		if len(matched) != 1 {
 			t.Errorf("Expected 1 match for %s, got %d", path, len(matched))
 		}
@@ -231,7 +233,7 @@ func TestPathRouter_MatchWithExplicitPath(t *testing.T) {
 	router := NewPathRouter(upstreams)
 
 	// Should match
-	matched := router.Match("/api/v1/users")
+	matched := router.Match("/api/v1/users", nil)
 	if len(matched) != 1 {
 		t.Errorf("Expected 1 match, got %d", len(matched))
 	}
@@ -259,7 +261,7 @@ func TestPathRouter_MultipleSameNameUpstreams(t *testing.T) {
 	router := NewPathRouter(upstreams)
 
 	// Should match first notification
-	matched := router.Match("/events/OrderCreated")
+	matched := router.Match("/events/OrderCreated", nil)
 	if len(matched) != 1 {
 		t.Errorf("Expected 1 match, got %d", len(matched))
 	}
@@ -268,7 +270,7 @@ func TestPathRouter_MultipleSameNameUpstreams(t *testing.T) {
 	}
 
 	// Should match second notification
-	matched = router.Match("/events/PaymentProcessed")
+	matched = router.Match("/events/PaymentProcessed", nil)
 	if len(matched) != 1 {
 		t.Errorf("Expected 1 match, got %d", len(matched))
 	}
@@ -277,8 +279,284 @@ func TestPathRouter_MultipleSameNameUpstreams(t *testing.T) {
 	}
 
 	// Should not match unknown
-	matched = router.Match("/events/Unknown")
+	matched = router.Match("/events/Unknown", nil)
 	if matched != nil {
 		t.Error("Expected no match for unknown event")
 	}
 }
+
+func TestPathRouter_RegexMatch(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "versioned", Match: []string{"~^/api/v[0-9]+/"}},
+		{Name: "legacy", Match: []string{"/api"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	tests := []struct {
+		path          string
+		expectMatches []string
+	}{
+		{path: "/api/v2/orders", expectMatches: []string{"versioned"}}, // "/api/v2/" is more specific than "/api"
+		{path: "/api/orders", expectMatches: []string{"legacy"}},
+		{path: "/v2/api/v2/orders", expectMatches: nil}, // Anchored regex
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assertMatches(t, router.Match(tt.path, nil), tt.expectMatches)
+		})
+	}
+}
+
+func TestPathRouter_RegexPrecedenceOverLiteral(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		// "~" always marks a regexp, matched anywhere in the path unless anchored
+		{Name: "regex", Match: []string{"~/v1\\.0$"}},
+		// Without "~" metacharacters are literal: "." is not a wildcard, "~" not a marker
+		{Name: "literal", Match: []string{"/api/v1.0"}},
+		{Name: "tilde", Match: []string{"/~user"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	tests := []struct {
+		path          string
+		expectMatches []string
+	}{
+		{path: "/api/v1.0", expectMatches: []string{"literal"}}, // Longer match than the regexp's "/v1.0"
+		{path: "/api/v1.0/users", expectMatches: []string{"literal"}},
+		{path: "/api/v1x0", expectMatches: nil},
+		{path: "/other/v1.0", expectMatches: []string{"regex"}},
+		{path: "/~user/home", expectMatches: []string{"tilde"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assertMatches(t, router.Match(tt.path, nil), tt.expectMatches)
+		})
+	}
+}
+
+func TestPathRouter_HeaderMatch(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "acme", Match: []string{"header:X-Tenant=acme"}},
+		{Name: "orders", Match: []string{"/orders", "header:X-Route=orders"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	tests := []struct {
+		name          string
+		path          string
+		headers       http.Header
+		expectMatches []string
+	}{
+		{name: "tenant header", path: "/", headers: http.Header{"X-Tenant": {"acme"}}, expectMatches: []string{"acme"}},
+		{name: "header name is case-insensitive", path: "/", headers: http.Header{http.CanonicalHeaderKey("x-tenant"): {"acme"}}, expectMatches: []string{"acme"}},
+		{name: "header value must match exactly", path: "/", headers: http.Header{"X-Tenant": {"Acme"}}, expectMatches: nil},
+		{name: "any header value", path: "/", headers: http.Header{"X-Tenant": {"globex", "acme"}}, expectMatches: []string{"acme"}},
+		{name: "path or header", path: "/orders/1", headers: nil, expectMatches: []string{"orders"}},
+		{name: "header or path", path: "/", headers: http.Header{"X-Route": {"orders"}, "X-Tenant": {"acme"}}, expectMatches: []string{"acme", "orders"}},
+		{name: "no headers", path: "/", headers: nil, expectMatches: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMatches(t, router.Match(tt.path, tt.headers), tt.expectMatches)
+		})
+	}
+}
+
+func TestPathRouter_InvalidMatchNeverMatches(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "bad-regex", Match: []string{"~^/api/(v1"}},
+		{Name: "bad-header", Match: []string{"header:X-Tenant"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	if matched := router.Match("/api/(v1", http.Header{"X-Tenant": {""}}); matched != nil {
+		t.Errorf("Expected invalid match entries not to match, got %d upstreams", len(matched))
+	}
+}
+
+func TestPathRouter_LongestPrefixWins(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "api", Match: []string{"/api"}},
+		{Name: "orders", Match: []string{"/api/orders"}},
+		{Name: "order-items", Match: []string{"/api/orders/items"}},
+		{Name: "audit", Match: nil}, // Catch-all
+	}
+
+	// Overlapping prefixes resolve the same way whatever the configuration order
+	reversed := []*service.UpstreamConfig{upstreams[3], upstreams[2], upstreams[1], upstreams[0]}
+
+	tests := []struct {
+		path          string
+		expectMatches []string
+	}{
+		{path: "/api/users", expectMatches: []string{"api", "audit"}},
+		{path: "/api/orders", expectMatches: []string{"orders", "audit"}},
+		{path: "/api/orders/42", expectMatches: []string{"orders", "audit"}},
+		{path: "/api/orders/items/7", expectMatches: []string{"order-items", "audit"}},
+		{path: "/other", expectMatches: []string{"audit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assertMatches(t, NewPathRouter(upstreams).Match(tt.path, nil), tt.expectMatches)
+
+			matched := NewPathRouter(reversed).Match(tt.path, nil)
+			if len(matched) != len(tt.expectMatches) || matched[len(matched)-1].Name != tt.expectMatches[0] {
+				t.Errorf("Expected %v in reverse order, got %d matches", tt.expectMatches, len(matched))
+			}
+		})
+	}
+}
+
+func TestPathRouter_PriorityBreaksTies(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "orders-v1", Match: []string{"/orders"}},
+		{Name: "orders-v2", Match: []string{"/orders"}, Priority: 10},
+		{Name: "orders-shadow", Match: []string{"/orders"}, Priority: 10},
+		{Name: "order-items", Match: []string{"/orders/items"}, Priority: -1},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	// Highest priority wins between equally specific matches; remaining ties are all called
+	assertMatches(t, router.Match("/orders/42", nil), []string{"orders-v2", "orders-shadow"})
+
+	// Priority only breaks ties: a more specific match wins whatever its priority
+	assertMatches(t, router.Match("/orders/items/7", nil), []string{"order-items"})
+}
+
+func TestPathRouter_EqualMatchesAllCalled(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "order-api", Match: []string{"/orders"}},
+		{Name: "order-audit", Match: []string{"/orders"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	assertMatches(t, router.Match("/orders/1", nil), []string{"order-api", "order-audit"})
+}
+
+func TestPathRouter_HeaderMoreSpecificThanPath(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "acme", Match: []string{"header:X-Tenant=acme"}},
+		{Name: "shared", Match: []string{"/"}},
+		{Name: "orders", Match: []string{"/orders/very/specific/path"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	assertMatches(t, router.Match("/orders/very/specific/path", http.Header{"X-Tenant": {"acme"}}), []string{"acme"})
+	assertMatches(t, router.Match("/orders", http.Header{"X-Tenant": {"globex"}}), []string{"shared"})
+}
+
+func TestPathRouter_SpecificityBeforeWeightedSelection(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "ok", Match: []string{"/pay"}, Group: "outcome"},
+		{Name: "fail", Match: []string{"/pay"}, Group: "outcome"},
+		{Name: "legacy", Match: []string{"/"}},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	// The group wins on specificity, then one of its members is picked by weight
+	matched := router.MatchWithWeights("/pay/1", nil, map[string]int{"fail": 100}, nil, nil)
+	assertMatches(t, matched, []string{"fail"})
+}
+
+func TestPathRouter_RoutePin(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "checkout-v1", Match: []string{"/checkout"}, Group: "checkout"},
+		{Name: "checkout-v2", Match: []string{"/checkout"}, Group: "checkout"},
+		{Name: "audit", Match: nil},
+	}
+
+	router := NewPathRouter(upstreams)
+
+	// The pin wins over weights on every request
+	weights := map[string]int{"checkout-v1": 100}
+	for i := 0; i < 50; i++ {
+		matched := router.MatchWithWeights("/checkout", nil, weights, map[string]string{"checkout": "checkout-v2"}, nil)
+		assertMatches(t, matched, []string{"audit", "checkout-v2"})
+	}
+
+	// A pin naming an upstream outside the group falls back to weighted selection
+	for _, pins := range []map[string]string{{"checkout": "audit"}, {"checkout": "checkout-v3"}} {
+		matched := router.MatchWithWeights("/checkout", nil, weights, pins, nil)
+		assertMatches(t, matched, []string{"audit", "checkout-v1"})
+	}
+}
+
+// assertMatches checks that matched holds exactly the expected upstream names, in order
+func assertMatches(t *testing.T, matched []*service.UpstreamConfig, expected []string) {
+	t.Helper()
+	if len(matched) != len(expected) {
+		t.Fatalf("Expected %d matches %v, got %d", len(expected), expected, len(matched))
+	}
+	for i, name := range expected {
+		if matched[i].Name != name {
+			t.Errorf("Expected match %d to be %s, got %s", i, name, matched[i].Name)
+		}
+	}
+}
+
+func TestPathRouter_SeededSelection(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "v1", Group: "api"},
+		{Name: "v2", Group: "api"},
+		{Name: "audit", Probability: 0.5},
+	}
+	router := NewPathRouter(upstreams)
+
+	run := func() []string {
+		rnd := service.NewRand(42)
+		var picks []string
+		for i := 0; i < 20; i++ {
+			var names []string
+			for _, u := range router.MatchWithWeights("/", nil, nil, nil, rnd) {
+				names = append(names, u.Name)
+			}
+			picks = append(picks, strings.Join(names, "+"))
+		}
+		return picks
+	}
+
+	first, second := run(), run()
+	if strings.Join(first, ",") != strings.Join(second, ",") {
+		t.Errorf("expected the same seed to select the same upstreams, got %v and %v", first, second)
+	}
+}
+
+func TestPathRouter_SeededSelectionGroups(t *testing.T) {
+	upstreams := []*service.UpstreamConfig{
+		{Name: "v1", Group: "api"},
+		{Name: "v2", Group: "api"},
+		{Name: "v3", Group: "api"},
+		{Name: "primary", Group: "db"},
+		{Name: "replica", Group: "db"},
+		{Name: "standby", Group: "db"},
+	}
+	router := NewPathRouter(upstreams)
+
+	// Each run draws for both groups from a fresh seeded source, so the draw order matters
+	run := func() string {
+		var names []string
+		for _, u := range router.MatchWithWeights("/", nil, nil, nil, service.NewRand(42)) {
+			names = append(names, u.Name)
+		}
+		return strings.Join(names, "+")
+	}
+
+	want := run()
+	for i := 0; i < 50; i++ {
+		if got := run(); got != want {
+			t.Fatalf("expected the same seed to select %s every run, got %s on run %d", want, got, i)
+		}
+	}
+}
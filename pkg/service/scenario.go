@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aslakknutsen/kkbase/testapp/pkg/dsl/types"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a behavior injected for a window of time relative to service start
+type Scenario struct {
+	Name     string
+	At       time.Duration // Offset from start when the behavior is injected
+	Duration time.Duration // How long it stays injected (0 = until shutdown)
+	Behavior string        // Behavior chain applied while active (e.g. "error=503")
+}
+
+// ScenarioRunner drives scenarios with timers and reports which behaviors are active
+type ScenarioRunner struct {
+	mu        sync.RWMutex
+	scenarios []Scenario // Sorted by At, so later-starting scenarios come last
+	active    []bool
+	timers    []*time.Timer
+	gen       int // Bumped on Stop so timers that already fired can't touch new state
+}
+
+// Scenarios is the runner consulted by request handling for time-based behaviors
+var Scenarios = &ScenarioRunner{}
+
+// LoadScenarios reads scenarios from SCENARIOS (inline YAML) or the file named by SCENARIOS_FILE.
+// Returns nil if neither is set.
+func LoadScenarios() ([]Scenario, error) {
+	data := []byte(os.Getenv("SCENARIOS"))
+	if len(data) == 0 {
+		path := os.Getenv("SCENARIOS_FILE")
+		if path == "" {
+			return nil, nil
+		}
+		var err error
+		data, err = os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read scenarios file: %w", err)
+		}
+	}
+	return ParseScenarios(data)
+}
+
+// ParseScenarios parses a YAML list of DSL scenarios
+// Example: [{name: payment-outage, at: 30s, duration: 1m, action: inject, params: {behavior: "payment:error=503"}}]
+func ParseScenarios(data []byte) ([]Scenario, error) {
+	var configs []types.ScenarioConfig
+	if err := yaml.Unmarshal(data, &configs); err != nil {
+		return nil, fmt.Errorf("parse scenarios: %w", err)
+	}
+
+	scenarios := make([]Scenario, 0, len(configs))
+	for i, sc := range configs {
+		s, err := scenarioFromConfig(sc)
+		if err != nil {
+			name := sc.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i)
+			}
+			return nil, fmt.Errorf("scenario %s: %w", name, err)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// scenarioFromConfig validates a DSL scenario and converts it to a runnable Scenario
+func scenarioFromConfig(sc types.ScenarioConfig) (Scenario, error) {
+	s := Scenario{Name: sc.Name}
+
+	if sc.At == "" {
+		return s, fmt.Errorf("at is required")
+	}
+	at, err := time.ParseDuration(sc.At)
+	if err != nil {
+		return s, fmt.Errorf("invalid at: %w", err)
+	}
+	if at < 0 {
+		return s, fmt.Errorf("at cannot be negative")
+	}
+	s.At = at
+
+	if sc.Duration != "" {
+		d, err := time.ParseDuration(sc.Duration)
+		if err != nil {
+			return s, fmt.Errorf("invalid duration: %w", err)
+		}
+		if d < 0 {
+			return s, fmt.Errorf("duration cannot be negative")
+		}
+		s.Duration = d
+	}
+
+	switch sc.Action {
+	case "inject":
+		behavior, ok := sc.Params["behavior"].(string)
+		if !ok || behavior == "" {
+			return s, fmt.Errorf("inject requires a behavior param")
+		}
+		s.Behavior = behavior
+	default:
+		return s, fmt.Errorf("unknown action %q (expected inject)", sc.Action)
+	}
+
+	return s, nil
+}
+
+// Start schedules the scenarios relative to now, replacing any previously started ones
+func (r *ScenarioRunner) Start(scenarios []Scenario, logger *zap.Logger) {
+	r.Stop()
+
+	sorted := make([]Scenario, len(scenarios))
+	copy(sorted, scenarios)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At < sorted[j].At })
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.scenarios = sorted
+	r.active = make([]bool, len(sorted))
+	gen := r.gen
+	for i, s := range sorted {
+		i, s := i, s
+		r.timers = append(r.timers, time.AfterFunc(s.At, func() {
+			if !r.setActive(gen, i, true) {
+				return
+			}
+			logger.Info("Scenario started",
+				zap.String("scenario", s.Name),
+				zap.String("behavior", s.Behavior),
+				zap.Duration("duration", s.Duration),
+			)
+		}))
+		if s.Duration > 0 {
+			r.timers = append(r.timers, time.AfterFunc(s.At+s.Duration, func() {
+				if !r.setActive(gen, i, false) {
+					return
+				}
+				logger.Info("Scenario ended", zap.String("scenario", s.Name))
+			}))
+		}
+	}
+}
+
+// Stop cancels pending timers and deactivates all scenarios
+func (r *ScenarioRunner) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.timers {
+		t.Stop()
+	}
+	r.timers = nil
+	r.scenarios = nil
+	r.active = nil
+	r.gen++
+}
+
+// setActive flips scenario i, reporting false if the runner was stopped since gen
+func (r *ScenarioRunner) setActive(gen, i int, active bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if gen != r.gen {
+		return false
+	}
+	r.active[i] = active
+	return true
+}
+
+// ActiveBehaviors returns the behavior chains of the currently active scenarios,
+// ordered by start offset. When scenarios overlap, later entries take precedence.
+func (r *ScenarioRunner) ActiveBehaviors() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var behaviors []string
+	for i, s := range r.scenarios {
+		if r.active[i] {
+			behaviors = append(behaviors, s.Behavior)
+		}
+	}
+	return behaviors
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestParseScenarios(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError string
+		validate  func(t *testing.T, scenarios []Scenario)
+	}{
+		{
+			name: "inject with duration",
+			input: `
+- name: payment-outage
+  at: 30s
+  duration: 1m
+  action: inject
+  params:
+    behavior: payment:error=503
+`,
+			validate: func(t *testing.T, scenarios []Scenario) {
+				if len(scenarios) != 1 {
+					t.Fatalf("expected 1 scenario, got %d", len(scenarios))
+				}
+				s := scenarios[0]
+				if s.Name != "payment-outage" || s.At != 30*time.Second || s.Duration != time.Minute {
+					t.Errorf("unexpected scenario %+v", s)
+				}
+				if s.Behavior != "payment:error=503" {
+					t.Errorf("expected behavior payment:error=503, got %s", s.Behavior)
+				}
+			},
+		},
+		{
+			name:  "no duration runs until shutdown",
+			input: `[{name: slow, at: 0s, action: inject, params: {behavior: "latency=100ms"}}]`,
+			validate: func(t *testing.T, scenarios []Scenario) {
+				if scenarios[0].Duration != 0 {
+					t.Errorf("expected no duration, got %s", scenarios[0].Duration)
+				}
+			},
+		},
+		{
+			name:      "invalid at",
+			input:     `[{name: bad, at: soon, action: inject, params: {behavior: "error=503"}}]`,
+			wantError: "invalid at",
+		},
+		{
+			name:      "missing at",
+			input:     `[{name: bad, action: inject, params: {behavior: "error=503"}}]`,
+			wantError: "at is required",
+		},
+		{
+			name:      "invalid duration",
+			input:     `[{name: bad, at: 10s, duration: forever, action: inject, params: {behavior: "error=503"}}]`,
+			wantError: "invalid duration",
+		},
+		{
+			name:      "unknown action",
+			input:     `[{name: bad, at: 10s, action: reboot}]`,
+			wantError: "unknown action",
+		},
+		{
+			name:      "inject without behavior",
+			input:     `[{name: bad, at: 10s, action: inject}]`,
+			wantError: "requires a behavior",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarios, err := ParseScenarios([]byte(tt.input))
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseScenarios() failed: %v", err)
+			}
+			tt.validate(t, scenarios)
+		})
+	}
+}
+
+func TestLoadScenarios_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scenarios.yaml")
+	content := `[{name: outage, at: 5s, duration: 10s, action: inject, params: {behavior: "error=503"}}]`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write scenarios: %v", err)
+	}
+
+	t.Setenv("SCENARIOS", "")
+	t.Setenv("SCENARIOS_FILE", path)
+
+	scenarios, err := LoadScenarios()
+	if err != nil {
+		t.Fatalf("LoadScenarios() failed: %v", err)
+	}
+	if len(scenarios) != 1 || scenarios[0].Name != "outage" {
+		t.Errorf("unexpected scenarios %+v", scenarios)
+	}
+}
+
+func TestScenarioRunner(t *testing.T) {
+	r := &ScenarioRunner{}
+	defer r.Stop()
+
+	// Declared out of order: the runner orders by start offset so the
+	// later-starting scenario takes precedence while they overlap
+	r.Start([]Scenario{
+		{Name: "second", At: 100 * time.Millisecond, Duration: 200 * time.Millisecond, Behavior: "error=500"},
+		{Name: "first", At: 0, Duration: 200 * time.Millisecond, Behavior: "error=503"},
+	}, zap.NewNop())
+
+	waitFor := func(want []string) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			got := r.ActiveBehaviors()
+			if strings.Join(got, "|") == strings.Join(want, "|") {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected active behaviors %v, got %v", want, got)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor([]string{"error=503"})
+	waitFor([]string{"error=503", "error=500"})
+	waitFor([]string{"error=500"})
+	waitFor(nil)
+}
+
+func TestScenarioRunner_Stop(t *testing.T) {
+	r := &ScenarioRunner{}
+	r.Start([]Scenario{{Name: "later", At: 50 * time.Millisecond, Behavior: "error=503"}}, zap.NewNop())
+	r.Stop()
+
+	time.Sleep(80 * time.Millisecond)
+	if got := r.ActiveBehaviors(); len(got) != 0 {
+		t.Errorf("expected no active scenarios after Stop, got %v", got)
+	}
+}
@@ -29,6 +29,13 @@ type Telemetry struct {
 	Metrics     *Metrics
 	ServiceName string
 	Namespace   string
+
+	// verboseLogger logs at every level without sampling, for per-request level overrides
+	verboseLogger *zap.Logger
+
+	// TracingEnabled is set when traces are exported, so trace IDs can be
+	// attached to histogram observations as exemplars
+	TracingEnabled bool
 }
 
 // Metrics holds Prometheus metrics
@@ -43,31 +50,57 @@ type Metrics struct {
 	HTTPClientRequestDuration *prometheus.HistogramVec
 	HTTPClientActiveRequests  *prometheus.GaugeVec
 
+	// Service graph: one series per caller -> upstream edge
+	ServiceGraphTotal *prometheus.CounterVec
+
 	// gRPC Server metrics (application-level, supplements grpc_prometheus)
 	GRPCServerRequestsTotal   *prometheus.CounterVec
 	GRPCServerRequestDuration *prometheus.HistogramVec
 
 	// Custom behavior metrics
 	BehaviorAppliedTotal *prometheus.CounterVec
+
+	// Resources currently held by resource-exhaustion behaviors
+	BehaviorMemoryBytes *prometheus.GaugeVec
+	BehaviorDiskBytes   *prometheus.GaugeVec
+	BehaviorGoroutines  *prometheus.GaugeVec
+	BehaviorFDs         *prometheus.GaugeVec
+
+	// Bytes allocated by each memory spike at its peak
+	MemorySpikePeakBytes *prometheus.HistogramVec
+
+	// Time requests waited for admission by the queue behavior, admitted or not
+	QueueWaitSeconds *prometheus.HistogramVec
+
+	// Time requests queued for the lock behavior
+	LockWaitSeconds *prometheus.HistogramVec
+
+	// Lookups in the simulated response cache, by result (hit or miss)
+	CacheRequestsTotal *prometheus.CounterVec
+
+	// Requests in flight on this process across protocols, as seen by concurrency limits
+	InFlightRequests prometheus.GaugeFunc
 }
 
 // InitTelemetry initializes all telemetry components
 func InitTelemetry(serviceName, namespace, logLevel, otelEndpoint string, cfg *service.Config) (*Telemetry, error) {
 	// Initialize logger
-	logger, err := initLogger(serviceName, namespace, logLevel)
+	logger, verboseLogger, err := initLogger(serviceName, namespace, logLevel, cfg.LogSampleInitial, cfg.LogSampleThereafter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to init logger: %w", err)
 	}
 
 	// Initialize tracer
+	tracingEnabled := otelEndpoint != ""
 	tracer, err := initTracer(serviceName, namespace, otelEndpoint, cfg)
 	if err != nil {
 		logger.Warn("Failed to init tracer, continuing without tracing", zap.Error(err))
 		tracer = otel.Tracer(serviceName)
+		tracingEnabled = false
 	}
 
 	// Initialize metrics
-	metrics := initMetrics()
+	metrics := initMetrics(serviceName)
 
 	return &Telemetry{
 		Logger:      logger,
@@ -75,11 +108,17 @@ func InitTelemetry(serviceName, namespace, logLevel, otelEndpoint string, cfg *s
 		Metrics:     metrics,
 		ServiceName: serviceName,
 		Namespace:   namespace,
+
+		verboseLogger:  verboseLogger,
+		TracingEnabled: tracingEnabled,
 	}, nil
 }
 
-// initLogger creates a structured logger
-func initLogger(serviceName, namespace, logLevel string) (*zap.Logger, error) {
+// initLogger creates a structured logger at logLevel and an unsampled debug logger
+// for per-request overrides. Past sampleInitial identical messages in a second, the
+// logger only logs every sampleThereafter-th, so high QPS doesn't flood stdout.
+// A sampleInitial of 0 disables sampling.
+func initLogger(serviceName, namespace, logLevel string, sampleInitial, sampleThereafter int) (*zap.Logger, *zap.Logger, error) {
 	level := zapcore.InfoLevel
 	switch logLevel {
 	case "debug":
@@ -91,29 +130,57 @@ func initLogger(serviceName, namespace, logLevel string) (*zap.Logger, error) {
 	}
 
 	config := zap.Config{
-		Level:            zap.NewAtomicLevelAt(level),
+		Level:            zap.NewAtomicLevelAt(zapcore.DebugLevel),
 		Encoding:         "json",
 		EncoderConfig:    zap.NewProductionEncoderConfig(),
 		OutputPaths:      []string{"stdout"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
 
-	logger, err := config.Build()
+	verbose, err := config.Build()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Add default fields
-	logger = logger.With(
+	verbose = verbose.With(
 		zap.String("service", serviceName),
 		zap.String("namespace", namespace),
 	)
 
-	return logger, nil
+	logger := verbose.WithOptions(zap.IncreaseLevel(level))
+	if sampleInitial > 0 {
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, sampleInitial, sampleThereafter)
+		}))
+	}
+
+	return logger, verbose, nil
+}
+
+// RequestLogger returns the logger for a single request. With a level override
+// ("debug", "info", "warn" or "error") it logs at that level and is never sampled,
+// so one request can be inspected in detail; otherwise it is the regular Logger.
+func (t *Telemetry) RequestLogger(level string) *zap.Logger {
+	if level == "" || t.verboseLogger == nil {
+		return t.Logger
+	}
+	l, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return t.Logger
+	}
+	return t.verboseLogger.WithOptions(zap.IncreaseLevel(l))
 }
 
 // initTracer creates an OTEL tracer
 func initTracer(serviceName, namespace, endpoint string, cfg *service.Config) (trace.Tracer, error) {
+	// Propagate trace context and baggage even without an exporter, so baggage
+	// and incoming trace IDs still flow through the call chain
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
 	if endpoint == "" {
 		// No endpoint configured, return noop tracer
 		return otel.Tracer(serviceName), nil
@@ -173,16 +240,12 @@ func initTracer(serviceName, namespace, endpoint string, cfg *service.Config) (t
 	)
 
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
 
 	return tp.Tracer(serviceName), nil
 }
 
 // initMetrics creates Prometheus metrics
-func initMetrics() *Metrics {
+func initMetrics(serviceName string) *Metrics {
 	return &Metrics{
 		// HTTP Server metrics (RED method)
 		HTTPServerRequestsTotal: promauto.NewCounterVec(
@@ -232,6 +295,15 @@ func initMetrics() *Metrics {
 			[]string{"destination_service"},
 		),
 
+		// Service graph metrics (topology from metrics alone, without full trace sampling)
+		ServiceGraphTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "testservice_service_graph_total",
+				Help: "Total number of calls from a service to an upstream, by protocol and status",
+			},
+			[]string{"source", "destination", "protocol", "status"},
+		),
+
 		// gRPC Server metrics (application-level, captures actual response codes)
 		GRPCServerRequestsTotal: promauto.NewCounterVec(
 			prometheus.CounterOpts{
@@ -257,11 +329,96 @@ func initMetrics() *Metrics {
 			},
 			[]string{"service", "behavior_type"},
 		),
+
+		// Resource-exhaustion behavior gauges
+		BehaviorMemoryBytes: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "testservice_behavior_memory_bytes",
+				Help: "Bytes of memory currently held by memory behaviors",
+			},
+			[]string{"service"},
+		),
+		BehaviorDiskBytes: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "testservice_behavior_disk_bytes",
+				Help: "Bytes of disk currently filled by disk behaviors",
+			},
+			[]string{"service"},
+		),
+		BehaviorGoroutines: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "testservice_behavior_goroutines",
+				Help: "Goroutines currently held by goroutine-leak behaviors",
+			},
+			[]string{"service"},
+		),
+		BehaviorFDs: promauto.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "testservice_behavior_fds",
+				Help: "File descriptors currently held by fd-leak behaviors",
+			},
+			[]string{"service"},
+		),
+		MemorySpikePeakBytes: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "testservice_memory_spike_peak_bytes",
+				Help:    "Bytes allocated by memory spikes at their peak",
+				Buckets: prometheus.ExponentialBuckets(1<<20, 4, 8), // 1MiB to 16GiB
+			},
+			[]string{"service"},
+		),
+
+		QueueWaitSeconds: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "testservice_queue_wait_seconds",
+				Help:    "Time requests spent waiting for admission by the queue behavior",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"service"},
+		),
+
+		LockWaitSeconds: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "testservice_lock_wait_seconds",
+				Help:    "Time requests spent waiting for the simulated distributed lock",
+				Buckets: prometheus.DefBuckets,
+			},
+			[]string{"service"},
+		),
+
+		CacheRequestsTotal: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "testservice_cache_requests_total",
+				Help: "Requests looked up in the simulated response cache, by result (hit or miss)",
+			},
+			[]string{"service", "result"},
+		),
+
+		// Read from the shared in-flight counter on scrape, so it never goes stale
+		InFlightRequests: promauto.NewGaugeFunc(
+			prometheus.GaugeOpts{
+				Name:        "testservice_inflight_requests",
+				Help:        "HTTP and gRPC requests currently in flight",
+				ConstLabels: prometheus.Labels{"service": serviceName},
+			},
+			func() float64 { return float64(service.InFlight.Count()) },
+		),
+	}
+}
+
+// observe records a histogram observation, attaching traceID as an exemplar so
+// dashboards can link a latency spike to a trace. Without tracing, or without a
+// trace ID, it is a plain observation.
+func (t *Telemetry) observe(o prometheus.Observer, value float64, traceID string) {
+	if eo, ok := o.(prometheus.ExemplarObserver); ok && t.TracingEnabled && traceID != "" {
+		eo.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
 	}
+	o.Observe(value)
 }
 
-// RecordRequest records metrics for an HTTP server request
-func (t *Telemetry) RecordRequest(method, path string, statusCode int, duration time.Duration) {
+// RecordRequest records metrics for an HTTP server request, with traceID as the duration exemplar
+func (t *Telemetry) RecordRequest(method, path string, statusCode int, duration time.Duration, traceID string) {
 	if t.Metrics == nil {
 		return
 	}
@@ -277,16 +434,17 @@ func (t *Telemetry) RecordRequest(method, path string, statusCode int, duration
 	}
 
 	if t.Metrics.HTTPServerRequestDuration != nil {
-		t.Metrics.HTTPServerRequestDuration.WithLabelValues(
+		t.observe(t.Metrics.HTTPServerRequestDuration.WithLabelValues(
 			method,
 			path,
 			statusCodeStr,
-		).Observe(duration.Seconds())
+		), duration.Seconds(), traceID)
 	}
 }
 
-// RecordGRPCRequest records metrics for a gRPC server request (application-level)
-func (t *Telemetry) RecordGRPCRequest(method string, responseCode int, duration time.Duration) {
+// RecordGRPCRequest records metrics for a gRPC server request (application-level),
+// with traceID as the duration exemplar
+func (t *Telemetry) RecordGRPCRequest(method string, responseCode int, duration time.Duration, traceID string) {
 	if t.Metrics == nil {
 		return
 	}
@@ -301,15 +459,16 @@ func (t *Telemetry) RecordGRPCRequest(method string, responseCode int, duration
 	}
 
 	if t.Metrics.GRPCServerRequestDuration != nil {
-		t.Metrics.GRPCServerRequestDuration.WithLabelValues(
+		t.observe(t.Metrics.GRPCServerRequestDuration.WithLabelValues(
 			method,
 			responseCodeStr,
-		).Observe(duration.Seconds())
+		), duration.Seconds(), traceID)
 	}
 }
 
-// RecordUpstreamCall records metrics for an HTTP client (upstream) call
-func (t *Telemetry) RecordUpstreamCall(method, destinationService string, statusCode int, duration time.Duration) {
+// RecordUpstreamCall records metrics for an HTTP client (upstream) call, with
+// traceID as the duration exemplar
+func (t *Telemetry) RecordUpstreamCall(method, destinationService string, statusCode int, duration time.Duration, traceID string) {
 	if t.Metrics == nil {
 		return
 	}
@@ -325,12 +484,26 @@ func (t *Telemetry) RecordUpstreamCall(method, destinationService string, status
 	}
 
 	if t.Metrics.HTTPClientRequestDuration != nil {
-		t.Metrics.HTTPClientRequestDuration.WithLabelValues(
+		t.observe(t.Metrics.HTTPClientRequestDuration.WithLabelValues(
 			method,
 			destinationService,
 			statusCodeStr,
-		).Observe(duration.Seconds())
+		), duration.Seconds(), traceID)
+	}
+}
+
+// RecordServiceGraph records a call from source to the destination upstream. Both are
+// service names, never URLs, to keep cardinality bounded; status 0 is a connection error.
+func (t *Telemetry) RecordServiceGraph(source, destination, protocol string, statusCode int) {
+	if t.Metrics == nil || t.Metrics.ServiceGraphTotal == nil {
+		return
 	}
+	t.Metrics.ServiceGraphTotal.WithLabelValues(
+		source,
+		destination,
+		protocol,
+		fmt.Sprintf("%d", statusCode),
+	).Inc()
 }
 
 // RecordBehavior records when a behavior is applied
@@ -344,6 +517,68 @@ func (t *Telemetry) RecordBehavior(behaviorType string) {
 	).Inc()
 }
 
+// RecordQueueWait records how long a request waited for a queue slot, with traceID as
+// the exemplar
+func (t *Telemetry) RecordQueueWait(wait time.Duration, traceID string) {
+	if t.Metrics == nil || t.Metrics.QueueWaitSeconds == nil {
+		return
+	}
+	t.observe(t.Metrics.QueueWaitSeconds.WithLabelValues(t.ServiceName), wait.Seconds(), traceID)
+}
+
+// RecordLockWait records how long a request queued for the lock behavior, with traceID
+// as the exemplar
+func (t *Telemetry) RecordLockWait(wait time.Duration, traceID string) {
+	if t.Metrics == nil || t.Metrics.LockWaitSeconds == nil {
+		return
+	}
+	t.observe(t.Metrics.LockWaitSeconds.WithLabelValues(t.ServiceName), wait.Seconds(), traceID)
+}
+
+// RecordCacheLookup records a lookup in the response cache behavior as a hit or a miss
+func (t *Telemetry) RecordCacheLookup(hit bool) {
+	if t.Metrics == nil || t.Metrics.CacheRequestsTotal == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	t.Metrics.CacheRequestsTotal.WithLabelValues(t.ServiceName, result).Inc()
+}
+
+// AddBehaviorResource adjusts the gauge for a resource held by resource-exhaustion
+// behaviors ("memory", "disk", "goroutines" or "fds") by delta
+func (t *Telemetry) AddBehaviorResource(resource string, delta float64) {
+	if t.Metrics == nil {
+		return
+	}
+
+	var gauge *prometheus.GaugeVec
+	switch resource {
+	case "memory":
+		gauge = t.Metrics.BehaviorMemoryBytes
+	case "disk":
+		gauge = t.Metrics.BehaviorDiskBytes
+	case "goroutines":
+		gauge = t.Metrics.BehaviorGoroutines
+	case "fds":
+		gauge = t.Metrics.BehaviorFDs
+	}
+	if gauge == nil {
+		return
+	}
+	gauge.WithLabelValues(t.ServiceName).Add(delta)
+}
+
+// ObserveMemorySpike records the bytes a memory spike allocated at its peak
+func (t *Telemetry) ObserveMemorySpike(bytes float64) {
+	if t.Metrics == nil || t.Metrics.MemorySpikePeakBytes == nil {
+		return
+	}
+	t.Metrics.MemorySpikePeakBytes.WithLabelValues(t.ServiceName).Observe(bytes)
+}
+
 // IncActiveRequests increments active HTTP server request counter
 func (t *Telemetry) IncActiveRequests(method, path string) {
 	if t.Metrics == nil || t.Metrics.HTTPServerActiveRequests == nil {
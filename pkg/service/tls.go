@@ -0,0 +1,28 @@
+package service
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// ServerTLSConfig loads the configured server certificate. Returns nil when TLS isn't
+// configured, and an error if only one of the certificate and key files is set.
+func (c *Config) ServerTLSConfig() (*tls.Config, error) {
+	if c.TLSCertFile == "" && c.TLSKeyFile == "" {
+		return nil, nil
+	}
+	if c.TLSCertFile == "" || c.TLSKeyFile == "" {
+		return nil, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together (cert=%q, key=%q)", c.TLSCertFile, c.TLSKeyFile)
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCertFile, c.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		// gRPC clients require h2 to be negotiated
+		NextProtos: []string{"h2", "http/1.1"},
+	}, nil
+}
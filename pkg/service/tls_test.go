@@ -0,0 +1,95 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSelfSignedCert writes a self-signed certificate and key to dir
+func writeSelfSignedCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "testservice"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+func TestServerTLSConfig(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+
+	tests := []struct {
+		name      string
+		certFile  string
+		keyFile   string
+		wantTLS   bool
+		wantError bool
+	}{
+		{name: "plaintext by default"},
+		{name: "cert and key", certFile: certFile, keyFile: keyFile, wantTLS: true},
+		{name: "cert without key", certFile: certFile, wantError: true},
+		{name: "key without cert", keyFile: keyFile, wantError: true},
+		{name: "missing files", certFile: certFile + ".missing", keyFile: keyFile, wantError: true},
+		{name: "key as cert", certFile: keyFile, keyFile: keyFile, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TLSCertFile: tt.certFile, TLSKeyFile: tt.keyFile}
+			tlsConfig, err := cfg.ServerTLSConfig()
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ServerTLSConfig() error = %v, wantError %v", err, tt.wantError)
+			}
+			if (tlsConfig != nil) != tt.wantTLS {
+				t.Fatalf("ServerTLSConfig() = %v, wantTLS %v", tlsConfig, tt.wantTLS)
+			}
+			if tlsConfig != nil && len(tlsConfig.Certificates) != 1 {
+				t.Errorf("Expected the certificate to be loaded, got %d", len(tlsConfig.Certificates))
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromEnv_TLS(t *testing.T) {
+	t.Setenv("TLS_CERT_FILE", "/etc/tls/tls.crt")
+	t.Setenv("TLS_KEY_FILE", "/etc/tls/tls.key")
+
+	cfg := LoadConfigFromEnv()
+	if cfg.TLSCertFile != "/etc/tls/tls.crt" || cfg.TLSKeyFile != "/etc/tls/tls.key" {
+		t.Errorf("Expected TLS files from env, got cert=%q key=%q", cfg.TLSCertFile, cfg.TLSKeyFile)
+	}
+}
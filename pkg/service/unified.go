@@ -0,0 +1,29 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+)
+
+// UnifiedHandler serves HTTP and gRPC from one http.Server: gRPC requests, recognized by
+// their content-type, go to grpcHandler (the *grpc.Server) and everything else, including
+// HTTP/2 from ordinary clients, to httpHandler. Serve it with UnifiedProtocols.
+func UnifiedHandler(grpcHandler, httpHandler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+			grpcHandler.ServeHTTP(w, r)
+			return
+		}
+		httpHandler.ServeHTTP(w, r)
+	})
+}
+
+// UnifiedProtocols returns the protocols of a server using UnifiedHandler: HTTP/1.1, and
+// HTTP/2 both over TLS and as plaintext h2c, which gRPC clients use without TLS
+func UnifiedProtocols() *http.Protocols {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return protocols
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startUnified serves UnifiedHandler on a local port, with TLS if tlsConfig is set,
+// and returns its address
+func startUnified(t *testing.T, tlsConfig *tls.Config) string {
+	t.Helper()
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+	t.Cleanup(grpcServer.Stop)
+
+	httpServer := &http.Server{
+		Handler: UnifiedHandler(grpcServer, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, r.Proto)
+		})),
+		TLSConfig: tlsConfig,
+		Protocols: UnifiedProtocols(),
+	}
+	t.Cleanup(func() { httpServer.Close() })
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	if tlsConfig != nil {
+		go httpServer.ServeTLS(listener, "", "")
+	} else {
+		go httpServer.Serve(listener)
+	}
+	return listener.Addr().String()
+}
+
+// checkHealth calls the gRPC health service at addr
+func checkHealth(t *testing.T, addr string, creds credentials.TransportCredentials) {
+	t.Helper()
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Health check failed: %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING, got %s", resp.Status)
+	}
+}
+
+func TestUnified_TLS(t *testing.T) {
+	certFile, keyFile := writeSelfSignedCert(t, t.TempDir())
+	cfg := &Config{TLSCertFile: certFile, TLSKeyFile: keyFile}
+	tlsConfig, err := cfg.ServerTLSConfig()
+	if err != nil {
+		t.Fatalf("ServerTLSConfig() error = %v", err)
+	}
+	addr := startUnified(t, tlsConfig)
+
+	tests := []struct {
+		name      string
+		http2     bool
+		wantProto string
+	}{
+		{name: "HTTP/2", http2: true, wantProto: "HTTP/2.0"},
+		{name: "HTTP/1.1", http2: false, wantProto: "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+					ForceAttemptHTTP2: tt.http2,
+				},
+				Timeout: 5 * time.Second,
+			}
+			defer client.CloseIdleConnections()
+
+			resp, err := client.Get("https://" + addr + "/")
+			if err != nil {
+				t.Fatalf("GET failed: %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if resp.Proto != tt.wantProto || string(body) != tt.wantProto {
+				t.Errorf("Expected an %s response from the HTTP handler, got %s %q", tt.wantProto, resp.Proto, body)
+			}
+		})
+	}
+
+	t.Run("gRPC", func(t *testing.T) {
+		checkHealth(t, addr, credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}))
+	})
+}
+
+func TestUnified_Plaintext(t *testing.T) {
+	addr := startUnified(t, nil)
+
+	t.Run("HTTP/1.1", func(t *testing.T) {
+		resp, err := http.Get("http://" + addr + "/")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if body, _ := io.ReadAll(resp.Body); string(body) != "HTTP/1.1" {
+			t.Errorf("Expected an HTTP/1.1 response from the HTTP handler, got %q", body)
+		}
+	})
+
+	t.Run("HTTP/2 prior knowledge", func(t *testing.T) {
+		transport := &http.Transport{Protocols: new(http.Protocols)}
+		transport.Protocols.SetUnencryptedHTTP2(true)
+		client := &http.Client{Transport: transport, Timeout: 5 * time.Second}
+		defer client.CloseIdleConnections()
+
+		resp, err := client.Get("http://" + addr + "/")
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		defer resp.Body.Close()
+		if body, _ := io.ReadAll(resp.Body); string(body) != "HTTP/2.0" {
+			t.Errorf("Expected an HTTP/2 response from the HTTP handler, got %q", body)
+		}
+	})
+
+	t.Run("gRPC", func(t *testing.T) {
+		checkHealth(t, addr, insecure.NewCredentials())
+	})
+}
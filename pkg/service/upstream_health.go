@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// UpstreamHealth makes readiness depend on the reachability of the configured upstreams
+type UpstreamHealth struct {
+	enabled   atomic.Bool
+	mu        sync.RWMutex
+	upstreams []*UpstreamConfig
+	timeout   time.Duration // Per-upstream dial timeout
+}
+
+// UpstreamReadiness is the upstream check consulted by the /ready endpoint
+var UpstreamReadiness = &UpstreamHealth{timeout: time.Second}
+
+// SetUpstreams sets the upstreams that are checked when enabled
+func (u *UpstreamHealth) SetUpstreams(upstreams []*UpstreamConfig) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.upstreams = upstreams
+}
+
+// SetEnabled turns dependency-aware readiness on or off
+func (u *UpstreamHealth) SetEnabled(enabled bool) {
+	u.enabled.Store(enabled)
+}
+
+// Enabled reports whether readiness should reflect upstream reachability
+func (u *UpstreamHealth) Enabled() bool {
+	return u.enabled.Load()
+}
+
+// Check dials every upstream concurrently and returns an error naming the
+// first unreachable one, or nil if all are reachable
+func (u *UpstreamHealth) Check(ctx context.Context) error {
+	u.mu.RLock()
+	upstreams := u.upstreams
+	u.mu.RUnlock()
+
+	errs := make([]error, len(upstreams))
+	var wg sync.WaitGroup
+	for i, upstream := range upstreams {
+		wg.Add(1)
+		go func(i int, upstream *UpstreamConfig) {
+			defer wg.Done()
+			addr := upstreamAddress(upstream.URL)
+			dialer := net.Dialer{Timeout: u.timeout}
+			conn, err := dialer.DialContext(ctx, "tcp", addr)
+			if err != nil {
+				errs[i] = fmt.Errorf("upstream %s (%s) unreachable: %w", upstream.Name, addr, err)
+				return
+			}
+			conn.Close()
+		}(i, upstream)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upstreamAddress extracts host:port from an upstream URL, defaulting the port by scheme
+// Examples: "http://svc:8080/path" -> "svc:8080", "grpc://svc:9090" -> "svc:9090", "https://svc" -> "svc:443"
+func upstreamAddress(url string) string {
+	port := "80"
+	if idx := strings.Index(url, "://"); idx != -1 {
+		if url[:idx] == "https" {
+			port = "443"
+		}
+		url = url[idx+3:]
+	}
+	if idx := strings.Index(url, "/"); idx != -1 {
+		url = url[:idx]
+	}
+	if _, _, err := net.SplitHostPort(url); err != nil {
+		return net.JoinHostPort(url, port)
+	}
+	return url
+}